@@ -56,10 +56,108 @@ type ClusterSpec struct {
 	// for provisioning infrastructure for a cluster in said provider.
 	// +optional
 	InfrastructureRef *corev1.ObjectReference `json:"infrastructureRef,omitempty"`
+
+	// This encapsulates the topology for the cluster.
+	// NOTE: It is required to enable the ClusterTopology
+	// feature gate flag to activate managed topologies support;
+	// this feature is highly experimental, and parts of it might still be not implemented.
+	// +optional
+	Topology *Topology `json:"topology,omitempty"`
+
+	// MaintenanceWindows restricts when disruptive operations, i.e. control plane rollouts, MachineDeployment
+	// rollouts and MachineHealthCheck remediations, can start; they can start only while one of the windows is open.
+	// Control plane rollouts of machines whose certificates are about to expire are not restricted.
+	// When empty, disruptive operations can start at any time.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // ANCHOR_END: ClusterSpec
 
+// ANCHOR: MaintenanceWindow
+
+// MaintenanceWindow defines a recurring time window in which disruptive operations can start.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression in the standard five fields format (minute, hour, day of month, month
+	// and day of week) defining when the window opens, e.g. "0 22 * * 1-5".
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open, e.g. "4h".
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the name of the time zone in the IANA Time Zone database the schedule is evaluated in,
+	// e.g. "Europe/Rome". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ANCHOR_END: MaintenanceWindow
+
+// Topology encapsulates the information of the managed resources.
+type Topology struct {
+	// The name of the ClusterClass object to create the topology.
+	Class string `json:"class"`
+
+	// The Kubernetes version of the cluster.
+	Version string `json:"version"`
+
+	// ControlPlane describes the cluster control plane.
+	// +optional
+	ControlPlane ControlPlaneTopology `json:"controlPlane,omitempty"`
+
+	// Workers encapsulates the different constructs that form the worker nodes
+	// for the cluster.
+	// +optional
+	Workers *WorkersTopology `json:"workers,omitempty"`
+}
+
+// ControlPlaneTopology specifies the parameters for the control plane nodes in the cluster.
+type ControlPlaneTopology struct {
+	// Metadata is the metadata applied to the control plane object.
+	// +optional
+	Metadata ObjectMeta `json:"metadata,omitempty"`
+
+	// Replicas is the number of control plane nodes.
+	// If the value is nil, the ControlPlane object is created without the number of Replicas
+	// and it's assumed that the control plane controller does not implement support for this field.
+	// When specified against a control plane provider that lacks support for this field, this value will be ignored.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// WorkersTopology represents the different sets of worker nodes in the cluster.
+type WorkersTopology struct {
+	// MachineDeployments is a list of machine deployments in the cluster.
+	// +optional
+	MachineDeployments []MachineDeploymentTopology `json:"machineDeployments,omitempty"`
+}
+
+// MachineDeploymentTopology specifies the different parameters for a set of worker nodes in the topology.
+// This set of nodes is managed by a MachineDeployment object whose lifecycle is managed by the Cluster controller.
+type MachineDeploymentTopology struct {
+	// Metadata is the metadata applied to the machines of the MachineDeployment.
+	// At runtime this metadata is merged with the corresponding metadata from the ClusterClass.
+	// +optional
+	Metadata ObjectMeta `json:"metadata,omitempty"`
+
+	// Class is the name of the MachineDeploymentClass used to create the set of worker nodes.
+	// This should match one of the deployment classes defined in the ClusterClass object
+	// mentioned in the `Cluster.Spec.Topology.Class` field.
+	Class string `json:"class"`
+
+	// Name is the unique identifier for this MachineDeploymentTopology.
+	// The value is used together with the cluster's name to create the MachineDeployment's Name,
+	// and it MUST be unique within a Cluster topology.
+	Name string `json:"name"`
+
+	// Replicas is the number of worker nodes belonging to this set.
+	// If the value is nil, the MachineDeployment is created without the number of Replicas (defaulting to 1)
+	// and it's assumed that an external entity (like cluster autoscaler) is responsible for the management
+	// of this value.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
 // ANCHOR: ClusterNetwork
 
 // ClusterNetwork specifies the different networking
@@ -17,9 +17,15 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -48,12 +54,19 @@ func (c *Cluster) Default() {
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (c *Cluster) ValidateCreate() error {
-	return c.validate()
+	return c.validate(nil)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (c *Cluster) ValidateUpdate(old runtime.Object) error {
-	return c.validate()
+	var oldCluster *Cluster
+	if old != nil {
+		var ok bool
+		if oldCluster, ok = old.(*Cluster); !ok {
+			return apierrors.NewBadRequest(fmt.Sprintf("expected a Cluster but got a %T", old))
+		}
+	}
+	return c.validate(oldCluster)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -61,7 +74,7 @@ func (c *Cluster) ValidateDelete() error {
 	return nil
 }
 
-func (c *Cluster) validate() error {
+func (c *Cluster) validate(old *Cluster) error {
 	var allErrs field.ErrorList
 	if c.Spec.InfrastructureRef != nil && c.Spec.InfrastructureRef.Namespace != c.Namespace {
 		allErrs = append(
@@ -87,8 +100,82 @@ func (c *Cluster) validate() error {
 
 	}
 
+	allErrs = append(allErrs, c.validateTopology(old)...)
+	allErrs = append(allErrs, c.validateMaintenanceWindows()...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return apierrors.NewInvalid(GroupVersion.WithKind("Cluster").GroupKind(), c.Name, allErrs)
 }
+
+func (c *Cluster) validateTopology(old *Cluster) field.ErrorList {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec", "topology")
+
+	// Changing a Cluster from managed to unmanaged topology (or vice versa) is not supported.
+	if old != nil && (old.Spec.Topology == nil) != (c.Spec.Topology == nil) {
+		allErrs = append(allErrs, field.Forbidden(path, "cannot be added or removed from an existing Cluster"))
+		return allErrs
+	}
+
+	if c.Spec.Topology == nil {
+		return allErrs
+	}
+
+	// NOTE: ClusterClass and managed topologies are behind ClusterTopology feature gate flag; the web hook
+	// must prevent the usage of Cluster.Topology in case the feature flag is disabled.
+	if !feature.Gates.Enabled(feature.ClusterTopology) {
+		allErrs = append(allErrs, field.Forbidden(path, "can be set only if the ClusterTopology feature flag is enabled"))
+		return allErrs
+	}
+
+	if c.Spec.Topology.Class == "" {
+		allErrs = append(allErrs, field.Required(path.Child("class"), "must not be empty"))
+	}
+	if old != nil && old.Spec.Topology.Class != c.Spec.Topology.Class {
+		allErrs = append(allErrs, field.Invalid(path.Child("class"), c.Spec.Topology.Class, "field is immutable"))
+	}
+
+	if !kubeSemver.MatchString(c.Spec.Topology.Version) {
+		allErrs = append(allErrs, field.Invalid(path.Child("version"), c.Spec.Topology.Version, "must be a valid semantic version"))
+	}
+
+	if c.Spec.Topology.Workers != nil {
+		names := map[string]bool{}
+		for i, md := range c.Spec.Topology.Workers.MachineDeployments {
+			mdPath := path.Child("workers", "machineDeployments").Index(i)
+			if md.Class == "" {
+				allErrs = append(allErrs, field.Required(mdPath.Child("class"), "must not be empty"))
+			}
+			for _, msg := range validation.IsDNS1123Label(md.Name) {
+				allErrs = append(allErrs, field.Invalid(mdPath.Child("name"), md.Name, msg))
+			}
+			if names[md.Name] {
+				allErrs = append(allErrs, field.Duplicate(mdPath.Child("name"), md.Name))
+			}
+			names[md.Name] = true
+		}
+	}
+
+	return allErrs
+}
+
+func (c *Cluster) validateMaintenanceWindows() field.ErrorList {
+	var allErrs field.ErrorList
+	for i, window := range c.Spec.MaintenanceWindows {
+		path := field.NewPath("spec", "maintenanceWindows").Index(i)
+		if schedule, err := cron.ParseStandard(window.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("schedule"), window.Schedule, err.Error()))
+		} else if schedule.Next(time.Now()).IsZero() {
+			allErrs = append(allErrs, field.Invalid(path.Child("schedule"), window.Schedule, "must activate at least once"))
+		}
+		if window.Duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("duration"), window.Duration.String(), "must be greater than 0"))
+		}
+		if _, err := time.LoadLocation(window.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), window.TimeZone, "must be a valid IANA time zone name"))
+		}
+	}
+	return allErrs
+}
@@ -18,11 +18,15 @@ package v1alpha4
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/cluster-api/feature"
 )
 
 func TestClusterDefault(t *testing.T) {
@@ -99,3 +103,196 @@ func TestClusterValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterMaintenanceWindowsValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		expectErr bool
+		window    MaintenanceWindow
+	}{
+		{
+			name:      "should succeed with a valid window",
+			expectErr: false,
+			window:    MaintenanceWindow{Schedule: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		},
+		{
+			name:      "should succeed with a valid window in a time zone",
+			expectErr: false,
+			window:    MaintenanceWindow{Schedule: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}, TimeZone: "Europe/Rome"},
+		},
+		{
+			name:      "should return error when the schedule is not valid",
+			expectErr: true,
+			window:    MaintenanceWindow{Schedule: "0 22 * *", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		},
+		{
+			name:      "should return error when the schedule never activates",
+			expectErr: true,
+			window:    MaintenanceWindow{Schedule: "0 22 30 2 *", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		},
+		{
+			name:      "should return error when the duration is not set",
+			expectErr: true,
+			window:    MaintenanceWindow{Schedule: "0 22 * * 1-5"},
+		},
+		{
+			name:      "should return error when the time zone is not valid",
+			expectErr: true,
+			window:    MaintenanceWindow{Schedule: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}, TimeZone: "Mars/Olympus_Mons"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &Cluster{
+				Spec: ClusterSpec{
+					MaintenanceWindows: []MaintenanceWindow{tt.window},
+				},
+			}
+			if tt.expectErr {
+				g.Expect(c.ValidateCreate()).NotTo(Succeed())
+				g.Expect(c.ValidateUpdate(c)).NotTo(Succeed())
+			} else {
+				g.Expect(c.ValidateCreate()).To(Succeed())
+				g.Expect(c.ValidateUpdate(c)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestClusterTopologyValidation(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	valid := &Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+		},
+		Spec: ClusterSpec{
+			Topology: &Topology{
+				Class:   "foo",
+				Version: "v1.19.1",
+				ControlPlane: ControlPlaneTopology{
+					Replicas: pointer.Int32Ptr(3),
+				},
+				Workers: &WorkersTopology{
+					MachineDeployments: []MachineDeploymentTopology{
+						{
+							Class:    "worker",
+							Name:     "md-1",
+							Replicas: pointer.Int32Ptr(2),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	missingClass := valid.DeepCopy()
+	missingClass.Spec.Topology.Class = ""
+
+	invalidVersion := valid.DeepCopy()
+	invalidVersion.Spec.Topology.Version = "1.19.1"
+
+	invalidMachineDeploymentName := valid.DeepCopy()
+	invalidMachineDeploymentName.Spec.Topology.Workers.MachineDeployments[0].Name = "Not_A_Valid_Name"
+
+	duplicateMachineDeploymentName := valid.DeepCopy()
+	duplicateMachineDeploymentName.Spec.Topology.Workers.MachineDeployments = append(
+		duplicateMachineDeploymentName.Spec.Topology.Workers.MachineDeployments,
+		duplicateMachineDeploymentName.Spec.Topology.Workers.MachineDeployments[0],
+	)
+
+	tests := []struct {
+		name      string
+		expectErr bool
+		c         *Cluster
+	}{
+		{
+			name:      "should succeed for a valid topology",
+			expectErr: false,
+			c:         valid,
+		},
+		{
+			name:      "should return error when the class is empty",
+			expectErr: true,
+			c:         missingClass,
+		},
+		{
+			name:      "should return error when the version is not a valid semantic version",
+			expectErr: true,
+			c:         invalidVersion,
+		},
+		{
+			name:      "should return error when a machine deployment name is not valid",
+			expectErr: true,
+			c:         invalidMachineDeploymentName,
+		},
+		{
+			name:      "should return error when machine deployment names are duplicated",
+			expectErr: true,
+			c:         duplicateMachineDeploymentName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			if tt.expectErr {
+				g.Expect(tt.c.ValidateCreate()).NotTo(Succeed())
+			} else {
+				g.Expect(tt.c.ValidateCreate()).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestClusterTopologyValidateUpdate(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	g := NewWithT(t)
+
+	old := &Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+		},
+		Spec: ClusterSpec{
+			Topology: &Topology{
+				Class:   "foo",
+				Version: "v1.19.1",
+			},
+		},
+	}
+
+	versionUpgrade := old.DeepCopy()
+	versionUpgrade.Spec.Topology.Version = "v1.20.1"
+	g.Expect(versionUpgrade.ValidateUpdate(old)).To(Succeed())
+
+	classChange := old.DeepCopy()
+	classChange.Spec.Topology.Class = "bar"
+	g.Expect(classChange.ValidateUpdate(old)).NotTo(Succeed())
+
+	topologyRemoved := old.DeepCopy()
+	topologyRemoved.Spec.Topology = nil
+	g.Expect(topologyRemoved.ValidateUpdate(old)).NotTo(Succeed())
+}
+
+func TestClusterTopologyFeatureGated(t *testing.T) {
+	g := NewWithT(t)
+
+	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to set Cluster.Topologies.
+	c := &Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+		},
+		Spec: ClusterSpec{
+			Topology: &Topology{
+				Class:   "foo",
+				Version: "v1.19.1",
+			},
+		},
+	}
+	g.Expect(c.ValidateCreate()).NotTo(Succeed())
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api/feature"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (in *ClusterClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(in).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cluster-x-k8s-io-v1alpha4-clusterclass,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=clusterclasses,versions=v1alpha4,name=validation.clusterclass.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-cluster-x-k8s-io-v1alpha4-clusterclass,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=clusterclasses,versions=v1alpha4,name=default.clusterclass.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+var _ webhook.Defaulter = &ClusterClass{}
+var _ webhook.Validator = &ClusterClass{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (in *ClusterClass) Default() {
+	// Default all namespaces in the references to the object namespace.
+	defaultNamespace(in.Spec.Infrastructure.Ref, in.Namespace)
+	defaultNamespace(in.Spec.ControlPlane.Ref, in.Namespace)
+	if in.Spec.ControlPlane.MachineInfrastructure != nil {
+		defaultNamespace(in.Spec.ControlPlane.MachineInfrastructure.Ref, in.Namespace)
+	}
+	for i := range in.Spec.Workers.MachineDeployments {
+		defaultNamespace(in.Spec.Workers.MachineDeployments[i].Template.Bootstrap.Ref, in.Namespace)
+		defaultNamespace(in.Spec.Workers.MachineDeployments[i].Template.Infrastructure.Ref, in.Namespace)
+	}
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (in *ClusterClass) ValidateCreate() error {
+	return in.validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (in *ClusterClass) ValidateUpdate(old runtime.Object) error {
+	oldClusterClass, ok := old.(*ClusterClass)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a ClusterClass but got a %T", old))
+	}
+	return in.validate(oldClusterClass)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (in *ClusterClass) ValidateDelete() error {
+	return nil
+}
+
+func (in *ClusterClass) validate(old *ClusterClass) error {
+	// NOTE: ClusterClass and managed topologies are behind ClusterTopology feature gate flag; the web hook
+	// must prevent creating new objects in case the feature flag is disabled.
+	if !feature.Gates.Enabled(feature.ClusterTopology) {
+		return field.Forbidden(
+			field.NewPath("spec"),
+			"can be set only if the ClusterTopology feature flag is enabled",
+		)
+	}
+
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, in.Spec.Infrastructure.validate(in.Namespace, specPath.Child("infrastructure"))...)
+	allErrs = append(allErrs, in.Spec.ControlPlane.LocalObjectTemplate.validate(in.Namespace, specPath.Child("controlPlane"))...)
+	if in.Spec.ControlPlane.MachineInfrastructure != nil {
+		allErrs = append(allErrs, in.Spec.ControlPlane.MachineInfrastructure.validate(in.Namespace, specPath.Child("controlPlane", "machineInfrastructure"))...)
+	}
+
+	classes := map[string]bool{}
+	for i, class := range in.Spec.Workers.MachineDeployments {
+		mdPath := specPath.Child("workers", "machineDeployments").Index(i)
+		if class.Class == "" {
+			allErrs = append(allErrs, field.Required(mdPath.Child("class"), "must not be empty"))
+		}
+		if classes[class.Class] {
+			allErrs = append(allErrs, field.Duplicate(mdPath.Child("class"), class.Class))
+		}
+		classes[class.Class] = true
+
+		allErrs = append(allErrs, class.Template.Bootstrap.validate(in.Namespace, mdPath.Child("template", "bootstrap"))...)
+		allErrs = append(allErrs, class.Template.Infrastructure.validate(in.Namespace, mdPath.Child("template", "infrastructure"))...)
+	}
+
+	if old != nil {
+		allErrs = append(allErrs, in.validateCompatibleWith(old)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("ClusterClass").GroupKind(), in.Name, allErrs)
+}
+
+// validateCompatibleWith ensures that an update of the ClusterClass does not change the kind of the
+// objects generated for the Clusters already using it, given that the topology controller can only
+// update objects in place.
+func (in *ClusterClass) validateCompatibleWith(old *ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, in.Spec.Infrastructure.validateCompatibleWith(old.Spec.Infrastructure, specPath.Child("infrastructure"))...)
+	allErrs = append(allErrs, in.Spec.ControlPlane.LocalObjectTemplate.validateCompatibleWith(old.Spec.ControlPlane.LocalObjectTemplate, specPath.Child("controlPlane"))...)
+
+	oldClasses := map[string]MachineDeploymentClass{}
+	for _, class := range old.Spec.Workers.MachineDeployments {
+		oldClasses[class.Class] = class
+	}
+	for i, class := range in.Spec.Workers.MachineDeployments {
+		oldClass, ok := oldClasses[class.Class]
+		if !ok {
+			continue
+		}
+		mdPath := specPath.Child("workers", "machineDeployments").Index(i)
+		allErrs = append(allErrs, class.Template.Bootstrap.validateCompatibleWith(oldClass.Template.Bootstrap, mdPath.Child("template", "bootstrap"))...)
+		allErrs = append(allErrs, class.Template.Infrastructure.validateCompatibleWith(oldClass.Template.Infrastructure, mdPath.Child("template", "infrastructure"))...)
+	}
+
+	return allErrs
+}
+
+func (r *LocalObjectTemplate) validate(namespace string, pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Ref == nil {
+		allErrs = append(allErrs, field.Required(pathPrefix.Child("ref"), "must be defined"))
+		return allErrs
+	}
+	if r.Ref.Name == "" {
+		allErrs = append(allErrs, field.Required(pathPrefix.Child("ref", "name"), "must not be empty"))
+	}
+	if r.Ref.Namespace != namespace {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("ref", "namespace"), r.Ref.Namespace, "must match metadata.namespace"))
+	}
+
+	return allErrs
+}
+
+func (r *LocalObjectTemplate) validateCompatibleWith(old LocalObjectTemplate, pathPrefix *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Ref == nil || old.Ref == nil {
+		return allErrs
+	}
+	if r.Ref.GroupVersionKind().GroupKind() != old.Ref.GroupVersionKind().GroupKind() {
+		allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("ref"), "cannot change the kind or the API group of the referenced template"))
+	}
+
+	return allErrs
+}
+
+func defaultNamespace(ref *corev1.ObjectReference, namespace string) {
+	if ref != nil && len(ref.Namespace) == 0 {
+		ref.Namespace = namespace
+	}
+}
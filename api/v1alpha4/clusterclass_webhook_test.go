@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/cluster-api/feature"
+)
+
+func newTestClusterClass() *ClusterClass {
+	ref := func(kind, name string) LocalObjectTemplate {
+		return LocalObjectTemplate{
+			Ref: &corev1.ObjectReference{
+				APIVersion: "foo/v1alpha4",
+				Kind:       kind,
+				Name:       name,
+				Namespace:  "default",
+			},
+		}
+	}
+	return &ClusterClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "class",
+			Namespace: "default",
+		},
+		Spec: ClusterClassSpec{
+			Infrastructure: ref("FooClusterTemplate", "infra"),
+			ControlPlane: ControlPlaneClass{
+				LocalObjectTemplate: ref("FooControlPlaneTemplate", "cp"),
+			},
+			Workers: WorkersClass{
+				MachineDeployments: []MachineDeploymentClass{
+					{
+						Class: "worker",
+						Template: MachineDeploymentClassTemplate{
+							Bootstrap:      ref("FooConfigTemplate", "bootstrap"),
+							Infrastructure: ref("FooMachineTemplate", "infra"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestClusterClassDefaultNamespaces(t *testing.T) {
+	g := NewWithT(t)
+
+	in := newTestClusterClass()
+	in.Namespace = "foo"
+	in.Spec.Infrastructure.Ref.Namespace = ""
+	in.Spec.ControlPlane.Ref.Namespace = ""
+	in.Spec.ControlPlane.MachineInfrastructure = &LocalObjectTemplate{Ref: &corev1.ObjectReference{}}
+	in.Spec.Workers.MachineDeployments[0].Template.Bootstrap.Ref.Namespace = ""
+	in.Spec.Workers.MachineDeployments[0].Template.Infrastructure.Ref.Namespace = ""
+
+	in.Default()
+
+	g.Expect(in.Spec.Infrastructure.Ref.Namespace).To(Equal("foo"))
+	g.Expect(in.Spec.ControlPlane.Ref.Namespace).To(Equal("foo"))
+	g.Expect(in.Spec.ControlPlane.MachineInfrastructure.Ref.Namespace).To(Equal("foo"))
+	g.Expect(in.Spec.Workers.MachineDeployments[0].Template.Bootstrap.Ref.Namespace).To(Equal("foo"))
+	g.Expect(in.Spec.Workers.MachineDeployments[0].Template.Infrastructure.Ref.Namespace).To(Equal("foo"))
+}
+
+func TestClusterClassValidationFeatureGated(t *testing.T) {
+	g := NewWithT(t)
+
+	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to create ClusterClass.
+	in := newTestClusterClass()
+	g.Expect(in.ValidateCreate()).NotTo(Succeed())
+	g.Expect(in.ValidateUpdate(in)).NotTo(Succeed())
+}
+
+func TestClusterClassValidation(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	missingInfrastructureRef := newTestClusterClass()
+	missingInfrastructureRef.Spec.Infrastructure.Ref = nil
+
+	wrongControlPlaneNamespace := newTestClusterClass()
+	wrongControlPlaneNamespace.Spec.ControlPlane.Ref.Namespace = "bar"
+
+	missingWorkerClassName := newTestClusterClass()
+	missingWorkerClassName.Spec.Workers.MachineDeployments[0].Class = ""
+
+	duplicateWorkerClass := newTestClusterClass()
+	duplicateWorkerClass.Spec.Workers.MachineDeployments = append(duplicateWorkerClass.Spec.Workers.MachineDeployments,
+		*duplicateWorkerClass.Spec.Workers.MachineDeployments[0].DeepCopy())
+
+	missingBootstrapRef := newTestClusterClass()
+	missingBootstrapRef.Spec.Workers.MachineDeployments[0].Template.Bootstrap.Ref = nil
+
+	missingMachineInfrastructureName := newTestClusterClass()
+	missingMachineInfrastructureName.Spec.ControlPlane.MachineInfrastructure = &LocalObjectTemplate{
+		Ref: &corev1.ObjectReference{Namespace: "default"},
+	}
+
+	tests := []struct {
+		name      string
+		in        *ClusterClass
+		expectErr bool
+	}{
+		{
+			name:      "should succeed for a valid ClusterClass",
+			in:        newTestClusterClass(),
+			expectErr: false,
+		},
+		{
+			name:      "should return error when the infrastructure ref is missing",
+			in:        missingInfrastructureRef,
+			expectErr: true,
+		},
+		{
+			name:      "should return error when the control plane ref namespace does not match",
+			in:        wrongControlPlaneNamespace,
+			expectErr: true,
+		},
+		{
+			name:      "should return error when a worker class has no name",
+			in:        missingWorkerClassName,
+			expectErr: true,
+		},
+		{
+			name:      "should return error when worker classes are duplicated",
+			in:        duplicateWorkerClass,
+			expectErr: true,
+		},
+		{
+			name:      "should return error when a worker bootstrap ref is missing",
+			in:        missingBootstrapRef,
+			expectErr: true,
+		},
+		{
+			name:      "should return error when the control plane machine infrastructure ref has no name",
+			in:        missingMachineInfrastructureName,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			if tt.expectErr {
+				g.Expect(tt.in.ValidateCreate()).NotTo(Succeed())
+			} else {
+				g.Expect(tt.in.ValidateCreate()).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestClusterClassValidateUpdate(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	changedName := newTestClusterClass()
+	changedName.Spec.Infrastructure.Ref.Name = "another-infra"
+
+	changedKind := newTestClusterClass()
+	changedKind.Spec.ControlPlane.Ref.Kind = "BarControlPlaneTemplate"
+
+	changedWorkerKind := newTestClusterClass()
+	changedWorkerKind.Spec.Workers.MachineDeployments[0].Template.Infrastructure.Ref.Kind = "BarMachineTemplate"
+
+	addedWorkerClass := newTestClusterClass()
+	addedWorkerClass.Spec.Workers.MachineDeployments = append(addedWorkerClass.Spec.Workers.MachineDeployments,
+		*addedWorkerClass.Spec.Workers.MachineDeployments[0].DeepCopy())
+	addedWorkerClass.Spec.Workers.MachineDeployments[1].Class = "another-worker"
+	addedWorkerClass.Spec.Workers.MachineDeployments[1].Template.Infrastructure.Ref.Kind = "BarMachineTemplate"
+
+	tests := []struct {
+		name      string
+		in        *ClusterClass
+		expectErr bool
+	}{
+		{
+			name:      "should allow changing the name of a referenced template",
+			in:        changedName,
+			expectErr: false,
+		},
+		{
+			name:      "should allow adding a new worker class",
+			in:        addedWorkerClass,
+			expectErr: false,
+		},
+		{
+			name:      "should return error when changing the kind of the control plane template",
+			in:        changedKind,
+			expectErr: true,
+		},
+		{
+			name:      "should return error when changing the kind of a worker infrastructure template",
+			in:        changedWorkerKind,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			if tt.expectErr {
+				g.Expect(tt.in.ValidateUpdate(newTestClusterClass())).NotTo(Succeed())
+			} else {
+				g.Expect(tt.in.ValidateUpdate(newTestClusterClass())).To(Succeed())
+			}
+		})
+	}
+}
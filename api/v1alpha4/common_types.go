@@ -42,6 +42,23 @@ const (
 	// when KCP or a machineset scales down. This annotation is given top priority on all delete policies.
 	DeleteMachineAnnotation = "cluster.x-k8s.io/delete-machine"
 
+	// MaintenanceWindowOverrideAnnotation can be set on a Cluster to allow disruptive operations to start outside of
+	// the Cluster's maintenance windows, e.g. to roll out an emergency fix.
+	MaintenanceWindowOverrideAnnotation = "cluster.x-k8s.io/maintenance-window-override"
+
+	// OrphanAnnotation can be set on a Cluster before deleting it to remove the Cluster API objects while leaving
+	// the underlying infrastructure, e.g. VMs and load balancers, and the Kubernetes Nodes running.
+	//
+	// Infrastructure providers must check the existence of this annotation on the owning Cluster when deleting
+	// infrastructure objects, and if the Cluster is being deleted they must remove their finalizers without
+	// destroying the infrastructure.
+	OrphanAnnotation = "cluster.x-k8s.io/orphan"
+
+	// DisableMachineCreate is an annotation that can be used to signal a MachineSet to stop creating new machines.
+	// It is utilized in the OnDelete MachineDeploymentStrategy to allow the MachineDeployment controller to scale down
+	// older MachineSets when Machines are deleted and add the new replicas to the latest MachineSet.
+	DisableMachineCreate = "machineset.cluster.x-k8s.io/disable-machine-create"
+
 	// TemplateClonedFromNameAnnotation is the infrastructure machine annotation that stores the name of the infrastructure template resource
 	// that was cloned for the machine. This annotation is set only during cloning a template. Older/adopted machines will not have this annotation.
 	TemplateClonedFromNameAnnotation = "cluster.x-k8s.io/cloned-from-name"
@@ -55,6 +72,70 @@ const (
 
 	// InterruptibleLabel is the label used to mark the nodes that run on interruptible instances
 	InterruptibleLabel = "cluster.x-k8s.io/interruptible"
+
+	// NodeMetadataDomain is the domain of the labels and annotations that are kept in sync from a Machine to
+	// the corresponding Node. Labels and annotations with a key prefix in this domain or in one of its subdomains,
+	// e.g. node.cluster.x-k8s.io/role or example.node.cluster.x-k8s.io/pool, are propagated to the Node.
+	NodeMetadataDomain = "node.cluster.x-k8s.io"
+
+	// ManagedNodeLabelsAnnotation is the annotation set on Nodes to track the labels propagated from the Machine;
+	// it is used to remove labels from the Node when they are removed from the Machine.
+	ManagedNodeLabelsAnnotation = "cluster.x-k8s.io/managed-labels"
+
+	// ManagedNodeAnnotationsAnnotation is the annotation set on Nodes to track the annotations propagated from the Machine;
+	// it is used to remove annotations from the Node when they are removed from the Machine.
+	ManagedNodeAnnotationsAnnotation = "cluster.x-k8s.io/managed-annotations"
+
+	// ManagedNodeTaintsAnnotation is the annotation set on Nodes to track the taints applied from the Machine, in the key:effect form;
+	// it is used to remove taints from the Node when they are removed from the Machine.
+	ManagedNodeTaintsAnnotation = "cluster.x-k8s.io/managed-taints"
+
+	// InPlaceTemplateAnnotation is the annotation set on MachineSets to track the fields of the machine template last
+	// propagated in place to the Machines; it is used to apply to the Machines only the values changed in the machine template.
+	InPlaceTemplateAnnotation = "cluster.x-k8s.io/in-place-template"
+
+	// ClusterTopologyOwnedLabel is the label set on all the object which are managed as part of a ClusterTopology.
+	ClusterTopologyOwnedLabel = "topology.cluster.x-k8s.io/owned"
+
+	// ClusterTopologyManagedFieldsAnnotation is the annotation set on the objects generated from a ClusterClass to track
+	// the spec fields set by the topology controller; it is used to remove fields from the object when they are removed
+	// from the ClusterClass templates.
+	ClusterTopologyManagedFieldsAnnotation = "topology.cluster.x-k8s.io/managed-field-paths"
+
+	// ClusterTopologyMachineDeploymentLabelName is the label set on the generated MachineDeployment objects
+	// to track the name of the MachineDeployment topology it represents.
+	ClusterTopologyMachineDeploymentLabelName = "topology.cluster.x-k8s.io/deployment-name"
+)
+
+// Annotations set on MachineDeployments and MachineSets to describe the Nodes they create; they are read by the
+// cluster autoscaler to scale a MachineDeployment or a MachineSet from zero replicas.
+// The annotations are set only if the infrastructure machine template publishes its capacity in status.capacity.
+const (
+	// CPUCapacityAnnotation is the annotation containing the number of CPUs of the Nodes.
+	CPUCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/cpu"
+
+	// MemoryCapacityAnnotation is the annotation containing the amount of memory of the Nodes.
+	MemoryCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/memory"
+
+	// EphemeralDiskCapacityAnnotation is the annotation containing the amount of ephemeral storage of the Nodes.
+	EphemeralDiskCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/ephemeral-disk"
+
+	// GPUTypeCapacityAnnotation is the annotation containing the resource name of the GPUs of the Nodes, e.g. nvidia.com/gpu.
+	GPUTypeCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-type"
+
+	// GPUCountCapacityAnnotation is the annotation containing the number of GPUs of the Nodes.
+	GPUCountCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-count"
+
+	// MaxPodsCapacityAnnotation is the annotation containing the maximum number of Pods that can run on the Nodes.
+	MaxPodsCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/maxPods"
+
+	// LabelsCapacityAnnotation is the annotation containing the labels propagated to the Nodes, in the key=value form,
+	// separated by commas.
+	LabelsCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/labels"
+
+	// TaintsCapacityAnnotation is the annotation containing the taints applied to the Nodes, in the key=value:effect form,
+	// separated by commas.
+	TaintsCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/taints"
 )
 
 // MachineAddressType describes a valid MachineAddress type.
@@ -72,6 +72,15 @@ const (
 	// NOTE: Having the control plane machine available is a pre-condition for joining additional control planes
 	// or workers nodes.
 	WaitingForControlPlaneAvailableReason = "WaitingForControlPlaneAvailable"
+
+	// TopologyReconciledCondition reports if the objects defined by the Cluster's managed topology
+	// (e.g. infrastructure cluster, control plane, MachineDeployments) are in sync with the ClusterClass.
+	// NOTE: This condition is set only on Clusters with a managed topology.
+	TopologyReconciledCondition ConditionType = "TopologyReconciled"
+
+	// TopologyReconcileFailedReason (Severity=Error) documents the topology controller failing to
+	// reconcile the objects defined by the Cluster's managed topology.
+	TopologyReconcileFailedReason = "TopologyReconcileFailed"
 )
 
 // Conditions and condition Reasons for the Machine object
@@ -101,11 +110,15 @@ const (
 	// PreDrainDeleteHookSucceededCondition reports a machine waiting for a PreDrainDeleteHook before being delete.
 	PreDrainDeleteHookSucceededCondition ConditionType = "PreDrainDeleteHookSucceeded"
 
-	// PreTerminateDeleteHookSucceededCondition reports a machine waiting for a PreDrainDeleteHook before being delete.
+	// PreTerminateDeleteHookSucceededCondition reports a machine waiting for a PreTerminateDeleteHook before being delete.
 	PreTerminateDeleteHookSucceededCondition ConditionType = "PreTerminateDeleteHookSucceeded"
 
 	// WaitingExternalHookReason (Severity=Info) provide evidence that we are waiting for an external hook to complete.
 	WaitingExternalHookReason = "WaitingExternalHook"
+
+	// WaitingForReadinessGatesReason (Severity=Info) documents a machine waiting for the conditions listed in
+	// its readiness gates to be set by external controllers.
+	WaitingForReadinessGatesReason = "WaitingForReadinessGates"
 )
 
 const (
@@ -173,6 +186,22 @@ const (
 	NodeConditionsFailedReason = "NodeConditionsFailed"
 )
 
+// Conditions and condition Reasons for the MachineDeployment object
+
+const (
+	// MachineDeploymentProgressingCondition reports whether the rollout of a MachineDeployment is completed, or it is
+	// making progress within the MachineDeployment's ProgressDeadlineSeconds.
+	MachineDeploymentProgressingCondition ConditionType = "Progressing"
+
+	// ProgressDeadlineExceededReason (Severity=Error) documents a MachineDeployment whose rollout did not make
+	// progress for longer than the MachineDeployment's ProgressDeadlineSeconds.
+	ProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+	// MachineDeploymentPausedReason (Severity=Info) documents a MachineDeployment being paused; the progress
+	// of the rollout is not estimated while the MachineDeployment is paused.
+	MachineDeploymentPausedReason = "MachineDeploymentPaused"
+)
+
 // Conditions and condition Reasons for the MachineHealthCheck object
 
 const (
@@ -184,3 +213,12 @@ const (
 	// from making any further remediations.
 	TooManyUnhealthyReason = "TooManyUnhealthy"
 )
+
+// Condition Reasons for the operations gated by the Cluster's maintenance windows
+
+const (
+	// WaitingForMaintenanceWindowReason (Severity=Info) documents a disruptive operation, i.e. a control plane rollout,
+	// a MachineDeployment rollout or a MachineHealthCheck remediation, waiting for one of the Cluster's maintenance
+	// windows to open before starting.
+	WaitingForMaintenanceWindowReason = "WaitingForMaintenanceWindow"
+)
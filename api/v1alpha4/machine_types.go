@@ -95,10 +95,28 @@ type MachineSpec struct {
 	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
 	// +optional
 	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// Taints are the taints to be applied to the Node corresponding to this Machine.
+	// Taints are kept in sync with the Node, and they are removed from the Node when removed from this list;
+	// taints added to the Node by other actors are left untouched.
+	// Taints are not supported on MachinePools, which do not create Machines.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// ReadinessGates specifies additional conditions, set by external controllers, which must be true for
+	// the Machine to be considered ready and available, in addition to its Node being ready.
+	// +optional
+	ReadinessGates []MachineReadinessGate `json:"readinessGates,omitempty"`
 }
 
 // ANCHOR_END: MachineSpec
 
+// MachineReadinessGate contains the type of a condition which must be true for a Machine to be considered ready.
+type MachineReadinessGate struct {
+	// ConditionType refers to a condition in the Machine's conditions with a matching type.
+	ConditionType ConditionType `json:"conditionType"`
+}
+
 // ANCHOR: MachineStatus
 
 // MachineStatus defines the observed state of Machine
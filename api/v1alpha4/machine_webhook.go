@@ -129,6 +129,31 @@ func (m *Machine) validate(old *Machine) error {
 		}
 	}
 
+	// Taints are applied to the Node, so the same key and effect can't be used more than once.
+	taints := map[string]bool{}
+	for i, taint := range m.Spec.Taints {
+		key := string(taint.Effect) + "/" + taint.Key
+		if taints[key] {
+			allErrs = append(allErrs, field.Duplicate(field.NewPath("spec", "taints").Index(i), taint.Key+":"+string(taint.Effect)))
+		}
+		taints[key] = true
+	}
+
+	// Readiness gates are used to compute the Ready condition, so they can't refer to the Ready condition itself.
+	readinessGates := map[ConditionType]bool{}
+	for i, gate := range m.Spec.ReadinessGates {
+		path := field.NewPath("spec", "readinessGates").Index(i).Child("conditionType")
+		switch {
+		case gate.ConditionType == "":
+			allErrs = append(allErrs, field.Required(path, "conditionType is required"))
+		case gate.ConditionType == ReadyCondition:
+			allErrs = append(allErrs, field.Invalid(path, gate.ConditionType, "cannot be the Ready condition"))
+		case readinessGates[gate.ConditionType]:
+			allErrs = append(allErrs, field.Duplicate(path, gate.ConditionType))
+		}
+		readinessGates[gate.ConditionType] = true
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
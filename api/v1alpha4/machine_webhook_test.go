@@ -246,3 +246,110 @@ func TestMachineVersionValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineTaintsValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		taints    []corev1.Taint
+		expectErr bool
+	}{
+		{
+			name: "should succeed when taints have different keys or effects",
+			taints: []corev1.Taint{
+				{Key: "node.cluster.x-k8s.io/pool", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.cluster.x-k8s.io/pool", Value: "a", Effect: corev1.TaintEffectNoExecute},
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error when taints have the same key and effect",
+			taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "dedicated", Value: "cpu", Effect: corev1.TaintEffectNoSchedule},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &Machine{
+				Spec: MachineSpec{
+					Bootstrap: Bootstrap{ConfigRef: nil, DataSecretName: pointer.StringPtr("test")},
+					Taints:    tt.taints,
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestMachineReadinessGatesValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		readinessGates []MachineReadinessGate
+		expectErr      bool
+	}{
+		{
+			name: "should not return error when readiness gates are valid",
+			readinessGates: []MachineReadinessGate{
+				{ConditionType: "GPUDriverReady"},
+				{ConditionType: "SecurityAgentReady"},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error when the condition type is empty",
+			readinessGates: []MachineReadinessGate{
+				{ConditionType: ""},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when the condition type is Ready",
+			readinessGates: []MachineReadinessGate{
+				{ConditionType: ReadyCondition},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when the condition type is duplicated",
+			readinessGates: []MachineReadinessGate{
+				{ConditionType: "GPUDriverReady"},
+				{ConditionType: "GPUDriverReady"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &Machine{
+				Spec: MachineSpec{
+					Bootstrap:      Bootstrap{ConfigRef: nil, DataSecretName: pointer.StringPtr("test")},
+					ReadinessGates: tt.readinessGates,
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
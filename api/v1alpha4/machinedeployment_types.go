@@ -28,6 +28,11 @@ const (
 	// i.e. gradually scale down the old MachineSet and scale up the new one.
 	RollingUpdateMachineDeploymentStrategyType MachineDeploymentStrategyType = "RollingUpdate"
 
+	// OnDeleteMachineDeploymentStrategyType replaces old MachineSets when the deletion of the associated machines is completed.
+	// i.e. old machines are replaced only after they get deleted by a user or an external system,
+	// or after they get marked with the DeleteMachineAnnotation.
+	OnDeleteMachineDeploymentStrategyType MachineDeploymentStrategyType = "OnDelete"
+
 	// RevisionAnnotation is the revision annotation of a machine deployment's machine sets which records its rollout sequence
 	RevisionAnnotation = "machinedeployment.clusters.x-k8s.io/revision"
 	// RevisionHistoryAnnotation maintains the history of all old revisions that a machine set has served for a machine deployment.
@@ -67,6 +72,12 @@ type MachineDeploymentSpec struct {
 	// +optional
 	Strategy *MachineDeploymentStrategy `json:"strategy,omitempty"`
 
+	// FailureDomainDistribution defines how machines are distributed across
+	// the Cluster's failure domains; it is propagated to the MachineSets.
+	// When not set, all the machines are created in Template.Spec.FailureDomain.
+	// +optional
+	FailureDomainDistribution *FailureDomainDistribution `json:"failureDomainDistribution,omitempty"`
+
 	// Minimum number of seconds for which a newly created machine should
 	// be ready.
 	// Defaults to 0 (machine will be considered available as soon as it
@@ -99,9 +110,10 @@ type MachineDeploymentSpec struct {
 // MachineDeploymentStrategy describes how to replace existing machines
 // with new ones.
 type MachineDeploymentStrategy struct {
-	// Type of deployment. Currently the only supported strategy is
-	// "RollingUpdate".
-	// Default is RollingUpdate.
+	// Type of deployment.
+	// Allowed values are RollingUpdate and OnDelete.
+	// The default is RollingUpdate.
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
 	// +optional
 	Type MachineDeploymentStrategyType `json:"type,omitempty"`
 
@@ -195,6 +207,21 @@ type MachineDeploymentStatus struct {
 	// Phase represents the current phase of a MachineDeployment (ScalingUp, ScalingDown, Running, Failed, or Unknown).
 	// +optional
 	Phase string `json:"phase,omitempty"`
+
+	// LastProgressTime is the last time the rollout of the MachineDeployment made progress;
+	// it is used to detect rollouts exceeding ProgressDeadlineSeconds, and it is not set while the deployment is paused or complete.
+	// +optional
+	LastProgressTime *metav1.Time `json:"lastProgressTime,omitempty"`
+
+	// Conditions defines current service state of the MachineDeployment.
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
+
+	// InPlaceUpdatedFields are the fields of the machine template propagated in place to the current MachineSet
+	// and its Machines by the last change of the machine template, without rolling out new Machines.
+	// This list is cleared when a change of the machine template rolls out a new MachineSet.
+	// +optional
+	InPlaceUpdatedFields []string `json:"inPlaceUpdatedFields,omitempty"`
 }
 
 // ANCHOR_END: MachineDeploymentStatus
@@ -239,6 +266,14 @@ func (md *MachineDeploymentStatus) GetTypedPhase() MachineDeploymentPhase {
 	}
 }
 
+func (m *MachineDeployment) GetConditions() Conditions {
+	return m.Status.Conditions
+}
+
+func (m *MachineDeployment) SetConditions(conditions Conditions) {
+	m.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=machinedeployments,shortName=md,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
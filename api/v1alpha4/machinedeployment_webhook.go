@@ -85,6 +85,15 @@ func (m *MachineDeployment) validate(old *MachineDeployment) error {
 		)
 	}
 
+	if m.Spec.Strategy != nil && m.Spec.Strategy.Type == OnDeleteMachineDeploymentStrategyType && m.Spec.Strategy.RollingUpdate != nil {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(field.NewPath("spec", "strategy", "rollingUpdate"), "may not be specified when strategy type is OnDelete"),
+		)
+	}
+
+	allErrs = append(allErrs, validateFailureDomainDistribution(m.Spec.FailureDomainDistribution, field.NewPath("spec", "failureDomainDistribution"))...)
+
 	if old != nil && old.Spec.ClusterName != m.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
@@ -177,3 +177,27 @@ func TestMachineDeploymentClusterNameImmutable(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineDeploymentOnDeleteStrategy(t *testing.T) {
+	g := NewWithT(t)
+
+	md := &MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-md",
+		},
+		Spec: MachineDeploymentSpec{
+			Strategy: &MachineDeploymentStrategy{
+				Type: OnDeleteMachineDeploymentStrategyType,
+			},
+		},
+	}
+
+	md.Default()
+	g.Expect(md.Spec.Strategy.Type).To(Equal(OnDeleteMachineDeploymentStrategyType))
+	g.Expect(md.Spec.Strategy.RollingUpdate).To(BeNil())
+	g.Expect(md.ValidateCreate()).To(Succeed())
+
+	md.Spec.Strategy.RollingUpdate = &MachineRollingUpdateDeployment{}
+	g.Expect(md.ValidateCreate()).NotTo(Succeed())
+	g.Expect(md.ValidateUpdate(md)).NotTo(Succeed())
+}
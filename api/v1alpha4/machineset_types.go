@@ -44,10 +44,15 @@ type MachineSetSpec struct {
 	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
 
 	// DeletePolicy defines the policy used to identify nodes to delete when downscaling.
-	// Defaults to "Random".  Valid values are "Random, "Newest", "Oldest"
-	// +kubebuilder:validation:Enum=Random;Newest;Oldest
+	// Defaults to "Random".  Valid values are "Random, "Newest", "Oldest", "LeastDisruptive"
+	// +kubebuilder:validation:Enum=Random;Newest;Oldest;LeastDisruptive
 	DeletePolicy string `json:"deletePolicy,omitempty"`
 
+	// FailureDomainDistribution defines how Machines are distributed across the Cluster's failure domains.
+	// When not set, all the Machines are created in Template.Spec.FailureDomain.
+	// +optional
+	FailureDomainDistribution *FailureDomainDistribution `json:"failureDomainDistribution,omitempty"`
+
 	// Selector is a label query over machines that should match the replica count.
 	// Label keys and values that must match in order to be controlled by this MachineSet.
 	// It must match the machine template's labels.
@@ -102,6 +107,39 @@ const (
 	// (Status.FailureReason or Status.FailureMessage are set to a non-empty value).
 	// It then prioritizes the oldest Machines for deletion based on the Machine's CreationTimestamp.
 	OldestMachineSetDeletePolicy MachineSetDeletePolicy = "Oldest"
+
+	// LeastDisruptiveMachineSetDeletePolicy prioritizes both Machines that have the annotation
+	// "cluster.x-k8s.io/delete-machine=yes" and Machines that are unhealthy
+	// (Status.FailureReason or Status.FailureMessage are set to a non-empty value).
+	// It then prioritizes the Machines whose Node runs the lowest workload, based on the number of
+	// non-DaemonSet Pods, of Pods protected by a PodDisruptionBudget and of Pods using local storage.
+	LeastDisruptiveMachineSetDeletePolicy MachineSetDeletePolicy = "LeastDisruptive"
+)
+
+// ANCHOR: FailureDomainDistribution
+
+// FailureDomainDistribution defines how Machines are distributed across the Cluster's failure domains.
+type FailureDomainDistribution struct {
+	// Policy is the policy used to assign Machines to failure domains.
+	// +kubebuilder:validation:Enum=Spread
+	Policy FailureDomainDistributionPolicy `json:"policy"`
+
+	// FailureDomains is the list of the failure domains, among the ones in Cluster.Status.FailureDomains,
+	// Machines can be assigned to. Defaults to all the failure domains of the Cluster.
+	// +optional
+	FailureDomains []string `json:"failureDomains,omitempty"`
+}
+
+// ANCHOR_END: FailureDomainDistribution
+
+// FailureDomainDistributionPolicy defines how Machines are assigned to failure domains.
+type FailureDomainDistributionPolicy string
+
+const (
+	// SpreadFailureDomainDistributionPolicy assigns each new Machine to the eligible failure domain with the fewest
+	// Machines, and prioritizes Machines in the failure domains with the most Machines for deletion when downscaling.
+	// Template.Spec.FailureDomain is ignored, unless the Cluster does not report any eligible failure domain.
+	SpreadFailureDomainDistributionPolicy FailureDomainDistributionPolicy = "Spread"
 )
 
 // ANCHOR: MachineSetStatus
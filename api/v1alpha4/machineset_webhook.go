@@ -109,6 +109,26 @@ func (m *MachineSet) validate(old *MachineSet) error {
 		)
 	}
 
+	switch MachineSetDeletePolicy(m.Spec.DeletePolicy) {
+	case "", RandomMachineSetDeletePolicy, NewestMachineSetDeletePolicy, OldestMachineSetDeletePolicy, LeastDisruptiveMachineSetDeletePolicy:
+	default:
+		allErrs = append(
+			allErrs,
+			field.NotSupported(
+				field.NewPath("spec", "deletePolicy"),
+				m.Spec.DeletePolicy,
+				[]string{
+					string(RandomMachineSetDeletePolicy),
+					string(NewestMachineSetDeletePolicy),
+					string(OldestMachineSetDeletePolicy),
+					string(LeastDisruptiveMachineSetDeletePolicy),
+				},
+			),
+		)
+	}
+
+	allErrs = append(allErrs, validateFailureDomainDistribution(m.Spec.FailureDomainDistribution, field.NewPath("spec", "failureDomainDistribution"))...)
+
 	if old != nil && old.Spec.ClusterName != m.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
@@ -122,3 +142,30 @@ func (m *MachineSet) validate(old *MachineSet) error {
 
 	return apierrors.NewInvalid(GroupVersion.WithKind("MachineSet").GroupKind(), m.Name, allErrs)
 }
+
+// validateFailureDomainDistribution validates the FailureDomainDistribution of a MachineSet or of a MachineDeployment.
+func validateFailureDomainDistribution(distribution *FailureDomainDistribution, fldPath *field.Path) field.ErrorList {
+	if distribution == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if distribution.Policy != SpreadFailureDomainDistributionPolicy {
+		allErrs = append(
+			allErrs,
+			field.NotSupported(fldPath.Child("policy"), distribution.Policy, []string{string(SpreadFailureDomainDistributionPolicy)}),
+		)
+	}
+
+	seen := map[string]bool{}
+	for i, failureDomain := range distribution.FailureDomains {
+		switch {
+		case failureDomain == "":
+			allErrs = append(allErrs, field.Required(fldPath.Child("failureDomains").Index(i), "failure domain must not be empty"))
+		case seen[failureDomain]:
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("failureDomains").Index(i), failureDomain))
+		}
+		seen[failureDomain] = true
+	}
+	return allErrs
+}
@@ -108,6 +108,128 @@ func TestMachineSetLabelSelectorMatchValidation(t *testing.T) {
 
 }
 
+func TestMachineSetDeletePolicyValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		deletePolicy string
+		expectErr    bool
+	}{
+		{
+			name:         "should not return error when the delete policy is not set",
+			deletePolicy: "",
+			expectErr:    false,
+		},
+		{
+			name:         "should not return error for the Random delete policy",
+			deletePolicy: string(RandomMachineSetDeletePolicy),
+			expectErr:    false,
+		},
+		{
+			name:         "should not return error for the LeastDisruptive delete policy",
+			deletePolicy: string(LeastDisruptiveMachineSetDeletePolicy),
+			expectErr:    false,
+		},
+		{
+			name:         "should return error for an unknown delete policy",
+			deletePolicy: "Unknown",
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ms := &MachineSet{
+				Spec: MachineSetSpec{
+					DeletePolicy: tt.deletePolicy,
+				},
+			}
+			if tt.expectErr {
+				g.Expect(ms.ValidateCreate()).NotTo(Succeed())
+				g.Expect(ms.ValidateUpdate(ms)).NotTo(Succeed())
+			} else {
+				g.Expect(ms.ValidateCreate()).To(Succeed())
+				g.Expect(ms.ValidateUpdate(ms)).To(Succeed())
+			}
+		})
+	}
+}
+
+func TestMachineSetFailureDomainDistributionValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		distribution *FailureDomainDistribution
+		expectErr    bool
+	}{
+		{
+			name:         "should not return error when the failure domain distribution is not set",
+			distribution: nil,
+			expectErr:    false,
+		},
+		{
+			name:         "should not return error for the Spread policy",
+			distribution: &FailureDomainDistribution{Policy: SpreadFailureDomainDistributionPolicy},
+			expectErr:    false,
+		},
+		{
+			name: "should not return error for the Spread policy with a list of failure domains",
+			distribution: &FailureDomainDistribution{
+				Policy:         SpreadFailureDomainDistributionPolicy,
+				FailureDomains: []string{"one", "two"},
+			},
+			expectErr: false,
+		},
+		{
+			name:         "should return error for an unknown policy",
+			distribution: &FailureDomainDistribution{Policy: "Unknown"},
+			expectErr:    true,
+		},
+		{
+			name: "should return error for an empty failure domain",
+			distribution: &FailureDomainDistribution{
+				Policy:         SpreadFailureDomainDistributionPolicy,
+				FailureDomains: []string{"one", ""},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error for a duplicated failure domain",
+			distribution: &FailureDomainDistribution{
+				Policy:         SpreadFailureDomainDistributionPolicy,
+				FailureDomains: []string{"one", "two", "one"},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ms := &MachineSet{
+				Spec: MachineSetSpec{
+					FailureDomainDistribution: tt.distribution,
+				},
+			}
+			md := &MachineDeployment{
+				Spec: MachineDeploymentSpec{
+					FailureDomainDistribution: tt.distribution,
+				},
+			}
+			if tt.expectErr {
+				g.Expect(ms.ValidateCreate()).NotTo(Succeed())
+				g.Expect(ms.ValidateUpdate(ms)).NotTo(Succeed())
+				g.Expect(md.ValidateCreate()).NotTo(Succeed())
+				g.Expect(md.ValidateUpdate(md)).NotTo(Succeed())
+			} else {
+				g.Expect(ms.ValidateCreate()).To(Succeed())
+				g.Expect(ms.ValidateUpdate(ms)).To(Succeed())
+				g.Expect(md.ValidateCreate()).To(Succeed())
+				g.Expect(md.ValidateUpdate(md)).To(Succeed())
+			}
+		})
+	}
+}
+
 func TestMachineSetClusterNameImmutable(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -22,13 +22,24 @@ import (
 	kubeadmv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 )
 
+const (
+	// ForceInitAnnotation is a KubeadmConfig annotation that makes a control plane machine run kubeadm init
+	// even if the control plane of the cluster has already been initialized; the kubeadm preflight check on the etcd
+	// data directory is skipped, so the directory can be populated in advance. It is used by the KubeadmControlPlane
+	// controller when rebuilding a control plane from an etcd snapshot.
+	ForceInitAnnotation = "bootstrap.cluster.x-k8s.io/force-init"
+)
+
 // Format specifies the output format of the bootstrap data
-// +kubebuilder:validation:Enum=cloud-config
+// +kubebuilder:validation:Enum=cloud-config;ignition
 type Format string
 
 const (
 	// CloudConfig make the bootstrap data to be of cloud-config format
 	CloudConfig Format = "cloud-config"
+
+	// Ignition make the bootstrap data to be of Ignition format
+	Ignition Format = "ignition"
 )
 
 // KubeadmConfigSpec defines the desired state of KubeadmConfig.
@@ -74,7 +85,9 @@ type KubeadmConfigSpec struct {
 	// +optional
 	NTP *NTP `json:"ntp,omitempty"`
 
-	// Format specifies the output format of the bootstrap data
+	// Format specifies the output format of the bootstrap data.
+	// When using the ignition format, the kubeadm configuration files are written in /etc/kubeadm,
+	// and kubeadm is run by a systemd unit on the first boot.
 	// +optional
 	Format Format `json:"format,omitempty"`
 
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 // These tests are written in BDD-style using Ginkgo framework. Refer to
@@ -145,6 +146,137 @@ func TestClusterValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		"valid ignition": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					DiskSetup: &DiskSetup{
+						Partitions: []Partition{
+							{
+								Device:    "/dev/sdb",
+								Layout:    true,
+								TableType: pointer.StringPtr("gpt"),
+							},
+						},
+						Filesystems: []Filesystem{
+							{
+								Device:     "/dev/sdb1",
+								Filesystem: "ext4",
+								Label:      "etcd_disk",
+							},
+						},
+					},
+					Mounts: []MountPoints{
+						{"LABEL=etcd_disk", "/var/lib/etcddisk"},
+					},
+				},
+			},
+		},
+		"invalid ignition with experimental retry join": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format:                   Ignition,
+					UseExperimentalRetryJoin: true,
+				},
+			},
+			expectErr: true,
+		},
+		"invalid ignition with inactive user": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					Users: []User{
+						{
+							Name:     "foo",
+							Inactive: pointer.BoolPtr(true),
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid ignition with mbr partition table": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					DiskSetup: &DiskSetup{
+						Partitions: []Partition{
+							{
+								Device:    "/dev/sdb",
+								Layout:    true,
+								TableType: pointer.StringPtr("mbr"),
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid ignition with filesystem partition": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					DiskSetup: &DiskSetup{
+						Filesystems: []Filesystem{
+							{
+								Device:     "/dev/sdb",
+								Filesystem: "ext4",
+								Label:      "etcd_disk",
+								Partition:  pointer.StringPtr("auto"),
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"invalid ignition with mount without mount point": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format: Ignition,
+					Mounts: []MountPoints{
+						{"LABEL=etcd_disk"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		"valid cloud-config with experimental retry join": {
+			in: &KubeadmConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "baz",
+					Namespace: "default",
+				},
+				Spec: KubeadmConfigSpec{
+					Format:                   CloudConfig,
+					UseExperimentalRetryJoin: true,
+				},
+			},
+		},
 	}
 
 	for name, tt := range cases {
@@ -32,6 +32,7 @@ var (
 	MissingSecretNameMsg     = "secret file source must specify non-empty secret name"
 	MissingSecretKeyMsg      = "secret file source must specify non-empty secret key"
 	PathConflictMsg          = "path property must be unique among all files"
+	IgnitionUnsupportedMsg   = "not supported when using the ignition format"
 )
 
 func (c *KubeadmConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -115,8 +116,77 @@ func (c *KubeadmConfigSpec) validate(name string) error {
 		knownPaths[file.Path] = struct{}{}
 	}
 
+	if c.Format == Ignition {
+		allErrs = append(allErrs, c.validateIgnition()...)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return apierrors.NewInvalid(GroupVersion.WithKind("KubeadmConfig").GroupKind(), name, allErrs)
 }
+
+// validateIgnition validates the fields that cannot be converted into an Ignition config.
+func (c *KubeadmConfigSpec) validateIgnition() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if c.UseExperimentalRetryJoin {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(field.NewPath("spec", "useExperimentalRetryJoin"), IgnitionUnsupportedMsg),
+		)
+	}
+
+	for i, user := range c.Users {
+		if user.Inactive != nil && *user.Inactive {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(field.NewPath("spec", "users", fmt.Sprintf("%d", i), "inactive"), IgnitionUnsupportedMsg),
+			)
+		}
+	}
+
+	if c.DiskSetup != nil {
+		for i, partition := range c.DiskSetup.Partitions {
+			if partition.TableType != nil && *partition.TableType != "gpt" {
+				allErrs = append(
+					allErrs,
+					field.Invalid(
+						field.NewPath("spec", "diskSetup", "partitions", fmt.Sprintf("%d", i), "tableType"),
+						*partition.TableType,
+						"only the gpt table type is supported when using the ignition format",
+					),
+				)
+			}
+		}
+		for i, filesystem := range c.DiskSetup.Filesystems {
+			if filesystem.Partition != nil {
+				allErrs = append(
+					allErrs,
+					field.Forbidden(field.NewPath("spec", "diskSetup", "filesystems", fmt.Sprintf("%d", i), "partition"), IgnitionUnsupportedMsg),
+				)
+			}
+			if filesystem.ReplaceFS != nil {
+				allErrs = append(
+					allErrs,
+					field.Forbidden(field.NewPath("spec", "diskSetup", "filesystems", fmt.Sprintf("%d", i), "replaceFS"), IgnitionUnsupportedMsg),
+				)
+			}
+		}
+	}
+
+	for i, mount := range c.Mounts {
+		if len(mount) < 2 {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("spec", "mounts", fmt.Sprintf("%d", i)),
+					mount,
+					"the device and the mount point must be specified when using the ignition format",
+				),
+			)
+		}
+	}
+
+	return allErrs
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -34,6 +35,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/ignition"
 	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/locking"
 	kubeadmv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 	bsutil "sigs.k8s.io/cluster-api/bootstrap/util"
@@ -237,7 +239,7 @@ func (r *KubeadmConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, nil
 	}
 
-	if !cluster.Status.ControlPlaneInitialized {
+	if !cluster.Status.ControlPlaneInitialized || (isForceInit(config) && configOwner.IsControlPlaneMachine()) {
 		return r.handleClusterNotInitialized(ctx, scope)
 	}
 
@@ -339,18 +341,22 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 	// acquire the init lock so that only the first machine configured
 	// as control plane get processed here
 	// if not the first, requeue
-	if !r.KubeadmInitLock.Lock(ctx, scope.Cluster, machine) {
-		scope.Info("A control plane is already being initialized, requeing until control plane is ready")
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
-	}
+	// Nb. forced init is requested only by the control plane provider, which is responsible for
+	// creating a single machine, so the lock is not required in this case.
+	if !isForceInit(scope.Config) {
+		if !r.KubeadmInitLock.Lock(ctx, scope.Cluster, machine) {
+			scope.Info("A control plane is already being initialized, requeing until control plane is ready")
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
 
-	defer func() {
-		if reterr != nil {
-			if !r.KubeadmInitLock.Unlock(ctx, scope.Cluster) {
-				reterr = kerrors.NewAggregate([]error{reterr, errors.New("failed to unlock the kubeadm init lock")})
+		defer func() {
+			if reterr != nil {
+				if !r.KubeadmInitLock.Unlock(ctx, scope.Cluster) {
+					reterr = kerrors.NewAggregate([]error{reterr, errors.New("failed to unlock the kubeadm init lock")})
+				}
 			}
-		}
-	}()
+		}()
+	}
 
 	scope.Info("Creating BootstrapData for the init control plane")
 
@@ -409,6 +415,10 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 	if scope.Config.Spec.Verbosity != nil {
 		verbosityFlag = fmt.Sprintf("--v %s", strconv.Itoa(int(*scope.Config.Spec.Verbosity)))
 	}
+	// A forced init runs on top of an etcd data directory already populated, e.g. with a restored snapshot.
+	if isForceInit(scope.Config) {
+		verbosityFlag = strings.TrimSpace(fmt.Sprintf("%s --ignore-preflight-errors=%s", verbosityFlag, etcdDataDirPreflightCheck(scope.Config.Spec.ClusterConfiguration)))
+	}
 
 	files, err := r.resolveFiles(ctx, scope.Config)
 	if err != nil {
@@ -416,7 +426,7 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		return ctrl.Result{}, err
 	}
 
-	cloudInitData, err := cloudinit.NewInitControlPlane(&cloudinit.ControlPlaneInput{
+	controlPlaneInput := &cloudinit.ControlPlaneInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:     files,
 			NTP:                 scope.Config.Spec.NTP,
@@ -430,13 +440,20 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 		InitConfiguration:    initdata,
 		ClusterConfiguration: clusterdata,
 		Certificates:         certificates,
-	})
+	}
+
+	var bootstrapData []byte
+	if scope.Config.Spec.Format == bootstrapv1.Ignition {
+		bootstrapData, err = ignition.NewInitControlPlane(controlPlaneInput)
+	} else {
+		bootstrapData, err = cloudinit.NewInitControlPlane(controlPlaneInput)
+	}
 	if err != nil {
 		scope.Error(err, "Failed to generate cloud init for bootstrap control plane")
 		return ctrl.Result{}, err
 	}
 
-	if err := r.storeBootstrapData(ctx, scope, cloudInitData); err != nil {
+	if err := r.storeBootstrapData(ctx, scope, bootstrapData); err != nil {
 		scope.Error(err, "Failed to store bootstrap data")
 		return ctrl.Result{}, err
 	}
@@ -491,7 +508,7 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		return ctrl.Result{}, err
 	}
 
-	cloudJoinData, err := cloudinit.NewNode(&cloudinit.NodeInput{
+	nodeInput := &cloudinit.NodeInput{
 		BaseUserData: cloudinit.BaseUserData{
 			AdditionalFiles:      files,
 			NTP:                  scope.Config.Spec.NTP,
@@ -504,13 +521,20 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 			UseExperimentalRetry: scope.Config.Spec.UseExperimentalRetryJoin,
 		},
 		JoinConfiguration: joinData,
-	})
+	}
+
+	var bootstrapData []byte
+	if scope.Config.Spec.Format == bootstrapv1.Ignition {
+		bootstrapData, err = ignition.NewNode(nodeInput)
+	} else {
+		bootstrapData, err = cloudinit.NewNode(nodeInput)
+	}
 	if err != nil {
 		scope.Error(err, "Failed to create a worker join configuration")
 		return ctrl.Result{}, err
 	}
 
-	if err := r.storeBootstrapData(ctx, scope, cloudJoinData); err != nil {
+	if err := r.storeBootstrapData(ctx, scope, bootstrapData); err != nil {
 		scope.Error(err, "Failed to store bootstrap data")
 		return ctrl.Result{}, err
 	}
@@ -568,7 +592,7 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		return ctrl.Result{}, err
 	}
 
-	cloudJoinData, err := cloudinit.NewJoinControlPlane(&cloudinit.ControlPlaneJoinInput{
+	controlPlaneJoinInput := &cloudinit.ControlPlaneJoinInput{
 		JoinConfiguration: joinData,
 		Certificates:      certificates,
 		BaseUserData: cloudinit.BaseUserData{
@@ -582,13 +606,20 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 			KubeadmVerbosity:     verbosityFlag,
 			UseExperimentalRetry: scope.Config.Spec.UseExperimentalRetryJoin,
 		},
-	})
+	}
+
+	var bootstrapData []byte
+	if scope.Config.Spec.Format == bootstrapv1.Ignition {
+		bootstrapData, err = ignition.NewJoinControlPlane(controlPlaneJoinInput)
+	} else {
+		bootstrapData, err = cloudinit.NewJoinControlPlane(controlPlaneJoinInput)
+	}
 	if err != nil {
 		scope.Error(err, "Failed to create a control plane join configuration")
 		return ctrl.Result{}, err
 	}
 
-	if err := r.storeBootstrapData(ctx, scope, cloudJoinData); err != nil {
+	if err := r.storeBootstrapData(ctx, scope, bootstrapData); err != nil {
 		scope.Error(err, "Failed to store bootstrap data")
 		return ctrl.Result{}, err
 	}
@@ -849,7 +880,8 @@ func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope
 			},
 		},
 		Data: map[string][]byte{
-			"value": data,
+			"value":  data,
+			"format": []byte(bootstrapDataFormat(scope.Config)),
 		},
 		Type: clusterv1.ClusterSecretType,
 	}
@@ -870,3 +902,28 @@ func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope
 	conditions.MarkTrue(scope.Config, bootstrapv1.DataSecretAvailableCondition)
 	return nil
 }
+
+// bootstrapDataFormat returns the format of the bootstrap data generated for the KubeadmConfig,
+// so it can be stored in the bootstrap data secret for the infrastructure providers to consume.
+func bootstrapDataFormat(config *bootstrapv1.KubeadmConfig) bootstrapv1.Format {
+	if config.Spec.Format == "" {
+		return bootstrapv1.CloudConfig
+	}
+	return config.Spec.Format
+}
+
+// isForceInit returns true if the KubeadmConfig must run kubeadm init even if the control plane is already initialized,
+// e.g. when a control plane is rebuilt from an etcd snapshot.
+func isForceInit(config *bootstrapv1.KubeadmConfig) bool {
+	_, ok := config.GetAnnotations()[bootstrapv1.ForceInitAnnotation]
+	return ok
+}
+
+// etcdDataDirPreflightCheck returns the name of the kubeadm preflight check verifying the etcd data directory is empty.
+func etcdDataDirPreflightCheck(clusterConfiguration *kubeadmv1beta1.ClusterConfiguration) string {
+	dataDir := "/var/lib/etcd"
+	if clusterConfiguration != nil && clusterConfiguration.Etcd.Local != nil && clusterConfiguration.Etcd.Local.DataDir != "" {
+		dataDir = clusterConfiguration.Etcd.Local.DataDir
+	}
+	return "DirAvailable-" + strings.ReplaceAll(dataDir, "/", "-")
+}
@@ -417,6 +417,55 @@ func TestKubeadmConfigReconciler_Reconcile_GenerateCloudConfigData(t *testing.T)
 	g.Expect(err).NotTo(HaveOccurred())
 }
 
+// A control plane config with the force init annotation runs kubeadm init even if the control plane is already initialized.
+func TestKubeadmConfigReconciler_Reconcile_GenerateCloudConfigDataForForcedInit(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster("cluster")
+	cluster.Status.InfrastructureReady = true
+	cluster.Status.ControlPlaneInitialized = true
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+
+	controlPlaneInitMachine := newControlPlaneMachine(cluster, "control-plane-init-machine")
+	controlPlaneInitConfig := newControlPlaneInitKubeadmConfig(controlPlaneInitMachine, "control-plane-init-cfg")
+	controlPlaneInitConfig.Annotations = map[string]string{bootstrapv1.ForceInitAnnotation: ""}
+
+	objects := []client.Object{
+		cluster,
+		controlPlaneInitMachine,
+		controlPlaneInitConfig,
+	}
+	objects = append(objects, createSecrets(t, cluster, controlPlaneInitConfig)...)
+
+	myclient := helpers.NewFakeClientWithScheme(setupScheme(), objects...)
+
+	k := &KubeadmConfigReconciler{
+		Client: myclient,
+		// The init lock is held by someone else, and it must not be required for a forced init.
+		KubeadmInitLock: &myInitLocker{locked: true},
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: "default",
+			Name:      "control-plane-init-cfg",
+		},
+	}
+	result, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Requeue).To(BeFalse())
+	g.Expect(result.RequeueAfter).To(Equal(time.Duration(0)))
+
+	cfg, err := getKubeadmConfig(myclient, "control-plane-init-cfg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.Ready).To(BeTrue())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: "default", Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+	g.Expect(string(s.Data["value"])).To(ContainSubstring("kubeadm init --config /run/kubeadm/kubeadm.yaml --ignore-preflight-errors=DirAvailable--var-lib-etcd"))
+}
+
 // If a control plane has no JoinConfiguration, then we will create a default and no error will occur
 func TestKubeadmConfigReconciler_Reconcile_ErrorIfJoiningControlPlaneHasInvalidConfiguration(t *testing.T) {
 	g := NewWithT(t)
@@ -1893,6 +1942,33 @@ func createSecrets(t *testing.T, cluster *clusterv1.Cluster, config *bootstrapv1
 	return out
 }
 
+func TestEtcdDataDirPreflightCheck(t *testing.T) {
+	tests := []struct {
+		name                 string
+		clusterConfiguration *kubeadmv1beta1.ClusterConfiguration
+		want                 string
+	}{
+		{
+			name:                 "should use the default etcd data directory",
+			clusterConfiguration: nil,
+			want:                 "DirAvailable--var-lib-etcd",
+		},
+		{
+			name: "should use the etcd data directory from the cluster configuration",
+			clusterConfiguration: &kubeadmv1beta1.ClusterConfiguration{
+				Etcd: kubeadmv1beta1.Etcd{Local: &kubeadmv1beta1.LocalEtcd{DataDir: "/mnt/etcd"}},
+			},
+			want: "DirAvailable--mnt-etcd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(etcdDataDirPreflightCheck(tt.clusterConfiguration)).To(Equal(tt.want))
+		})
+	}
+}
+
 type myInitLocker struct {
 	locked bool
 }
@@ -0,0 +1,353 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ignition generates the bootstrap data for the kubeadm bootstrap provider
+// in the Ignition format, used by operating systems like Flatcar Container Linux and
+// Fedora CoreOS.
+package ignition
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+)
+
+const (
+	// NOTE: Ignition writes files before the root filesystem is mounted, so files written to /run
+	// are hidden at boot; the kubeadm configuration files are written to /etc/kubeadm instead.
+	kubeadmInitConfigPath = "/etc/kubeadm/kubeadm.yaml"
+	kubeadmJoinConfigPath = "/etc/kubeadm/kubeadm-join-config.yaml"
+	kubeadmScriptPath     = "/etc/kubeadm/kubeadm.sh"
+	kubeadmSentinelPath   = "/etc/kubeadm/kubeadm.done"
+	kubeadmServiceName    = "kubeadm.service"
+
+	timesyncdConfigPath  = "/etc/systemd/timesyncd.conf"
+	timesyncdServiceName = "systemd-timesyncd.service"
+
+	sudoersPath = "/etc/sudoers.d"
+
+	kubeadmService = `[Unit]
+Description=kubeadm
+# Run only once, the sentinel file is created by the kubeadm script after a successful run.
+ConditionPathExists=!` + kubeadmSentinelPath + `
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=` + kubeadmScriptPath + `
+
+[Install]
+WantedBy=multi-user.target
+`
+)
+
+// NewInitControlPlane returns the Ignition config to be used on the instance initializing the control plane.
+func NewInitControlPlane(input *cloudinit.ControlPlaneInput) ([]byte, error) {
+	files := append(input.Certificates.AsFiles(), input.AdditionalFiles...)
+	files = append(files, bootstrapv1.File{
+		Path:        kubeadmInitConfigPath,
+		Owner:       "root:root",
+		Permissions: "0640",
+		Content:     fmt.Sprintf("---\n%s\n---\n%s", input.ClusterConfiguration, input.InitConfiguration),
+	})
+	command := fmt.Sprintf("kubeadm init --config %s %s", kubeadmInitConfigPath, input.KubeadmVerbosity)
+
+	userData, err := generate(&input.BaseUserData, files, command)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate Ignition config for machine initializing the control plane")
+	}
+	return userData, nil
+}
+
+// NewJoinControlPlane returns the Ignition config to be used on a new control plane instance.
+func NewJoinControlPlane(input *cloudinit.ControlPlaneJoinInput) ([]byte, error) {
+	files := append(input.Certificates.AsFiles(), input.AdditionalFiles...)
+
+	userData, err := generateJoin(&input.BaseUserData, files, input.JoinConfiguration)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate Ignition config for machine joining control plane")
+	}
+	return userData, nil
+}
+
+// NewNode returns the Ignition config to be used on a node instance.
+func NewNode(input *cloudinit.NodeInput) ([]byte, error) {
+	userData, err := generateJoin(&input.BaseUserData, input.AdditionalFiles, input.JoinConfiguration)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate Ignition config for machine joining the cluster")
+	}
+	return userData, nil
+}
+
+func generateJoin(input *cloudinit.BaseUserData, files []bootstrapv1.File, joinConfiguration string) ([]byte, error) {
+	if input.UseExperimentalRetry {
+		return nil, errors.New("experimental retry join is not supported with the Ignition format")
+	}
+	files = append(files, bootstrapv1.File{
+		Path:        kubeadmJoinConfigPath,
+		Owner:       "root:root",
+		Permissions: "0640",
+		Content:     fmt.Sprintf("---\n%s", joinConfiguration),
+	})
+	command := fmt.Sprintf("kubeadm join --config %s %s", kubeadmJoinConfigPath, input.KubeadmVerbosity)
+	return generate(input, files, command)
+}
+
+// generate converts the bootstrap data into an Ignition config; kubeadm and the pre/post kubeadm commands
+// are run by a systemd unit executing a script on the first boot.
+func generate(input *cloudinit.BaseUserData, files []bootstrapv1.File, kubeadmCommand string) ([]byte, error) {
+	passwd := &Passwd{}
+	storage := &Storage{}
+	systemd := &Systemd{}
+
+	for _, user := range input.Users {
+		passwd.Users = append(passwd.Users, convertUser(user))
+		if user.Sudo != nil {
+			files = append(files, bootstrapv1.File{
+				Path:        fmt.Sprintf("%s/%s", sudoersPath, user.Name),
+				Owner:       "root:root",
+				Permissions: "0440",
+				Content:     fmt.Sprintf("%s %s\n", user.Name, *user.Sudo),
+			})
+		}
+	}
+
+	if input.NTP != nil && input.NTP.Enabled != nil && *input.NTP.Enabled {
+		files = append(files, bootstrapv1.File{
+			Path:        timesyncdConfigPath,
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content:     fmt.Sprintf("[Time]\nNTP=%s\n", strings.Join(input.NTP.Servers, " ")),
+		})
+		systemd.Units = append(systemd.Units, Unit{Name: timesyncdServiceName, Enabled: pointer.BoolPtr(true)})
+	}
+
+	if input.DiskSetup != nil {
+		for _, partition := range input.DiskSetup.Partitions {
+			storage.Disks = append(storage.Disks, convertPartition(partition))
+		}
+		for _, filesystem := range input.DiskSetup.Filesystems {
+			storage.Filesystems = append(storage.Filesystems, convertFilesystem(filesystem))
+		}
+	}
+
+	for _, mount := range input.Mounts {
+		unit, err := convertMount(mount)
+		if err != nil {
+			return nil, err
+		}
+		systemd.Units = append(systemd.Units, unit)
+	}
+
+	files = append(files, bootstrapv1.File{
+		Path:        kubeadmScriptPath,
+		Owner:       "root:root",
+		Permissions: "0700",
+		Content:     kubeadmScript(input.PreKubeadmCommands, strings.TrimSpace(kubeadmCommand), input.PostKubeadmCommands),
+	})
+	systemd.Units = append(systemd.Units, Unit{Name: kubeadmServiceName, Enabled: pointer.BoolPtr(true), Contents: pointer.StringPtr(kubeadmService)})
+
+	for _, file := range files {
+		f, err := convertFile(file)
+		if err != nil {
+			return nil, err
+		}
+		storage.Files = append(storage.Files, f)
+	}
+
+	config := Config{
+		Ignition: Ignition{Version: ignitionVersion},
+		Storage:  storage,
+		Systemd:  systemd,
+	}
+	if len(passwd.Users) > 0 {
+		config.Passwd = passwd
+	}
+
+	return json.Marshal(config)
+}
+
+func kubeadmScript(preKubeadmCommands []string, kubeadmCommand string, postKubeadmCommands []string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\nset -e\n")
+	for _, command := range preKubeadmCommands {
+		b.WriteString(command + "\n")
+	}
+	b.WriteString(kubeadmCommand + "\n")
+	for _, command := range postKubeadmCommands {
+		b.WriteString(command + "\n")
+	}
+	b.WriteString(fmt.Sprintf("touch %s\n", kubeadmSentinelPath))
+	return b.String()
+}
+
+func convertUser(user bootstrapv1.User) PasswdUser {
+	u := PasswdUser{
+		Name:              user.Name,
+		Gecos:             user.Gecos,
+		HomeDir:           user.HomeDir,
+		PasswordHash:      user.Passwd,
+		PrimaryGroup:      user.PrimaryGroup,
+		Shell:             user.Shell,
+		SSHAuthorizedKeys: user.SSHAuthorizedKeys,
+	}
+	if user.Groups != nil {
+		for _, group := range strings.Split(*user.Groups, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				u.Groups = append(u.Groups, group)
+			}
+		}
+	}
+	return u
+}
+
+func convertFile(file bootstrapv1.File) (File, error) {
+	f := File{
+		Path:      file.Path,
+		Overwrite: pointer.BoolPtr(true),
+	}
+
+	switch file.Encoding {
+	case bootstrapv1.Base64:
+		f.Contents.Source = dataURL(strings.Join(strings.Fields(file.Content), ""))
+	case bootstrapv1.GzipBase64:
+		f.Contents.Source = dataURL(strings.Join(strings.Fields(file.Content), ""))
+		f.Contents.Compression = pointer.StringPtr("gzip")
+	case bootstrapv1.Gzip:
+		f.Contents.Source = dataURL(base64.StdEncoding.EncodeToString([]byte(file.Content)))
+		f.Contents.Compression = pointer.StringPtr("gzip")
+	default:
+		f.Contents.Source = dataURL(base64.StdEncoding.EncodeToString([]byte(file.Content)))
+	}
+
+	if file.Permissions != "" {
+		mode, err := strconv.ParseInt(file.Permissions, 8, 32)
+		if err != nil {
+			return File{}, errors.Wrapf(err, "failed to parse permissions %q of file %q", file.Permissions, file.Path)
+		}
+		f.Mode = pointer.Int32Ptr(int32(mode))
+	}
+
+	if file.Owner != "" {
+		owner := strings.SplitN(file.Owner, ":", 2)
+		f.User = &NodeUser{Name: owner[0]}
+		if len(owner) == 2 {
+			f.Group = &NodeGroup{Name: owner[1]}
+		}
+	}
+	return f, nil
+}
+
+func dataURL(base64Content string) string {
+	return "data:;base64," + base64Content
+}
+
+func convertPartition(partition bootstrapv1.Partition) Disk {
+	disk := Disk{
+		Device:    partition.Device,
+		WipeTable: partition.Overwrite,
+	}
+	if partition.Layout {
+		// A single partition using the whole disk.
+		disk.Partitions = []Partition{{}}
+	}
+	return disk
+}
+
+func convertFilesystem(filesystem bootstrapv1.Filesystem) Filesystem {
+	fs := Filesystem{
+		Device:         filesystem.Device,
+		Options:        filesystem.ExtraOpts,
+		WipeFilesystem: filesystem.Overwrite,
+	}
+	if filesystem.Filesystem != "" {
+		fs.Format = pointer.StringPtr(filesystem.Filesystem)
+	}
+	if filesystem.Label != "" {
+		fs.Label = pointer.StringPtr(filesystem.Label)
+	}
+	return fs
+}
+
+// convertMount converts a cloud-init mount entry, in the fstab format, into a systemd mount unit.
+func convertMount(mount bootstrapv1.MountPoints) (Unit, error) {
+	if len(mount) < 2 {
+		return Unit{}, errors.Errorf("invalid mount %v: at least the device and the mount point must be specified", mount)
+	}
+
+	var b strings.Builder
+	b.WriteString("[Unit]\nBefore=local-fs.target\n\n[Mount]\n")
+	b.WriteString(fmt.Sprintf("What=%s\n", mountDevicePath(mount[0])))
+	b.WriteString(fmt.Sprintf("Where=%s\n", mount[1]))
+	if len(mount) > 2 && mount[2] != "" && mount[2] != "auto" {
+		b.WriteString(fmt.Sprintf("Type=%s\n", mount[2]))
+	}
+	if len(mount) > 3 && mount[3] != "" {
+		b.WriteString(fmt.Sprintf("Options=%s\n", mount[3]))
+	}
+	b.WriteString("\n[Install]\nWantedBy=local-fs.target\n")
+
+	return Unit{
+		Name:     escapePath(mount[1]) + ".mount",
+		Enabled:  pointer.BoolPtr(true),
+		Contents: pointer.StringPtr(b.String()),
+	}, nil
+}
+
+// mountDevicePath converts a device in the fstab format into a device path, following the cloud-init conventions.
+func mountDevicePath(device string) string {
+	switch {
+	case strings.HasPrefix(device, "LABEL="):
+		return "/dev/disk/by-label/" + strings.TrimPrefix(device, "LABEL=")
+	case strings.HasPrefix(device, "UUID="):
+		return "/dev/disk/by-uuid/" + strings.TrimPrefix(device, "UUID=")
+	case strings.HasPrefix(device, "/"):
+		return device
+	default:
+		return "/dev/" + device
+	}
+}
+
+// escapePath escapes a path as done by systemd-escape --path, so it can be used as the name of a mount unit.
+func escapePath(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "-"
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '/':
+			b.WriteByte('-')
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '.' && i > 0:
+			b.WriteByte(c)
+		default:
+			b.WriteString(fmt.Sprintf(`\x%02x`, c))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignition
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/pointer"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/bootstrap/kubeadm/internal/cloudinit"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+func TestNewNode(t *testing.T) {
+	g := NewWithT(t)
+
+	out, err := NewNode(&cloudinit.NodeInput{
+		BaseUserData: cloudinit.BaseUserData{
+			PreKubeadmCommands:  []string{"echo pre"},
+			PostKubeadmCommands: []string{"echo post"},
+			AdditionalFiles: []bootstrapv1.File{
+				{
+					Path:        "/etc/foo",
+					Owner:       "root:root",
+					Permissions: "0644",
+					Content:     "foo",
+				},
+			},
+			Users: []bootstrapv1.User{
+				{
+					Name:              "core",
+					Groups:            pointer.StringPtr("docker, wheel"),
+					Sudo:              pointer.StringPtr("ALL=(ALL) NOPASSWD:ALL"),
+					SSHAuthorizedKeys: []string{"ssh-rsa AAAA"},
+				},
+			},
+			NTP: &bootstrapv1.NTP{
+				Enabled: pointer.BoolPtr(true),
+				Servers: []string{"0.pool.ntp.org", "1.pool.ntp.org"},
+			},
+			DiskSetup: &bootstrapv1.DiskSetup{
+				Partitions: []bootstrapv1.Partition{
+					{
+						Device:    "/dev/sdb",
+						Layout:    true,
+						Overwrite: pointer.BoolPtr(false),
+					},
+				},
+				Filesystems: []bootstrapv1.Filesystem{
+					{
+						Device:     "/dev/sdb1",
+						Filesystem: "ext4",
+						Label:      "etcd_disk",
+						ExtraOpts:  []string{"-F"},
+					},
+				},
+			},
+			Mounts: []bootstrapv1.MountPoints{
+				{"LABEL=etcd_disk", "/var/lib/etcd-disk"},
+			},
+			KubeadmVerbosity: "--v 4",
+		},
+		JoinConfiguration: "join-config",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	golden, err := ioutil.ReadFile("testdata/node.json")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out).To(MatchJSON(golden))
+
+	config := &Config{}
+	g.Expect(json.Unmarshal(out, config)).To(Succeed())
+	g.Expect(fileContent(g, config, kubeadmScriptPath)).To(Equal(`#!/bin/bash
+set -e
+echo pre
+kubeadm join --config /etc/kubeadm/kubeadm-join-config.yaml --v 4
+echo post
+touch /etc/kubeadm/kubeadm.done
+`))
+	g.Expect(fileContent(g, config, "/etc/sudoers.d/core")).To(Equal("core ALL=(ALL) NOPASSWD:ALL\n"))
+	g.Expect(fileContent(g, config, timesyncdConfigPath)).To(Equal("[Time]\nNTP=0.pool.ntp.org 1.pool.ntp.org\n"))
+}
+
+func TestNewInitControlPlane(t *testing.T) {
+	g := NewWithT(t)
+
+	cpinput := &cloudinit.ControlPlaneInput{
+		BaseUserData: cloudinit.BaseUserData{
+			PreKubeadmCommands: []string{`echo "$(date) ': hello world!'"`},
+		},
+		Certificates:         secret.NewCertificatesForInitialControlPlane(nil),
+		ClusterConfiguration: "my-cluster-config",
+		InitConfiguration:    "my-init-config",
+	}
+	for _, certificate := range cpinput.Certificates {
+		certificate.KeyPair = &certs.KeyPair{
+			Cert: []byte("some certificate"),
+			Key:  []byte("some key"),
+		}
+	}
+
+	out, err := NewInitControlPlane(cpinput)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	config := &Config{}
+	g.Expect(json.Unmarshal(out, config)).To(Succeed())
+	g.Expect(config.Ignition.Version).To(Equal(ignitionVersion))
+	g.Expect(config.Passwd).To(BeNil())
+	g.Expect(fileContent(g, config, kubeadmInitConfigPath)).To(Equal("---\nmy-cluster-config\n---\nmy-init-config"))
+	g.Expect(fileContent(g, config, "/etc/kubernetes/pki/ca.crt")).To(Equal("some certificate"))
+	g.Expect(fileContent(g, config, kubeadmScriptPath)).To(ContainSubstring(`echo "$(date) ': hello world!'"
+kubeadm init --config /etc/kubeadm/kubeadm.yaml
+`))
+}
+
+func TestNewJoinControlPlaneExperimentalRetry(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewJoinControlPlane(&cloudinit.ControlPlaneJoinInput{
+		BaseUserData: cloudinit.BaseUserData{
+			UseExperimentalRetry: true,
+		},
+		JoinConfiguration: "join-config",
+	})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestConvertFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        bootstrapv1.File
+		source      string
+		compression *string
+		mode        *int32
+		expectErr   bool
+	}{
+		{
+			name:   "plain content",
+			file:   bootstrapv1.File{Path: "/tmp/my-path", Content: "hi"},
+			source: "data:;base64,aGk=",
+		},
+		{
+			name:   "base64 content",
+			file:   bootstrapv1.File{Path: "/tmp/my-path", Encoding: bootstrapv1.Base64, Content: "aGk=\n"},
+			source: "data:;base64,aGk=",
+		},
+		{
+			name:        "gzip+base64 content",
+			file:        bootstrapv1.File{Path: "/tmp/my-path", Encoding: bootstrapv1.GzipBase64, Content: "H4sI"},
+			source:      "data:;base64,H4sI",
+			compression: pointer.StringPtr("gzip"),
+		},
+		{
+			name:   "permissions",
+			file:   bootstrapv1.File{Path: "/tmp/my-path", Permissions: "0755", Content: "hi"},
+			source: "data:;base64,aGk=",
+			mode:   pointer.Int32Ptr(0755),
+		},
+		{
+			name:      "invalid permissions",
+			file:      bootstrapv1.File{Path: "/tmp/my-path", Permissions: "rwx", Content: "hi"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			f, err := convertFile(tt.file)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(f.Path).To(Equal(tt.file.Path))
+			g.Expect(f.Contents.Source).To(Equal(tt.source))
+			g.Expect(f.Contents.Compression).To(Equal(tt.compression))
+			g.Expect(f.Mode).To(Equal(tt.mode))
+		})
+	}
+}
+
+func TestConvertMount(t *testing.T) {
+	g := NewWithT(t)
+
+	unit, err := convertMount(bootstrapv1.MountPoints{"sdc", "/var/lib/my-data", "xfs", "noatime"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(unit.Name).To(Equal(`var-lib-my\x2ddata.mount`))
+	g.Expect(*unit.Contents).To(ContainSubstring("What=/dev/sdc\nWhere=/var/lib/my-data\nType=xfs\nOptions=noatime\n"))
+
+	_, err = convertMount(bootstrapv1.MountPoints{"sdc"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func fileContent(g *WithT, config *Config, path string) string {
+	for _, f := range config.Storage.Files {
+		if f.Path != path {
+			continue
+		}
+		g.Expect(f.Contents.Source).To(HavePrefix("data:;base64,"))
+		content, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(f.Contents.Source, "data:;base64,"))
+		g.Expect(err).NotTo(HaveOccurred())
+		return string(content)
+	}
+	g.Expect(path).To(BeEmpty(), "file not found in the Ignition config")
+	return ""
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignition
+
+// The types in this file are the subset of the Ignition v3 configuration specification
+// used by the kubeadm bootstrap provider.
+// See https://coreos.github.io/ignition/configuration-v3_1/ for the full specification.
+
+const (
+	// ignitionVersion is the version of the Ignition configuration specification.
+	ignitionVersion = "3.1.0"
+)
+
+// Config is the root of an Ignition configuration.
+type Config struct {
+	Ignition Ignition `json:"ignition"`
+	Passwd   *Passwd  `json:"passwd,omitempty"`
+	Storage  *Storage `json:"storage,omitempty"`
+	Systemd  *Systemd `json:"systemd,omitempty"`
+}
+
+// Ignition contains the metadata of the configuration.
+type Ignition struct {
+	Version string `json:"version"`
+}
+
+// Passwd describes the users to be added to the system.
+type Passwd struct {
+	Users []PasswdUser `json:"users,omitempty"`
+}
+
+// PasswdUser describes a user to be added to the system.
+type PasswdUser struct {
+	Name              string   `json:"name"`
+	Gecos             *string  `json:"gecos,omitempty"`
+	Groups            []string `json:"groups,omitempty"`
+	HomeDir           *string  `json:"homeDir,omitempty"`
+	PasswordHash      *string  `json:"passwordHash,omitempty"`
+	PrimaryGroup      *string  `json:"primaryGroup,omitempty"`
+	Shell             *string  `json:"shell,omitempty"`
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+}
+
+// Storage describes the desired state of the system's storage devices.
+type Storage struct {
+	Disks       []Disk       `json:"disks,omitempty"`
+	Filesystems []Filesystem `json:"filesystems,omitempty"`
+	Files       []File       `json:"files,omitempty"`
+}
+
+// Disk describes the partition table of a disk.
+type Disk struct {
+	Device     string      `json:"device"`
+	Partitions []Partition `json:"partitions,omitempty"`
+	WipeTable  *bool       `json:"wipeTable,omitempty"`
+}
+
+// Partition describes a partition of a disk; zero values for number and size
+// use the next available partition number and all the remaining space.
+type Partition struct {
+	Number  int  `json:"number"`
+	SizeMiB *int `json:"sizeMiB,omitempty"`
+}
+
+// Filesystem describes a filesystem to be created on a device.
+type Filesystem struct {
+	Device         string   `json:"device"`
+	Format         *string  `json:"format,omitempty"`
+	Label          *string  `json:"label,omitempty"`
+	Options        []string `json:"options,omitempty"`
+	WipeFilesystem *bool    `json:"wipeFilesystem,omitempty"`
+}
+
+// File describes a file to be written to the filesystem.
+type File struct {
+	Path      string       `json:"path"`
+	Overwrite *bool        `json:"overwrite,omitempty"`
+	Contents  FileContents `json:"contents"`
+	Mode      *int32       `json:"mode,omitempty"`
+	User      *NodeUser    `json:"user,omitempty"`
+	Group     *NodeGroup   `json:"group,omitempty"`
+}
+
+// FileContents describes the contents of a file.
+type FileContents struct {
+	Compression *string `json:"compression,omitempty"`
+	Source      string  `json:"source"`
+}
+
+// NodeUser specifies the owner of a file.
+type NodeUser struct {
+	Name string `json:"name"`
+}
+
+// NodeGroup specifies the group of a file.
+type NodeGroup struct {
+	Name string `json:"name"`
+}
+
+// Systemd describes the desired state of the systemd units.
+type Systemd struct {
+	Units []Unit `json:"units,omitempty"`
+}
+
+// Unit describes a systemd unit.
+type Unit struct {
+	Name     string  `json:"name"`
+	Enabled  *bool   `json:"enabled,omitempty"`
+	Contents *string `json:"contents,omitempty"`
+}
@@ -24,8 +24,17 @@ import (
 // Rollout defines the behavior of a rollout implementation.
 type Rollout interface {
 	ObjectRestarter(cluster.Proxy, util.ResourceTuple, string) error
+	ObjectPauser(cluster.Proxy, util.ResourceTuple, string) error
+	ObjectResumer(cluster.Proxy, util.ResourceTuple, string) error
+	ObjectRollbacker(cluster.Proxy, util.ResourceTuple, string, int64) error
+	ObjectStatusViewer(cluster.Proxy, util.ResourceTuple, string) (*RolloutStatus, error)
+	ObjectHistoryViewer(cluster.Proxy, util.ResourceTuple, string) ([]RolloutRevision, error)
 }
 
+// validResourceTypes are the resource types supported by the rollout sub-commands; KubeadmControlPlanes do not keep
+// a revision history, so they are not supported by restart, undo and history.
+var validResourceTypes = []string{"machinedeployment", "kubeadmcontrolplane"}
+
 var _ Rollout = &rollout{}
 
 type rollout struct{}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RolloutRevision describes a revision of a MachineDeployment, as recorded by the MachineSet serving it.
+type RolloutRevision struct {
+	// Revision is the revision number.
+	Revision int64
+
+	// MachineSet is the name of the MachineSet serving the revision.
+	MachineSet string
+
+	// CreationTimestamp is the creation timestamp of the MachineSet serving the revision.
+	CreationTimestamp metav1.Time
+
+	// Template is the machine template of the revision.
+	Template clusterv1.MachineTemplateSpec
+}
+
+// ObjectHistoryViewer will return the revision history of the specified cluster-api resource.
+func (r *rollout) ObjectHistoryViewer(proxy cluster.Proxy, tuple util.ResourceTuple, namespace string) ([]RolloutRevision, error) {
+	switch tuple.Resource {
+	case "machinedeployment":
+		deployment, err := getMachineDeployment(proxy, tuple.Name, namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch %v/%v", tuple.Resource, tuple.Name)
+		}
+		return getMachineDeploymentRevisions(proxy, deployment)
+	case "kubeadmcontrolplane":
+		return nil, errors.Errorf("History is not supported for %v/%v: a kubeadmcontrolplane does not keep a revision history", tuple.Resource, tuple.Name)
+	default:
+		return nil, errors.Errorf("Invalid resource type %v. Valid values: %v", tuple.Resource, validResourceTypes)
+	}
+}
+
+// getMachineDeploymentRevisions returns the revisions of a MachineDeployment sorted by revision number; revisions
+// are read from the MachineSets retained by the MachineDeployment, according to its RevisionHistoryLimit.
+func getMachineDeploymentRevisions(proxy cluster.Proxy, deployment *clusterv1.MachineDeployment) ([]RolloutRevision, error) {
+	msList, err := getMachineSetsForDeployment(proxy, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []RolloutRevision{}
+	for _, ms := range msList {
+		revision, err := mdutil.Revision(ms)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the revision of MachineSet %s/%s", ms.Namespace, ms.Name)
+		}
+
+		// A MachineSet serves more revisions when a MachineDeployment is rolled back to one of its previous templates;
+		// the older revisions are tracked in the revision history annotation.
+		numbers := []int64{revision}
+		if history, ok := ms.Annotations[clusterv1.RevisionHistoryAnnotation]; ok && history != "" {
+			for _, h := range strings.Split(history, ",") {
+				n, err := strconv.ParseInt(h, 10, 64)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to read the revision history of MachineSet %s/%s", ms.Namespace, ms.Name)
+				}
+				numbers = append(numbers, n)
+			}
+		}
+
+		template := mdTemplateFromMachineSet(ms)
+		for _, n := range numbers {
+			revisions = append(revisions, RolloutRevision{
+				Revision:          n,
+				MachineSet:        ms.Name,
+				CreationTimestamp: ms.CreationTimestamp,
+				Template:          template,
+			})
+		}
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// getMachineSetsForDeployment returns the MachineSets controlled by the MachineDeployment.
+func getMachineSetsForDeployment(proxy cluster.Proxy, deployment *clusterv1.MachineDeployment) ([]*clusterv1.MachineSet, error) {
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsMap(&deployment.Spec.Selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the selector of MachineDeployment %s/%s", deployment.Namespace, deployment.Name)
+	}
+
+	msList := &clusterv1.MachineSetList{}
+	if err := c.List(context.TODO(), msList, client.InNamespace(deployment.Namespace), client.MatchingLabels(selector)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineSets for MachineDeployment %s/%s", deployment.Namespace, deployment.Name)
+	}
+
+	machineSets := make([]*clusterv1.MachineSet, 0, len(msList.Items))
+	for i := range msList.Items {
+		ms := &msList.Items[i]
+		if metav1.IsControlledBy(ms, deployment) {
+			machineSets = append(machineSets, ms)
+		}
+	}
+	return machineSets, nil
+}
+
+// mdTemplateFromMachineSet returns the MachineDeployment template a MachineSet was created from,
+// dropping the label added by the MachineDeployment controller to identify the MachineSet machines.
+func mdTemplateFromMachineSet(ms *clusterv1.MachineSet) clusterv1.MachineTemplateSpec {
+	template := ms.Spec.Template.DeepCopy()
+	delete(template.Labels, mdutil.DefaultMachineDeploymentUniqueLabelKey)
+	return *template
+}
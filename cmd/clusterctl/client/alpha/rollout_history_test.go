@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+)
+
+func Test_ObjectHistoryViewer(t *testing.T) {
+	g := NewWithT(t)
+
+	r := newRolloutClient()
+	proxy := test.NewFakeProxy().WithObjs(rolloutHistoryObjs(false)...)
+
+	revisions, err := r.ObjectHistoryViewer(proxy, util.ResourceTuple{Resource: "machinedeployment", Name: "md-1"}, "default")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(revisions).To(HaveLen(4))
+
+	wantRevisions := []struct {
+		revision   int64
+		machineSet string
+		version    string
+	}{
+		{revision: 1, machineSet: "ms-1", version: "v1.18.1"},
+		{revision: 2, machineSet: "ms-2", version: "v1.19.1"},
+		{revision: 3, machineSet: "ms-2", version: "v1.19.1"},
+		{revision: 4, machineSet: "ms-3", version: "v1.20.1"},
+	}
+	for i, want := range wantRevisions {
+		g.Expect(revisions[i].Revision).To(Equal(want.revision))
+		g.Expect(revisions[i].MachineSet).To(Equal(want.machineSet))
+		g.Expect(*revisions[i].Template.Spec.Version).To(Equal(want.version))
+		g.Expect(revisions[i].Template.Labels).ToNot(HaveKey(mdutil.DefaultMachineDeploymentUniqueLabelKey))
+	}
+
+	_, err = r.ObjectHistoryViewer(proxy, util.ResourceTuple{Resource: "machinedeployment", Name: "does-not-exist"}, "default")
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = r.ObjectHistoryViewer(proxy, util.ResourceTuple{Resource: "kubeadmcontrolplane", Name: "kcp"}, "default")
+	g.Expect(err).To(MatchError(ContainSubstring("does not keep a revision history")))
+
+	_, err = r.ObjectHistoryViewer(proxy, util.ResourceTuple{Resource: "machineset", Name: "ms"}, "default")
+	g.Expect(err).To(MatchError(ContainSubstring("Valid values: [machinedeployment kubeadmcontrolplane]")))
+}
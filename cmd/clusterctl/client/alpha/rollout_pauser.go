@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectPauser will issue a pause on the specified cluster-api resource.
+func (r *rollout) ObjectPauser(proxy cluster.Proxy, tuple util.ResourceTuple, namespace string) error {
+	switch tuple.Resource {
+	case "machinedeployment":
+		deployment, err := getMachineDeployment(proxy, tuple.Name, namespace)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch %v/%v", tuple.Resource, tuple.Name)
+		}
+		if deployment.Spec.Paused {
+			return errors.Errorf("machinedeployment is already paused: %v/%v\n", tuple.Resource, tuple.Name)
+		}
+		if err := pauseMachineDeployment(proxy, tuple.Name, namespace); err != nil {
+			return err
+		}
+	case "kubeadmcontrolplane":
+		kcp, err := getKubeadmControlPlane(proxy, tuple.Name, namespace)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch %v/%v", tuple.Resource, tuple.Name)
+		}
+		if annotations.HasPausedAnnotation(kcp) {
+			return errors.Errorf("kubeadmcontrolplane is already paused: %v/%v\n", tuple.Resource, tuple.Name)
+		}
+		if err := pauseKubeadmControlPlane(proxy, tuple.Name, namespace); err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf("Invalid resource type %v. Valid values: %v", tuple.Resource, validResourceTypes)
+	}
+	return nil
+}
+
+// pauseMachineDeployment sets Paused to true in the MachineDeployment's spec.
+func pauseMachineDeployment(proxy cluster.Proxy, name, namespace string) error {
+	patch := client.RawPatch(types.MergePatchType, []byte("{\"spec\":{\"paused\":true}}"))
+	return patchMachineDeployemt(proxy, name, namespace, patch)
+}
+
+// pauseKubeadmControlPlane sets the paused annotation on the KubeadmControlPlane; the KubeadmControlPlane
+// does not have a paused field, but the controller does not reconcile objects with the paused annotation.
+func pauseKubeadmControlPlane(proxy cluster.Proxy, name, namespace string) error {
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf("{\"metadata\":{\"annotations\":{%q:\"true\"}}}", clusterv1.PausedAnnotation)))
+	return patchKubeadmControlPlane(proxy, name, namespace, patch)
+}
+
+// getKubeadmControlPlane retrieves the KubeadmControlPlane object corresponding to the name and namespace specified.
+func getKubeadmControlPlane(proxy cluster.Proxy, name, namespace string) (*controlplanev1.KubeadmControlPlane, error) {
+	kcpObj := &controlplanev1.KubeadmControlPlane{}
+	c, err := proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	kcpObjKey := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+	if err := c.Get(context.TODO(), kcpObjKey, kcpObj); err != nil {
+		return nil, errors.Wrapf(err, "error reading %q %s/%s",
+			kcpObj.GroupVersionKind(), kcpObjKey.Namespace, kcpObjKey.Name)
+	}
+	return kcpObj, nil
+}
+
+// patchKubeadmControlPlane applies a patch to a kubeadmcontrolplane.
+func patchKubeadmControlPlane(proxy cluster.Proxy, name, namespace string, patch client.Patch) error {
+	cFrom, err := proxy.NewClient()
+	if err != nil {
+		return err
+	}
+	kcpObj := &controlplanev1.KubeadmControlPlane{}
+	kcpObjKey := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+	if err := cFrom.Get(context.TODO(), kcpObjKey, kcpObj); err != nil {
+		return errors.Wrapf(err, "error reading %s/%s", kcpObj.GetNamespace(), kcpObj.GetName())
+	}
+
+	if err := cFrom.Patch(context.TODO(), kcpObj, patch); err != nil {
+		return errors.Wrapf(err, "error while patching %s/%s", kcpObj.GetNamespace(), kcpObj.GetName())
+	}
+	return nil
+}
@@ -29,8 +29,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var validResourceTypes = []string{"machinedeployment"}
-
 // ObjectRestarter will issue a restart on the specified cluster-api resource.
 func (r *rollout) ObjectRestarter(proxy cluster.Proxy, tuple util.ResourceTuple, namespace string) error {
 	switch tuple.Resource {
@@ -45,6 +43,8 @@ func (r *rollout) ObjectRestarter(proxy cluster.Proxy, tuple util.ResourceTuple,
 		if err := setRestartedAtAnnotation(proxy, tuple.Name, namespace); err != nil {
 			return err
 		}
+	case "kubeadmcontrolplane":
+		return errors.Errorf("Restart is not supported for %v/%v: a kubeadmcontrolplane is rolled out only when its spec changes", tuple.Resource, tuple.Name)
 	default:
 		return errors.Errorf("Invalid resource type %v. Valid values: %v", tuple.Resource, validResourceTypes)
 	}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_ObjectResumer(t *testing.T) {
+	type fields struct {
+		objs      []client.Object
+		tuple     util.ResourceTuple
+		namespace string
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		wantErr    bool
+		wantPaused bool
+	}{
+		{
+			name: "paused machinedeployment should be resumed",
+			fields: fields{
+				objs: []client.Object{
+					&clusterv1.MachineDeployment{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "MachineDeployment",
+							APIVersion: "cluster.x-k8s.io/v1alpha4",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "md-1",
+						},
+						Spec: clusterv1.MachineDeploymentSpec{
+							Paused: true,
+						},
+					},
+				},
+				tuple: util.ResourceTuple{
+					Resource: "machinedeployment",
+					Name:     "md-1",
+				},
+				namespace: "default",
+			},
+			wantErr:    false,
+			wantPaused: false,
+		},
+		{
+			name: "resuming a machinedeployment which is not paused should return error",
+			fields: fields{
+				objs: []client.Object{
+					&clusterv1.MachineDeployment{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "MachineDeployment",
+							APIVersion: "cluster.x-k8s.io/v1alpha4",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "md-1",
+						},
+					},
+				},
+				tuple: util.ResourceTuple{
+					Resource: "machinedeployment",
+					Name:     "md-1",
+				},
+				namespace: "default",
+			},
+			wantErr: true,
+		},
+		{
+			name: "paused kubeadmcontrolplane should be resumed",
+			fields: fields{
+				objs: []client.Object{
+					&controlplanev1.KubeadmControlPlane{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "KubeadmControlPlane",
+							APIVersion: "controlplane.cluster.x-k8s.io/v1alpha4",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:   "default",
+							Name:        "kcp",
+							Annotations: map[string]string{clusterv1.PausedAnnotation: "true"},
+						},
+					},
+				},
+				tuple: util.ResourceTuple{
+					Resource: "kubeadmcontrolplane",
+					Name:     "kcp",
+				},
+				namespace: "default",
+			},
+			wantErr:    false,
+			wantPaused: false,
+		},
+		{
+			name: "resuming a kubeadmcontrolplane which is not paused should return error",
+			fields: fields{
+				objs: []client.Object{
+					&controlplanev1.KubeadmControlPlane{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "KubeadmControlPlane",
+							APIVersion: "controlplane.cluster.x-k8s.io/v1alpha4",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+							Name:      "kcp",
+						},
+					},
+				},
+				tuple: util.ResourceTuple{
+					Resource: "kubeadmcontrolplane",
+					Name:     "kcp",
+				},
+				namespace: "default",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid resource type should return error",
+			fields: fields{
+				tuple: util.ResourceTuple{
+					Resource: "machineset",
+					Name:     "ms-1",
+				},
+				namespace: "default",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.fields.objs...)
+			err := r.ObjectResumer(proxy, tt.fields.tuple, tt.fields.namespace)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			for _, obj := range tt.fields.objs {
+				cl, err := proxy.NewClient()
+				g.Expect(err).ToNot(HaveOccurred())
+				key := client.ObjectKeyFromObject(obj)
+				switch obj.(type) {
+				case *clusterv1.MachineDeployment:
+					md := &clusterv1.MachineDeployment{}
+					g.Expect(cl.Get(context.TODO(), key, md)).To(Succeed())
+					g.Expect(md.Spec.Paused).To(Equal(tt.wantPaused))
+				case *controlplanev1.KubeadmControlPlane:
+					kcp := &controlplanev1.KubeadmControlPlane{}
+					g.Expect(cl.Get(context.TODO(), key, kcp)).To(Succeed())
+					g.Expect(annotations.HasPausedAnnotation(kcp)).To(Equal(tt.wantPaused))
+				}
+			}
+		})
+	}
+}
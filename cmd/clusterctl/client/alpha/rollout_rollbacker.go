@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectRollbacker will issue a rollback on the specified cluster-api resource.
+func (r *rollout) ObjectRollbacker(proxy cluster.Proxy, tuple util.ResourceTuple, namespace string, toRevision int64) error {
+	switch tuple.Resource {
+	case "machinedeployment":
+		deployment, err := getMachineDeployment(proxy, tuple.Name, namespace)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch %v/%v", tuple.Resource, tuple.Name)
+		}
+		if deployment.Spec.Paused {
+			return errors.Errorf("can't rollback paused machinedeployment (run rollout resume first): %v/%v\n", tuple.Resource, tuple.Name)
+		}
+		if err := rollbackMachineDeployment(proxy, deployment, toRevision); err != nil {
+			return err
+		}
+	case "kubeadmcontrolplane":
+		return errors.Errorf("Rollback is not supported for %v/%v: a kubeadmcontrolplane does not keep a revision history", tuple.Resource, tuple.Name)
+	default:
+		return errors.Errorf("Invalid resource type %v. Valid values: %v", tuple.Resource, validResourceTypes)
+	}
+	return nil
+}
+
+// rollbackMachineDeployment copies the machine template of the given revision into the MachineDeployment's spec;
+// if toRevision is 0, the MachineDeployment is rolled back to the revision preceding the current one.
+func rollbackMachineDeployment(proxy cluster.Proxy, deployment *clusterv1.MachineDeployment, toRevision int64) error {
+	log := logf.Log
+
+	if toRevision < 0 {
+		return errors.Errorf("revision number cannot be negative: %v", toRevision)
+	}
+
+	revision, err := getMachineDeploymentRevision(proxy, deployment, toRevision)
+	if err != nil {
+		return err
+	}
+
+	if mdutil.EqualMachineTemplate(&deployment.Spec.Template, &revision.Template) {
+		log.Info("Skipping rollback, the current template already matches the revision", "MachineDeployment", deployment.Name, "Revision", revision.Revision)
+		return nil
+	}
+
+	c, err := proxy.NewClient()
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFrom(deployment.DeepCopy())
+	deployment.Spec.Template = revision.Template
+	if err := c.Patch(context.TODO(), deployment, patch); err != nil {
+		return errors.Wrapf(err, "error while patching %s/%s", deployment.GetNamespace(), deployment.GetName())
+	}
+	log.Info("Rolled back", "MachineDeployment", deployment.Name, "Revision", revision.Revision, "MachineSet", revision.MachineSet)
+	return nil
+}
+
+// getMachineDeploymentRevision returns the given revision of a MachineDeployment, or the revision preceding the
+// current one if toRevision is 0.
+func getMachineDeploymentRevision(proxy cluster.Proxy, deployment *clusterv1.MachineDeployment, toRevision int64) (*RolloutRevision, error) {
+	revisions, err := getMachineDeploymentRevisions(proxy, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	if toRevision == 0 {
+		if len(revisions) < 2 {
+			return nil, errors.Errorf("no rollout history found for machinedeployment %s/%s", deployment.Namespace, deployment.Name)
+		}
+		return &revisions[len(revisions)-2], nil
+	}
+
+	for i := range revisions {
+		if revisions[i].Revision == toRevision {
+			return &revisions[i], nil
+		}
+	}
+	return nil, errors.Errorf("unable to find revision %d for machinedeployment %s/%s", toRevision, deployment.Namespace, deployment.Name)
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_ObjectRollbacker(t *testing.T) {
+	type fields struct {
+		objs       []client.Object
+		tuple      util.ResourceTuple
+		namespace  string
+		toRevision int64
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		wantErr     bool
+		wantErrMsg  string
+		wantVersion string
+	}{
+		{
+			name: "machinedeployment should rollback to the previous revision",
+			fields: fields{
+				objs: rolloutHistoryObjs(false),
+				tuple: util.ResourceTuple{
+					Resource: "machinedeployment",
+					Name:     "md-1",
+				},
+				namespace: "default",
+			},
+			wantErr:     false,
+			wantVersion: "v1.19.1",
+		},
+		{
+			name: "machinedeployment should rollback to the given revision",
+			fields: fields{
+				objs: rolloutHistoryObjs(false),
+				tuple: util.ResourceTuple{
+					Resource: "machinedeployment",
+					Name:     "md-1",
+				},
+				namespace:  "default",
+				toRevision: 1,
+			},
+			wantErr:     false,
+			wantVersion: "v1.18.1",
+		},
+		{
+			name: "machinedeployment should rollback to a revision recorded in the revision history",
+			fields: fields{
+				objs: rolloutHistoryObjs(false),
+				tuple: util.ResourceTuple{
+					Resource: "machinedeployment",
+					Name:     "md-1",
+				},
+				namespace:  "default",
+				toRevision: 2,
+			},
+			wantErr:     false,
+			wantVersion: "v1.19.1",
+		},
+		{
+			name: "rollback to the current revision should not change the machinedeployment",
+			fields: fields{
+				objs: rolloutHistoryObjs(false),
+				tuple: util.ResourceTuple{
+					Resource: "machinedeployment",
+					Name:     "md-1",
+				},
+				namespace:  "default",
+				toRevision: 4,
+			},
+			wantErr:     false,
+			wantVersion: "v1.20.1",
+		},
+		{
+			name: "rollback to a revision not found should return error",
+			fields: fields{
+				objs: rolloutHistoryObjs(false),
+				tuple: util.ResourceTuple{
+					Resource: "machinedeployment",
+					Name:     "md-1",
+				},
+				namespace:  "default",
+				toRevision: 5,
+			},
+			wantErr: true,
+		},
+		{
+			name: "rollback to a negative revision should return error",
+			fields: fields{
+				objs: rolloutHistoryObjs(false),
+				tuple: util.ResourceTuple{
+					Resource: "machinedeployment",
+					Name:     "md-1",
+				},
+				namespace:  "default",
+				toRevision: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "paused machinedeployment should not be rolled back",
+			fields: fields{
+				objs: rolloutHistoryObjs(true),
+				tuple: util.ResourceTuple{
+					Resource: "machinedeployment",
+					Name:     "md-1",
+				},
+				namespace: "default",
+			},
+			wantErr: true,
+		},
+		{
+			name: "kubeadmcontrolplane should not be rolled back",
+			fields: fields{
+				tuple: util.ResourceTuple{
+					Resource: "kubeadmcontrolplane",
+					Name:     "kcp",
+				},
+				namespace: "default",
+			},
+			wantErr:    true,
+			wantErrMsg: "does not keep a revision history",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.fields.objs...)
+			err := r.ObjectRollbacker(proxy, tt.fields.tuple, tt.fields.namespace, tt.fields.toRevision)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErrMsg))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			cl, err := proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			md := &clusterv1.MachineDeployment{}
+			g.Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: tt.fields.namespace, Name: tt.fields.tuple.Name}, md)).To(Succeed())
+			g.Expect(*md.Spec.Template.Spec.Version).To(Equal(tt.wantVersion))
+			g.Expect(md.Spec.Template.Labels).ToNot(HaveKey(mdutil.DefaultMachineDeploymentUniqueLabelKey))
+			g.Expect(md.Spec.Template.Labels).To(HaveKeyWithValue("foo", "bar"))
+		})
+	}
+}
+
+// rolloutHistoryObjs returns a MachineDeployment at revision 4, with three MachineSets serving
+// revision 1, revision 2 and 3 (the MachineDeployment was rolled back from revision 3), and revision 4.
+func rolloutHistoryObjs(paused bool) []client.Object {
+	md := &clusterv1.MachineDeployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MachineDeployment",
+			APIVersion: "cluster.x-k8s.io/v1alpha4",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "md-1",
+			UID:       "md-1-uid",
+			Annotations: map[string]string{
+				clusterv1.RevisionAnnotation: "4",
+			},
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: "test",
+			Paused:      paused,
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"foo": "bar"},
+			},
+			Template: rolloutTemplate("v1.20.1", ""),
+		},
+	}
+	ms := func(name, version, revision, history string) *clusterv1.MachineSet {
+		ms := &clusterv1.MachineSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "MachineSet",
+				APIVersion: "cluster.x-k8s.io/v1alpha4",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				Labels:    map[string]string{"foo": "bar", mdutil.DefaultMachineDeploymentUniqueLabelKey: name},
+				Annotations: map[string]string{
+					clusterv1.RevisionAnnotation: revision,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(md, clusterv1.GroupVersion.WithKind("MachineDeployment")),
+				},
+			},
+			Spec: clusterv1.MachineSetSpec{
+				ClusterName: "test",
+				Template:    rolloutTemplate(version, name),
+			},
+		}
+		if history != "" {
+			ms.Annotations[clusterv1.RevisionHistoryAnnotation] = history
+		}
+		return ms
+	}
+	// A MachineSet selected by the MachineDeployment but not controlled by it.
+	orphan := ms("ms-orphan", "v1.17.1", "6", "")
+	orphan.OwnerReferences = nil
+
+	return []client.Object{
+		md,
+		ms("ms-1", "v1.18.1", "1", ""),
+		ms("ms-2", "v1.19.1", "3", "2"),
+		ms("ms-3", "v1.20.1", "4", ""),
+		orphan,
+	}
+}
+
+func rolloutTemplate(version, hash string) clusterv1.MachineTemplateSpec {
+	template := clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels: map[string]string{"foo": "bar"},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test",
+			Version:     pointer.StringPtr(version),
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+				Kind:       "GenericInfrastructureMachineTemplate",
+				Name:       "infra-" + version,
+			},
+		},
+	}
+	if hash != "" {
+		template.Labels[mdutil.DefaultMachineDeploymentUniqueLabelKey] = hash
+	}
+	return template
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/annotations"
+)
+
+// RolloutStatus describes the progress of the rollout of a cluster-api resource.
+type RolloutStatus struct {
+	// Message is a human readable description of the rollout progress.
+	Message string
+
+	// Done is true when the rollout is completed.
+	Done bool
+}
+
+// ObjectStatusViewer will return the rollout status of the specified cluster-api resource.
+func (r *rollout) ObjectStatusViewer(proxy cluster.Proxy, tuple util.ResourceTuple, namespace string) (*RolloutStatus, error) {
+	switch tuple.Resource {
+	case "machinedeployment":
+		deployment, err := getMachineDeployment(proxy, tuple.Name, namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch %v/%v", tuple.Resource, tuple.Name)
+		}
+		return machineDeploymentRolloutStatus(deployment)
+	case "kubeadmcontrolplane":
+		kcp, err := getKubeadmControlPlane(proxy, tuple.Name, namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch %v/%v", tuple.Resource, tuple.Name)
+		}
+		return kubeadmControlPlaneRolloutStatus(kcp)
+	default:
+		return nil, errors.Errorf("Invalid resource type %v. Valid values: %v", tuple.Resource, validResourceTypes)
+	}
+}
+
+// machineDeploymentRolloutStatus returns the rollout status of a MachineDeployment.
+func machineDeploymentRolloutStatus(deployment *clusterv1.MachineDeployment) (*RolloutStatus, error) {
+	desired := int32(0)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	status := rolloutStatus("machinedeployment", deployment.Name, deployment.Generation, deployment.Status.ObservedGeneration,
+		desired, deployment.Status.Replicas, deployment.Status.UpdatedReplicas, deployment.Status.AvailableReplicas, "available")
+	if !status.Done && deployment.Spec.Paused {
+		return nil, errors.Errorf("machinedeployment %q rollout is paused (run rollout resume first)", deployment.Name)
+	}
+	return status, nil
+}
+
+// kubeadmControlPlaneRolloutStatus returns the rollout status of a KubeadmControlPlane.
+func kubeadmControlPlaneRolloutStatus(kcp *controlplanev1.KubeadmControlPlane) (*RolloutStatus, error) {
+	desired := int32(0)
+	if kcp.Spec.Replicas != nil {
+		desired = *kcp.Spec.Replicas
+	}
+	status := rolloutStatus("kubeadmcontrolplane", kcp.Name, kcp.Generation, kcp.Status.ObservedGeneration,
+		desired, kcp.Status.Replicas, kcp.Status.UpdatedReplicas, kcp.Status.ReadyReplicas, "ready")
+	if !status.Done && annotations.HasPausedAnnotation(kcp) {
+		return nil, errors.Errorf("kubeadmcontrolplane %q rollout is paused (run rollout resume first)", kcp.Name)
+	}
+	return status, nil
+}
+
+// rolloutStatus computes the rollout status of a resource from its replica counters; the rollout is completed
+// when all the replicas are updated and available, and all the old replicas are gone.
+func rolloutStatus(resource, name string, generation, observedGeneration int64, desired, replicas, updated, available int32, availableState string) *RolloutStatus {
+	if generation > observedGeneration {
+		return &RolloutStatus{Message: fmt.Sprintf("Waiting for %s %q spec update to be observed...", resource, name)}
+	}
+	if updated < desired {
+		return &RolloutStatus{Message: fmt.Sprintf("Waiting for %s %q rollout to finish: %d out of %d new replicas have been updated...", resource, name, updated, desired)}
+	}
+	if replicas > updated {
+		return &RolloutStatus{Message: fmt.Sprintf("Waiting for %s %q rollout to finish: %d old replicas are pending termination...", resource, name, replicas-updated)}
+	}
+	if available < updated {
+		return &RolloutStatus{Message: fmt.Sprintf("Waiting for %s %q rollout to finish: %d of %d updated replicas are %s...", resource, name, available, updated, availableState)}
+	}
+	return &RolloutStatus{Message: fmt.Sprintf("%s %q successfully rolled out", resource, name), Done: true}
+}
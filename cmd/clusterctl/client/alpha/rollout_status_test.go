@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alpha
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_ObjectStatusViewer(t *testing.T) {
+	md := func(paused bool, generation int64, status clusterv1.MachineDeploymentStatus) *clusterv1.MachineDeployment {
+		return &clusterv1.MachineDeployment{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "MachineDeployment",
+				APIVersion: "cluster.x-k8s.io/v1alpha4",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "default",
+				Name:       "md-1",
+				Generation: generation,
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				Replicas: pointer.Int32Ptr(3),
+				Paused:   paused,
+			},
+			Status: status,
+		}
+	}
+	kcp := func(annotations map[string]string, status controlplanev1.KubeadmControlPlaneStatus) *controlplanev1.KubeadmControlPlane {
+		return &controlplanev1.KubeadmControlPlane{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "KubeadmControlPlane",
+				APIVersion: "controlplane.cluster.x-k8s.io/v1alpha4",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        "kcp",
+				Annotations: annotations,
+			},
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Replicas: pointer.Int32Ptr(3),
+			},
+			Status: status,
+		}
+	}
+	mdTuple := util.ResourceTuple{Resource: "machinedeployment", Name: "md-1"}
+	kcpTuple := util.ResourceTuple{Resource: "kubeadmcontrolplane", Name: "kcp"}
+
+	tests := []struct {
+		name        string
+		obj         client.Object
+		tuple       util.ResourceTuple
+		wantErr     bool
+		wantDone    bool
+		wantMessage string
+	}{
+		{
+			name:        "machinedeployment spec update not observed yet",
+			obj:         md(false, 2, clusterv1.MachineDeploymentStatus{ObservedGeneration: 1}),
+			tuple:       mdTuple,
+			wantMessage: "Waiting for machinedeployment \"md-1\" spec update to be observed...",
+		},
+		{
+			name:        "machinedeployment replicas being updated",
+			obj:         md(false, 1, clusterv1.MachineDeploymentStatus{ObservedGeneration: 1, Replicas: 4, UpdatedReplicas: 1}),
+			tuple:       mdTuple,
+			wantMessage: "Waiting for machinedeployment \"md-1\" rollout to finish: 1 out of 3 new replicas have been updated...",
+		},
+		{
+			name:        "machinedeployment old replicas pending termination",
+			obj:         md(false, 1, clusterv1.MachineDeploymentStatus{ObservedGeneration: 1, Replicas: 4, UpdatedReplicas: 3}),
+			tuple:       mdTuple,
+			wantMessage: "Waiting for machinedeployment \"md-1\" rollout to finish: 1 old replicas are pending termination...",
+		},
+		{
+			name:        "machinedeployment updated replicas not available",
+			obj:         md(false, 1, clusterv1.MachineDeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2}),
+			tuple:       mdTuple,
+			wantMessage: "Waiting for machinedeployment \"md-1\" rollout to finish: 2 of 3 updated replicas are available...",
+		},
+		{
+			name:        "machinedeployment rolled out",
+			obj:         md(false, 1, clusterv1.MachineDeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}),
+			tuple:       mdTuple,
+			wantDone:    true,
+			wantMessage: "machinedeployment \"md-1\" successfully rolled out",
+		},
+		{
+			name:        "paused machinedeployment rolled out",
+			obj:         md(true, 1, clusterv1.MachineDeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}),
+			tuple:       mdTuple,
+			wantDone:    true,
+			wantMessage: "machinedeployment \"md-1\" successfully rolled out",
+		},
+		{
+			name:    "paused machinedeployment with a rollout in progress should return error",
+			obj:     md(true, 1, clusterv1.MachineDeploymentStatus{ObservedGeneration: 1, Replicas: 4, UpdatedReplicas: 1}),
+			tuple:   mdTuple,
+			wantErr: true,
+		},
+		{
+			name:        "kubeadmcontrolplane updated replicas not ready",
+			obj:         kcp(nil, controlplanev1.KubeadmControlPlaneStatus{Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 2}),
+			tuple:       kcpTuple,
+			wantMessage: "Waiting for kubeadmcontrolplane \"kcp\" rollout to finish: 2 of 3 updated replicas are ready...",
+		},
+		{
+			name:        "kubeadmcontrolplane rolled out",
+			obj:         kcp(nil, controlplanev1.KubeadmControlPlaneStatus{Replicas: 3, UpdatedReplicas: 3, ReadyReplicas: 3}),
+			tuple:       kcpTuple,
+			wantDone:    true,
+			wantMessage: "kubeadmcontrolplane \"kcp\" successfully rolled out",
+		},
+		{
+			name:    "paused kubeadmcontrolplane with a rollout in progress should return error",
+			obj:     kcp(map[string]string{clusterv1.PausedAnnotation: "true"}, controlplanev1.KubeadmControlPlaneStatus{Replicas: 4, UpdatedReplicas: 1}),
+			tuple:   kcpTuple,
+			wantErr: true,
+		},
+		{
+			name:    "invalid resource type should return error",
+			obj:     md(false, 1, clusterv1.MachineDeploymentStatus{}),
+			tuple:   util.ResourceTuple{Resource: "machineset", Name: "md-1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := newRolloutClient()
+			proxy := test.NewFakeProxy().WithObjs(tt.obj)
+			status, err := r.ObjectStatusViewer(proxy, tt.tuple, "default")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(status.Done).To(Equal(tt.wantDone))
+			g.Expect(status.Message).To(Equal(tt.wantMessage))
+		})
+	}
+}
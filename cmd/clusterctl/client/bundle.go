@@ -0,0 +1,522 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	bundleIndexFile       = "bundle.yaml"
+	bundleCertManagerFile = "cert-manager.yaml"
+	bundleImagesFile      = "images.txt"
+	bundleProvidersDir    = "providers"
+
+	bundleMetadataFile         = "metadata.yaml"
+	bundleTemplateFile         = "cluster-template.yaml"
+	bundleFlavorTemplateFile   = "cluster-template-%s.yaml"
+	bundleMaxFileSize          = 100 << 20
+	bundleFilePermissions      = 0600
+	bundleDirectoryPermissions = 0700
+)
+
+// CreateBundleOptions carries the options supported by CreateBundle.
+type CreateBundleOptions struct {
+	// CoreProvider version (e.g. cluster-api:v0.3.0) to add to the bundle. If unspecified, the
+	// cluster-api core provider's latest release is used.
+	CoreProvider string
+
+	// BootstrapProviders and versions (e.g. kubeadm:v0.3.0) to add to the bundle.
+	// If unspecified, the kubeadm bootstrap provider's latest release is used.
+	BootstrapProviders []string
+
+	// InfrastructureProviders and versions (e.g. aws:v0.5.0) to add to the bundle.
+	InfrastructureProviders []string
+
+	// ControlPlaneProviders and versions (e.g. kubeadm:v0.3.0) to add to the bundle.
+	// If unspecified, the kubeadm control plane provider latest release is used.
+	ControlPlaneProviders []string
+
+	// Flavors of the workload cluster templates to add to the bundle for each infrastructure provider; the
+	// default workload cluster template is always added to the bundle, if it exists.
+	Flavors []string
+
+	// OutputFile defines the path of the bundle to be created.
+	OutputFile string
+}
+
+// bundleIndex describes the content of a bundle.
+type bundleIndex struct {
+	// Providers contained in the bundle.
+	Providers []bundleProvider `json:"providers"`
+}
+
+// bundleProvider describes a provider release contained in a bundle.
+type bundleProvider struct {
+	Name           string                    `json:"name"`
+	Type           clusterctlv1.ProviderType `json:"type"`
+	Version        string                    `json:"version"`
+	ComponentsPath string                    `json:"componentsPath"`
+}
+
+// CreateBundle resolves a set of providers and versions into a self-contained tarball, that can be used
+// for installing providers in environments without access to the provider repositories.
+// The bundle contains the components YAML, the metadata YAML and the workload cluster templates of each provider,
+// the cert-manager manifest embedded in clusterctl and the list of the images required for the installation.
+func (c *clusterctlClient) CreateBundle(options CreateBundleOptions) error {
+	log := logf.Log
+
+	if options.OutputFile == "" {
+		return errors.New("invalid arguments: the output file can't be empty")
+	}
+
+	initOptions := &InitOptions{
+		CoreProvider:            options.CoreProvider,
+		BootstrapProviders:      options.BootstrapProviders,
+		ControlPlaneProviders:   options.ControlPlaneProviders,
+		InfrastructureProviders: options.InfrastructureProviders,
+	}
+	setDefaultProviders(initOptions)
+
+	files := map[string][]byte{}
+	index := bundleIndex{}
+	images := []string{}
+
+	providers := []struct {
+		providerType clusterctlv1.ProviderType
+		providers    []string
+	}{
+		{clusterctlv1.CoreProviderType, []string{initOptions.CoreProvider}},
+		{clusterctlv1.BootstrapProviderType, initOptions.BootstrapProviders},
+		{clusterctlv1.ControlPlaneProviderType, initOptions.ControlPlaneProviders},
+		{clusterctlv1.InfrastructureProviderType, initOptions.InfrastructureProviders},
+	}
+	for _, p := range providers {
+		for _, provider := range p.providers {
+			// It is possible to opt-out from bootstrap/control-plane providers using '-' as a provider name (NoopProvider).
+			if provider == NoopProvider {
+				if p.providerType == clusterctlv1.CoreProviderType {
+					return errors.New("the '-' value can not be used for the core provider")
+				}
+				continue
+			}
+
+			log.Info("Adding provider to the bundle", "Provider", provider, "Type", p.providerType)
+			bp, providerImages, err := c.addProviderToBundle(files, p.providerType, provider, options.Flavors)
+			if err != nil {
+				return errors.Wrapf(err, "failed to add the %q provider to the bundle", provider)
+			}
+			for _, existing := range index.Providers {
+				if existing.Name == bp.Name && existing.Type == bp.Type {
+					return errors.Errorf("the %q provider can be added only once to the bundle", provider)
+				}
+			}
+			index.Providers = append(index.Providers, *bp)
+			images = append(images, providerImages...)
+		}
+	}
+
+	// Adds the cert-manager manifest embedded in clusterctl, and the images required for installing it.
+	certManagerManifest, err := cluster.CertManagerManifest()
+	if err != nil {
+		return errors.Wrap(err, "failed to read the cert-manager manifest")
+	}
+	files[bundleCertManagerFile] = certManagerManifest
+
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{})
+	if err != nil {
+		return err
+	}
+	certManager, err := clusterClient.CertManager()
+	if err != nil {
+		return err
+	}
+	certManagerImages, err := certManager.Images()
+	if err != nil {
+		return err
+	}
+	images = append(images, certManagerImages...)
+
+	// Adds the list of images and the bundle index.
+	files[bundleImagesFile] = []byte(strings.Join(uniqueSorted(images), "\n") + "\n")
+
+	indexYaml, err := yaml.Marshal(index)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the bundle index")
+	}
+	files[bundleIndexFile] = indexYaml
+
+	if err := writeBundle(options.OutputFile, files); err != nil {
+		return errors.Wrapf(err, "failed to write the bundle to %q", options.OutputFile)
+	}
+	return nil
+}
+
+// addProviderToBundle adds the files of a provider release to the bundle files, and returns the corresponding bundleProvider
+// together with the list of images required by the provider.
+func (c *clusterctlClient) addProviderToBundle(files map[string][]byte, providerType clusterctlv1.ProviderType, provider string, flavors []string) (*bundleProvider, []string, error) {
+	name, version, err := parseProviderName(provider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	providerConfig, err := c.configClient.Providers().Get(name, providerType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repositoryClient, err := c.repositoryClientFactory(RepositoryClientFactoryInput{Provider: providerConfig})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Reads the provider components, so the version is resolved in case it is not specified, and we get the list of images.
+	components, err := repositoryClient.Components().Get(repository.ComponentsOptions{Version: version, SkipVariables: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	version = components.Version()
+
+	// The providers are stored in the bundle using the same layout of a local repository: {provider-label}/{version}/{file}.
+	dir := path.Join(bundleProvidersDir, providerConfig.ManifestLabel(), version)
+
+	componentsPath := repositoryClient.ComponentsPath()
+	componentsYaml, err := repositoryClient.GetFile(version, componentsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	files[path.Join(dir, componentsPath)] = componentsYaml
+
+	// Metadata are read using the metadata client, so the metadata embedded in clusterctl are used if the provider does not publish them.
+	metadata, err := repositoryClient.Metadata(version).Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata.APIVersion = clusterctlv1.GroupVersion.String()
+	metadata.Kind = "Metadata"
+	metadataYaml, err := yaml.Marshal(metadata)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal the provider metadata")
+	}
+	files[path.Join(dir, bundleMetadataFile)] = metadataYaml
+
+	if providerType == clusterctlv1.InfrastructureProviderType {
+		// The default template is optional, while the explicitly requested flavors must exist.
+		if template, err := repositoryClient.GetFile(version, bundleTemplateFile); err == nil {
+			files[path.Join(dir, bundleTemplateFile)] = template
+		} else {
+			logf.Log.V(1).Info("Skipping the default workload cluster template", "Provider", provider, "Error", err.Error())
+		}
+		for _, flavor := range flavors {
+			name := fmt.Sprintf(bundleFlavorTemplateFile, flavor)
+			template, err := repositoryClient.GetFile(version, name)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to get the workload cluster template for the %q flavor", flavor)
+			}
+			files[path.Join(dir, name)] = template
+		}
+	}
+
+	bp := &bundleProvider{
+		Name:           providerConfig.Name(),
+		Type:           providerConfig.Type(),
+		Version:        version,
+		ComponentsPath: componentsPath,
+	}
+	return bp, components.Images(), nil
+}
+
+// uniqueSorted returns the sorted list of unique values.
+func uniqueSorted(values []string) []string {
+	set := map[string]struct{}{}
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	unique := make([]string, 0, len(set))
+	for v := range set {
+		unique = append(unique, v)
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// writeBundle writes the files into a gzipped tarball.
+func writeBundle(outputFile string, files map[string][]byte) error {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		header := &tar.Header{
+			Name:     name,
+			Mode:     bundleFilePermissions,
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(outputFile, buf.Bytes(), bundleFilePermissions)
+}
+
+// extractedBundle is a bundle extracted in a temporary directory.
+type extractedBundle struct {
+	dir   string
+	index bundleIndex
+}
+
+// openBundle extracts a bundle into a temporary directory; the caller is responsible for calling Close
+// to remove the temporary directory.
+func openBundle(bundleFile string) (_ *extractedBundle, reterr error) {
+	f, err := os.Open(bundleFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the bundle %q", bundleFile)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the bundle %q", bundleFile)
+	}
+	defer gr.Close()
+
+	dir, err := ioutil.TempDir("", "clusterctl-bundle")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create a temporary directory for the bundle")
+	}
+	b := &extractedBundle{dir: dir}
+	defer func() {
+		if reterr != nil {
+			b.Close()
+		}
+	}()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the bundle %q", bundleFile)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Ensure the files are extracted inside the bundle directory.
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, errors.Errorf("invalid file %q in the bundle %q", header.Name, bundleFile)
+		}
+		if header.Size > bundleMaxFileSize {
+			return nil, errors.Errorf("file %q in the bundle %q is too big", header.Name, bundleFile)
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), bundleDirectoryPermissions); err != nil {
+			return nil, errors.Wrapf(err, "failed to extract the bundle %q", bundleFile)
+		}
+		content, err := ioutil.ReadAll(io.LimitReader(tr, bundleMaxFileSize))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read file %q from the bundle %q", header.Name, bundleFile)
+		}
+		if err := ioutil.WriteFile(target, content, bundleFilePermissions); err != nil {
+			return nil, errors.Wrapf(err, "failed to extract the bundle %q", bundleFile)
+		}
+	}
+
+	indexYaml, err := ioutil.ReadFile(filepath.Join(dir, bundleIndexFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid bundle %q: failed to read %s", bundleFile, bundleIndexFile)
+	}
+	if err := yaml.Unmarshal(indexYaml, &b.index); err != nil {
+		return nil, errors.Wrapf(err, "invalid bundle %q: failed to parse %s", bundleFile, bundleIndexFile)
+	}
+
+	// cert-manager is installed using the manifest embedded in clusterctl, so the bundle must have been
+	// created with a clusterctl version embedding the same manifest.
+	certManagerManifest, err := ioutil.ReadFile(filepath.Join(dir, bundleCertManagerFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid bundle %q: failed to read %s", bundleFile, bundleCertManagerFile)
+	}
+	embeddedCertManagerManifest, err := cluster.CertManagerManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the cert-manager manifest")
+	}
+	if !bytes.Equal(certManagerManifest, embeddedCertManagerManifest) {
+		return nil, errors.Errorf("invalid bundle %q: the bundle was created with a version of clusterctl embedding a different cert-manager version", bundleFile)
+	}
+
+	return b, nil
+}
+
+// Close removes the directory where the bundle was extracted.
+func (b *extractedBundle) Close() error {
+	return os.RemoveAll(b.dir)
+}
+
+// provider returns the configuration of a provider pointing to the local repository extracted from the bundle, if any.
+func (b *extractedBundle) provider(name string, providerType clusterctlv1.ProviderType) (config.Provider, bool) {
+	for _, p := range b.index.Providers {
+		if p.Name != name || p.Type != providerType {
+			continue
+		}
+		provider := config.NewProvider(p.Name, "", p.Type)
+		componentsFile := filepath.Join(b.dir, bundleProvidersDir, provider.ManifestLabel(), p.Version, p.ComponentsPath)
+		return config.NewProvider(p.Name, "file://"+filepath.ToSlash(componentsFile), p.Type), true
+	}
+	return nil, false
+}
+
+// bundleConfigClient implements config.Client reading providers from an extracted bundle and
+// moving all the images to a target registry, if defined.
+type bundleConfigClient struct {
+	config.Client
+	bundle        *extractedBundle
+	imageRegistry string
+}
+
+func (c *bundleConfigClient) Providers() config.ProvidersClient {
+	if c.bundle == nil {
+		return c.Client.Providers()
+	}
+	return &bundleProvidersClient{
+		ProvidersClient: c.Client.Providers(),
+		bundle:          c.bundle,
+	}
+}
+
+func (c *bundleConfigClient) ImageMeta() config.ImageMetaClient {
+	if c.imageRegistry == "" {
+		return c.Client.ImageMeta()
+	}
+	return config.ImageMetaClientWithRegistry(c.Client.ImageMeta(), c.imageRegistry)
+}
+
+// bundleProvidersClient implements config.ProvidersClient reading providers from an extracted bundle;
+// providers not included in the bundle are read from the clusterctl configuration.
+type bundleProvidersClient struct {
+	config.ProvidersClient
+	bundle *extractedBundle
+}
+
+func (p *bundleProvidersClient) List() ([]config.Provider, error) {
+	providers, err := p.ProvidersClient.List()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []config.Provider{}
+	for _, provider := range providers {
+		if bundleProvider, ok := p.bundle.provider(provider.Name(), provider.Type()); ok {
+			provider = bundleProvider
+		}
+		ret = append(ret, provider)
+	}
+
+	// Adds the providers in the bundle which are not defined in the clusterctl configuration.
+	for _, bp := range p.bundle.index.Providers {
+		found := false
+		for _, provider := range providers {
+			if provider.Name() == bp.Name && provider.Type() == bp.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			bundleProvider, _ := p.bundle.provider(bp.Name, bp.Type)
+			ret = append(ret, bundleProvider)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Less(ret[j])
+	})
+	return ret, nil
+}
+
+func (p *bundleProvidersClient) Get(name string, providerType clusterctlv1.ProviderType) (config.Provider, error) {
+	if provider, ok := p.bundle.provider(name, providerType); ok {
+		return provider, nil
+	}
+	return p.ProvidersClient.Get(name, providerType)
+}
+
+// withBundle returns a clusterctl client reading providers from a bundle and moving all the images to
+// a target registry, if defined; the returned func must be called for cleaning up the extracted bundle.
+func (c *clusterctlClient) withBundle(bundleFile, imageRegistry string) (*clusterctlClient, func(), error) {
+	configClient := &bundleConfigClient{
+		Client:        c.configClient,
+		imageRegistry: imageRegistry,
+	}
+	cleanup := func() {}
+	if bundleFile != "" {
+		bundle, err := openBundle(bundleFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		configClient.bundle = bundle
+		cleanup = func() {
+			if err := bundle.Close(); err != nil {
+				logf.Log.V(1).Info("Failed to remove the extracted bundle", "Dir", bundle.dir, "Error", err.Error())
+			}
+		}
+	}
+
+	client, err := newClusterctlClient("", InjectConfig(configClient))
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return client, cleanup, nil
+}
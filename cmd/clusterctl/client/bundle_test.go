@@ -0,0 +1,284 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+func Test_clusterctlClient_CreateBundle(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  CreateBundleOptions
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "creates a bundle with the default providers",
+			options: CreateBundleOptions{
+				InfrastructureProviders: []string{"infra"},
+			},
+		},
+		{
+			name: "fails if a flavor does not exist",
+			options: CreateBundleOptions{
+				InfrastructureProviders: []string{"infra"},
+				Flavors:                 []string{"does-not-exist"},
+			},
+			wantErr:  true,
+			errorMsg: `failed to get the workload cluster template for the "does-not-exist" flavor`,
+		},
+		{
+			name: "fails if a provider version does not exist",
+			options: CreateBundleOptions{
+				InfrastructureProviders: []string{"infra:v9.9.9"},
+			},
+			wantErr:  true,
+			errorMsg: `failed to add the "infra:v9.9.9" provider to the bundle`,
+		},
+		{
+			name: "fails if the output file is not set",
+			options: CreateBundleOptions{
+				InfrastructureProviders: []string{"infra"},
+			},
+			wantErr:  true,
+			errorMsg: "the output file can't be empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir, err := ioutil.TempDir("", "bundle")
+			g.Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			if tt.errorMsg != "the output file can't be empty" {
+				tt.options.OutputFile = filepath.Join(dir, "bundle.tar.gz")
+			}
+
+			client := fakeBundleClient()
+			err = client.CreateBundle(tt.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.errorMsg))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			files := readBundleFiles(g, tt.options.OutputFile)
+			g.Expect(files).To(HaveKey(bundleIndexFile))
+			g.Expect(files).To(HaveKey(bundleCertManagerFile))
+			g.Expect(files).To(HaveKey("providers/cluster-api/v1.0.0/components.yaml"))
+			g.Expect(files).To(HaveKey("providers/cluster-api/v1.0.0/metadata.yaml"))
+			g.Expect(files).To(HaveKey("providers/bootstrap-kubeadm/v2.0.0/components.yaml"))
+			g.Expect(files).To(HaveKey("providers/control-plane-kubeadm/v2.0.0/components.yaml"))
+			g.Expect(files).To(HaveKey("providers/infrastructure-infra/v3.0.0/components.yaml"))
+			g.Expect(files).To(HaveKey("providers/infrastructure-infra/v3.0.0/cluster-template.yaml"))
+			g.Expect(files["providers/cluster-api/v1.0.0/metadata.yaml"]).To(ContainSubstring("kind: Metadata"))
+
+			g.Expect(strings.Split(strings.TrimSpace(files[bundleImagesFile]), "\n")).To(Equal([]string{
+				"gcr.io/kubebuilder/kube-rbac-proxy:v0.5.0",
+				"quay.io/jetstack/cert-manager-controller:v0.11.0",
+				"us.gcr.io/k8s-artifacts-prod/cluster-api-aws/cluster-api-aws-controller:v0.5.3",
+			}))
+
+			b, err := openBundle(tt.options.OutputFile)
+			g.Expect(err).NotTo(HaveOccurred())
+			defer b.Close()
+
+			g.Expect(b.index.Providers).To(ConsistOf(
+				bundleProvider{Name: config.ClusterAPIProviderName, Type: clusterctlv1.CoreProviderType, Version: "v1.0.0", ComponentsPath: "components.yaml"},
+				bundleProvider{Name: config.KubeadmBootstrapProviderName, Type: clusterctlv1.BootstrapProviderType, Version: "v2.0.0", ComponentsPath: "components.yaml"},
+				bundleProvider{Name: config.KubeadmControlPlaneProviderName, Type: clusterctlv1.ControlPlaneProviderType, Version: "v2.0.0", ComponentsPath: "components.yaml"},
+				bundleProvider{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Version: "v3.0.0", ComponentsPath: "components.yaml"},
+			))
+
+			provider, ok := b.provider("infra", clusterctlv1.InfrastructureProviderType)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(provider.URL()).To(Equal("file://" + filepath.ToSlash(filepath.Join(b.dir, "providers", "infrastructure-infra", "v3.0.0", "components.yaml"))))
+		})
+	}
+}
+
+func Test_openBundle(t *testing.T) {
+	certManagerManifest, err := cluster.CertManagerManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := []byte("providers:\n- name: infra\n  type: InfrastructureProvider\n  version: v3.0.0\n  componentsPath: components.yaml\n")
+
+	tests := []struct {
+		name     string
+		files    map[string][]byte
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "opens a valid bundle",
+			files: map[string][]byte{
+				bundleIndexFile:       index,
+				bundleCertManagerFile: certManagerManifest,
+				"providers/infrastructure-infra/v3.0.0/components.yaml": infraComponentsYAML("ns4"),
+			},
+		},
+		{
+			name: "fails if the bundle contains files outside of the bundle directory",
+			files: map[string][]byte{
+				bundleIndexFile:       index,
+				bundleCertManagerFile: certManagerManifest,
+				"../components.yaml":  infraComponentsYAML("ns4"),
+			},
+			wantErr:  true,
+			errorMsg: `invalid file "../components.yaml"`,
+		},
+		{
+			name: "fails if the bundle index is missing",
+			files: map[string][]byte{
+				bundleCertManagerFile: certManagerManifest,
+			},
+			wantErr:  true,
+			errorMsg: "failed to read bundle.yaml",
+		},
+		{
+			name: "fails if the bundle contains a different cert-manager manifest",
+			files: map[string][]byte{
+				bundleIndexFile:       index,
+				bundleCertManagerFile: []byte("foo"),
+			},
+			wantErr:  true,
+			errorMsg: "embedding a different cert-manager version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir, err := ioutil.TempDir("", "bundle")
+			g.Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			bundleFile := filepath.Join(dir, "bundle.tar.gz")
+			g.Expect(writeBundle(bundleFile, tt.files)).To(Succeed())
+
+			b, err := openBundle(bundleFile)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.errorMsg))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			components, err := ioutil.ReadFile(filepath.Join(b.dir, "providers", "infrastructure-infra", "v3.0.0", "components.yaml"))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(components).To(Equal(infraComponentsYAML("ns4")))
+
+			g.Expect(b.Close()).To(Succeed())
+			_, err = os.Stat(b.dir)
+			g.Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	}
+}
+
+func Test_bundleProvidersClient(t *testing.T) {
+	g := NewWithT(t)
+
+	b := &extractedBundle{
+		dir: "/bundle",
+		index: bundleIndex{
+			Providers: []bundleProvider{
+				{Name: "infra", Type: clusterctlv1.InfrastructureProviderType, Version: "v3.0.0", ComponentsPath: "components.yaml"},
+				{Name: "another-infra", Type: clusterctlv1.InfrastructureProviderType, Version: "v1.0.0", ComponentsPath: "infrastructure-components.yaml"},
+			},
+		},
+	}
+	configClient := &bundleConfigClient{
+		Client: newFakeConfig().WithProvider(capiProviderConfig).WithProvider(infraProviderConfig),
+		bundle: b,
+	}
+
+	// Providers in the bundle are read from the bundle.
+	provider, err := configClient.Providers().Get("infra", clusterctlv1.InfrastructureProviderType)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(provider.URL()).To(Equal("file:///bundle/providers/infrastructure-infra/v3.0.0/components.yaml"))
+
+	// Providers not in the bundle are read from the clusterctl configuration.
+	provider, err = configClient.Providers().Get(config.ClusterAPIProviderName, clusterctlv1.CoreProviderType)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(provider.URL()).To(Equal(capiProviderConfig.URL()))
+
+	// List includes providers in the bundle not defined in the clusterctl configuration.
+	providers, err := configClient.Providers().List()
+	g.Expect(err).NotTo(HaveOccurred())
+	urls := map[string]string{}
+	for _, p := range providers {
+		urls[p.ManifestLabel()] = p.URL()
+	}
+	g.Expect(urls).To(HaveKeyWithValue("infrastructure-infra", "file:///bundle/providers/infrastructure-infra/v3.0.0/components.yaml"))
+	g.Expect(urls).To(HaveKeyWithValue("infrastructure-another-infra", "file:///bundle/providers/infrastructure-another-infra/v1.0.0/infrastructure-components.yaml"))
+	g.Expect(urls).To(HaveKeyWithValue("cluster-api", capiProviderConfig.URL()))
+}
+
+// fakeBundleClient returns a clusterctl client with repositories for the default providers and for the infra provider.
+func fakeBundleClient() *fakeClient {
+	config1 := fakeConfig(
+		[]config.Provider{capiProviderConfig, bootstrapProviderConfig, controlPlaneProviderConfig, infraProviderConfig},
+		nil,
+	)
+	repositories := fakeRepositories(config1, nil)
+
+	// CreateBundle reads the cert-manager images without connecting to a management cluster.
+	cluster1 := newFakeCluster(cluster.Kubeconfig{}, config1).
+		WithCertManagerClient(newFakeCertManagerClient([]string{"quay.io/jetstack/cert-manager-controller:v0.11.0"}, nil))
+
+	return fakeClusterCtlClient(config1, repositories, []*fakeClusterClient{cluster1})
+}
+
+// readBundleFiles returns the files contained in a bundle.
+func readBundleFiles(g *WithT, bundleFile string) map[string]string {
+	content, err := ioutil.ReadFile(bundleFile)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	gr, err := gzip.NewReader(bytes.NewReader(content))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	files := map[string]string{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		data, err := ioutil.ReadAll(tr)
+		g.Expect(err).NotTo(HaveOccurred())
+		files[header.Name] = string(data)
+	}
+	return files
+}
@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
 )
 
 // Client is exposes the clusterctl high-level client library.
@@ -38,6 +39,10 @@ type Client interface {
 	// InitImages returns the list of images required for executing the init command.
 	InitImages(options InitOptions) ([]string, error)
 
+	// CreateBundle creates a self-contained bundle with the requested list of providers, to be used for
+	// initializing management clusters without access to the provider repositories.
+	CreateBundle(options CreateBundleOptions) error
+
 	// GetClusterTemplate returns a workload cluster template.
 	GetClusterTemplate(options GetClusterTemplateOptions) (Template, error)
 
@@ -67,6 +72,13 @@ type Client interface {
 	// variables.
 	ProcessYAML(options ProcessYAMLOptions) (YamlPrinter, error)
 
+	// DeleteCluster deletes a workload cluster; if the Orphan option is set, the infrastructure of the
+	// workload cluster is left running.
+	DeleteCluster(options DeleteClusterOptions) error
+
+	// DescribeCluster returns the object tree representing the status of a Cluster API cluster.
+	DescribeCluster(options DescribeClusterOptions) (*tree.ObjectTree, error)
+
 	// Interface for alpha features in clusterctl
 	AlphaClient
 }
@@ -75,6 +87,18 @@ type Client interface {
 type AlphaClient interface {
 	// RolloutRestart provides rollout restart of cluster-api resources
 	RolloutRestart(options RolloutRestartOptions) error
+	// RolloutPause provides rollout pause of cluster-api resources
+	RolloutPause(options RolloutPauseOptions) error
+	// RolloutResume provides rollout resume of paused cluster-api resources
+	RolloutResume(options RolloutResumeOptions) error
+	// RolloutUndo provides rollout rollback of cluster-api resources
+	RolloutUndo(options RolloutUndoOptions) error
+	// RolloutStatus returns the rollout status of a cluster-api resource
+	RolloutStatus(options RolloutStatusOptions) (*RolloutStatus, error)
+	// RolloutHistory returns the rollout history of a cluster-api resource
+	RolloutHistory(options RolloutHistoryOptions) ([]RolloutRevision, error)
+	// PushProviderRelease pushes the files of a provider release to an OCI registry
+	PushProviderRelease(options PushProviderReleaseOptions) error
 }
 
 // YamlPrinter exposes methods that prints the processed template and
@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
 	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
@@ -94,6 +95,10 @@ func (f fakeClient) InitImages(options InitOptions) ([]string, error) {
 	return f.internalClient.InitImages(options)
 }
 
+func (f fakeClient) CreateBundle(options CreateBundleOptions) error {
+	return f.internalClient.CreateBundle(options)
+}
+
 func (f fakeClient) Delete(options DeleteOptions) error {
 	return f.internalClient.Delete(options)
 }
@@ -118,10 +123,42 @@ func (f fakeClient) ProcessYAML(options ProcessYAMLOptions) (YamlPrinter, error)
 	return f.internalClient.ProcessYAML(options)
 }
 
+func (f fakeClient) DeleteCluster(options DeleteClusterOptions) error {
+	return f.internalClient.DeleteCluster(options)
+}
+
+func (f fakeClient) DescribeCluster(options DescribeClusterOptions) (*tree.ObjectTree, error) {
+	return f.internalClient.DescribeCluster(options)
+}
+
 func (f fakeClient) RolloutRestart(options RolloutRestartOptions) error {
 	return f.internalClient.RolloutRestart(options)
 }
 
+func (f fakeClient) RolloutPause(options RolloutPauseOptions) error {
+	return f.internalClient.RolloutPause(options)
+}
+
+func (f fakeClient) RolloutResume(options RolloutResumeOptions) error {
+	return f.internalClient.RolloutResume(options)
+}
+
+func (f fakeClient) RolloutUndo(options RolloutUndoOptions) error {
+	return f.internalClient.RolloutUndo(options)
+}
+
+func (f fakeClient) RolloutStatus(options RolloutStatusOptions) (*RolloutStatus, error) {
+	return f.internalClient.RolloutStatus(options)
+}
+
+func (f fakeClient) RolloutHistory(options RolloutHistoryOptions) ([]RolloutRevision, error) {
+	return f.internalClient.RolloutHistory(options)
+}
+
+func (f fakeClient) PushProviderRelease(options PushProviderReleaseOptions) error {
+	return f.internalClient.PushProviderRelease(options)
+}
+
 // newFakeClient returns a clusterctl client that allows to execute tests on a set of fake config, fake repositories and fake clusters.
 // you can use WithCluster and WithRepository to prepare for the test case.
 func newFakeClient(configClient config.Client) *fakeClient {
@@ -293,6 +330,10 @@ func (f *fakeClusterClient) WorkloadCluster() cluster.WorkloadCluster {
 	return f.internalclient.WorkloadCluster()
 }
 
+func (f *fakeClusterClient) ClusterDescriber() cluster.ClusterDescriber {
+	return f.internalclient.ClusterDescriber()
+}
+
 func (f *fakeClusterClient) WithObjs(objs ...client.Object) *fakeClusterClient {
 	f.fakeProxy.WithObjs(objs...)
 	return f
@@ -396,6 +437,14 @@ func (f fakeRepositoryClient) GetVersions() ([]string, error) {
 	return f.fakeRepository.GetVersions()
 }
 
+func (f fakeRepositoryClient) ComponentsPath() string {
+	return f.fakeRepository.ComponentsPath()
+}
+
+func (f fakeRepositoryClient) GetFile(version string, path string) ([]byte, error) {
+	return f.fakeRepository.GetFile(version, path)
+}
+
 func (f fakeRepositoryClient) Components() repository.ComponentsClient {
 	// use a fakeComponentClient (instead of the internal client used in other fake objects) we can de deterministic on what is returned (e.g. avoid interferences from overrides)
 	return &fakeComponentClient{
@@ -389,6 +389,11 @@ func (cm *certManagerClient) getManifestObjs() ([]unstructured.Unstructured, err
 	return objs, nil
 }
 
+// CertManagerManifest returns the cert-manager manifest embedded in the clusterctl binary.
+func CertManagerManifest() ([]byte, error) {
+	return manifests.Asset(embeddedCertManagerManifestPath)
+}
+
 // getTestResourcesManifestObjs gets the cert-manager test manifests, converted to unstructured objects.
 // These are used to ensure the cert-manager API components are all ready and the API is available for use.
 func getTestResourcesManifestObjs() ([]unstructured.Unstructured, error) {
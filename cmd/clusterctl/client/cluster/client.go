@@ -89,6 +89,9 @@ type Client interface {
 
 	// WorkloadCluster has methods for fetching kubeconfig of workload cluster from management cluster.
 	WorkloadCluster() WorkloadCluster
+
+	// ClusterDescriber has methods for describing the status of a workload cluster.
+	ClusterDescriber() ClusterDescriber
 }
 
 // PollImmediateWaiter tries a condition func until it returns true, an error, or the timeout is reached.
@@ -149,6 +152,10 @@ func (c *clusterClient) WorkloadCluster() WorkloadCluster {
 	return newWorkloadCluster(c.proxy)
 }
 
+func (c *clusterClient) ClusterDescriber() ClusterDescriber {
+	return newClusterDescriber(c.proxy)
+}
+
 // Option is a configuration option supplied to New
 type Option func(*clusterClient)
 
@@ -0,0 +1,290 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterDescriber has methods for describing the status of a workload cluster.
+type ClusterDescriber interface {
+	// Describe returns an object tree representing the status of a Cluster and of the objects linked to it.
+	Describe(namespace, name string, options tree.ObjectTreeOptions) (*tree.ObjectTree, error)
+}
+
+// clusterDescriber implements ClusterDescriber.
+type clusterDescriber struct {
+	proxy  Proxy
+	client client.Client
+}
+
+// newClusterDescriber returns a clusterDescriber.
+func newClusterDescriber(proxy Proxy) *clusterDescriber {
+	return &clusterDescriber{
+		proxy: proxy,
+	}
+}
+
+func (d *clusterDescriber) Describe(namespace, name string, options tree.ObjectTreeOptions) (*tree.ObjectTree, error) {
+	// Gets all the types defined by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
+	graph := newObjectGraph(d.proxy)
+	if err := graph.getDiscoveryTypes(); err != nil {
+		return nil, err
+	}
+
+	// Discovery the object graph for the selected types; this also computes the ownership relations
+	// between the objects linked to each Cluster.
+	if err := graph.Discovery(namespace); err != nil {
+		return nil, err
+	}
+
+	return d.objectTree(graph, namespace, name, options)
+}
+
+// objectTree generates the object tree for a Cluster from an object graph.
+// The tree includes the Cluster, the infrastructure cluster, the control plane and its machines, and the workers
+// (MachineDeployments with their machines, MachinePools and standalone machines); other objects not relevant for
+// triaging the cluster status, e.g. MachineSets, are not included.
+func (d *clusterDescriber) objectTree(graph *objectGraph, namespace, name string, options tree.ObjectTreeOptions) (*tree.ObjectTree, error) {
+	var clusterNode *node
+	for _, n := range graph.getClusters() {
+		if n.identity.Namespace == namespace && n.identity.Name == name {
+			clusterNode = n
+			break
+		}
+	}
+	if clusterNode == nil {
+		return nil, errors.Errorf("failed to find Cluster %s/%s", namespace, name)
+	}
+
+	clusterObj, err := d.getObj(clusterNode)
+	if err != nil {
+		return nil, err
+	}
+	cluster := &clusterv1.Cluster{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, cluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert Cluster %s/%s", namespace, name)
+	}
+
+	objs := tree.NewObjectTree(clusterObj, options)
+
+	// Adds the infrastructure cluster.
+	if infraNode := findRef(graph, namespace, cluster.Spec.InfrastructureRef); infraNode != nil {
+		obj, err := d.getObj(infraNode)
+		if err != nil {
+			return nil, err
+		}
+		objs.Add(objs.GetRoot(), obj, tree.ObjectMetaName("ClusterInfrastructure"), tree.NoEcho(true))
+	}
+
+	// Adds the control plane and its machines.
+	var controlPlaneNode *node
+	if controlPlaneNode = findRef(graph, namespace, cluster.Spec.ControlPlaneRef); controlPlaneNode != nil {
+		obj, err := d.getObj(controlPlaneNode)
+		if err != nil {
+			return nil, err
+		}
+		controlPlane := objs.Add(objs.GetRoot(), obj, tree.ObjectMetaName("ControlPlane"))
+		if err := d.addMachines(objs, controlPlane, graph, ownedBy(graph, controlPlaneNode, machineGroupKind)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Adds the workers: MachineDeployments with their machines, MachinePools and standalone machines.
+	machineDeployments := tenantsOf(graph, clusterNode, machineDeploymentGroupKind)
+	machinePools := tenantsOf(graph, clusterNode, machinePoolGroupKind)
+	var standaloneMachines []*node
+	for _, m := range tenantsOf(graph, clusterNode, machineGroupKind) {
+		if !(controlPlaneNode != nil && m.isOwnedBy(controlPlaneNode)) && len(ownersOf(m, machineSetGroupKind)) == 0 && len(ownersOf(m, machinePoolGroupKind)) == 0 {
+			standaloneMachines = append(standaloneMachines, m)
+		}
+	}
+	if len(machineDeployments)+len(machinePools)+len(standaloneMachines) == 0 {
+		return objs, nil
+	}
+
+	workers := objs.AddVirtual(objs.GetRoot(), "WorkerGroup", "Workers")
+	for _, md := range machineDeployments {
+		obj, err := d.getObj(md)
+		if err != nil {
+			return nil, err
+		}
+		mdNode := objs.Add(workers, obj)
+
+		var machines []*node
+		for _, ms := range ownedBy(graph, md, machineSetGroupKind) {
+			machines = append(machines, ownedBy(graph, ms, machineGroupKind)...)
+		}
+		if err := d.addMachines(objs, mdNode, graph, machines); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, mp := range machinePools {
+		obj, err := d.getObj(mp)
+		if err != nil {
+			return nil, err
+		}
+		objs.Add(workers, obj)
+	}
+
+	if err := d.addMachines(objs, workers, graph, standaloneMachines); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+// addMachines adds a list of machines to the object tree, grouping machines with the same ready condition;
+// the infrastructure machine and the bootstrap config are added as children of each machine unless they are an echo of
+// the machine's ready condition.
+func (d *clusterDescriber) addMachines(objs *tree.ObjectTree, parent *tree.Node, graph *objectGraph, machines []*node) error {
+	sortNodes(machines)
+	for _, m := range machines {
+		obj, err := d.getObj(m)
+		if err != nil {
+			return err
+		}
+		machine := &clusterv1.Machine{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, machine); err != nil {
+			return errors.Wrapf(err, "failed to convert Machine %s/%s", obj.GetNamespace(), obj.GetName())
+		}
+
+		machineNode := objs.Add(parent, obj, tree.GroupingObject(true))
+		if machineNode == nil {
+			continue
+		}
+
+		if infraNode := findRef(graph, machine.Namespace, &machine.Spec.InfrastructureRef); infraNode != nil {
+			infraObj, err := d.getObj(infraNode)
+			if err != nil {
+				return err
+			}
+			objs.Add(machineNode, infraObj, tree.ObjectMetaName("MachineInfrastructure"), tree.NoEcho(true))
+		}
+
+		if bootstrapNode := findRef(graph, machine.Namespace, machine.Spec.Bootstrap.ConfigRef); bootstrapNode != nil {
+			bootstrapObj, err := d.getObj(bootstrapNode)
+			if err != nil {
+				return err
+			}
+			objs.Add(machineNode, bootstrapObj, tree.ObjectMetaName("BootstrapConfig"), tree.NoEcho(true))
+		}
+	}
+	return nil
+}
+
+// getObj returns the object corresponding to a node in the object graph; the object observed during discovery
+// is used if available, otherwise the object is read from the management cluster.
+func (d *clusterDescriber) getObj(n *node) (*unstructured.Unstructured, error) {
+	if n.obj != nil {
+		return n.obj, nil
+	}
+
+	if d.client == nil {
+		c, err := d.proxy.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		d.client = c
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(n.identity.APIVersion)
+	obj.SetKind(n.identity.Kind)
+	key := client.ObjectKey{Namespace: n.identity.Namespace, Name: n.identity.Name}
+	if err := d.client.Get(ctx, key, obj); err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s %s", n.identity.Kind, key)
+	}
+	return obj, nil
+}
+
+var (
+	machineGroupKind           = clusterv1.GroupVersion.WithKind("Machine").GroupKind()
+	machineSetGroupKind        = clusterv1.GroupVersion.WithKind("MachineSet").GroupKind()
+	machineDeploymentGroupKind = clusterv1.GroupVersion.WithKind("MachineDeployment").GroupKind()
+	machinePoolGroupKind       = expv1.GroupVersion.WithKind("MachinePool").GroupKind()
+)
+
+// findRef returns the node in the object graph corresponding to an object reference, if any.
+// If the reference does not specify a namespace, the given namespace is used.
+func findRef(graph *objectGraph, namespace string, ref *corev1.ObjectReference) *node {
+	if ref == nil {
+		return nil
+	}
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	groupKind := ref.GroupVersionKind().GroupKind()
+	for _, n := range graph.getNodes() {
+		if !n.virtual && n.identity.GroupVersionKind().GroupKind() == groupKind && n.identity.Namespace == namespace && n.identity.Name == ref.Name {
+			return n
+		}
+	}
+	return nil
+}
+
+// ownedBy returns the nodes of the given kind directly owned by a node, sorted by name.
+func ownedBy(graph *objectGraph, owner *node, groupKind schema.GroupKind) []*node {
+	var nodes []*node
+	for _, n := range graph.getNodes() {
+		if !n.virtual && n.identity.GroupVersionKind().GroupKind() == groupKind && n.isOwnedBy(owner) {
+			nodes = append(nodes, n)
+		}
+	}
+	sortNodes(nodes)
+	return nodes
+}
+
+// tenantsOf returns the nodes of the given kind linked to a Cluster, sorted by name.
+func tenantsOf(graph *objectGraph, cluster *node, groupKind schema.GroupKind) []*node {
+	var nodes []*node
+	for _, n := range graph.getNodes() {
+		if _, ok := n.tenantClusters[cluster]; ok && !n.virtual && n.identity.GroupVersionKind().GroupKind() == groupKind {
+			nodes = append(nodes, n)
+		}
+	}
+	sortNodes(nodes)
+	return nodes
+}
+
+// ownersOf returns the owners of a node with the given kind.
+func ownersOf(n *node, groupKind schema.GroupKind) []*node {
+	var owners []*node
+	for owner := range n.owners {
+		if owner.identity.GroupVersionKind().GroupKind() == groupKind {
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+func sortNodes(nodes []*node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].identity.Name < nodes[j].identity.Name
+	})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestClusterDescriber_objectTree(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := test.NewFakeCluster("ns1", "cluster1").
+		WithControlPlane(
+			test.NewFakeControlPlane("cp1").
+				WithMachines(
+					test.NewFakeMachine("cp1-m1"),
+					test.NewFakeMachine("cp1-m2"),
+				),
+		).
+		WithMachineDeployments(
+			test.NewFakeMachineDeployment("md1").
+				WithMachineSets(
+					test.NewFakeMachineSet("ms1").
+						WithMachines(
+							test.NewFakeMachine("md1-m1"),
+						),
+				),
+		).
+		WithMachines(
+			test.NewFakeMachine("m1"),
+		).
+		Objs()
+
+	graph := getObjectGraphWithObjs(objs)
+	g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+	g.Expect(graph.Discovery("ns1")).To(Succeed())
+
+	d := newClusterDescriber(graph.proxy)
+	objectTree, err := d.objectTree(graph, "ns1", "cluster1", tree.ObjectTreeOptions{DisableNoEcho: true})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	root := objectTree.GetRoot()
+	g.Expect(root.Object.GetKind()).To(Equal("Cluster"))
+	g.Expect(names(root.Children)).To(Equal([]string{"cluster1", "cp1", "Workers"}))
+	g.Expect(root.Children[0].MetaName).To(Equal("ClusterInfrastructure"))
+
+	controlPlane := root.Children[1]
+	g.Expect(controlPlane.MetaName).To(Equal("ControlPlane"))
+	g.Expect(names(controlPlane.Children)).To(Equal([]string{"cp1-m1", "cp1-m2"}))
+
+	workers := root.Children[2]
+	g.Expect(workers.Virtual).To(BeTrue())
+	g.Expect(names(workers.Children)).To(Equal([]string{"md1", "m1"}))
+	g.Expect(names(workers.Children[0].Children)).To(Equal([]string{"md1-m1"}))
+
+	// The infrastructure cluster is an echo of the Cluster, given that none of the fake objects have a Ready condition.
+	objectTree, err = d.objectTree(graph, "ns1", "cluster1", tree.ObjectTreeOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(names(objectTree.GetRoot().Children)).To(Equal([]string{"cp1", "Workers"}))
+
+	_, err = d.objectTree(graph, "ns1", "does-not-exist", tree.ObjectTreeOptions{})
+	g.Expect(err).To(HaveOccurred())
+
+	// The tree is built from the objects observed during discovery, without reading them again from the cluster.
+	offline := &clusterDescriber{}
+	objectTree, err = offline.objectTree(graph, "ns1", "cluster1", tree.ObjectTreeOptions{DisableNoEcho: true})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(names(objectTree.GetRoot().Children)).To(Equal([]string{"cluster1", "cp1", "Workers"}))
+}
+
+func names(nodes []*tree.Node) []string {
+	var names []string
+	for _, n := range nodes {
+		names = append(names, n.Object.GetName())
+	}
+	return names
+}
@@ -18,6 +18,8 @@ package cluster
 
 import (
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/version"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -37,6 +40,12 @@ import (
 type ObjectMover interface {
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
 	Move(namespace string, toCluster Client, dryRun bool) error
+
+	// ToDirectory writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target directory.
+	ToDirectory(namespace string, directory string) error
+
+	// FromDirectory reads all the Cluster API objects existing in a configured directory and creates them in a target management cluster.
+	FromDirectory(toCluster Client, directory string) error
 }
 
 // objectMover implements the ObjectMover interface.
@@ -59,8 +68,6 @@ func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) erro
 		log.Info("********************************************************")
 	}
 
-	objectGraph := newObjectGraph(o.fromProxy)
-
 	// checks that all the required providers in place in the target cluster.
 	if !o.dryRun {
 		if err := o.checkTargetProviders(namespace, toCluster.ProviderInventory()); err != nil {
@@ -68,17 +75,87 @@ func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) erro
 		}
 	}
 
+	objectGraph, err := o.prepareMoveGraph(namespace)
+	if err != nil {
+		return err
+	}
+
+	// Move the objects to the target cluster.
+	var proxy Proxy
+	if !o.dryRun {
+		proxy = toCluster.Proxy()
+	}
+
+	if err := o.move(objectGraph, proxy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *objectMover) ToDirectory(namespace string, directory string) error {
+	log := logf.Log
+	log.Info("Moving to directory...")
+
+	objectGraph, err := o.prepareMoveGraph(namespace)
+	if err != nil {
+		return err
+	}
+
+	if err := o.toDirectory(objectGraph, directory); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *objectMover) FromDirectory(toCluster Client, directory string) error {
+	log := logf.Log
+	log.Info("Moving from directory...")
+
+	// Gets all the types defined by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
+	// NB. The types are read from the target cluster, which is expected to have the same providers of the backed up cluster.
+	objectGraph := newObjectGraph(toCluster.Proxy())
+	if err := objectGraph.getDiscoveryTypes(); err != nil {
+		return err
+	}
+
+	// Gets all the objects in the directory.
+	objs, err := o.filesToObjs(directory)
+	if err != nil {
+		return err
+	}
+
+	// Completes the object graph using the objects read from the directory, by processing the OwnerReferences
+	// and the soft ownership relations, and then by setting the tenants for each node.
+	objectGraph.addRestoredObjs(objs)
+
+	// Check whether nodes are not included in GVK considered for fromDirectory.
+	objectGraph.checkVirtualNode()
+
+	// Restore the objects to the target cluster.
+	if err := o.fromDirectory(objectGraph, toCluster.Proxy(), objs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// prepareMoveGraph discovers the object graph to be moved and checks that the objects are ready to be moved.
+func (o *objectMover) prepareMoveGraph(namespace string) (*objectGraph, error) {
+	objectGraph := newObjectGraph(o.fromProxy)
+
 	// Gets all the types defines by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
 	err := objectGraph.getDiscoveryTypes()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Discovery the object graph for the selected types:
 	// - Nodes are defined the Kubernetes objects (Clusters, Machines etc.) identified during the discovery process.
 	// - Edges are derived by the OwnerReferences between nodes.
 	if err := objectGraph.Discovery(namespace); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Checks if Cluster API has already completed the provisioning of the infrastructure for the objects involved in the move operation.
@@ -86,23 +163,13 @@ func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) erro
 	// not currently waiting for long-running reconciliation loops, and so we can safely rely on the pause field on the Cluster object
 	// for blocking any further object reconciliation on the source objects.
 	if err := o.checkProvisioningCompleted(objectGraph); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Check whether nodes are not included in GVK considered for move
 	objectGraph.checkVirtualNode()
 
-	// Move the objects to the target cluster.
-	var proxy Proxy
-	if !o.dryRun {
-		proxy = toCluster.Proxy()
-	}
-
-	if err := o.move(objectGraph, proxy); err != nil {
-		return err
-	}
-
-	return nil
+	return objectGraph, nil
 }
 
 func newObjectMover(fromProxy Proxy, fromProviderInventory InventoryClient) *objectMover {
@@ -256,6 +323,155 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 	return nil
 }
 
+// toDirectory writes all the Cluster API objects in the object graph to a directory.
+// The source clusters are paused while the objects are written, so they are saved in a consistent state, and resumed afterwards.
+func (o *objectMover) toDirectory(graph *objectGraph, directory string) error {
+	log := logf.Log
+
+	clusters := graph.getClusters()
+	log.Info("Writing Cluster API objects to directory", "Clusters", len(clusters), "Directory", directory)
+
+	// Sets the pause field on the Cluster object in the source management cluster, so the controllers stop reconciling it.
+	log.V(1).Info("Pausing the source cluster")
+	if err := setClusterPause(o.fromProxy, clusters, true, o.dryRun); err != nil {
+		return err
+	}
+
+	// Writes all the objects to the directory.
+	// Nb. Objects are written together with their UIDs and OwnerReferences, so the object graph can be rebuilt when reading them back.
+	errList := []error{}
+	writeObjectBackoff := newWriteBackoff()
+	for _, n := range graph.getMoveNodes() {
+		nodeToWrite := n
+		if err := retryWithExponentialBackoff(writeObjectBackoff, func() error {
+			return o.writeObjectToFile(nodeToWrite, directory)
+		}); err != nil {
+			errList = append(errList, err)
+		}
+	}
+
+	// Resets the pause field on the Cluster object in the source management cluster, so the controllers start reconciling it again.
+	// Nb. This happens also if writing the objects failed, because the source cluster is not deleted.
+	log.V(1).Info("Resuming the source cluster")
+	if err := setClusterPause(o.fromProxy, clusters, false, o.dryRun); err != nil {
+		errList = append(errList, err)
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
+// writeObjectToFile writes the Kubernetes object corresponding to the object graph node to a file in the directory.
+func (o *objectMover) writeObjectToFile(nodeToWrite *node, directory string) error {
+	log := logf.Log
+	log.V(1).Info("Writing", nodeToWrite.identity.Kind, nodeToWrite.identity.Name, "Namespace", nodeToWrite.identity.Namespace)
+
+	cFrom, err := o.fromProxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	// Get the source object
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(nodeToWrite.identity.APIVersion)
+	obj.SetKind(nodeToWrite.identity.Kind)
+	objKey := client.ObjectKey{
+		Namespace: nodeToWrite.identity.Namespace,
+		Name:      nodeToWrite.identity.Name,
+	}
+
+	if err := cFrom.Get(ctx, objKey, obj); err != nil {
+		return errors.Wrapf(err, "error reading %q %s/%s",
+			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	data, err := utilyaml.FromUnstructured([]unstructured.Unstructured{*obj})
+	if err != nil {
+		return errors.Wrapf(err, "error converting %q %s/%s to yaml",
+			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	// Nb. Files are readable only by the current user, because they might contain secrets.
+	path := filepath.Join(directory, fmt.Sprintf("%s_%s_%s.yaml", obj.GetKind(), obj.GetNamespace(), obj.GetName()))
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.Wrapf(err, "error writing %q %s/%s to %s",
+			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), path)
+	}
+
+	return nil
+}
+
+// filesToObjs reads all the Kubernetes objects from the yaml files in a directory.
+func (o *objectMover) filesToObjs(directory string) ([]unstructured.Unstructured, error) {
+	log := logf.Log
+	log.Info("Reading Cluster API objects from directory", "Directory", directory)
+
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading directory %s", directory)
+	}
+
+	objs := []unstructured.Unstructured{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".yaml" {
+			continue
+		}
+
+		path := filepath.Join(directory, file.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading file %s", path)
+		}
+
+		fileObjs, err := utilyaml.ToUnstructured(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error converting file %s to Kubernetes objects", path)
+		}
+		objs = append(objs, fileObjs...)
+	}
+
+	return objs, nil
+}
+
+// fromDirectory creates in the target management cluster all the Cluster API objects in the object graph, using
+// the objects read from a directory.
+func (o *objectMover) fromDirectory(graph *objectGraph, toProxy Proxy, objs []unstructured.Unstructured) error {
+	log := logf.Log
+
+	clusters := graph.getClusters()
+	log.Info("Restoring Cluster API objects", "Clusters", len(clusters))
+
+	// Ensure all the expected target namespaces are in place before creating objects.
+	log.V(1).Info("Creating target namespaces, if missing")
+	if err := o.ensureNamespaces(graph, toProxy); err != nil {
+		return err
+	}
+
+	// Define the move sequence by processing the ownerReference chain, so we ensure that a Kubernetes object is created only after its owners.
+	moveSequence := getMoveSequence(graph)
+
+	uidToObj := make(map[types.UID]*unstructured.Unstructured, len(objs))
+	for i := range objs {
+		uidToObj[objs[i].GetUID()] = &objs[i]
+	}
+
+	// Create all objects group by group, ensuring all the ownerReferences are re-created.
+	log.Info("Creating objects in the target cluster")
+	for groupIndex := 0; groupIndex < len(moveSequence.groups); groupIndex++ {
+		if err := o.restoreGroup(moveSequence.getGroup(groupIndex), toProxy, uidToObj); err != nil {
+			return err
+		}
+	}
+
+	// Reset the pause field on the Cluster object in the target management cluster, so the controllers start reconciling it.
+	// Nb. Clusters are written to the directory while paused, so they are restored paused.
+	log.V(1).Info("Resuming the target cluster")
+	if err := setClusterPause(toProxy, clusters, false, o.dryRun); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // moveSequence defines a list of group of moveGroups
 type moveSequence struct {
 	groups   []moveGroup
@@ -530,6 +746,47 @@ func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy) erro
 			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
 	}
 
+	return o.createObj(nodeToCreate, obj, toProxy)
+}
+
+// restoreGroup creates all the Kubernetes objects into the target management cluster corresponding to the object graph nodes in a moveGroup,
+// using the objects read from a directory.
+func (o *objectMover) restoreGroup(group moveGroup, toProxy Proxy, uidToObj map[types.UID]*unstructured.Unstructured) error {
+	restoreTargetObjectBackoff := newWriteBackoff()
+	errList := []error{}
+	for i := range group {
+		nodeToRestore := group[i]
+
+		obj, ok := uidToObj[nodeToRestore.identity.UID]
+		if !ok {
+			errList = append(errList, errors.Errorf("failed to find %s %s/%s in the objects read from the directory",
+				nodeToRestore.identity.Kind, nodeToRestore.identity.Namespace, nodeToRestore.identity.Name))
+			continue
+		}
+
+		// Creates the Kubernetes object corresponding to the nodeToRestore.
+		// Nb. The operation is wrapped in a retry loop to make restore more resilient to unexpected conditions.
+		err := retryWithExponentialBackoff(restoreTargetObjectBackoff, func() error {
+			logf.Log.V(1).Info("Restoring", nodeToRestore.identity.Kind, nodeToRestore.identity.Name, "Namespace", nodeToRestore.identity.Namespace)
+			return o.createObj(nodeToRestore, obj.DeepCopy(), toProxy)
+		})
+		if err != nil {
+			errList = append(errList, err)
+		}
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
+// createObj creates a Kubernetes object in the target Management cluster, taking care of restoring the OwnerReference with the owner nodes, if any.
+func (o *objectMover) createObj(nodeToCreate *node, obj *unstructured.Unstructured, toProxy Proxy) error {
+	log := logf.Log
+
+	objKey := client.ObjectKey{
+		Namespace: nodeToCreate.identity.Namespace,
+		Name:      nodeToCreate.identity.Name,
+	}
+
 	// New objects cannot have a specified resource version. Clear it out.
 	obj.SetResourceVersion("")
 
@@ -17,6 +17,10 @@ limitations under the License.
 package cluster
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -620,6 +624,137 @@ func Test_objectMover_move(t *testing.T) {
 	}
 }
 
+func Test_objectMover_toDirectory(t *testing.T) {
+	// NB. we are testing the move to directory using the same set of moveTests used for move.
+	for _, tt := range moveTests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir, err := ioutil.TempDir("", "clusterctl")
+			g.Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
+			graph := getObjectGraphWithObjs(tt.fields.objs)
+
+			// Get all the types to be considered for discovery
+			g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+
+			// trigger discovery the content of the source cluster
+			g.Expect(graph.Discovery("")).To(Succeed())
+
+			// Run move to directory
+			mover := objectMover{
+				fromProxy: graph.proxy,
+			}
+
+			err = mover.toDirectory(graph, dir)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			csFrom, err := graph.proxy.NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			for _, node := range graph.getMoveNodes() {
+				// objects are written to the directory
+				path := filepath.Join(dir, fmt.Sprintf("%s_%s_%s.yaml", node.identity.Kind, node.identity.Namespace, node.identity.Name))
+				g.Expect(path).To(BeAnExistingFile())
+
+				// objects are not deleted from the source cluster
+				key := client.ObjectKey{
+					Namespace: node.identity.Namespace,
+					Name:      node.identity.Name,
+				}
+				oFrom := &unstructured.Unstructured{}
+				oFrom.SetAPIVersion(node.identity.APIVersion)
+				oFrom.SetKind(node.identity.Kind)
+				g.Expect(csFrom.Get(ctx, key, oFrom)).To(Succeed())
+			}
+
+			// clusters in the source cluster are resumed
+			for _, node := range graph.getClusters() {
+				cluster := &clusterv1.Cluster{}
+				g.Expect(csFrom.Get(ctx, client.ObjectKey{Namespace: node.identity.Namespace, Name: node.identity.Name}, cluster)).To(Succeed())
+				g.Expect(cluster.Spec.Paused).To(BeFalse())
+			}
+		})
+	}
+}
+
+func Test_objectMover_fromDirectory(t *testing.T) {
+	// NB. we are testing the move from directory using the same set of moveTests used for move, writing the objects
+	// to the directory with a move to directory first.
+	for _, tt := range moveTests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir, err := ioutil.TempDir("", "clusterctl")
+			g.Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test,
+			// and write its content to the directory.
+			fromGraph := getObjectGraphWithObjs(tt.fields.objs)
+			g.Expect(getFakeDiscoveryTypes(fromGraph)).To(Succeed())
+			g.Expect(fromGraph.Discovery("")).To(Succeed())
+
+			fromMover := objectMover{
+				fromProxy: fromGraph.proxy,
+			}
+			if err := fromMover.toDirectory(fromGraph, dir); err != nil {
+				g.Expect(tt.wantErr).To(BeTrue())
+				return
+			}
+
+			// Create an objectGraph bound to an empty target cluster with all the CRDs for the types involved in the test,
+			// and complete it with the objects read from the directory.
+			toProxy := getFakeProxyWithCRDs()
+			graph := newObjectGraph(toProxy)
+			g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+
+			mover := objectMover{}
+			objs, err := mover.filesToObjs(dir)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(objs).To(HaveLen(len(fromGraph.getMoveNodes())))
+
+			graph.addRestoredObjs(objs)
+
+			// Run move from directory
+			err = mover.fromDirectory(graph, toProxy, objs)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			// check that the objects are created in the target cluster
+			csTo, err := toProxy.NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			for _, node := range fromGraph.getMoveNodes() {
+				key := client.ObjectKey{
+					Namespace: node.identity.Namespace,
+					Name:      node.identity.Name,
+				}
+				oTo := &unstructured.Unstructured{}
+				oTo.SetAPIVersion(node.identity.APIVersion)
+				oTo.SetKind(node.identity.Kind)
+				g.Expect(csTo.Get(ctx, key, oTo)).To(Succeed())
+			}
+
+			// clusters in the target cluster are resumed
+			for _, node := range graph.getClusters() {
+				cluster := &clusterv1.Cluster{}
+				g.Expect(csTo.Get(ctx, client.ObjectKey{Namespace: node.identity.Namespace, Name: node.identity.Name}, cluster)).To(Succeed())
+				g.Expect(cluster.Spec.Paused).To(BeFalse())
+			}
+		})
+	}
+}
+
 func Test_objectMover_checkProvisioningCompleted(t *testing.T) {
 	type fields struct {
 		objs []client.Object
@@ -73,6 +73,9 @@ type node struct {
 	// tenantCRSs define the list of ClusterResourceSet which are tenant for the node, no matter if the node has a direct OwnerReference to the ClusterResourceSet or if
 	// the node is linked to a ClusterResourceSet indirectly in the OwnerReference chain.
 	tenantCRSs map[*node]empty
+
+	// obj is the object observed during discovery; it is nil for virtual nodes.
+	obj *unstructured.Unstructured
 }
 
 type discoveryTypeInfo struct {
@@ -173,6 +176,7 @@ func (o *objectGraph) objToNode(obj *unstructured.Unstructured) *node {
 	existingNode, found := o.uidToNode[obj.GetUID()]
 	if found {
 		existingNode.markObserved()
+		existingNode.obj = obj
 
 		// In order to compensate the lack of labels when adding a virtual node,
 		// it is required to re-compute the forceMove flag when the real node is processed
@@ -200,6 +204,7 @@ func (o *objectGraph) objToNode(obj *unstructured.Unstructured) *node {
 		tenantCRSs:     make(map[*node]empty),
 		virtual:        false,
 		forceMove:      o.getForceMove(obj.GetKind(), obj.GetAPIVersion(), obj.GetLabels()),
+		obj:            obj,
 		isGlobal:       isGlobal,
 	}
 
@@ -336,6 +341,24 @@ func (o *objectGraph) Discovery(namespace string) error {
 	return nil
 }
 
+// addRestoredObjs adds to the object graph the Kubernetes objects read from a directory, and completes the graph in the
+// same way of the discovery phase.
+func (o *objectGraph) addRestoredObjs(objs []unstructured.Unstructured) {
+	for i := range objs {
+		o.addObj(&objs[i])
+	}
+
+	// Completes the graph by searching for soft ownership relations such as secrets linked to the cluster
+	// by a naming convention (without any explicit OwnerReference).
+	o.setSoftOwnership()
+
+	// Completes the graph by setting for each node the list of Clusters the node belong to.
+	o.setClusterTenants()
+
+	// Completes the graph by setting for each node the list of ClusterResourceSet the node belong to.
+	o.setCRSTenants()
+}
+
 func getObjList(proxy Proxy, typeMeta metav1.TypeMeta, selectors []client.ListOption, objList *unstructured.UnstructuredList) error {
 	c, err := proxy.NewClient()
 	if err != nil {
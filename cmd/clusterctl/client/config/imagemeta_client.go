@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/docker/distribution/reference"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api/util/container"
 )
@@ -149,3 +150,41 @@ func (i *imageMeta) ApplyToImage(image container.Image) string {
 	// returns the resulting image name
 	return image.String()
 }
+
+// registryImageMetaClient implements ImageMetaClient moving all the images to a target registry.
+type registryImageMetaClient struct {
+	ImageMetaClient
+	registry string
+}
+
+// ImageMetaClientWithRegistry returns an ImageMetaClient that, after applying the image override configurations,
+// moves each image to the target registry, preserving the image path, name, tag and digest; this allows to install
+// providers in environments where all the images are mirrored into a private registry.
+func ImageMetaClientWithRegistry(client ImageMetaClient, registry string) ImageMetaClient {
+	return &registryImageMetaClient{
+		ImageMetaClient: client,
+		registry:        strings.TrimSuffix(registry, "/"),
+	}
+}
+
+func (p *registryImageMetaClient) AlterImage(component, imageString string) (string, error) {
+	imageString, err := p.ImageMetaClient.AlterImage(component, imageString)
+	if err != nil {
+		return "", err
+	}
+
+	named, err := reference.ParseNormalizedNamed(imageString)
+	if err != nil {
+		return "", errors.Wrapf(err, "couldn't parse image name %q", imageString)
+	}
+
+	// Replace the registry (the domain) of the image with the target registry.
+	image := fmt.Sprintf("%s/%s", p.registry, reference.Path(named))
+	if tagged, ok := named.(reference.Tagged); ok {
+		image = fmt.Sprintf("%s:%s", image, tagged.Tag())
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		image = fmt.Sprintf("%s@%s", image, digested.Digest())
+	}
+	return image, nil
+}
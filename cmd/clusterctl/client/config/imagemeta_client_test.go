@@ -256,3 +256,42 @@ func Test_imageMetaClient_AlterImage(t *testing.T) {
 		})
 	}
 }
+
+func Test_registryImageMetaClient_AlterImage(t *testing.T) {
+	tests := []struct {
+		name   string
+		reader Reader
+		image  string
+		want   string
+	}{
+		{
+			name:   "image is moved to the target registry",
+			reader: test.NewFakeReader(),
+			image:  "quay.io/jetstack/cert-manager-cainjector:v0.11.0",
+			want:   "registry.example.com:5000/mirror/jetstack/cert-manager-cainjector:v0.11.0",
+		},
+		{
+			name:   "image from docker hub is moved to the target registry",
+			reader: test.NewFakeReader(),
+			image:  "docker.io/library/nginx:1.19",
+			want:   "registry.example.com:5000/mirror/library/nginx:1.19",
+		},
+		{
+			name:   "image config are applied before moving the image to the target registry",
+			reader: test.NewFakeReader().WithImageMeta(allImageConfig, "", "foo-tag"),
+			image:  "gcr.io/k8s-staging-cluster-api/cluster-api-controller:v0.4.0",
+			want:   "registry.example.com:5000/mirror/k8s-staging-cluster-api/cluster-api-controller:foo-tag",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			p := ImageMetaClientWithRegistry(newImageMetaClient(tt.reader), "registry.example.com:5000/mirror/")
+
+			got, err := p.AlterImage("any", tt.image)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
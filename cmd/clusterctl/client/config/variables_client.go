@@ -19,6 +19,24 @@ package config
 const (
 	// GitHubTokenVariable defines a variable hosting the GitHub access token
 	GitHubTokenVariable = "github-token"
+
+	// GitLabTokenVariable defines a variable hosting the GitLab access token
+	GitLabTokenVariable = "gitlab-token"
+
+	// OCIUsernameVariable defines a variable hosting the username for authenticating to OCI registries
+	OCIUsernameVariable = "oci-username"
+
+	// OCIPasswordVariable defines a variable hosting the password or token for authenticating to OCI registries
+	OCIPasswordVariable = "oci-password"
+
+	// OCICAFileVariable defines a variable hosting the path of a PEM file with the CA certificates to be trusted when connecting to OCI registries
+	OCICAFileVariable = "oci-ca-file"
+
+	// OCIInsecureSkipVerifyVariable defines a variable that, if true, disables the verification of the OCI registries certificates
+	OCIInsecureSkipVerifyVariable = "oci-insecure-skip-verify"
+
+	// OCIPlainHTTPVariable defines a variable that, if true, makes clusterctl connect to OCI registries using plain http instead of https
+	OCIPlainHTTPVariable = "oci-plain-http"
 )
 
 // VariablesClient has methods to work with environment variables and with variables defined in the clusterctl configuration file.
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeleteClusterOptions carries the options supported by DeleteCluster.
+type DeleteClusterOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the workload cluster is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterName of the workload cluster to be deleted.
+	ClusterName string
+
+	// Orphan deletes the Cluster API objects of the workload cluster while leaving the infrastructure,
+	// e.g. VMs and load balancers, running.
+	Orphan bool
+}
+
+func (c *clusterctlClient) DeleteCluster(options DeleteClusterOptions) error {
+	if options.ClusterName == "" {
+		return errors.New("ClusterName can't be empty")
+	}
+
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	cs, err := clusterClient.Proxy().NewClient()
+	if err != nil {
+		return err
+	}
+
+	cluster := &clusterv1.Cluster{}
+	clusterKey := client.ObjectKey{
+		Namespace: options.Namespace,
+		Name:      options.ClusterName,
+	}
+	if err := cs.Get(context.TODO(), clusterKey, cluster); err != nil {
+		return errors.Wrapf(err, "failed to get Cluster %s/%s", clusterKey.Namespace, clusterKey.Name)
+	}
+
+	if options.Orphan {
+		// The orphan annotation must be in place before the deletion starts, otherwise the controllers
+		// could have already destroyed part of the infrastructure.
+		if !cluster.DeletionTimestamp.IsZero() {
+			if _, ok := cluster.Annotations[clusterv1.OrphanAnnotation]; !ok {
+				return errors.Errorf("Cluster %s/%s is already being deleted and can't be orphaned", clusterKey.Namespace, clusterKey.Name)
+			}
+			return nil
+		}
+
+		// Cluster API orphans the infrastructure objects it owns, but the control plane object and any resource
+		// managed directly by a provider are still deleted as usual, so warn the user before starting.
+		logf.Log.Info("Warning: providers not supporting orphan deletion, e.g. the control plane provider, will still tear down the infrastructure they manage",
+			"Cluster", clusterKey.Name, "Namespace", clusterKey.Namespace)
+
+		patch := client.MergeFrom(cluster.DeepCopy())
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations[clusterv1.OrphanAnnotation] = ""
+		if err := cs.Patch(context.TODO(), cluster, patch); err != nil {
+			return errors.Wrapf(err, "failed to set the %s annotation on Cluster %s/%s", clusterv1.OrphanAnnotation, clusterKey.Namespace, clusterKey.Name)
+		}
+	}
+
+	if err := cs.Delete(context.TODO(), cluster); err != nil {
+		return errors.Wrapf(err, "failed to delete Cluster %s/%s", clusterKey.Namespace, clusterKey.Name)
+	}
+	return nil
+}
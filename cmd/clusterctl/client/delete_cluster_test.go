@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_clusterctlClient_DeleteCluster(t *testing.T) {
+	kubeconfig := cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}
+
+	deletionTimestamp := metav1.Now()
+	newCluster := func(name string, modify func(*clusterv1.Cluster)) *clusterv1.Cluster {
+		c := &clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Cluster",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns1",
+				Name:      name,
+			},
+		}
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		options DeleteClusterOptions
+		wantErr bool
+	}{
+		{
+			name:    "returns error if the cluster name is empty",
+			options: DeleteClusterOptions{Namespace: "ns1"},
+			wantErr: true,
+		},
+		{
+			name:    "returns error if the cluster does not exist",
+			options: DeleteClusterOptions{Namespace: "ns1", ClusterName: "cluster1"},
+			wantErr: true,
+		},
+		{
+			name:    "deletes the cluster",
+			objs:    []client.Object{newCluster("cluster1", nil)},
+			options: DeleteClusterOptions{Namespace: "ns1", ClusterName: "cluster1"},
+		},
+		{
+			name:    "deletes the cluster in orphan mode",
+			objs:    []client.Object{newCluster("cluster1", nil)},
+			options: DeleteClusterOptions{Namespace: "ns1", ClusterName: "cluster1", Orphan: true},
+		},
+		{
+			name: "returns error if the cluster is already being deleted without the orphan annotation",
+			objs: []client.Object{newCluster("cluster1", func(c *clusterv1.Cluster) {
+				c.DeletionTimestamp = &deletionTimestamp
+			})},
+			options: DeleteClusterOptions{Namespace: "ns1", ClusterName: "cluster1", Orphan: true},
+			wantErr: true,
+		},
+		{
+			name: "does nothing if the cluster is already being deleted in orphan mode",
+			objs: []client.Object{newCluster("cluster1", func(c *clusterv1.Cluster) {
+				c.DeletionTimestamp = &deletionTimestamp
+				c.Annotations = map[string]string{clusterv1.OrphanAnnotation: ""}
+			})},
+			options: DeleteClusterOptions{Namespace: "ns1", ClusterName: "cluster1", Orphan: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config1 := newFakeConfig()
+			cluster1 := newFakeCluster(kubeconfig, config1).WithObjs(tt.objs...)
+			c := newFakeClient(config1).WithCluster(cluster1)
+
+			tt.options.Kubeconfig = Kubeconfig(kubeconfig)
+			err := c.DeleteCluster(tt.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			if tt.objs[0].GetDeletionTimestamp() != nil {
+				return
+			}
+			cs, err := cluster1.Proxy().NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+			err = cs.Get(context.TODO(), client.ObjectKey{Namespace: "ns1", Name: "cluster1"}, &clusterv1.Cluster{})
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+)
+
+// DescribeClusterOptions carries the options supported by DescribeCluster.
+type DescribeClusterOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the workload cluster is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterName to be used for the workload cluster.
+	ClusterName string
+
+	// ShowOtherConditions is a list of comma separated kind or kind/name for which we should show all the object's conditions
+	// (not only the Ready condition); use "all" for showing all the conditions for all the objects.
+	ShowOtherConditions string
+
+	// DisableNoEcho disables hiding objects if the object's ready condition has the
+	// same Status, Severity and Reason of the parent's object ready condition (it is an echo).
+	DisableNoEcho bool
+
+	// DisableGrouping disables grouping machines objects in case the ready condition
+	// has the same Status, Severity and Reason.
+	DisableGrouping bool
+}
+
+func (c *clusterctlClient) DescribeCluster(options DescribeClusterOptions) (*tree.ObjectTree, error) {
+	if options.ClusterName == "" {
+		return nil, errors.New("ClusterName can't be empty")
+	}
+
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensures the custom resource definitions required by clusterctl are in place.
+	if err := clusterClient.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
+		return nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	return clusterClient.ClusterDescriber().Describe(options.Namespace, options.ClusterName, tree.ObjectTreeOptions{
+		ShowOtherConditions: options.ShowOtherConditions,
+		DisableNoEcho:       options.DisableNoEcho,
+		DisableGrouping:     options.DisableGrouping,
+	})
+}
@@ -61,6 +61,14 @@ type InitOptions struct {
 	// LogUsageInstructions instructs the init command to print the usage instructions in case of first run.
 	LogUsageInstructions bool
 
+	// FromBundle defines the path of a bundle created with CreateBundle to install the providers from; providers
+	// included in the bundle are read from it instead of the provider repositories.
+	FromBundle string
+
+	// ImageRegistry defines the registry where all the images are pulled from, e.g. a registry mirroring
+	// the images listed in a bundle. If unspecified, the images are pulled from their original registry.
+	ImageRegistry string
+
 	// skipVariables skips variable parsing in the provider components yaml.
 	// It is set to true for listing images of provider components.
 	skipVariables bool
@@ -70,6 +78,17 @@ type InitOptions struct {
 func (c *clusterctlClient) Init(options InitOptions) ([]Components, error) {
 	log := logf.Log
 
+	if options.FromBundle != "" || options.ImageRegistry != "" {
+		client, cleanup, err := c.withBundle(options.FromBundle, options.ImageRegistry)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+
+		options.FromBundle, options.ImageRegistry = "", ""
+		return client.Init(options)
+	}
+
 	// gets access to the management cluster
 	cluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -140,6 +159,17 @@ func (c *clusterctlClient) Init(options InitOptions) ([]Components, error) {
 
 // Init returns the list of images required for init.
 func (c *clusterctlClient) InitImages(options InitOptions) ([]string, error) {
+	if options.FromBundle != "" || options.ImageRegistry != "" {
+		client, cleanup, err := c.withBundle(options.FromBundle, options.ImageRegistry)
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+
+		options.FromBundle, options.ImageRegistry = "", ""
+		return client.InitImages(options)
+	}
+
 	// gets access to the management cluster
 	cluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -221,19 +251,24 @@ func (c *clusterctlClient) addDefaultProviders(cluster cluster.Client, options *
 	// of providers to be installed.
 	if currentCoreProvider == "" {
 		firstRun = true
-		if options.CoreProvider == "" {
-			options.CoreProvider = config.ClusterAPIProviderName
-		}
-		if len(options.BootstrapProviders) == 0 {
-			options.BootstrapProviders = append(options.BootstrapProviders, config.KubeadmBootstrapProviderName)
-		}
-		if len(options.ControlPlaneProviders) == 0 {
-			options.ControlPlaneProviders = append(options.ControlPlaneProviders, config.KubeadmControlPlaneProviderName)
-		}
+		setDefaultProviders(options)
 	}
 	return firstRun
 }
 
+// setDefaultProviders adds the default core, bootstrap and control plane providers to the options, if not already defined.
+func setDefaultProviders(options *InitOptions) {
+	if options.CoreProvider == "" {
+		options.CoreProvider = config.ClusterAPIProviderName
+	}
+	if len(options.BootstrapProviders) == 0 {
+		options.BootstrapProviders = append(options.BootstrapProviders, config.KubeadmBootstrapProviderName)
+	}
+	if len(options.ControlPlaneProviders) == 0 {
+		options.ControlPlaneProviders = append(options.ControlPlaneProviders, config.KubeadmControlPlaneProviderName)
+	}
+}
+
 type addToInstallerOptions struct {
 	installer         cluster.ProviderInstaller
 	targetNamespace   string
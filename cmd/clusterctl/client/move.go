@@ -17,6 +17,9 @@ limitations under the License.
 package client
 
 import (
+	"os"
+
+	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
 
@@ -36,9 +39,35 @@ type MoveOptions struct {
 
 	// DryRun means the move action is a dry run, no real action will be performed
 	DryRun bool
+
+	// ToDirectory defines a directory where to write the objects describing the workload clusters, instead of moving
+	// them to a target management cluster; the objects are not deleted from the source management cluster.
+	ToDirectory string
+
+	// FromDirectory defines a directory where to read the objects describing the workload clusters from, instead of reading
+	// them from a source management cluster; the objects are created in the target management cluster.
+	FromDirectory string
 }
 
 func (c *clusterctlClient) Move(options MoveOptions) error {
+	if options.ToDirectory != "" && options.FromDirectory != "" {
+		return errors.New("ToDirectory and FromDirectory cannot be used together")
+	}
+	if options.DryRun && (options.ToDirectory != "" || options.FromDirectory != "") {
+		return errors.New("DryRun cannot be used together with ToDirectory or FromDirectory")
+	}
+
+	switch {
+	case options.ToDirectory != "":
+		return c.toDirectory(options)
+	case options.FromDirectory != "":
+		return c.fromDirectory(options)
+	default:
+		return c.move(options)
+	}
+}
+
+func (c *clusterctlClient) move(options MoveOptions) error {
 	// Get the client for interacting with the source management cluster.
 	fromCluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.FromKubeconfig})
 	if err != nil {
@@ -79,3 +108,51 @@ func (c *clusterctlClient) Move(options MoveOptions) error {
 
 	return nil
 }
+
+func (c *clusterctlClient) toDirectory(options MoveOptions) error {
+	// Get the client for interacting with the source management cluster.
+	fromCluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.FromKubeconfig})
+	if err != nil {
+		return err
+	}
+
+	// Ensures the custom resource definitions required by clusterctl are in place.
+	if err := fromCluster.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
+		return err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := fromCluster.Proxy().CurrentNamespace()
+		if err != nil {
+			return err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	// Ensures the target directory exists; it is readable only by the current user, because it is going to contain secrets.
+	if err := os.MkdirAll(options.ToDirectory, 0700); err != nil {
+		return errors.Wrapf(err, "failed to create directory %s", options.ToDirectory)
+	}
+
+	return fromCluster.ObjectMover().ToDirectory(options.Namespace, options.ToDirectory)
+}
+
+func (c *clusterctlClient) fromDirectory(options MoveOptions) error {
+	// Get the client for interacting with the target management cluster.
+	toCluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.ToKubeconfig})
+	if err != nil {
+		return err
+	}
+
+	// Ensures the custom resource definitions required by clusterctl are in place.
+	if err := toCluster.ProviderInventory().EnsureCustomResourceDefinitions(); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(options.FromDirectory); err != nil {
+		return errors.Wrapf(err, "failed to read directory %s", options.FromDirectory)
+	}
+
+	return toCluster.ObjectMover().FromDirectory(toCluster, options.FromDirectory)
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package client
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -93,6 +96,99 @@ func Test_clusterctlClient_Move(t *testing.T) {
 	}
 }
 
+func Test_clusterctlClient_MoveDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clusterctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	type args struct {
+		options MoveOptions
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "moves to a directory, creating it if missing",
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToDirectory:    filepath.Join(dir, "backup"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "moves from a directory",
+			args: args{
+				options: MoveOptions{
+					ToKubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					FromDirectory: dir,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if the directory to move from does not exist",
+			args: args{
+				options: MoveOptions{
+					ToKubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					FromDirectory: filepath.Join(dir, "does-not-exist"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if moving both to and from a directory",
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToDirectory:    dir,
+					FromDirectory:  dir,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if moving to a directory with dry run",
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToDirectory:    dir,
+					DryRun:         true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if the cluster client to move to a directory is not found",
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "does-not-exist"},
+					ToDirectory:    dir,
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := fakeClientForMove().Move(tt.args.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
 func fakeClientForMove() *fakeClient {
 	core := config.NewProvider("cluster-api", "https://somewhere.com", clusterctlv1.CoreProviderType)
 	infra := config.NewProvider("infra", "https://somewhere.com", clusterctlv1.InfrastructureProviderType)
@@ -109,7 +205,8 @@ func fakeClientForMove() *fakeClient {
 	// Creating this cluster for move_test
 	cluster2 := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "worker-context"}, config1).
 		WithProviderInventory(core.Name(), core.Type(), "v1.0.0", "cluster-api-system", "").
-		WithProviderInventory(infra.Name(), infra.Type(), "v2.0.0", "infra-system", "")
+		WithProviderInventory(infra.Name(), infra.Type(), "v2.0.0", "infra-system", "").
+		WithObjectMover(&fakeObjectMover{})
 
 	client := newFakeClient(config1).
 		WithCluster(cluster1).
@@ -119,9 +216,19 @@ func fakeClientForMove() *fakeClient {
 }
 
 type fakeObjectMover struct {
-	moveErr error
+	moveErr          error
+	toDirectoryErr   error
+	fromDirectoryErr error
 }
 
 func (f *fakeObjectMover) Move(namespace string, toCluster cluster.Client, dryRun bool) error {
 	return f.moveErr
 }
+
+func (f *fakeObjectMover) ToDirectory(namespace string, directory string) error {
+	return f.toDirectoryErr
+}
+
+func (f *fakeObjectMover) FromDirectory(toCluster cluster.Client, directory string) error {
+	return f.fromDirectoryErr
+}
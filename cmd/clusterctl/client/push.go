@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+)
+
+// PushProviderReleaseOptions carries the options supported by PushProviderRelease.
+type PushProviderReleaseOptions struct {
+	// URL of the provider release in the OCI registry, in the form oci://{registry}/{repository}/{version}.
+	URL string
+
+	// Files of the provider release to be pushed, e.g. the components YAML, the metadata YAML and the cluster templates.
+	// Each file is identified in the release by its base name.
+	Files []string
+}
+
+func (c *clusterctlClient) PushProviderRelease(options PushProviderReleaseOptions) error {
+	files := map[string][]byte{}
+	for _, f := range options.Files {
+		name := filepath.Base(f)
+		if _, ok := files[name]; ok {
+			return errors.Errorf("invalid arguments: more than one file named %q", name)
+		}
+
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file %q", f)
+		}
+		files[name] = content
+	}
+
+	return repository.PushOCIRelease(options.URL, files, c.configClient.Variables())
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_clusterctlClient_PushProviderRelease(t *testing.T) {
+	g := NewWithT(t)
+
+	dir, err := ioutil.TempDir("", "clusterctl")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	g.Expect(os.MkdirAll(filepath.Join(dir, "a"), 0755)).To(Succeed())
+	g.Expect(os.MkdirAll(filepath.Join(dir, "b"), 0755)).To(Succeed())
+	g.Expect(ioutil.WriteFile(filepath.Join(dir, "a", "metadata.yaml"), []byte("metadata"), 0600)).To(Succeed())
+	g.Expect(ioutil.WriteFile(filepath.Join(dir, "b", "metadata.yaml"), []byte("metadata"), 0600)).To(Succeed())
+
+	tests := []struct {
+		name    string
+		files   []string
+		wantErr string
+	}{
+		{
+			name:    "fails if a file does not exist",
+			files:   []string{filepath.Join(dir, "components.yaml")},
+			wantErr: "failed to read file",
+		},
+		{
+			name:    "fails if more files have the same name",
+			files:   []string{filepath.Join(dir, "a", "metadata.yaml"), filepath.Join(dir, "b", "metadata.yaml")},
+			wantErr: "more than one file",
+		},
+		{
+			name:    "fails if the url is not an OCI url",
+			files:   []string{filepath.Join(dir, "a", "metadata.yaml")},
+			wantErr: "an OCI url should start with oci://",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := newFakeClient(newFakeConfig())
+			err := c.PushProviderRelease(PushProviderReleaseOptions{
+				URL:   "https://registry.example.com/capi/infrastructure-aws/v0.6.4",
+				Files: tt.files,
+			})
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
@@ -44,6 +44,16 @@ type Client interface {
 
 	// Metadata provide access to YAML with the provider's metadata.
 	Metadata(version string) MetadataClient
+
+	// DefaultVersion returns the default provider version hosted in the provider repository.
+	DefaultVersion() string
+
+	// ComponentsPath returns the name of the YAML file for creating provider components.
+	ComponentsPath() string
+
+	// GetFile returns a file hosted in the provider repository for a given version, without any processing;
+	// this allows e.g. to copy a provider release into a bundle.
+	GetFile(version string, path string) ([]byte, error)
 }
 
 // repositoryClient implements Client.
@@ -61,6 +71,18 @@ func (c *repositoryClient) GetVersions() ([]string, error) {
 	return c.repository.GetVersions()
 }
 
+func (c *repositoryClient) DefaultVersion() string {
+	return c.repository.DefaultVersion()
+}
+
+func (c *repositoryClient) ComponentsPath() string {
+	return c.repository.ComponentsPath()
+}
+
+func (c *repositoryClient) GetFile(version string, path string) ([]byte, error) {
+	return c.repository.GetFile(version, path)
+}
+
 func (c *repositoryClient) Components() ComponentsClient {
 	return newComponentsClient(c.Provider, c.repository, c.configClient)
 }
@@ -167,6 +189,33 @@ func repositoryFactory(providerConfig config.Provider, configVariablesClient con
 		return repo, err
 	}
 
+	// if the url is a GitLab repository
+	if isGitLabRepositoryURL(rURL) {
+		repo, err := newGitLabRepository(providerConfig, configVariablesClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the GitLab repository client")
+		}
+		return repo, err
+	}
+
+	// if the url is a generic HTTP(S) repository
+	if rURL.Scheme == httpsScheme || rURL.Scheme == httpScheme {
+		repo, err := newHTTPRepository(providerConfig, configVariablesClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the HTTP repository client")
+		}
+		return repo, err
+	}
+
+	// if the url is an OCI repository
+	if rURL.Scheme == ociScheme {
+		repo, err := newOCIRepository(providerConfig, configVariablesClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the OCI repository client")
+		}
+		return repo, err
+	}
+
 	// if the url is a local filesystem repository
 	if rURL.Scheme == "file" || rURL.Scheme == "" {
 		repo, err := newLocalRepository(providerConfig, configVariablesClient)
@@ -0,0 +1,378 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociConfigMediaType   = "application/vnd.oci.image.config.v1+json"
+	ociLayerMediaType    = "application/vnd.cluster.x-k8s.io.provider.file.v1+yaml"
+
+	// ociTitleAnnotation is the annotation hosting the name of the file stored in a layer.
+	ociTitleAnnotation = "org.opencontainers.image.title"
+)
+
+// ociDescriptor describes the content of a blob stored in an OCI registry.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is an OCI image manifest; clusterctl uses a layer for each file of a provider release.
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// ociRegistryClient implements the subset of the OCI distribution API used by clusterctl for
+// pulling and pushing provider releases, including basic and token authentication.
+type ociRegistryClient struct {
+	httpClient *http.Client
+	host       string
+	username   string
+	password   string
+	plainHTTP  bool
+
+	// authorizations caches the Authorization header to be used for each scope.
+	authorizations map[string]string
+}
+
+func newOCIRegistryClient(httpClient *http.Client, host, username, password string, plainHTTP bool) *ociRegistryClient {
+	return &ociRegistryClient{
+		httpClient:     httpClient,
+		host:           host,
+		username:       username,
+		password:       password,
+		plainHTTP:      plainHTTP,
+		authorizations: map[string]string{},
+	}
+}
+
+// ociRequest defines a request to the registry.
+type ociRequest struct {
+	method      string
+	url         string
+	accept      string
+	contentType string
+	body        []byte
+	scope       string
+}
+
+// url returns the url of the registry API for the given repository, with the given path appended.
+func (c *ociRegistryClient) url(repository, path string) string {
+	scheme := "https"
+	if c.plainHTTP {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/%s", scheme, c.host, repository, path)
+}
+
+// do performs a request to the registry; if the registry requires authentication, the request is
+// authenticated according to the challenge returned by the registry and retried.
+func (c *ociRegistryClient) do(r ociRequest) (*http.Response, error) {
+	resp, err := c.send(r)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	authorization, err := c.authorize(challenge, r.scope)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to authenticate to registry %s", c.host)
+	}
+	c.authorizations[r.scope] = authorization
+
+	return c.send(r)
+}
+
+func (c *ociRegistryClient) send(r ociRequest) (*http.Response, error) {
+	req, err := http.NewRequest(r.method, r.url, bytes.NewReader(r.body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create request for %q", r.url)
+	}
+	if r.accept != "" {
+		req.Header.Set("Accept", r.accept)
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	if authorization, ok := c.authorizations[r.scope]; ok {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to %s %q", r.method, r.url)
+	}
+	return resp, nil
+}
+
+var ociChallengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authorize returns the Authorization header answering to a WWW-Authenticate challenge.
+func (c *ociRegistryClient) authorize(challenge, scope string) (string, error) {
+	switch {
+	case strings.HasPrefix(challenge, "Basic"):
+		if c.username == "" {
+			return "", errors.Errorf("the registry requires credentials, please set the %s and %s variables", config.OCIUsernameVariable, config.OCIPasswordVariable)
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(c.username, c.password)
+		return req.Header.Get("Authorization"), nil
+	case strings.HasPrefix(challenge, "Bearer"):
+		params := map[string]string{}
+		for _, m := range ociChallengeParamRegex.FindAllStringSubmatch(challenge, -1) {
+			params[m[1]] = m[2]
+		}
+		realm, err := url.Parse(params["realm"])
+		if err != nil || params["realm"] == "" {
+			return "", errors.Errorf("invalid realm in authentication challenge %q", challenge)
+		}
+		query := realm.Query()
+		if service, ok := params["service"]; ok {
+			query.Set("service", service)
+		}
+		query.Set("scope", scope)
+		realm.RawQuery = query.Encode()
+
+		req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to create token request")
+		}
+		if c.username != "" {
+			req.SetBasicAuth(c.username, c.password)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get token")
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", errors.Errorf("failed to get token: %s", resp.Status)
+		}
+
+		token := struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}{}
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			return "", errors.Wrapf(err, "failed to decode token")
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		return "Bearer " + token.Token, nil
+	}
+	return "", errors.Errorf("unsupported authentication challenge %q", challenge)
+}
+
+// checkResponse returns an error if the response status code is not the expected one; in this case the body is closed.
+func checkResponse(resp *http.Response, expected ...int) error {
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	return errors.Errorf("unexpected response %s from %s %s: %s", resp.Status, resp.Request.Method, resp.Request.URL, strings.TrimSpace(string(body)))
+}
+
+func pullScope(repository string) string {
+	return fmt.Sprintf("repository:%s:pull", repository)
+}
+
+func pushScope(repository string) string {
+	return fmt.Sprintf("repository:%s:pull,push", repository)
+}
+
+// listTags returns all the tags of a repository.
+func (c *ociRegistryClient) listTags(repository string) ([]string, error) {
+	tags := []string{}
+	next := c.url(repository, "tags/list")
+	for next != "" {
+		resp, err := c.do(ociRequest{method: http.MethodGet, url: next, scope: pullScope(repository)})
+		if err != nil {
+			return nil, err
+		}
+		if err := checkResponse(resp, http.StatusOK); err != nil {
+			return nil, err
+		}
+
+		list := struct {
+			Tags []string `json:"tags"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the list of tags")
+		}
+		tags = append(tags, list.Tags...)
+
+		next, err = nextPage(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// nextPage returns the url of the next page of results, as defined by the Link header; if there are no more pages, it returns an empty string.
+func nextPage(resp *http.Response) (string, error) {
+	link := resp.Header.Get("Link")
+	if link == "" || !strings.Contains(link, `rel="next"`) {
+		return "", nil
+	}
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end < start {
+		return "", errors.Errorf("invalid Link header %q", link)
+	}
+	next, err := resp.Request.URL.Parse(link[start+1 : end])
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid Link header %q", link)
+	}
+	return next.String(), nil
+}
+
+// getManifest returns the manifest for a tag.
+func (c *ociRegistryClient) getManifest(repository, tag string) (*ociManifest, error) {
+	resp, err := c.do(ociRequest{method: http.MethodGet, url: c.url(repository, "manifests/"+tag), accept: ociManifestMediaType, scope: pullScope(repository)})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	manifest := &ociManifest{}
+	if err := json.NewDecoder(resp.Body).Decode(manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the manifest for %s:%s", repository, tag)
+	}
+	return manifest, nil
+}
+
+// getBlob returns the content of a blob, verifying its digest.
+func (c *ociRegistryClient) getBlob(repository string, descriptor ociDescriptor) ([]byte, error) {
+	resp, err := c.do(ociRequest{method: http.MethodGet, url: c.url(repository, "blobs/"+descriptor.Digest), scope: pullScope(repository)})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read blob %s", descriptor.Digest)
+	}
+	if digest := ociDigest(content); digest != descriptor.Digest {
+		return nil, errors.Errorf("invalid digest for blob %s: got %s", descriptor.Digest, digest)
+	}
+	return content, nil
+}
+
+// pushBlob uploads a blob, unless it already exists in the repository, and returns its descriptor.
+func (c *ociRegistryClient) pushBlob(repository, mediaType string, content []byte) (ociDescriptor, error) {
+	descriptor := ociDescriptor{
+		MediaType: mediaType,
+		Digest:    ociDigest(content),
+		Size:      int64(len(content)),
+	}
+
+	resp, err := c.do(ociRequest{method: http.MethodHead, url: c.url(repository, "blobs/"+descriptor.Digest), scope: pushScope(repository)})
+	if err != nil {
+		return descriptor, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return descriptor, nil
+	}
+
+	resp, err = c.do(ociRequest{method: http.MethodPost, url: c.url(repository, "blobs/uploads/"), scope: pushScope(repository)})
+	if err != nil {
+		return descriptor, err
+	}
+	if err := checkResponse(resp, http.StatusAccepted); err != nil {
+		return descriptor, err
+	}
+	resp.Body.Close()
+
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return descriptor, errors.Wrapf(err, "invalid upload location %q", resp.Header.Get("Location"))
+	}
+	query := location.Query()
+	query.Set("digest", descriptor.Digest)
+	location.RawQuery = query.Encode()
+
+	resp, err = c.do(ociRequest{method: http.MethodPut, url: location.String(), contentType: "application/octet-stream", body: content, scope: pushScope(repository)})
+	if err != nil {
+		return descriptor, err
+	}
+	if err := checkResponse(resp, http.StatusCreated); err != nil {
+		return descriptor, err
+	}
+	resp.Body.Close()
+	return descriptor, nil
+}
+
+// pushManifest uploads a manifest for a tag.
+func (c *ociRegistryClient) pushManifest(repository, tag string, manifest *ociManifest) error {
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the manifest")
+	}
+
+	resp, err := c.do(ociRequest{method: http.MethodPut, url: c.url(repository, "manifests/"+tag), contentType: ociManifestMediaType, body: content, scope: pushScope(repository)})
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp, http.StatusCreated); err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ociDigest returns the sha256 digest of the content in the format used by OCI registries.
+func ociDigest(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
@@ -248,6 +248,12 @@ func (g *gitHubRepository) getLatestRelease() (string, error) {
 
 	// Search for the latest release according to semantic version ordering.
 	// Releases with tag name that are not in semver format are ignored.
+	return latestVersion(versions)
+}
+
+// latestVersion returns the latest version according to semantic version ordering, falling back to
+// the latest prerelease if no release has been cut; versions that are not in semver format are ignored.
+func latestVersion(versions []string) (string, error) {
 	var latestTag string
 	var latestPrereleaseTag string
 
@@ -547,4 +547,6 @@ func resetCaches() {
 	cacheVersions = map[string][]string{}
 	cacheReleases = map[string]*github.RepositoryRelease{}
 	cacheFiles = map[string][]byte{}
+	cacheGitLabReleases = map[string]*gitlabRelease{}
+	cacheOCIManifests = map[string]*ociManifest{}
 }
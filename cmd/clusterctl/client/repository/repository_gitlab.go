@@ -0,0 +1,327 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+const (
+	httpScheme              = "http"
+	gitlabDomain            = "gitlab.com"
+	gitlabReleaseSeparator  = "/-/releases/"
+	gitlabAPIPath           = "api/v4"
+	gitlabTokenHeader       = "PRIVATE-TOKEN"
+	gitlabReleasesPerPage   = 100
+	gitlabNextPageHeader    = "X-Next-Page"
+	gitlabLatestReleaseName = "latest"
+)
+
+var (
+	// Cache used to limit the number of GitLab API calls
+	cacheGitLabReleases = map[string]*gitlabRelease{}
+)
+
+// gitLabRepository provides support for providers hosted on GitLab, either on gitlab.com or on a self-hosted instance.
+//
+// We support GitLab repositories that use the release feature to publish artifacts and versions;
+// artifacts are expected to be linked to the release as assets with the same name of the file.
+type gitLabRepository struct {
+	providerConfig        config.Provider
+	configVariablesClient config.VariablesClient
+	httpClient            *http.Client
+	token                 string
+	scheme                string
+	host                  string
+	project               string
+	defaultVersion        string
+	rootPath              string
+	componentsPath        string
+}
+
+var _ Repository = &gitLabRepository{}
+
+// gitlabRelease is the subset of a GitLab release, as returned by the GitLab API, used by clusterctl.
+type gitlabRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  struct {
+		Links []gitlabReleaseLink `json:"links"`
+	} `json:"assets"`
+}
+
+// gitlabReleaseLink is a link to a release asset, as returned by the GitLab API.
+type gitlabReleaseLink struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+// DefaultVersion returns defaultVersion field of gitLabRepository struct
+func (g *gitLabRepository) DefaultVersion() string {
+	return g.defaultVersion
+}
+
+// GetVersions returns the list of versions that are available in a provider repository
+func (g *gitLabRepository) GetVersions() ([]string, error) {
+	versions, err := g.getVersions()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get repository versions")
+	}
+	return versions, nil
+}
+
+// RootPath returns rootPath field of gitLabRepository struct
+func (g *gitLabRepository) RootPath() string {
+	return g.rootPath
+}
+
+// ComponentsPath returns componentsPath field of gitLabRepository struct
+func (g *gitLabRepository) ComponentsPath() string {
+	return g.componentsPath
+}
+
+// GetFile returns a file for a given provider version
+func (g *gitLabRepository) GetFile(version, path string) ([]byte, error) {
+	release, err := g.getReleaseByTag(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get GitLab release %s", version)
+	}
+
+	// download files from the release
+	files, err := g.downloadFilesFromRelease(release, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download files from GitLab release %s", version)
+	}
+
+	return files, nil
+}
+
+// isGitLabRepositoryURL returns true if the url points to a GitLab release.
+func isGitLabRepositoryURL(rURL *url.URL) bool {
+	if rURL.Scheme != httpsScheme && rURL.Scheme != httpScheme {
+		return false
+	}
+	return rURL.Host == gitlabDomain || strings.Contains(rURL.Path, gitlabReleaseSeparator)
+}
+
+// newGitLabRepository returns a gitLabRepository implementation
+func newGitLabRepository(providerConfig config.Provider, configVariablesClient config.VariablesClient) (*gitLabRepository, error) {
+	if configVariablesClient == nil {
+		return nil, errors.New("invalid arguments: configVariablesClient can't be nil")
+	}
+
+	rURL, err := url.Parse(providerConfig.URL())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+
+	// Check if the url is a GitLab release url, and split it in the project path and the release path.
+	urlSplit := strings.SplitN(rURL.Path, gitlabReleaseSeparator, 2)
+	if !isGitLabRepositoryURL(rURL) || len(urlSplit) != 2 {
+		return nil, errors.New("invalid url: a GitLab repository url should be in the form https://{host}/{group}/{project}/-/releases/{latest|version-tag}/{componentsClient.yaml}")
+	}
+
+	project := strings.Trim(urlSplit[0], "/")
+	releaseSplit := strings.Split(urlSplit[1], "/")
+	if project == "" || len(releaseSplit) < 2 {
+		return nil, errors.New("invalid url: a GitLab repository url should be in the form https://{host}/{group}/{project}/-/releases/{latest|version-tag}/{componentsClient.yaml}")
+	}
+
+	// Extract all the info from the release path.
+	defaultVersion := releaseSplit[0]
+	path := strings.Join(releaseSplit[1:], "/")
+
+	// use path's directory as a rootPath
+	rootPath := filepath.Dir(path)
+	// use the file name (if any) as componentsPath
+	componentsPath := getComponentsPath(path, rootPath)
+
+	repo := &gitLabRepository{
+		providerConfig:        providerConfig,
+		configVariablesClient: configVariablesClient,
+		httpClient:            http.DefaultClient,
+		scheme:                rURL.Scheme,
+		host:                  rURL.Host,
+		project:               project,
+		defaultVersion:        defaultVersion,
+		rootPath:              rootPath,
+		componentsPath:        componentsPath,
+	}
+
+	if token, err := configVariablesClient.Get(config.GitLabTokenVariable); err == nil {
+		repo.token = token
+	}
+
+	if defaultVersion == gitlabLatestReleaseName {
+		repo.defaultVersion, err = repo.getLatestRelease()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get GitLab latest version")
+		}
+	}
+
+	return repo, nil
+}
+
+// projectAPIURL returns the url of the GitLab API for the project, with the given path appended.
+func (g *gitLabRepository) projectAPIURL(path string) string {
+	// The project path must be URL encoded, including the slashes separating groups and project.
+	project := strings.ReplaceAll(url.PathEscape(g.project), "/", "%2F")
+	return fmt.Sprintf("%s://%s/%s/projects/%s/%s", g.scheme, g.host, gitlabAPIPath, project, path)
+}
+
+// get performs a GET request, authenticating with the GitLab token if the request targets the GitLab host.
+func (g *gitLabRepository) get(rawURL string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create request for %q", rawURL)
+	}
+	// Never send the token to hosts other than the GitLab one, e.g. when release assets link to external storage.
+	if g.token != "" && req.URL.Host == g.host {
+		req.Header.Set(gitlabTokenHeader, g.token)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q", rawURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("failed to get %q: %s", rawURL, resp.Status)
+	}
+	return resp, nil
+}
+
+// getJSON performs a GET request and decodes the JSON response into out; it returns the response headers.
+func (g *gitLabRepository) getJSON(rawURL string, out interface{}) (http.Header, error) {
+	resp, err := g.get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the response from %q", rawURL)
+	}
+	return resp.Header, nil
+}
+
+// getVersions returns all the release versions for a GitLab project
+func (g *gitLabRepository) getVersions() ([]string, error) {
+	cacheID := fmt.Sprintf("%s/%s", g.host, g.project)
+	if versions, ok := cacheVersions[cacheID]; ok {
+		return versions, nil
+	}
+
+	versions := []string{}
+	page := "1"
+	for page != "" {
+		releases := []gitlabRelease{}
+		header, err := g.getJSON(g.projectAPIURL(fmt.Sprintf("releases?per_page=%d&page=%s", gitlabReleasesPerPage, page)), &releases)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the list of releases")
+		}
+		for _, r := range releases {
+			if _, err := version.ParseSemantic(r.TagName); err != nil {
+				// Discard releases with tags that are not a valid semantic versions (the user can point explicitly to such releases).
+				continue
+			}
+			versions = append(versions, r.TagName)
+		}
+		page = header.Get(gitlabNextPageHeader)
+	}
+
+	cacheVersions[cacheID] = versions
+	return versions, nil
+}
+
+// getLatestRelease returns the latest release for a GitLab project, according to
+// semantic version order of the release tag name.
+func (g *gitLabRepository) getLatestRelease() (string, error) {
+	versions, err := g.getVersions()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the list of versions")
+	}
+
+	return latestVersion(versions)
+}
+
+// getReleaseByTag returns the GitLab release with a specific tag name.
+func (g *gitLabRepository) getReleaseByTag(tag string) (*gitlabRelease, error) {
+	cacheID := fmt.Sprintf("%s/%s:%s", g.host, g.project, tag)
+	if release, ok := cacheGitLabReleases[cacheID]; ok {
+		return release, nil
+	}
+
+	release := &gitlabRelease{}
+	if _, err := g.getJSON(g.projectAPIURL("releases/"+url.PathEscape(tag)), release); err != nil {
+		return nil, errors.Wrapf(err, "failed to read release %q", tag)
+	}
+
+	cacheGitLabReleases[cacheID] = release
+	return release, nil
+}
+
+// downloadFilesFromRelease download a file from release.
+func (g *gitLabRepository) downloadFilesFromRelease(release *gitlabRelease, fileName string) ([]byte, error) {
+	cacheID := fmt.Sprintf("%s/%s:%s:%s", g.host, g.project, release.TagName, fileName)
+	if content, ok := cacheFiles[cacheID]; ok {
+		return content, nil
+	}
+
+	absoluteFileName := filepath.Join(g.rootPath, fileName)
+
+	// search for the file into the release assets, retrieving the link to download it
+	var link *gitlabReleaseLink
+	for i := range release.Assets.Links {
+		if release.Assets.Links[i].Name == absoluteFileName {
+			link = &release.Assets.Links[i]
+			break
+		}
+	}
+	if link == nil {
+		return nil, errors.Errorf("failed to get file %q from %q release", fileName, release.TagName)
+	}
+
+	linkURL := link.DirectAssetURL
+	if linkURL == "" {
+		linkURL = link.URL
+	}
+
+	resp, err := g.get(linkURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download file %q from %q release", fileName, release.TagName)
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read downloaded file %q from %q release", fileName, release.TagName)
+	}
+
+	cacheFiles[cacheID] = content
+	return content, nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_gitLabRepository_newGitLabRepository(t *testing.T) {
+	tests := []struct {
+		name               string
+		url                string
+		wantProject        string
+		wantDefaultVersion string
+		wantRootPath       string
+		wantComponentsPath string
+		wantErr            bool
+	}{
+		{
+			name:               "can create a new GitLab repo",
+			url:                "https://gitlab.example.com/group/project/-/releases/v0.4.1/path",
+			wantProject:        "group/project",
+			wantDefaultVersion: "v0.4.1",
+			wantRootPath:       ".",
+			wantComponentsPath: "path",
+			wantErr:            false,
+		},
+		{
+			name:               "can create a new GitLab repo with nested groups and folders",
+			url:                "https://gitlab.example.com/group/subgroup/project/-/releases/v0.4.1/folder/path",
+			wantProject:        "group/subgroup/project",
+			wantDefaultVersion: "v0.4.1",
+			wantRootPath:       "folder",
+			wantComponentsPath: "path",
+			wantErr:            false,
+		},
+		{
+			name:    "url is not a GitLab release",
+			url:     "https://gitlab.com/group/project/v0.4.1/path",
+			wantErr: true,
+		},
+		{
+			name:    "url does not have the project",
+			url:     "https://gitlab.example.com/-/releases/v0.4.1/path",
+			wantErr: true,
+		},
+		{
+			name:    "url does not have the components file",
+			url:     "https://gitlab.example.com/group/project/-/releases/v0.4.1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			providerConfig := config.NewProvider("test", tt.url, clusterctlv1.CoreProviderType)
+			gitLab, err := newGitLabRepository(providerConfig, test.NewFakeVariableClient())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(gitLab.project).To(Equal(tt.wantProject))
+			g.Expect(gitLab.DefaultVersion()).To(Equal(tt.wantDefaultVersion))
+			g.Expect(gitLab.RootPath()).To(Equal(tt.wantRootPath))
+			g.Expect(gitLab.ComponentsPath()).To(Equal(tt.wantComponentsPath))
+		})
+	}
+}
+
+func Test_gitLabRepository_getVersionsAndLatest(t *testing.T) {
+	g := NewWithT(t)
+	resetCaches()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// the project path is URL encoded, so the handler checks the escaped path
+	mux.HandleFunc("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		g.Expect(r.URL.EscapedPath()).To(Equal("/api/v4/projects/group%2Fproject/releases"))
+		g.Expect(r.Header.Get(gitlabTokenHeader)).To(Equal("token"))
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set(gitlabNextPageHeader, "2")
+			fmt.Fprint(w, `[{"tag_name": "v0.4.0"}, {"tag_name": "foo"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"tag_name": "v0.4.1"}, {"tag_name": "v0.5.0-alpha.0"}]`)
+	})
+
+	providerConfig := config.NewProvider("test", server.URL+"/group/project/-/releases/latest/file.yaml", clusterctlv1.CoreProviderType)
+	gitLab, err := newGitLabRepository(providerConfig, test.NewFakeVariableClient().WithVar(config.GitLabTokenVariable, "token"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gitLab.DefaultVersion()).To(Equal("v0.4.1"))
+
+	versions, err := gitLab.GetVersions()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(versions).To(ConsistOf("v0.4.0", "v0.4.1", "v0.5.0-alpha.0"))
+}
+
+func Test_gitLabRepository_getFile(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// handler for returning a fake release; the project path is URL encoded, so the handler checks the escaped path
+	mux.HandleFunc("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/releases/v0.4.1" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"tag_name": "v0.4.1", "assets": {"links": [{"name": "file.yaml", "url": "%s/downloads/file.yaml"}]}}`, server.URL)
+	})
+
+	// handler for returning a fake release asset
+	mux.HandleFunc("/downloads/file.yaml", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, "content")
+	})
+
+	providerConfig := config.NewProvider("test", server.URL+"/group/project/-/releases/v0.4.1/file.yaml", clusterctlv1.CoreProviderType)
+
+	tests := []struct {
+		name     string
+		release  string
+		fileName string
+		want     []byte
+		wantErr  bool
+	}{
+		{
+			name:     "Release and file exist",
+			release:  "v0.4.1",
+			fileName: "file.yaml",
+			want:     []byte("content"),
+			wantErr:  false,
+		},
+		{
+			name:     "Release does not exist",
+			release:  "not-a-release",
+			fileName: "file.yaml",
+			want:     nil,
+			wantErr:  true,
+		},
+		{
+			name:     "File does not exist",
+			release:  "v0.4.1",
+			fileName: "404.file",
+			want:     nil,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			gitLab, err := newGitLabRepository(providerConfig, test.NewFakeVariableClient())
+			g.Expect(err).NotTo(HaveOccurred())
+
+			got, err := gitLab.GetFile(tt.release, tt.fileName)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	httpVersionsIndexFile = "versions.yaml"
+	httpLatestVersionName = "latest"
+)
+
+// httpRepository provides support for providers hosted on a generic HTTP(S) server, e.g. an Artifactory mirror.
+//
+// We support HTTP repositories with a directory for each version of the provider, hosting all the files
+// for that version, and a versions index file listing all the versions available, e.g.
+//
+//	https://{host}/{path}/versions.yaml
+//	https://{host}/{path}/v0.3.0/infrastructure-components.yaml
+//	https://{host}/{path}/v0.3.0/metadata.yaml
+//
+// where the versions index file is in the form:
+//
+//	versions:
+//	- v0.3.0
+type httpRepository struct {
+	providerConfig        config.Provider
+	configVariablesClient config.VariablesClient
+	httpClient            *http.Client
+	baseURL               string
+	defaultVersion        string
+	rootPath              string
+	componentsPath        string
+}
+
+var _ Repository = &httpRepository{}
+
+// httpVersionsIndex is the content of the versions index file of an HTTP repository.
+type httpVersionsIndex struct {
+	Versions []string `json:"versions"`
+}
+
+// DefaultVersion returns defaultVersion field of httpRepository struct
+func (h *httpRepository) DefaultVersion() string {
+	return h.defaultVersion
+}
+
+// GetVersions returns the list of versions that are available in a provider repository
+func (h *httpRepository) GetVersions() ([]string, error) {
+	versions, err := h.getVersions()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get repository versions")
+	}
+	return versions, nil
+}
+
+// RootPath returns rootPath field of httpRepository struct
+func (h *httpRepository) RootPath() string {
+	return h.rootPath
+}
+
+// ComponentsPath returns componentsPath field of httpRepository struct
+func (h *httpRepository) ComponentsPath() string {
+	return h.componentsPath
+}
+
+// GetFile returns a file for a given provider version
+func (h *httpRepository) GetFile(version, fileName string) ([]byte, error) {
+	cacheID := fmt.Sprintf("%s:%s:%s", h.baseURL, version, fileName)
+	if content, ok := cacheFiles[cacheID]; ok {
+		return content, nil
+	}
+
+	content, err := h.download(fmt.Sprintf("%s/%s/%s", h.baseURL, version, path.Join(h.rootPath, fileName)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download file %q for version %s", fileName, version)
+	}
+
+	cacheFiles[cacheID] = content
+	return content, nil
+}
+
+// newHTTPRepository returns a httpRepository implementation
+func newHTTPRepository(providerConfig config.Provider, configVariablesClient config.VariablesClient) (*httpRepository, error) {
+	if configVariablesClient == nil {
+		return nil, errors.New("invalid arguments: configVariablesClient can't be nil")
+	}
+
+	rURL, err := url.Parse(providerConfig.URL())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+
+	if rURL.Scheme != httpsScheme && rURL.Scheme != httpScheme {
+		return nil, errors.New("invalid url: an HTTP repository url should start with http:// or https://")
+	}
+
+	// The last two segments of the url path are the version and the components file name.
+	urlSplit := strings.Split(strings.Trim(rURL.Path, "/"), "/")
+	if len(urlSplit) < 2 || urlSplit[len(urlSplit)-2] == "" || urlSplit[len(urlSplit)-1] == "" {
+		return nil, errors.New("invalid url: an HTTP repository url should be in the form https://{host}/{path}/{latest|version}/{componentsClient.yaml}")
+	}
+
+	defaultVersion := urlSplit[len(urlSplit)-2]
+	componentsPath := urlSplit[len(urlSplit)-1]
+
+	baseURL := *rURL
+	baseURL.Path = "/" + strings.Join(urlSplit[:len(urlSplit)-2], "/")
+	baseURL.RawQuery = ""
+	baseURL.Fragment = ""
+
+	repo := &httpRepository{
+		providerConfig:        providerConfig,
+		configVariablesClient: configVariablesClient,
+		httpClient:            http.DefaultClient,
+		baseURL:               strings.TrimSuffix(baseURL.String(), "/"),
+		defaultVersion:        defaultVersion,
+		rootPath:              ".",
+		componentsPath:        componentsPath,
+	}
+
+	if defaultVersion == httpLatestVersionName {
+		repo.defaultVersion, err = repo.getLatestVersion()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get latest version")
+		}
+	}
+
+	return repo, nil
+}
+
+// download returns the content of the file at the given url.
+func (h *httpRepository) download(fileURL string) ([]byte, error) {
+	resp, err := h.httpClient.Get(fileURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q", fileURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get %q: %s", fileURL, resp.Status)
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q", fileURL)
+	}
+	return content, nil
+}
+
+// getVersions returns all the versions listed in the versions index file of the repository.
+func (h *httpRepository) getVersions() ([]string, error) {
+	if versions, ok := cacheVersions[h.baseURL]; ok {
+		return versions, nil
+	}
+
+	content, err := h.download(fmt.Sprintf("%s/%s", h.baseURL, httpVersionsIndexFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the versions index file")
+	}
+
+	index := &httpVersionsIndex{}
+	if err := yaml.Unmarshal(content, index); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the versions index file")
+	}
+
+	versions := []string{}
+	for _, v := range index.Versions {
+		if _, err := version.ParseSemantic(v); err != nil {
+			// Discard versions that are not valid semantic versions (the user can point explicitly to such versions).
+			continue
+		}
+		versions = append(versions, v)
+	}
+
+	cacheVersions[h.baseURL] = versions
+	return versions, nil
+}
+
+// getLatestVersion returns the latest version listed in the versions index file, according to
+// semantic version order.
+func (h *httpRepository) getLatestVersion() (string, error) {
+	versions, err := h.getVersions()
+	if err != nil {
+		return "", err
+	}
+
+	return latestVersion(versions)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_httpRepository_newHTTPRepository(t *testing.T) {
+	tests := []struct {
+		name               string
+		url                string
+		wantBaseURL        string
+		wantDefaultVersion string
+		wantComponentsPath string
+		wantErr            bool
+	}{
+		{
+			name:               "can create a new HTTP repo",
+			url:                "https://artifacts.example.com/providers/infra/v0.4.1/infrastructure-components.yaml",
+			wantBaseURL:        "https://artifacts.example.com/providers/infra",
+			wantDefaultVersion: "v0.4.1",
+			wantComponentsPath: "infrastructure-components.yaml",
+			wantErr:            false,
+		},
+		{
+			name:               "can create a new HTTP repo at the server root",
+			url:                "http://artifacts.example.com/v0.4.1/infrastructure-components.yaml",
+			wantBaseURL:        "http://artifacts.example.com",
+			wantDefaultVersion: "v0.4.1",
+			wantComponentsPath: "infrastructure-components.yaml",
+			wantErr:            false,
+		},
+		{
+			name:    "url does not have the version",
+			url:     "https://artifacts.example.com/infrastructure-components.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "url is not HTTP",
+			url:     "ftp://artifacts.example.com/v0.4.1/infrastructure-components.yaml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			providerConfig := config.NewProvider("test", tt.url, clusterctlv1.CoreProviderType)
+			repo, err := newHTTPRepository(providerConfig, test.NewFakeVariableClient())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(repo.baseURL).To(Equal(tt.wantBaseURL))
+			g.Expect(repo.DefaultVersion()).To(Equal(tt.wantDefaultVersion))
+			g.Expect(repo.RootPath()).To(Equal("."))
+			g.Expect(repo.ComponentsPath()).To(Equal(tt.wantComponentsPath))
+		})
+	}
+}
+
+func Test_httpRepository_getVersionsAndFile(t *testing.T) {
+	g := NewWithT(t)
+	resetCaches()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/providers/infra/versions.yaml", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, "versions:\n- v0.4.0\n- v0.4.1\n- foo\n")
+	})
+	mux.HandleFunc("/providers/infra/v0.4.1/metadata.yaml", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, "content")
+	})
+
+	providerConfig := config.NewProvider("test", server.URL+"/providers/infra/latest/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType)
+	repo, err := newHTTPRepository(providerConfig, test.NewFakeVariableClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(repo.DefaultVersion()).To(Equal("v0.4.1"))
+
+	versions, err := repo.GetVersions()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(versions).To(ConsistOf("v0.4.0", "v0.4.1"))
+
+	content, err := repo.GetFile("v0.4.1", "metadata.yaml")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(content).To(Equal([]byte("content")))
+
+	_, err = repo.GetFile("v0.4.1", "404.yaml")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+const (
+	ociScheme             = "oci"
+	ociLatestVersionLabel = "latest"
+)
+
+var (
+	// Cache used to limit the number of OCI registry calls
+
+	cacheOCIManifests = map[string]*ociManifest{}
+)
+
+// ociRepository provides support for providers hosted on an OCI registry.
+//
+// We support OCI repositories where each provider release is an OCI artifact tagged with the release version;
+// each file of the release, e.g. the components YAML, the metadata YAML and the cluster templates, is stored
+// in a layer of the artifact annotated with the file name.
+type ociRepository struct {
+	providerConfig        config.Provider
+	configVariablesClient config.VariablesClient
+	registry              *ociRegistryClient
+	repository            string
+	defaultVersion        string
+	rootPath              string
+	componentsPath        string
+}
+
+var _ Repository = &ociRepository{}
+
+// ociOptions defines the options for interacting with an OCI registry.
+type ociOptions struct {
+	httpClient *http.Client
+}
+
+// OCIOption is a configuration option for interacting with an OCI registry.
+type OCIOption func(*ociOptions)
+
+// InjectOCIHTTPClient allows to override the http client used for interacting with the OCI registry;
+// when set, the CA file and the insecure skip verify settings defined in the clusterctl config are ignored.
+func InjectOCIHTTPClient(c *http.Client) OCIOption {
+	return func(o *ociOptions) {
+		o.httpClient = c
+	}
+}
+
+// DefaultVersion returns defaultVersion field of ociRepository struct
+func (o *ociRepository) DefaultVersion() string {
+	return o.defaultVersion
+}
+
+// GetVersions returns the list of versions that are available in a provider repository
+func (o *ociRepository) GetVersions() ([]string, error) {
+	versions, err := o.getVersions()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get repository versions")
+	}
+	return versions, nil
+}
+
+// RootPath returns rootPath field of ociRepository struct
+func (o *ociRepository) RootPath() string {
+	return o.rootPath
+}
+
+// ComponentsPath returns componentsPath field of ociRepository struct
+func (o *ociRepository) ComponentsPath() string {
+	return o.componentsPath
+}
+
+// GetFile returns a file for a given provider version
+func (o *ociRepository) GetFile(version, path string) ([]byte, error) {
+	cacheID := fmt.Sprintf("%s/%s:%s:%s", o.registry.host, o.repository, version, path)
+	if content, ok := cacheFiles[cacheID]; ok {
+		return content, nil
+	}
+
+	manifest, err := o.getManifest(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get OCI artifact %s", version)
+	}
+
+	// search for the file into the artifact layers
+	absoluteFileName := filepath.Join(o.rootPath, path)
+	var layer *ociDescriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].Annotations[ociTitleAnnotation] == absoluteFileName {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return nil, errors.Errorf("failed to get file %q from OCI artifact %s", path, version)
+	}
+
+	content, err := o.registry.getBlob(o.repository, *layer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download file %q from OCI artifact %s", path, version)
+	}
+
+	cacheFiles[cacheID] = content
+	return content, nil
+}
+
+// parseOCIURL splits an OCI url in the form oci://{registry}/{repository}/{segments...}, returning
+// the registry host, the repository and the last n segments of the path.
+func parseOCIURL(rawURL string, n int) (string, string, []string, error) {
+	rURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", nil, errors.Wrap(err, "invalid url")
+	}
+	if rURL.Scheme != ociScheme {
+		return "", "", nil, errors.Errorf("invalid url: an OCI url should start with %s://", ociScheme)
+	}
+
+	urlSplit := strings.Split(strings.Trim(rURL.Path, "/"), "/")
+	if rURL.Host == "" || len(urlSplit) < n+1 {
+		return "", "", nil, errors.New("invalid url: missing registry or repository")
+	}
+	for _, s := range urlSplit {
+		if s == "" {
+			return "", "", nil, errors.New("invalid url: empty path segment")
+		}
+	}
+
+	return rURL.Host, strings.Join(urlSplit[:len(urlSplit)-n], "/"), urlSplit[len(urlSplit)-n:], nil
+}
+
+// newOCIRegistryClientFor returns a client for the registry, using the connection settings and the credentials defined in the clusterctl config.
+func newOCIRegistryClientFor(host string, configVariablesClient config.VariablesClient, opts ...OCIOption) (*ociRegistryClient, error) {
+	options := &ociOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	plainHTTP, err := getOCIBoolVariable(configVariablesClient, config.OCIPlainHTTPVariable)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := options.httpClient
+	if httpClient == nil {
+		httpClient, err = newOCIHTTPClient(configVariablesClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	username, _ := configVariablesClient.Get(config.OCIUsernameVariable)
+	password, _ := configVariablesClient.Get(config.OCIPasswordVariable)
+	return newOCIRegistryClient(httpClient, host, username, password, plainHTTP), nil
+}
+
+// newOCIHTTPClient returns the http client for connecting to OCI registries, trusting the CA certificates
+// in the CA file defined in the clusterctl config, if any, in addition to the system ones.
+func newOCIHTTPClient(configVariablesClient config.VariablesClient) (*http.Client, error) {
+	insecureSkipVerify, err := getOCIBoolVariable(configVariablesClient, config.OCIInsecureSkipVerifyVariable)
+	if err != nil {
+		return nil, err
+	}
+	caFile, _ := configVariablesClient.Get(config.OCICAFileVariable)
+	if caFile == "" && !insecureSkipVerify {
+		return http.DefaultClient, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify, //nolint:gosec
+	}
+	if caFile != "" {
+		ca, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the %s file %q", config.OCICAFileVariable, caFile)
+		}
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(ca) {
+			return nil, errors.Errorf("invalid %s file %q: no PEM encoded certificates found", config.OCICAFileVariable, caFile)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
+
+// getOCIBoolVariable returns the value of a boolean variable defined in the clusterctl config; if the variable is not defined, it returns false.
+func getOCIBoolVariable(configVariablesClient config.VariablesClient, key string) (bool, error) {
+	value, err := configVariablesClient.Get(key)
+	if err != nil || value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Errorf("invalid value %q for the %s variable: it should be true or false", value, key)
+	}
+	return b, nil
+}
+
+// newOCIRepository returns an ociRepository implementation
+func newOCIRepository(providerConfig config.Provider, configVariablesClient config.VariablesClient, opts ...OCIOption) (*ociRepository, error) {
+	if configVariablesClient == nil {
+		return nil, errors.New("invalid arguments: configVariablesClient can't be nil")
+	}
+
+	host, repository, segments, err := parseOCIURL(providerConfig.URL(), 2)
+	if err != nil {
+		return nil, errors.Wrap(err, "an OCI repository url should be in the form oci://{registry}/{repository}/{latest|version}/{componentsClient.yaml}")
+	}
+
+	registry, err := newOCIRegistryClientFor(host, configVariablesClient, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &ociRepository{
+		providerConfig:        providerConfig,
+		configVariablesClient: configVariablesClient,
+		registry:              registry,
+		repository:            repository,
+		defaultVersion:        segments[0],
+		rootPath:              ".",
+		componentsPath:        segments[1],
+	}
+
+	if repo.defaultVersion == ociLatestVersionLabel {
+		repo.defaultVersion, err = repo.getLatestVersion()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get OCI latest version")
+		}
+	}
+
+	return repo, nil
+}
+
+// getVersions returns all the tags of the OCI repository which are valid semantic versions.
+func (o *ociRepository) getVersions() ([]string, error) {
+	cacheID := fmt.Sprintf("%s/%s", o.registry.host, o.repository)
+	if versions, ok := cacheVersions[cacheID]; ok {
+		return versions, nil
+	}
+
+	tags, err := o.registry.listTags(o.repository)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the list of tags")
+	}
+
+	versions := []string{}
+	for _, tag := range tags {
+		if _, err := version.ParseSemantic(tag); err != nil {
+			// Discard tags that are not valid semantic versions (the user can point explicitly to such tags).
+			continue
+		}
+		versions = append(versions, tag)
+	}
+
+	cacheVersions[cacheID] = versions
+	return versions, nil
+}
+
+// getLatestVersion returns the latest version hosted in the OCI repository, according to
+// semantic version order of the tags.
+func (o *ociRepository) getLatestVersion() (string, error) {
+	versions, err := o.getVersions()
+	if err != nil {
+		return "", err
+	}
+
+	return latestVersion(versions)
+}
+
+// getManifest returns the manifest of the OCI artifact for a version.
+func (o *ociRepository) getManifest(version string) (*ociManifest, error) {
+	cacheID := fmt.Sprintf("%s/%s:%s", o.registry.host, o.repository, version)
+	if manifest, ok := cacheOCIManifests[cacheID]; ok {
+		return manifest, nil
+	}
+
+	manifest, err := o.registry.getManifest(o.repository, version)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheOCIManifests[cacheID] = manifest
+	return manifest, nil
+}
+
+// PushOCIRelease pushes the files of a provider release to an OCI registry as an OCI artifact, with a layer for
+// each file annotated with the file name; the release url must be in the form oci://{registry}/{repository}/{version}.
+func PushOCIRelease(releaseURL string, files map[string][]byte, configVariablesClient config.VariablesClient, opts ...OCIOption) error {
+	if configVariablesClient == nil {
+		return errors.New("invalid arguments: configVariablesClient can't be nil")
+	}
+	if len(files) == 0 {
+		return errors.New("invalid arguments: at least one file must be pushed")
+	}
+
+	host, repository, segments, err := parseOCIURL(releaseURL, 1)
+	if err != nil {
+		return errors.Wrap(err, "an OCI release url should be in the form oci://{registry}/{repository}/{version}")
+	}
+	releaseVersion := segments[0]
+	if _, err := version.ParseSemantic(releaseVersion); err != nil {
+		return errors.Errorf("invalid version %q: the version of a release must be a valid semantic version", releaseVersion)
+	}
+
+	registry, err := newOCIRegistryClientFor(host, configVariablesClient, opts...)
+	if err != nil {
+		return err
+	}
+
+	configDescriptor, err := registry.pushBlob(repository, ociConfigMediaType, []byte("{}"))
+	if err != nil {
+		return errors.Wrap(err, "failed to push the OCI artifact config")
+	}
+
+	// Push files in a stable order, so pushing the same release twice results in the same manifest.
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifest := &ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociManifestMediaType,
+		Config:        configDescriptor,
+		Layers:        []ociDescriptor{},
+	}
+	for _, name := range names {
+		layer, err := registry.pushBlob(repository, ociLayerMediaType, files[name])
+		if err != nil {
+			return errors.Wrapf(err, "failed to push file %q", name)
+		}
+		layer.Annotations = map[string]string{ociTitleAnnotation: name}
+		manifest.Layers = append(manifest.Layers, layer)
+	}
+
+	if err := registry.pushManifest(repository, releaseVersion, manifest); err != nil {
+		return errors.Wrapf(err, "failed to push the OCI artifact manifest for %s", releaseVersion)
+	}
+	return nil
+}
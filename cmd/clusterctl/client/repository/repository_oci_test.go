@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_ociRepository_parseURL(t *testing.T) {
+	tests := []struct {
+		name               string
+		url                string
+		wantHost           string
+		wantRepository     string
+		wantDefaultVersion string
+		wantComponentsPath string
+		wantErr            bool
+	}{
+		{
+			name:               "can parse an OCI url",
+			url:                "oci://registry.example.com:5000/capi/infrastructure-aws/v0.6.4/infrastructure-components.yaml",
+			wantHost:           "registry.example.com:5000",
+			wantRepository:     "capi/infrastructure-aws",
+			wantDefaultVersion: "v0.6.4",
+			wantComponentsPath: "infrastructure-components.yaml",
+			wantErr:            false,
+		},
+		{
+			name:    "url without repository",
+			url:     "oci://registry.example.com/v0.6.4/infrastructure-components.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "url with the wrong scheme",
+			url:     "https://registry.example.com/capi/v0.6.4/infrastructure-components.yaml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			host, repository, segments, err := parseOCIURL(tt.url, 2)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(host).To(Equal(tt.wantHost))
+			g.Expect(repository).To(Equal(tt.wantRepository))
+			g.Expect(segments).To(Equal([]string{tt.wantDefaultVersion, tt.wantComponentsPath}))
+		})
+	}
+}
+
+func Test_ociRepository_pushAndPull(t *testing.T) {
+	registry := test.NewFakeOCIRegistry("user", "password")
+	defer registry.Close()
+
+	configVariablesClient := test.NewFakeVariableClient().
+		WithVar(config.OCIUsernameVariable, "user").
+		WithVar(config.OCIPasswordVariable, "password")
+
+	release := func(version string) map[string][]byte {
+		return map[string][]byte{
+			"infrastructure-components.yaml": []byte("components " + version),
+			"metadata.yaml":                  []byte("metadata " + version),
+		}
+	}
+
+	t.Run("push releases", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, version := range []string{"v0.6.3", "v0.6.4", "v0.7.0-alpha.0"} {
+			url := "oci://" + registry.Host() + "/capi/infrastructure-aws/" + version
+			g.Expect(PushOCIRelease(url, release(version), configVariablesClient, InjectOCIHTTPClient(registry.Client()))).To(Succeed())
+		}
+		g.Expect(registry.Tags("capi/infrastructure-aws")).To(ConsistOf("v0.6.3", "v0.6.4", "v0.7.0-alpha.0"))
+	})
+
+	t.Run("pull the latest release", func(t *testing.T) {
+		g := NewWithT(t)
+		resetCaches()
+
+		providerConfig := config.NewProvider("aws", "oci://"+registry.Host()+"/capi/infrastructure-aws/latest/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType)
+		repo, err := newOCIRepository(providerConfig, configVariablesClient, InjectOCIHTTPClient(registry.Client()))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(repo.DefaultVersion()).To(Equal("v0.6.4"))
+		g.Expect(repo.ComponentsPath()).To(Equal("infrastructure-components.yaml"))
+
+		versions, err := repo.GetVersions()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(versions).To(ConsistOf("v0.6.3", "v0.6.4", "v0.7.0-alpha.0"))
+
+		content, err := repo.GetFile("v0.6.3", "metadata.yaml")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(content).To(Equal([]byte("metadata v0.6.3")))
+
+		_, err = repo.GetFile("v0.6.3", "cluster-template.yaml")
+		g.Expect(err).To(HaveOccurred())
+
+		_, err = repo.GetFile("v0.5.0", "metadata.yaml")
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("fails with invalid credentials", func(t *testing.T) {
+		g := NewWithT(t)
+		resetCaches()
+
+		providerConfig := config.NewProvider("aws", "oci://"+registry.Host()+"/capi/infrastructure-aws/latest/infrastructure-components.yaml", clusterctlv1.InfrastructureProviderType)
+		_, err := newOCIRepository(providerConfig, test.NewFakeVariableClient(), InjectOCIHTTPClient(registry.Client()))
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("fails to push a release without a valid version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		url := "oci://" + registry.Host() + "/capi/infrastructure-aws/latest"
+		g.Expect(PushOCIRelease(url, release("latest"), configVariablesClient, InjectOCIHTTPClient(registry.Client()))).ToNot(Succeed())
+	})
+}
+
+func Test_ociRepository_connectionSettings(t *testing.T) {
+	registry := test.NewFakeOCIRegistry("", "")
+	defer registry.Close()
+
+	dir, err := ioutil.TempDir("", "cc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.crt")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: registry.Certificate().Raw})
+	if err := ioutil.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalidCAFile := filepath.Join(dir, "invalid.crt")
+	if err := ioutil.WriteFile(invalidCAFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	url := "oci://" + registry.Host() + "/capi/infrastructure-aws/v0.6.3"
+	files := map[string][]byte{"metadata.yaml": []byte("metadata v0.6.3")}
+	if err := PushOCIRelease(url, files, test.NewFakeVariableClient(), InjectOCIHTTPClient(registry.Client())); err != nil {
+		t.Fatal(err)
+	}
+
+	plainHTTPRegistry := test.NewFakePlainHTTPOCIRegistry("", "")
+	defer plainHTTPRegistry.Close()
+
+	plainHTTPURL := "oci://" + plainHTTPRegistry.Host() + "/capi/infrastructure-aws/v0.6.3"
+	if err := PushOCIRelease(plainHTTPURL, files, test.NewFakeVariableClient().WithVar(config.OCIPlainHTTPVariable, "true")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                  string
+		registry              *test.FakeOCIRegistry
+		configVariablesClient config.VariablesClient
+		wantErr               bool
+	}{
+		{
+			name:                  "fails if the registry certificate is not trusted",
+			registry:              registry,
+			configVariablesClient: test.NewFakeVariableClient(),
+			wantErr:               true,
+		},
+		{
+			name:                  "trusts the CA certificates in the CA file",
+			registry:              registry,
+			configVariablesClient: test.NewFakeVariableClient().WithVar(config.OCICAFileVariable, caFile),
+			wantErr:               false,
+		},
+		{
+			name:                  "fails if the CA file does not exist",
+			registry:              registry,
+			configVariablesClient: test.NewFakeVariableClient().WithVar(config.OCICAFileVariable, filepath.Join(dir, "missing.crt")),
+			wantErr:               true,
+		},
+		{
+			name:                  "fails if the CA file does not contain certificates",
+			registry:              registry,
+			configVariablesClient: test.NewFakeVariableClient().WithVar(config.OCICAFileVariable, invalidCAFile),
+			wantErr:               true,
+		},
+		{
+			name:                  "skips the verification of the registry certificate",
+			registry:              registry,
+			configVariablesClient: test.NewFakeVariableClient().WithVar(config.OCIInsecureSkipVerifyVariable, "true"),
+			wantErr:               false,
+		},
+		{
+			name:                  "fails with an invalid insecure skip verify value",
+			registry:              registry,
+			configVariablesClient: test.NewFakeVariableClient().WithVar(config.OCIInsecureSkipVerifyVariable, "maybe"),
+			wantErr:               true,
+		},
+		{
+			name:                  "fails to connect to a plain http registry using https",
+			registry:              plainHTTPRegistry,
+			configVariablesClient: test.NewFakeVariableClient(),
+			wantErr:               true,
+		},
+		{
+			name:                  "connects to a plain http registry",
+			registry:              plainHTTPRegistry,
+			configVariablesClient: test.NewFakeVariableClient().WithVar(config.OCIPlainHTTPVariable, "true"),
+			wantErr:               false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resetCaches()
+
+			providerConfig := config.NewProvider("aws", "oci://"+tt.registry.Host()+"/capi/infrastructure-aws/latest/metadata.yaml", clusterctlv1.InfrastructureProviderType)
+			repo, err := newOCIRepository(providerConfig, tt.configVariablesClient)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(repo.DefaultVersion()).To(Equal("v0.6.3"))
+
+			content, err := repo.GetFile("v0.6.3", "metadata.yaml")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(content).To(Equal(files["metadata.yaml"]))
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
 )
 
@@ -37,38 +38,193 @@ type RolloutRestartOptions struct {
 	Namespace string
 }
 
+// RolloutPauseOptions carries the options supported by rollout pause.
+type RolloutPauseOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Resources to be rollout paused.
+	Resources []string
+
+	// Namespace where the resource(s) live. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+}
+
+// RolloutResumeOptions carries the options supported by rollout resume.
+type RolloutResumeOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Resources to be rollout resumed.
+	Resources []string
+
+	// Namespace where the resource(s) live. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+}
+
+// RolloutUndoOptions carries the options supported by rollout undo.
+type RolloutUndoOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Resources to be rolled back.
+	Resources []string
+
+	// Namespace where the resource(s) live. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+
+	// ToRevision is the revision to rollback to. If 0, the resource is rolled back to the previous revision.
+	ToRevision int64
+}
+
+// RolloutStatusOptions carries the options supported by rollout status.
+type RolloutStatusOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Resource to get the rollout status for.
+	Resource string
+
+	// Namespace where the resource lives. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+}
+
+// RolloutHistoryOptions carries the options supported by rollout history.
+type RolloutHistoryOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Resource to get the rollout history for.
+	Resource string
+
+	// Namespace where the resource lives. If unspecified, the namespace name will be inferred
+	// from the current configuration.
+	Namespace string
+}
+
 func (c *clusterctlClient) RolloutRestart(options RolloutRestartOptions) error {
-	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	proxy, namespace, tuples, err := c.getRolloutResources(options.Kubeconfig, options.Namespace, options.Resources)
 	if err != nil {
 		return err
 	}
 
-	// If the option specifying the Namespace is empty, try to detect it.
-	if options.Namespace == "" {
-		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
-		if err != nil {
+	for _, t := range tuples {
+		if err := c.alphaClient.Rollout().ObjectRestarter(proxy, t, namespace); err != nil {
 			return err
 		}
-		options.Namespace = currentNamespace
 	}
+	return nil
+}
 
-	if len(options.Resources) == 0 {
-		return fmt.Errorf("required resource not specified")
+func (c *clusterctlClient) RolloutPause(options RolloutPauseOptions) error {
+	proxy, namespace, tuples, err := c.getRolloutResources(options.Kubeconfig, options.Namespace, options.Resources)
+	if err != nil {
+		return err
 	}
-	normalized := normalizeResources(options.Resources)
-	tuples, err := util.ResourceTypeAndNameArgs(normalized...)
+
+	for _, t := range tuples {
+		if err := c.alphaClient.Rollout().ObjectPauser(proxy, t, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *clusterctlClient) RolloutResume(options RolloutResumeOptions) error {
+	proxy, namespace, tuples, err := c.getRolloutResources(options.Kubeconfig, options.Namespace, options.Resources)
 	if err != nil {
 		return err
 	}
 
 	for _, t := range tuples {
-		if err := c.alphaClient.Rollout().ObjectRestarter(clusterClient.Proxy(), t, options.Namespace); err != nil {
+		if err := c.alphaClient.Rollout().ObjectResumer(proxy, t, namespace); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (c *clusterctlClient) RolloutUndo(options RolloutUndoOptions) error {
+	proxy, namespace, tuples, err := c.getRolloutResources(options.Kubeconfig, options.Namespace, options.Resources)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tuples {
+		if err := c.alphaClient.Rollout().ObjectRollbacker(proxy, t, namespace, options.ToRevision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *clusterctlClient) RolloutStatus(options RolloutStatusOptions) (*RolloutStatus, error) {
+	proxy, namespace, tuples, err := c.getRolloutResources(options.Kubeconfig, options.Namespace, []string{options.Resource})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := c.alphaClient.Rollout().ObjectStatusViewer(proxy, tuples[0], namespace)
+	if err != nil {
+		return nil, err
+	}
+	return (*RolloutStatus)(status), nil
+}
+
+func (c *clusterctlClient) RolloutHistory(options RolloutHistoryOptions) ([]RolloutRevision, error) {
+	proxy, namespace, tuples, err := c.getRolloutResources(options.Kubeconfig, options.Namespace, []string{options.Resource})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := c.alphaClient.Rollout().ObjectHistoryViewer(proxy, tuples[0], namespace)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]RolloutRevision, 0, len(revisions))
+	for _, r := range revisions {
+		history = append(history, RolloutRevision(r))
+	}
+	return history, nil
+}
+
+// getRolloutResources returns the proxy to the management cluster, the namespace and the resource tuples
+// targeted by a rollout command.
+func (c *clusterctlClient) getRolloutResources(kubeconfig Kubeconfig, namespace string, resources []string) (cluster.Proxy, string, []util.ResourceTuple, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: kubeconfig})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, "", nil, err
+		}
+		namespace = currentNamespace
+	}
+
+	if len(resources) == 0 || resources[0] == "" {
+		return nil, "", nil, fmt.Errorf("required resource not specified")
+	}
+	normalized := normalizeResources(resources)
+	tuples, err := util.ResourceTypeAndNameArgs(normalized...)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return clusterClient.Proxy(), namespace, tuples, nil
+}
+
 func normalizeResources(input []string) []string {
 	normalized := make([]string, 0, len(input))
 	for _, in := range input {
@@ -126,6 +126,173 @@ func Test_clusterctlClient_RolloutRestart(t *testing.T) {
 	}
 }
 
+func Test_clusterctlClient_RolloutPause(t *testing.T) {
+	type fields struct {
+		client *fakeClient
+	}
+	type args struct {
+		options RolloutPauseOptions
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "do not return error if machinedeployment found",
+			fields: fields{
+				client: fakeClientForRollout(),
+			},
+			args: args{
+				options: RolloutPauseOptions{
+					Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					Resources:  []string{"machinedeployment/md-1"},
+					Namespace:  "default",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "return error if machinedeployment not found",
+			fields: fields{
+				client: fakeClientForRollout(),
+			},
+			args: args{
+				options: RolloutPauseOptions{
+					Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					Resources:  []string{"machinedeployment/foo"},
+					Namespace:  "default",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "return error if no resource specified",
+			fields: fields{
+				client: fakeClientForRollout(),
+			},
+			args: args{
+				options: RolloutPauseOptions{
+					Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					Namespace:  "default",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.fields.client.RolloutPause(tt.args.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func Test_clusterctlClient_RolloutUndo(t *testing.T) {
+	type fields struct {
+		client *fakeClient
+	}
+	type args struct {
+		options RolloutUndoOptions
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "return error if machinedeployment has no rollout history",
+			fields: fields{
+				client: fakeClientForRollout(),
+			},
+			args: args{
+				options: RolloutUndoOptions{
+					Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					Resources:  []string{"machinedeployment/md-1"},
+					Namespace:  "default",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "return error if machinedeployment not found",
+			fields: fields{
+				client: fakeClientForRollout(),
+			},
+			args: args{
+				options: RolloutUndoOptions{
+					Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					Resources:  []string{"machinedeployment/foo"},
+					Namespace:  "default",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.fields.client.RolloutUndo(tt.args.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func Test_clusterctlClient_RolloutStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	client := fakeClientForRollout()
+	status, err := client.RolloutStatus(RolloutStatusOptions{
+		Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		Resource:   "machinedeployment/md-1",
+		Namespace:  "default",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(status.Done).To(BeTrue())
+
+	_, err = client.RolloutStatus(RolloutStatusOptions{
+		Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		Resource:   "machinedeployment/foo",
+		Namespace:  "default",
+	})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func Test_clusterctlClient_RolloutHistory(t *testing.T) {
+	g := NewWithT(t)
+
+	client := fakeClientForRollout()
+	revisions, err := client.RolloutHistory(RolloutHistoryOptions{
+		Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		Resource:   "machinedeployment/md-1",
+		Namespace:  "default",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(revisions).To(BeEmpty())
+
+	_, err = client.RolloutHistory(RolloutHistoryOptions{
+		Kubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+		Resource:   "foo/bar",
+		Namespace:  "default",
+	})
+	g.Expect(err).To(HaveOccurred())
+}
+
 func fakeClientForRollout() *fakeClient {
 	core := config.NewProvider("cluster-api", "https://somewhere.com", clusterctlv1.CoreProviderType)
 	infra := config.NewProvider("infra", "https://somewhere.com", clusterctlv1.InfrastructureProviderType)
@@ -0,0 +1,272 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ObjectTreeOptions defines the options for an ObjectTree.
+type ObjectTreeOptions struct {
+	// ShowOtherConditions is a list of comma separated kind or kind/name for which we should show all the object's conditions
+	// (not only the Ready condition); use "all" for showing all the conditions for all the objects.
+	ShowOtherConditions string
+
+	// DisableNoEcho disables hiding objects if the object's ready condition has the
+	// same Status, Severity and Reason of the parent's object ready condition (it is an echo).
+	DisableNoEcho bool
+
+	// DisableGrouping disables grouping machines objects in case the ready condition
+	// has the same Status, Severity and Reason.
+	DisableGrouping bool
+}
+
+// ObjectTree defines an object tree representing the status of a Cluster API cluster.
+type ObjectTree struct {
+	root    *Node
+	options ObjectTreeOptions
+}
+
+// Node defines an object in the ObjectTree.
+type Node struct {
+	// Object is the object represented by the node. For virtual nodes only kind, name and namespace are set.
+	Object *unstructured.Unstructured
+
+	// MetaName is an optional name used for presenting the object in a consistent way no matter of its kind,
+	// e.g. ClusterInfrastructure instead of AWSCluster, VSphereCluster etc.
+	MetaName string
+
+	// Virtual is true for nodes not corresponding to an object existing in the management cluster,
+	// e.g. the node grouping all the worker machines.
+	Virtual bool
+
+	// GroupItems contains the names of the objects represented by a node grouping
+	// sibling objects with the same ready condition.
+	GroupItems []string
+
+	// ShowAllConditions signals the presentation layer to show all the object's conditions,
+	// not only the Ready condition.
+	ShowAllConditions bool
+
+	// Children contains the child nodes.
+	Children []*Node
+
+	// groupingAllowed is true if the node can be grouped with siblings of the same kind.
+	groupingAllowed bool
+
+	// readyCondition is the ready condition of the object, or the ready condition shared by all the
+	// objects in a group.
+	readyCondition *clusterv1.Condition
+}
+
+// NewObjectTree creates a new object tree with the given root and options.
+func NewObjectTree(root *unstructured.Unstructured, options ObjectTreeOptions) *ObjectTree {
+	t := &ObjectTree{options: options}
+	t.root = t.newNode(root)
+	return t
+}
+
+// GetRoot returns the root node of the ObjectTree.
+func (t *ObjectTree) GetRoot() *Node {
+	return t.root
+}
+
+// AddObjectOption defines an option for adding an object to the ObjectTree.
+type AddObjectOption func(*addObjectOptions)
+
+type addObjectOptions struct {
+	metaName        string
+	noEcho          bool
+	groupingAllowed bool
+}
+
+// ObjectMetaName sets the meta name for the object being added to the ObjectTree.
+func ObjectMetaName(metaName string) AddObjectOption {
+	return func(o *addObjectOptions) {
+		o.metaName = metaName
+	}
+}
+
+// NoEcho instructs the ObjectTree to skip the object if its ready condition is an echo
+// of the parent's ready condition.
+func NoEcho(noEcho bool) AddObjectOption {
+	return func(o *addObjectOptions) {
+		o.noEcho = noEcho
+	}
+}
+
+// GroupingObject instructs the ObjectTree to group the object with siblings of the same kind
+// having the same ready condition.
+func GroupingObject(grouping bool) AddObjectOption {
+	return func(o *addObjectOptions) {
+		o.groupingAllowed = grouping
+	}
+}
+
+// Add adds an object to the ObjectTree as a child of the given parent.
+// It returns the node added to the tree, or nil if the object has been skipped, because it is an echo
+// of the parent, or it has been collapsed into a group node together with its siblings.
+func (t *ObjectTree) Add(parent *Node, obj *unstructured.Unstructured, opts ...AddObjectOption) *Node {
+	options := &addObjectOptions{}
+	for _, o := range opts {
+		o(options)
+	}
+
+	n := t.newNode(obj)
+	n.MetaName = options.metaName
+	n.groupingAllowed = options.groupingAllowed && !t.options.DisableGrouping
+
+	// Skip the object if its ready condition is an echo of the parent's ready condition.
+	if options.noEcho && !t.options.DisableNoEcho && hasSameReadyStatusSeverityAndReason(parent.readyCondition, n.readyCondition) {
+		return nil
+	}
+
+	// Collapse the object into a group node if there is a sibling with the same kind and the same ready condition.
+	if n.groupingAllowed && !n.ShowAllConditions {
+		for i, sibling := range parent.Children {
+			if !canBeGrouped(sibling, n) {
+				continue
+			}
+			if sibling.GroupItems == nil {
+				group := &Node{
+					Object:          NewVirtualObject(sibling.Object.GetNamespace(), sibling.Object.GetKind(), sibling.Object.GetName()),
+					MetaName:        sibling.MetaName,
+					Virtual:         true,
+					GroupItems:      []string{sibling.Object.GetName()},
+					groupingAllowed: true,
+					readyCondition:  sibling.readyCondition,
+				}
+				parent.Children[i] = group
+				sibling = group
+			}
+			sibling.GroupItems = append(sibling.GroupItems, n.Object.GetName())
+			sort.Strings(sibling.GroupItems)
+			sibling.Object.SetName(fmt.Sprintf("%d %ss...", len(sibling.GroupItems), sibling.Object.GetKind()))
+			sibling.readyCondition = lastTransitioned(sibling.readyCondition, n.readyCondition)
+			return nil
+		}
+	}
+
+	parent.Children = append(parent.Children, n)
+	return n
+}
+
+// AddVirtual adds a virtual node with the given kind and name to the ObjectTree as a child of the given parent.
+func (t *ObjectTree) AddVirtual(parent *Node, kind, name string) *Node {
+	n := &Node{
+		Object:  NewVirtualObject(parent.Object.GetNamespace(), kind, name),
+		Virtual: true,
+	}
+	parent.Children = append(parent.Children, n)
+	return n
+}
+
+// GetReadyCondition returns the ready condition of the object represented by the node, if any.
+func (n *Node) GetReadyCondition() *clusterv1.Condition {
+	return n.readyCondition
+}
+
+// GetOtherConditions returns the conditions of the object represented by the node, except the Ready condition.
+func (n *Node) GetOtherConditions() []*clusterv1.Condition {
+	if n.Virtual {
+		return nil
+	}
+	var others []*clusterv1.Condition
+	for _, c := range conditions.UnstructuredGetter(n.Object).GetConditions() {
+		c := c
+		if c.Type != clusterv1.ReadyCondition {
+			others = append(others, &c)
+		}
+	}
+	return others
+}
+
+// NewVirtualObject returns a new unstructured object used for representing virtual nodes in the ObjectTree.
+func NewVirtualObject(namespace, kind, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("virtual.cluster.x-k8s.io/v1alpha4")
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func (t *ObjectTree) newNode(obj *unstructured.Unstructured) *Node {
+	return &Node{
+		Object:            obj,
+		ShowAllConditions: t.showAllConditions(obj),
+		readyCondition:    conditions.Get(conditions.UnstructuredGetter(obj), clusterv1.ReadyCondition),
+	}
+}
+
+// showAllConditions returns true if ShowOtherConditions matches the object's kind or kind/name.
+func (t *ObjectTree) showAllConditions(obj *unstructured.Unstructured) bool {
+	for _, s := range strings.Split(t.options.ShowOtherConditions, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if strings.EqualFold(s, "all") {
+			return true
+		}
+		kind, name := s, ""
+		if i := strings.Index(s, "/"); i >= 0 {
+			kind, name = s[:i], s[i+1:]
+		}
+		if strings.EqualFold(kind, obj.GetKind()) && (name == "" || name == obj.GetName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// canBeGrouped returns true if a node can be collapsed into the same group of a sibling.
+func canBeGrouped(sibling, n *Node) bool {
+	return sibling.groupingAllowed &&
+		!sibling.ShowAllConditions &&
+		len(sibling.Children) == 0 &&
+		sibling.Object.GetKind() == n.Object.GetKind() &&
+		sibling.readyCondition != nil && n.readyCondition != nil &&
+		hasSameReadyStatusSeverityAndReason(sibling.readyCondition, n.readyCondition)
+}
+
+func hasSameReadyStatusSeverityAndReason(a, b *clusterv1.Condition) bool {
+	if a == nil && b == nil {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Status == b.Status &&
+		a.Severity == b.Severity &&
+		a.Reason == b.Reason
+}
+
+// lastTransitioned returns the condition with the most recent LastTransitionTime, so a group node
+// reports when the last object in the group reached the shared state.
+func lastTransitioned(a, b *clusterv1.Condition) *clusterv1.Condition {
+	if b.LastTransitionTime.After(a.LastTransitionTime.Time) {
+		return b
+	}
+	return a
+}
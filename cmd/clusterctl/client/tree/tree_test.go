@@ -0,0 +1,194 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func newObject(kind, name string, readyConditions ...*clusterv1.Condition) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(clusterv1.GroupVersion.String())
+	u.SetKind(kind)
+	u.SetNamespace("ns")
+	u.SetName(name)
+	conditions.UnstructuredSetter(u).SetConditions(func() clusterv1.Conditions {
+		var c clusterv1.Conditions
+		for _, r := range readyConditions {
+			c = append(c, *r)
+		}
+		return c
+	}())
+	return u
+}
+
+func TestObjectTree_Add_NoEcho(t *testing.T) {
+	tests := []struct {
+		name          string
+		parentReady   *clusterv1.Condition
+		objReady      *clusterv1.Condition
+		noEcho        bool
+		disableNoEcho bool
+		wantAdded     bool
+	}{
+		{
+			name:        "should add the object if NoEcho is not set",
+			parentReady: conditions.TrueCondition(clusterv1.ReadyCondition),
+			objReady:    conditions.TrueCondition(clusterv1.ReadyCondition),
+			wantAdded:   true,
+		},
+		{
+			name:        "should skip the object if it is an echo of the parent",
+			parentReady: conditions.TrueCondition(clusterv1.ReadyCondition),
+			objReady:    conditions.TrueCondition(clusterv1.ReadyCondition),
+			noEcho:      true,
+			wantAdded:   false,
+		},
+		{
+			name:          "should add the object if it is an echo of the parent but NoEcho is disabled",
+			parentReady:   conditions.TrueCondition(clusterv1.ReadyCondition),
+			objReady:      conditions.TrueCondition(clusterv1.ReadyCondition),
+			noEcho:        true,
+			disableNoEcho: true,
+			wantAdded:     true,
+		},
+		{
+			name:        "should add the object if its reason is different from the parent",
+			parentReady: conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityInfo, ""),
+			objReady:    conditions.FalseCondition(clusterv1.ReadyCondition, "Bar", clusterv1.ConditionSeverityInfo, ""),
+			noEcho:      true,
+			wantAdded:   true,
+		},
+		{
+			name:        "should add the object if its severity is different from the parent",
+			parentReady: conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityInfo, ""),
+			objReady:    conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityError, ""),
+			noEcho:      true,
+			wantAdded:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tree := NewObjectTree(newObject("Cluster", "parent", tt.parentReady), ObjectTreeOptions{DisableNoEcho: tt.disableNoEcho})
+			n := tree.Add(tree.GetRoot(), newObject("Machine", "child", tt.objReady), NoEcho(tt.noEcho))
+
+			if tt.wantAdded {
+				g.Expect(n).ToNot(BeNil())
+				g.Expect(tree.GetRoot().Children).To(ConsistOf(n))
+				return
+			}
+			g.Expect(n).To(BeNil())
+			g.Expect(tree.GetRoot().Children).To(BeEmpty())
+		})
+	}
+}
+
+func TestObjectTree_Add_Grouping(t *testing.T) {
+	t.Run("should group siblings with the same ready condition", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tree := NewObjectTree(newObject("Cluster", "cluster"), ObjectTreeOptions{})
+		root := tree.GetRoot()
+		g.Expect(tree.Add(root, newObject("Machine", "m1", conditions.TrueCondition(clusterv1.ReadyCondition)), GroupingObject(true))).ToNot(BeNil())
+		g.Expect(tree.Add(root, newObject("Machine", "m2", conditions.TrueCondition(clusterv1.ReadyCondition)), GroupingObject(true))).To(BeNil())
+		g.Expect(tree.Add(root, newObject("Machine", "m3", conditions.TrueCondition(clusterv1.ReadyCondition)), GroupingObject(true))).To(BeNil())
+		failing := tree.Add(root, newObject("Machine", "m4", conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityError, "")), GroupingObject(true))
+		g.Expect(failing).ToNot(BeNil())
+
+		g.Expect(root.Children).To(HaveLen(2))
+		group := root.Children[0]
+		g.Expect(group.Virtual).To(BeTrue())
+		g.Expect(group.GroupItems).To(Equal([]string{"m1", "m2", "m3"}))
+		g.Expect(group.Object.GetName()).To(Equal("3 Machines..."))
+		g.Expect(group.GetReadyCondition().Status).To(Equal(corev1.ConditionTrue))
+		g.Expect(root.Children[1]).To(Equal(failing))
+	})
+
+	t.Run("should not group siblings when grouping is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tree := NewObjectTree(newObject("Cluster", "cluster"), ObjectTreeOptions{DisableGrouping: true})
+		root := tree.GetRoot()
+		g.Expect(tree.Add(root, newObject("Machine", "m1", conditions.TrueCondition(clusterv1.ReadyCondition)), GroupingObject(true))).ToNot(BeNil())
+		g.Expect(tree.Add(root, newObject("Machine", "m2", conditions.TrueCondition(clusterv1.ReadyCondition)), GroupingObject(true))).ToNot(BeNil())
+		g.Expect(root.Children).To(HaveLen(2))
+	})
+
+	t.Run("should not group siblings with children", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tree := NewObjectTree(newObject("Cluster", "cluster"), ObjectTreeOptions{})
+		root := tree.GetRoot()
+		m1 := tree.Add(root, newObject("Machine", "m1", conditions.TrueCondition(clusterv1.ReadyCondition)), GroupingObject(true))
+		g.Expect(tree.Add(m1, newObject("DockerMachine", "m1"))).ToNot(BeNil())
+		g.Expect(tree.Add(root, newObject("Machine", "m2", conditions.TrueCondition(clusterv1.ReadyCondition)), GroupingObject(true))).ToNot(BeNil())
+		g.Expect(root.Children).To(HaveLen(2))
+	})
+}
+
+func TestObjectTree_ShowOtherConditions(t *testing.T) {
+	tests := []struct {
+		name                string
+		showOtherConditions string
+		want                bool
+	}{
+		{
+			name:                "should not show other conditions by default",
+			showOtherConditions: "",
+			want:                false,
+		},
+		{
+			name:                "should show other conditions for all the objects",
+			showOtherConditions: "all",
+			want:                true,
+		},
+		{
+			name:                "should show other conditions for a kind",
+			showOtherConditions: "Cluster,machine",
+			want:                true,
+		},
+		{
+			name:                "should show other conditions for a kind/name",
+			showOtherConditions: "Machine/m1",
+			want:                true,
+		},
+		{
+			name:                "should not show other conditions for another object",
+			showOtherConditions: "Machine/m2",
+			want:                false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tree := NewObjectTree(newObject("Cluster", "cluster"), ObjectTreeOptions{ShowOtherConditions: tt.showOtherConditions})
+			n := tree.Add(tree.GetRoot(), newObject("Machine", "m1", conditions.TrueCondition(clusterv1.ReadyCondition), conditions.TrueCondition("Foo")))
+			g.Expect(n.ShowAllConditions).To(Equal(tt.want))
+			g.Expect(n.GetOtherConditions()).To(HaveLen(1))
+		})
+	}
+}
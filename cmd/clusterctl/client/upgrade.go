@@ -100,9 +100,28 @@ type ApplyUpgradeOptions struct {
 
 	// InfrastructureProviders instance and versions (e.g. capa-system/aws:v0.5.0) to upgrade to. This field can be used as alternative to Contract.
 	InfrastructureProviders []string
+
+	// FromBundle defines the path of a bundle created with CreateBundle to upgrade the providers from; providers
+	// included in the bundle are read from it instead of the provider repositories.
+	FromBundle string
+
+	// ImageRegistry defines the registry where all the images are pulled from, e.g. a registry mirroring
+	// the images listed in a bundle. If unspecified, the images are pulled from their original registry.
+	ImageRegistry string
 }
 
 func (c *clusterctlClient) ApplyUpgrade(options ApplyUpgradeOptions) error {
+	if options.FromBundle != "" || options.ImageRegistry != "" {
+		client, cleanup, err := c.withBundle(options.FromBundle, options.ImageRegistry)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		options.FromBundle, options.ImageRegistry = "", ""
+		return client.ApplyUpgrade(options)
+	}
+
 	// Get the client for interacting with the management cluster.
 	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type bundleCreateOptions struct {
+	coreProvider            string
+	bootstrapProviders      []string
+	controlPlaneProviders   []string
+	infrastructureProviders []string
+	flavors                 []string
+	outputFile              string
+}
+
+var bc = &bundleCreateOptions{}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a bundle with a set of providers.",
+	Long: LongDesc(`
+		Create a self-contained bundle with a set of providers.
+
+		The bundle contains the components YAML, the metadata and the workload cluster templates of the selected providers,
+		the cert-manager manifest embedded in clusterctl and the list of the container images required for the installation.
+
+		The bundle can be used with 'clusterctl init --from-bundle' or 'clusterctl upgrade apply --from-bundle' in
+		environments without access to the provider repositories; the images listed in the images.txt file of the bundle
+		should be mirrored to a registry reachable from the management cluster, to be used with the --image-registry flag.`),
+
+	Example: Examples(`
+		# Create a bundle with the latest release of the Cluster API core provider, of the kubeadm bootstrap
+		# and control plane providers and of the given infrastructure provider.
+		clusterctl bundle create --infrastructure=aws -o bundle.tar.gz
+
+		# Create a bundle with a specific version of the given infrastructure provider and the
+		# workload cluster templates for the given flavor.
+		clusterctl bundle create --infrastructure=aws:v0.5.0 --flavor=eks -o bundle.tar.gz`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBundleCreate()
+	},
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVar(&bc.coreProvider, "core", "",
+		"Core provider version (e.g. cluster-api:v0.3.0) to add to the bundle. If unspecified, Cluster API's latest release is used.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bc.infrastructureProviders, "infrastructure", "i", nil,
+		"Infrastructure providers and versions (e.g. aws:v0.5.0) to add to the bundle.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bc.bootstrapProviders, "bootstrap", "b", nil,
+		"Bootstrap providers and versions (e.g. kubeadm:v0.3.0) to add to the bundle. If unspecified, Kubeadm bootstrap provider's latest release is used.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bc.controlPlaneProviders, "control-plane", "c", nil,
+		"Control plane providers and versions (e.g. kubeadm:v0.3.0) to add to the bundle. If unspecified, the Kubeadm control plane provider's latest release is used.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bc.flavors, "flavor", "f", nil,
+		"Flavors of the workload cluster templates to add to the bundle for each infrastructure provider. The default template is always added, if it exists.")
+	bundleCreateCmd.Flags().StringVarP(&bc.outputFile, "output", "o", "",
+		"Path of the bundle to be created.")
+}
+
+func runBundleCreate() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.CreateBundle(client.CreateBundleOptions{
+		CoreProvider:            bc.coreProvider,
+		BootstrapProviders:      bc.bootstrapProviders,
+		ControlPlaneProviders:   bc.controlPlaneProviders,
+		InfrastructureProviders: bc.infrastructureProviders,
+		Flavors:                 bc.flavors,
+		OutputFile:              bc.outputFile,
+	})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type deleteClusterOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+
+	namespace string
+	orphan    bool
+}
+
+var dcl = &deleteClusterOptions{}
+
+var deleteClusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Delete a workload cluster",
+	Long: LongDesc(`
+		Delete a workload cluster from the management cluster.
+
+		By default the Cluster API controllers and the infrastructure providers destroy the infrastructure
+		of the workload cluster, e.g. VMs and load balancers. When the --orphan flag is set, only the Cluster API
+		objects are removed and the infrastructure is left running; this requires the providers in use to
+		support the orphan deletion contract, and providers not supporting it, e.g. a control plane provider,
+		will still tear down the infrastructure they manage.`),
+
+	Example: Examples(`
+		# Delete the cluster named test-1 and its infrastructure.
+		clusterctl delete cluster test-1
+
+		# Delete the Cluster API objects for the cluster named test-1, leaving its infrastructure running.
+		clusterctl delete cluster test-1 --orphan`),
+
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDeleteCluster(args[0])
+	},
+}
+
+func init() {
+	deleteClusterCmd.Flags().StringVar(&dcl.kubeconfig, "kubeconfig", "",
+		"Path to a kubeconfig file to use for the management cluster. If empty, default discovery rules apply.")
+	deleteClusterCmd.Flags().StringVar(&dcl.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	deleteClusterCmd.Flags().StringVarP(&dcl.namespace, "namespace", "n", "",
+		"The namespace where the workload cluster is located. If unspecified, the current namespace will be used.")
+
+	deleteClusterCmd.Flags().BoolVar(&dcl.orphan, "orphan", false,
+		"Remove the Cluster API objects while leaving the infrastructure of the workload cluster running.")
+
+	deleteCmd.AddCommand(deleteClusterCmd)
+}
+
+func runDeleteCluster(name string) error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	if err := c.DeleteCluster(client.DeleteClusterOptions{
+		Kubeconfig:  client.Kubeconfig{Path: dcl.kubeconfig, Context: dcl.kubeconfigContext},
+		Namespace:   dcl.namespace,
+		ClusterName: name,
+		Orphan:      dcl.orphan,
+	}); err != nil {
+		return err
+	}
+
+	if dcl.orphan {
+		fmt.Printf("Cluster %q deletion started in orphan mode, the infrastructure will be left running\n", name)
+		return nil
+	}
+	fmt.Printf("Cluster %q deletion started\n", name)
+	return nil
+}
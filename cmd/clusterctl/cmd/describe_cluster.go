@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+)
+
+const (
+	firstElemPrefix = `├─`
+	lastElemPrefix  = `└─`
+	pipe            = `│ `
+	indent          = `  `
+)
+
+type describeClusterOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+
+	namespace           string
+	showOtherConditions string
+	disableNoEcho       bool
+	disableGrouping     bool
+}
+
+var dc = &describeClusterOptions{}
+
+var describeClusterClusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Describe workload clusters",
+	Long: LongDesc(`
+		Provide an "at glance" view of a Cluster API cluster designed to help the user in quickly
+		understanding if there are problems and where.`),
+
+	Example: Examples(`
+		# Describe the cluster named test-1.
+		clusterctl describe cluster test-1
+
+		# Describe the cluster named test-1 showing all the conditions for the KubeadmControlPlane object kind.
+		clusterctl describe cluster test-1 --show-conditions KubeadmControlPlane
+
+		# Describe the cluster named test-1 showing all the conditions for a specific machine.
+		clusterctl describe cluster test-1 --show-conditions Machine/m1
+
+		# Describe the cluster named test-1 showing all the conditions for all the objects.
+		clusterctl describe cluster test-1 --show-conditions all
+
+		# Describe the cluster named test-1 showing also objects whose Ready condition is the same
+		# as the parent's Ready condition.
+		clusterctl describe cluster test-1 --echo
+
+		# Describe the cluster named test-1 disabling the automatic grouping of machines with the same Ready condition.
+		clusterctl describe cluster test-1 --disable-grouping`),
+
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDescribeCluster(args[0])
+	},
+}
+
+func init() {
+	describeClusterClusterCmd.Flags().StringVar(&dc.kubeconfig, "kubeconfig", "",
+		"Path to a kubeconfig file to use for the management cluster. If empty, default discovery rules apply.")
+	describeClusterClusterCmd.Flags().StringVar(&dc.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	describeClusterClusterCmd.Flags().StringVarP(&dc.namespace, "namespace", "n", "",
+		"The namespace where the workload cluster is located. If unspecified, the current namespace will be used.")
+
+	describeClusterClusterCmd.Flags().StringVar(&dc.showOtherConditions, "show-conditions", "",
+		"list of comma separated kind or kind/name for which the command should show all the object's conditions (use 'all' to show conditions for everything).")
+	describeClusterClusterCmd.Flags().BoolVar(&dc.disableNoEcho, "echo", false,
+		"Show objects with the same Ready condition of the parent; by default those objects are hidden, so only the failing branches of the tree are shown.")
+	describeClusterClusterCmd.Flags().BoolVar(&dc.disableGrouping, "disable-grouping", false,
+		"Disable grouping machines when ready condition has the same Status, Severity and Reason.")
+
+	describeCmd.AddCommand(describeClusterClusterCmd)
+}
+
+func runDescribeCluster(name string) error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	objectTree, err := c.DescribeCluster(client.DescribeClusterOptions{
+		Kubeconfig:          client.Kubeconfig{Path: dc.kubeconfig, Context: dc.kubeconfigContext},
+		Namespace:           dc.namespace,
+		ClusterName:         name,
+		ShowOtherConditions: dc.showOtherConditions,
+		DisableNoEcho:       dc.disableNoEcho,
+		DisableGrouping:     dc.disableGrouping,
+	})
+	if err != nil {
+		return err
+	}
+
+	printObjectTree(os.Stdout, objectTree)
+	return nil
+}
+
+// printObjectTree prints the cluster status to stdout.
+func printObjectTree(out io.Writer, objectTree *tree.ObjectTree) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tREADY\tSEVERITY\tREASON\tSINCE\tMESSAGE")
+
+	root := objectTree.GetRoot()
+	addObjectRow(w, "", "", root)
+	addChildrenRows(w, "", root)
+	_ = w.Flush()
+}
+
+// addChildrenRows adds the rows for the conditions and for the children of a node, using prefix
+// to draw the tree branches.
+func addChildrenRows(w io.Writer, prefix string, n *tree.Node) {
+	if n.ShowAllConditions {
+		otherConditions := n.GetOtherConditions()
+		conditionPrefix := prefix + indent
+		if len(n.Children) > 0 {
+			conditionPrefix = prefix + pipe
+		}
+		for i, c := range otherConditions {
+			branch := firstElemPrefix
+			if i == len(otherConditions)-1 {
+				branch = lastElemPrefix
+			}
+			addConditionRow(w, conditionPrefix+branch, c)
+		}
+	}
+
+	for i, child := range n.Children {
+		branch, childPrefix := firstElemPrefix, prefix+pipe
+		if i == len(n.Children)-1 {
+			branch, childPrefix = lastElemPrefix, prefix+indent
+		}
+		addObjectRow(w, prefix, branch, child)
+		addChildrenRows(w, childPrefix, child)
+	}
+}
+
+// addObjectRow adds a row for a node, reporting its ready condition.
+func addObjectRow(w io.Writer, prefix, branch string, n *tree.Node) {
+	name := getRowName(n)
+	if ready := n.GetReadyCondition(); ready != nil {
+		fmt.Fprintf(w, "%s%s%s\t%s\t%s\t%s\t%s\t%s\n", prefix, branch, name, ready.Status, ready.Severity, ready.Reason, since(ready), firstLine(ready.Message))
+		return
+	}
+	fmt.Fprintf(w, "%s%s%s\t\t\t\t\t\n", prefix, branch, name)
+}
+
+// addConditionRow adds a row for a condition other than the ready condition.
+func addConditionRow(w io.Writer, prefix string, c *clusterv1.Condition) {
+	fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\t%s\t%s\n", prefix, c.Type, c.Status, c.Severity, c.Reason, since(c), firstLine(c.Message))
+}
+
+// getRowName returns the name to be used for presenting a node, e.g. ControlPlane - KubeadmControlPlane/foo.
+func getRowName(n *tree.Node) string {
+	name := n.Object.GetName()
+	if !n.Virtual || len(n.GroupItems) > 0 {
+		if len(n.GroupItems) == 0 {
+			name = fmt.Sprintf("%s/%s", n.Object.GetKind(), name)
+		}
+		if n.MetaName != "" {
+			name = fmt.Sprintf("%s - %s", n.MetaName, name)
+		}
+	}
+	return name
+}
+
+func since(c *clusterv1.Condition) string {
+	if c.LastTransitionTime.IsZero() {
+		return ""
+	}
+	return duration.HumanDuration(time.Since(c.LastTransitionTime.Time))
+}
+
+func firstLine(s string) string {
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[:i] + " ..."
+	}
+	return s
+}
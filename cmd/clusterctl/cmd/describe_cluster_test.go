@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func fakeObject(kind, name string, c ...*clusterv1.Condition) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(clusterv1.GroupVersion.String())
+	u.SetKind(kind)
+	u.SetNamespace("ns")
+	u.SetName(name)
+	var cs clusterv1.Conditions
+	for _, condition := range c {
+		cs = append(cs, *condition)
+	}
+	conditions.UnstructuredSetter(u).SetConditions(cs)
+	return u
+}
+
+func Test_printObjectTree(t *testing.T) {
+	g := NewWithT(t)
+
+	notReady := conditions.FalseCondition(clusterv1.ReadyCondition, "Bootstrapping", clusterv1.ConditionSeverityInfo, "waiting\nfor bootstrap")
+
+	objectTree := tree.NewObjectTree(fakeObject("Cluster", "c1", notReady), tree.ObjectTreeOptions{ShowOtherConditions: "KubeadmControlPlane"})
+	root := objectTree.GetRoot()
+	objectTree.Add(root, fakeObject("DockerCluster", "c1", conditions.TrueCondition(clusterv1.ReadyCondition)), tree.ObjectMetaName("ClusterInfrastructure"))
+	cp := objectTree.Add(root, fakeObject("KubeadmControlPlane", "cp", notReady, conditions.TrueCondition("Available")), tree.ObjectMetaName("ControlPlane"))
+	objectTree.Add(cp, fakeObject("Machine", "m1", conditions.TrueCondition(clusterv1.ReadyCondition)), tree.GroupingObject(true))
+	objectTree.Add(cp, fakeObject("Machine", "m2", conditions.TrueCondition(clusterv1.ReadyCondition)), tree.GroupingObject(true))
+	workers := objectTree.AddVirtual(root, "WorkerGroup", "Workers")
+	objectTree.Add(workers, fakeObject("Machine", "m3", notReady), tree.GroupingObject(true))
+
+	out := &bytes.Buffer{}
+	printObjectTree(out, objectTree)
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	var names []string
+	for _, l := range lines {
+		names = append(names, string(bytes.Fields(l)[0]))
+	}
+	g.Expect(names).To(Equal([]string{
+		"NAME",
+		"Cluster/c1",
+		"├─ClusterInfrastructure",
+		"├─ControlPlane",
+		"│",
+		"│",
+		"└─Workers",
+		"└─Machine/m3",
+	}))
+	g.Expect(out.String()).To(ContainSubstring("├─ClusterInfrastructure - DockerCluster/c1"))
+	g.Expect(out.String()).To(ContainSubstring("│ │ └─Available"))
+	g.Expect(out.String()).To(ContainSubstring("│ └─2 Machines..."))
+	g.Expect(out.String()).To(ContainSubstring("waiting ..."))
+	g.Expect(out.String()).ToNot(ContainSubstring("for bootstrap"))
+}
@@ -33,6 +33,8 @@ type initOptions struct {
 	targetNamespace         string
 	watchingNamespace       string
 	listImages              bool
+	fromBundle              string
+	imageRegistry           string
 }
 
 var initOpts = &initOptions{}
@@ -82,7 +84,11 @@ var initCmd = &cobra.Command{
 		# Lists the container images required for initializing the management cluster.
 		#
 		# Note: This command is a dry-run; it won't perform any action other than printing to screen.
-		clusterctl init --infrastructure aws --list-images`),
+		clusterctl init --infrastructure aws --list-images
+
+		# Initialize a management cluster using the providers included in a bundle created with 'clusterctl bundle create',
+		# pulling all the container images from a registry mirroring the images listed in the bundle.
+		clusterctl init --infrastructure aws --from-bundle bundle.tar.gz --image-registry registry.example.com/mirror`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runInit()
@@ -107,6 +113,11 @@ func init() {
 	initCmd.Flags().StringVar(&initOpts.watchingNamespace, "watching-namespace", "",
 		"Namespace the providers should watch when reconciling objects. If unspecified, all namespaces are watched.")
 
+	initCmd.Flags().StringVar(&initOpts.fromBundle, "from-bundle", "",
+		"Path of a bundle created with 'clusterctl bundle create' to read the providers from, instead of the provider repositories.")
+	initCmd.Flags().StringVar(&initOpts.imageRegistry, "image-registry", "",
+		"Registry to pull all the container images from (e.g. registry.example.com/mirror). If unspecified, the images are pulled from their original registry.")
+
 	// TODO: Move this to a sub-command or similar, it shouldn't really be a flag.
 	initCmd.Flags().BoolVar(&initOpts.listImages, "list-images", false,
 		"Lists the container images required for initializing the management cluster (without actually installing the providers)")
@@ -129,6 +140,8 @@ func runInit() error {
 		TargetNamespace:         initOpts.targetNamespace,
 		WatchingNamespace:       initOpts.watchingNamespace,
 		LogUsageInstructions:    true,
+		FromBundle:              initOpts.fromBundle,
+		ImageRegistry:           initOpts.imageRegistry,
 	}
 
 	if initOpts.listImages {
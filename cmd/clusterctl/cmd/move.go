@@ -29,6 +29,8 @@ type moveOptions struct {
 	toKubeconfigContext   string
 	namespace             string
 	dryRun                bool
+	toDirectory           string
+	fromDirectory         string
 }
 
 var mo = &moveOptions{}
@@ -43,7 +45,13 @@ var moveCmd = &cobra.Command{
 
 	Example: Examples(`
 		Move Cluster API objects and all dependencies between management clusters.
-		clusterctl move --to-kubeconfig=target-kubeconfig.yaml`),
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml
+
+		Write Cluster API objects and all dependencies to a directory, e.g. for backing up the management cluster.
+		clusterctl move --to-directory=/tmp/backup
+
+		Read Cluster API objects and all dependencies from a directory and create them in a management cluster.
+		clusterctl move --from-directory=/tmp/backup --to-kubeconfig=target-kubeconfig.yaml`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runMove()
@@ -63,13 +71,21 @@ func init() {
 		"The namespace where the workload cluster is hosted. If unspecified, the current context's namespace is used.")
 	moveCmd.Flags().BoolVar(&mo.dryRun, "dry-run", false,
 		"Enable dry run, don't really perform the move actions")
+	moveCmd.Flags().StringVar(&mo.toDirectory, "to-directory", "",
+		"Write Cluster API objects and all dependencies from a management cluster to directory, instead of moving them to another management cluster.")
+	moveCmd.Flags().StringVar(&mo.fromDirectory, "from-directory", "",
+		"Read Cluster API objects and all dependencies from a directory and create them in the management cluster defined by --to-kubeconfig.")
 
 	RootCmd.AddCommand(moveCmd)
 }
 
 func runMove() error {
-	// if no to kubeconfig provided and it's not a dry run, return error
-	if mo.toKubeconfig == "" && !mo.dryRun {
+	if mo.toDirectory != "" && mo.fromDirectory != "" {
+		return errors.New("please specify only one of the --to-directory and --from-directory flags")
+	}
+
+	// if no to kubeconfig provided, it's not a dry run and objects are not moved to a directory, return error
+	if mo.toKubeconfig == "" && !mo.dryRun && mo.toDirectory == "" {
 		return errors.New("please specify a target cluster using the --to-kubeconfig flag")
 	}
 
@@ -83,6 +99,8 @@ func runMove() error {
 		ToKubeconfig:   client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
 		Namespace:      mo.namespace,
 		DryRun:         mo.dryRun,
+		ToDirectory:    mo.toDirectory,
+		FromDirectory:  mo.fromDirectory,
 	}); err != nil {
 		return err
 	}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+var pushCmd = &cobra.Command{
+	Use:   "push URL FILE...",
+	Short: "Push a provider release to an OCI registry",
+	Long: LongDesc(`
+		Push the files of a provider release, e.g. the components YAML, the metadata YAML and the
+		cluster templates, to an OCI registry as an OCI artifact tagged with the release version.
+
+		The release can then be used by clusterctl by configuring a provider with an URL in the form
+		oci://{registry}/{repository}/{latest|version}/{components-file}.
+
+		Credentials for the registry can be provided using the OCI_USERNAME and OCI_PASSWORD variables.`),
+
+	Example: Examples(`
+		# Push a release of the AWS infrastructure provider to an internal registry.
+		clusterctl alpha push oci://registry.example.com/capi/infrastructure-aws/v0.6.4 \
+			infrastructure-components.yaml metadata.yaml cluster-template.yaml`),
+
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPush(args[0], args[1:])
+	},
+}
+
+func runPush(url string, files []string) error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.PushProviderRelease(client.PushProviderReleaseOptions{
+		URL:   url,
+		Files: files,
+	})
+}
@@ -27,11 +27,27 @@ var (
 		Valid resource types include:
 
 		   * machinedeployment
+		   * kubeadmcontrolplane (pause, resume and status only)
 		`)
 
 	rolloutExample = Examples(`
 		# Force an immediate rollout of machinedeployment
-		clusterctl alpha rollout restart machinedeployment/my-md-0`)
+		clusterctl alpha rollout restart machinedeployment/my-md-0
+
+		# Mark the machinedeployment as paused
+		clusterctl alpha rollout pause machinedeployment/my-md-0
+
+		# Resume an already paused machinedeployment
+		clusterctl alpha rollout resume machinedeployment/my-md-0
+
+		# Rollback a machinedeployment to the previous revision
+		clusterctl alpha rollout undo machinedeployment/my-md-0
+
+		# Watch the rollout status of a kubeadmcontrolplane
+		clusterctl alpha rollout status kubeadmcontrolplane/my-kcp
+
+		# View the rollout history of a machinedeployment
+		clusterctl alpha rollout history machinedeployment/my-md-0`)
 
 	rolloutCmd = &cobra.Command{
 		Use:     "rollout SUBCOMMAND",
@@ -44,4 +60,9 @@ var (
 func init() {
 	// subcommands
 	rolloutCmd.AddCommand(rollout.NewCmdRolloutRestart(cfgFile))
+	rolloutCmd.AddCommand(rollout.NewCmdRolloutPause(cfgFile))
+	rolloutCmd.AddCommand(rollout.NewCmdRolloutResume(cfgFile))
+	rolloutCmd.AddCommand(rollout.NewCmdRolloutUndo(cfgFile))
+	rolloutCmd.AddCommand(rollout.NewCmdRolloutStatus(cfgFile))
+	rolloutCmd.AddCommand(rollout.NewCmdRolloutHistory(cfgFile))
 }
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/yaml"
+)
+
+// historyOptions is the start of the data required to perform the operation.
+type historyOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	resource          string
+	namespace         string
+	revision          int64
+}
+
+var ho = &historyOptions{}
+
+var (
+	historyLong = templates.LongDesc(`
+		View previous rollout revisions and configurations.
+
+	        Revisions are read from the MachineSets retained according to the MachineDeployment's RevisionHistoryLimit.
+	        KubeadmControlPlanes are not supported, because they do not keep a revision history.`)
+
+	historyExample = templates.Examples(`
+		# View the rollout history of a machinedeployment
+		clusterctl alpha rollout history machinedeployment/my-md-0
+
+		# View the details of machinedeployment revision 3
+		clusterctl alpha rollout history machinedeployment/my-md-0 --revision=3`)
+)
+
+// NewCmdRolloutHistory returns a Command instance for 'rollout history' sub command
+func NewCmdRolloutHistory(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "history RESOURCE",
+		DisableFlagsInUseLine: true,
+		Short:                 "View the rollout history of a cluster-api resource",
+		Long:                  historyLong,
+		Example:               historyExample,
+		Args:                  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&ho.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&ho.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVar(&ho.namespace, "namespace", "", "Namespace where the resource resides. If unspecified, the default namespace will be used.")
+	cmd.Flags().Int64Var(&ho.revision, "revision", ho.revision, "See the details, including the machine template of the revision specified.")
+
+	return cmd
+}
+
+func runHistory(cfgFile string, args []string) error {
+	ho.resource = args[0]
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := c.RolloutHistory(client.RolloutHistoryOptions{
+		Kubeconfig: client.Kubeconfig{Path: ho.kubeconfig, Context: ho.kubeconfigContext},
+		Namespace:  ho.namespace,
+		Resource:   ho.resource,
+	})
+	if err != nil {
+		return err
+	}
+
+	if ho.revision > 0 {
+		for _, r := range revisions {
+			if r.Revision != ho.revision {
+				continue
+			}
+			y, err := yaml.Marshal(r.Template)
+			if err != nil {
+				return errors.Wrapf(err, "failed to convert revision %d to yaml", r.Revision)
+			}
+			fmt.Printf("%s with revision #%d (MachineSet %s)\n", ho.resource, r.Revision, r.MachineSet)
+			fmt.Print(string(y))
+			return nil
+		}
+		return errors.Errorf("unable to find revision %d for %s", ho.revision, ho.resource)
+	}
+
+	if len(revisions) == 0 {
+		fmt.Printf("No rollout history found for %s\n", ho.resource)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 10, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tMACHINESET\tVERSION\tAGE")
+	for _, r := range revisions {
+		version := ""
+		if r.Template.Spec.Version != nil {
+			version = *r.Template.Spec.Version
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.Revision, r.MachineSet, version, duration.HumanDuration(time.Since(r.CreationTimestamp.Time)))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+const statusPollInterval = 5 * time.Second
+
+// statusOptions is the start of the data required to perform the operation.
+type statusOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	resource          string
+	namespace         string
+	watch             bool
+	timeout           time.Duration
+}
+
+var so = &statusOptions{}
+
+var (
+	statusLong = templates.LongDesc(`
+		Show the status of the rollout.
+
+	        By default, rollout status will watch the status of the latest rollout until it's done. If you don't want to
+	        wait for the rollout to finish then you can use --watch=false.`)
+
+	statusExample = templates.Examples(`
+		# Watch the rollout status of a machinedeployment
+		clusterctl alpha rollout status machinedeployment/my-md-0
+
+		# Show the current rollout status of a kubeadmcontrolplane, without waiting for the rollout to finish
+		clusterctl alpha rollout status kubeadmcontrolplane/my-kcp --watch=false`)
+)
+
+// NewCmdRolloutStatus returns a Command instance for 'rollout status' sub command
+func NewCmdRolloutStatus(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "status RESOURCE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Show the status of the rollout of a cluster-api resource",
+		Long:                  statusLong,
+		Example:               statusExample,
+		Args:                  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStatus(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&so.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&so.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVar(&so.namespace, "namespace", "", "Namespace where the resource resides. If unspecified, the default namespace will be used.")
+	cmd.Flags().BoolVarP(&so.watch, "watch", "w", true, "Watch the status of the rollout until it's done.")
+	cmd.Flags().DurationVar(&so.timeout, "timeout", 0, "The length of time to wait before ending watch, zero means never. Any other values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+
+	return cmd
+}
+
+func runStatus(cfgFile string, args []string) error {
+	so.resource = args[0]
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	options := client.RolloutStatusOptions{
+		Kubeconfig: client.Kubeconfig{Path: so.kubeconfig, Context: so.kubeconfigContext},
+		Namespace:  so.namespace,
+		Resource:   so.resource,
+	}
+
+	if !so.watch {
+		status, err := c.RolloutStatus(options)
+		if err != nil {
+			return err
+		}
+		fmt.Println(status.Message)
+		return nil
+	}
+
+	lastMessage := ""
+	condition := func() (bool, error) {
+		status, err := c.RolloutStatus(options)
+		if err != nil {
+			return false, err
+		}
+		if status.Message != lastMessage {
+			fmt.Println(status.Message)
+			lastMessage = status.Message
+		}
+		return status.Done, nil
+	}
+
+	if so.timeout == 0 {
+		return wait.PollImmediateInfinite(statusPollInterval, condition)
+	}
+	if err := wait.PollImmediate(statusPollInterval, so.timeout, condition); err != nil {
+		if err == wait.ErrWaitTimeout {
+			return errors.Errorf("timed out waiting for the rollout of %s to finish", so.resource)
+		}
+		return err
+	}
+	return nil
+}
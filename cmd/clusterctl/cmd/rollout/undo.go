@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+// undoOptions is the start of the data required to perform the operation.
+type undoOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	resources         []string
+	namespace         string
+	toRevision        int64
+}
+
+var uo = &undoOptions{}
+
+var (
+	undoLong = templates.LongDesc(`
+		Rollback to a previous rollout.
+
+	        The machine template of the MachineDeployment is restored from the MachineSet serving the given revision;
+	        only the revisions retained according to the MachineDeployment's RevisionHistoryLimit can be restored.
+	        KubeadmControlPlanes are not supported, because they do not keep a revision history.`)
+
+	undoExample = templates.Examples(`
+		# Rollback to the previous machinedeployment
+		clusterctl alpha rollout undo machinedeployment/my-md-0
+
+		# Rollback to revision 3
+		clusterctl alpha rollout undo machinedeployment/my-md-0 --to-revision=3`)
+)
+
+// NewCmdRolloutUndo returns a Command instance for 'rollout undo' sub command
+func NewCmdRolloutUndo(cfgFile string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "undo RESOURCE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Undo a previous rollout of a cluster-api resource",
+		Long:                  undoLong,
+		Example:               undoExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUndo(cfgFile, args)
+		},
+	}
+	cmd.Flags().StringVar(&uo.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	cmd.Flags().StringVar(&uo.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	cmd.Flags().StringVar(&uo.namespace, "namespace", "", "Namespace where the resource(s) reside. If unspecified, the default namespace will be used.")
+	cmd.Flags().Int64Var(&uo.toRevision, "to-revision", uo.toRevision, "The revision to rollback to. Default to 0 (last revision).")
+
+	return cmd
+}
+
+func runUndo(cfgFile string, args []string) error {
+	uo.resources = args
+
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.RolloutUndo(client.RolloutUndoOptions{
+		Kubeconfig: client.Kubeconfig{Path: uo.kubeconfig, Context: uo.kubeconfigContext},
+		Namespace:  uo.namespace,
+		Resources:  uo.resources,
+		ToRevision: uo.toRevision,
+	})
+}
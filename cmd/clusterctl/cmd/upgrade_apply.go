@@ -32,6 +32,8 @@ type upgradeApplyOptions struct {
 	bootstrapProviders      []string
 	controlPlaneProviders   []string
 	infrastructureProviders []string
+	fromBundle              string
+	imageRegistry           string
 }
 
 var ua = &upgradeApplyOptions{}
@@ -51,7 +53,11 @@ var upgradeApplyCmd = &cobra.Command{
 		clusterctl upgrade apply --management-group capi-system/cluster-api  --contract v1alpha3
 
 		# Upgrades only the capa-system/aws provider instance in the capi-system/cluster-api management group to the v0.5.0 version.
-		clusterctl upgrade apply --management-group capi-system/cluster-api  --infrastructure capa-system/aws:v0.5.0`),
+		clusterctl upgrade apply --management-group capi-system/cluster-api  --infrastructure capa-system/aws:v0.5.0
+
+		# Upgrades all the providers in the capi-system/cluster-api management group to the versions included in a bundle
+		# created with 'clusterctl bundle create', pulling all the container images from a registry mirroring the images listed in the bundle.
+		clusterctl upgrade apply --management-group capi-system/cluster-api  --contract v1alpha3 --from-bundle bundle.tar.gz --image-registry registry.example.com/mirror`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runUpgradeApply()
@@ -76,6 +82,10 @@ func init() {
 		"Bootstrap providers instance and versions (e.g. capi-kubeadm-bootstrap-system/kubeadm:v0.3.0) to upgrade to. This flag can be used as alternative to --contract.")
 	upgradeApplyCmd.Flags().StringSliceVarP(&ua.controlPlaneProviders, "control-plane", "c", nil,
 		"ControlPlane providers instance and versions (e.g. capi-kubeadm-control-plane-system/kubeadm:v0.3.0) to upgrade to. This flag can be used as alternative to --contract.")
+	upgradeApplyCmd.Flags().StringVar(&ua.fromBundle, "from-bundle", "",
+		"Path of a bundle created with 'clusterctl bundle create' to read the providers from, instead of the provider repositories.")
+	upgradeApplyCmd.Flags().StringVar(&ua.imageRegistry, "image-registry", "",
+		"Registry to pull all the container images from (e.g. registry.example.com/mirror). If unspecified, the images are pulled from their original registry.")
 }
 
 func runUpgradeApply() error {
@@ -101,6 +111,8 @@ func runUpgradeApply() error {
 		BootstrapProviders:      ua.bootstrapProviders,
 		ControlPlaneProviders:   ua.controlPlaneProviders,
 		InfrastructureProviders: ua.infrastructureProviders,
+		FromBundle:              ua.fromBundle,
+		ImageRegistry:           ua.imageRegistry,
 	}); err != nil {
 		return err
 	}
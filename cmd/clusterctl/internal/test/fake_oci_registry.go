@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const fakeOCIRegistryToken = "fake-token"
+
+// FakeOCIRegistry is an in-process OCI registry implementing the subset of the OCI distribution API
+// used by clusterctl. If credentials are defined, the registry requires token authentication.
+type FakeOCIRegistry struct {
+	*httptest.Server
+
+	username string
+	password string
+
+	lock      sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	tags      map[string][]string
+	uploads   int
+}
+
+// NewFakeOCIRegistry starts a FakeOCIRegistry serving https; if username is empty, the registry does not require authentication.
+func NewFakeOCIRegistry(username, password string) *FakeOCIRegistry {
+	r := newFakeOCIRegistry(username, password)
+	r.Server = httptest.NewTLSServer(http.HandlerFunc(r.serveHTTP))
+	return r
+}
+
+// NewFakePlainHTTPOCIRegistry starts a FakeOCIRegistry serving plain http; if username is empty, the registry does not require authentication.
+func NewFakePlainHTTPOCIRegistry(username, password string) *FakeOCIRegistry {
+	r := newFakeOCIRegistry(username, password)
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	return r
+}
+
+func newFakeOCIRegistry(username, password string) *FakeOCIRegistry {
+	return &FakeOCIRegistry{
+		username:  username,
+		password:  password,
+		blobs:     map[string][]byte{},
+		manifests: map[string][]byte{},
+		tags:      map[string][]string{},
+	}
+}
+
+// Host returns the host of the registry, to be used in oci:// urls.
+func (r *FakeOCIRegistry) Host() string {
+	return strings.TrimPrefix(strings.TrimPrefix(r.URL, "https://"), "http://")
+}
+
+// Tags returns the tags of a repository.
+func (r *FakeOCIRegistry) Tags(repository string) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string{}, r.tags[repository]...)
+}
+
+func (r *FakeOCIRegistry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if req.URL.Path == "/token" {
+		if user, password, ok := req.BasicAuth(); !ok || user != r.username || password != r.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"token": %q}`, fakeOCIRegistryToken)
+		return
+	}
+
+	if r.username != "" && req.Header.Get("Authorization") != "Bearer "+fakeOCIRegistryToken {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, r.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	switch {
+	case strings.HasSuffix(path, "/tags/list") && req.Method == http.MethodGet:
+		repository := strings.TrimSuffix(path, "/tags/list")
+		tags := append([]string{}, r.tags[repository]...)
+		sort.Strings(tags)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": repository, "tags": tags})
+
+	case strings.Contains(path, "/manifests/"):
+		i := strings.LastIndex(path, "/manifests/")
+		repository, tag := path[:i], path[i+len("/manifests/"):]
+		key := repository + ":" + tag
+		switch req.Method {
+		case http.MethodGet:
+			manifest, ok := r.manifests[key]
+			if !ok {
+				http.NotFound(w, req)
+				return
+			}
+			w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+			_, _ = w.Write(manifest)
+		case http.MethodPut:
+			manifest, _ := ioutil.ReadAll(req.Body)
+			if _, ok := r.manifests[key]; !ok {
+				r.tags[repository] = append(r.tags[repository], tag)
+			}
+			r.manifests[key] = manifest
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+
+	case strings.HasSuffix(path, "/blobs/uploads/") && req.Method == http.MethodPost:
+		r.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s%d?state=fake", path, r.uploads))
+		w.WriteHeader(http.StatusAccepted)
+
+	case strings.Contains(path, "/blobs/uploads/") && req.Method == http.MethodPut:
+		content, _ := ioutil.ReadAll(req.Body)
+		digest := fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+		if req.URL.Query().Get("digest") != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.blobs[digest] = content
+		w.WriteHeader(http.StatusCreated)
+
+	case strings.Contains(path, "/blobs/"):
+		digest := path[strings.LastIndex(path, "/")+1:]
+		content, ok := r.blobs[digest]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		if req.Method == http.MethodHead {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		_, _ = w.Write(content)
+
+	default:
+		http.NotFound(w, req)
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// capacityAnnotations are the annotations managed by reconcileCapacityAnnotations.
+var capacityAnnotations = []string{
+	clusterv1.CPUCapacityAnnotation,
+	clusterv1.MemoryCapacityAnnotation,
+	clusterv1.EphemeralDiskCapacityAnnotation,
+	clusterv1.GPUTypeCapacityAnnotation,
+	clusterv1.GPUCountCapacityAnnotation,
+	clusterv1.MaxPodsCapacityAnnotation,
+	clusterv1.LabelsCapacityAnnotation,
+	clusterv1.TaintsCapacityAnnotation,
+}
+
+// reconcileCapacityAnnotations sets on the given MachineDeployment or MachineSet the annotations describing the Nodes
+// created from the machine template, using the capacity published in status.capacity by the infrastructure machine
+// template. Objects using an infrastructure machine template not publishing its capacity, or not existing, are left untouched.
+// It returns true if the annotations of the object have been changed.
+func reconcileCapacityAnnotations(ctx context.Context, c client.Client, tracker *external.ObjectTracker, eventHandler handler.EventHandler, template *clusterv1.MachineTemplateSpec, obj metav1.Object) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	ref := &template.Spec.InfrastructureRef
+	if !strings.HasSuffix(ref.Kind, external.TemplateSuffix) {
+		return false, nil
+	}
+
+	infraTemplate, err := external.Get(ctx, c, ref, obj.GetNamespace())
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			// There is no capacity to publish; the annotations are updated once the template is created or the reference changes.
+			log.Info("Infrastructure machine template not found, skipping capacity annotations", "kind", ref.Kind, "name", ref.Name)
+			return false, nil
+		}
+		return false, err
+	}
+
+	// Watch the infrastructure machine template, so the annotations are updated as soon as the capacity is published.
+	if err := tracker.Watch(log, infraTemplate, eventHandler); err != nil {
+		return false, err
+	}
+
+	capacity, err := getTemplateCapacity(infraTemplate)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the capacity of %s %s/%s", ref.Kind, obj.GetNamespace(), ref.Name)
+	}
+	if len(capacity) == 0 {
+		return false, nil
+	}
+
+	return setCapacityAnnotations(obj, getCapacityAnnotations(capacity, template)), nil
+}
+
+// getTemplateCapacity returns the capacity published in status.capacity by an infrastructure machine template, if any.
+func getTemplateCapacity(infraTemplate *unstructured.Unstructured) (corev1.ResourceList, error) {
+	raw, found, err := unstructured.NestedMap(infraTemplate.Object, "status", "capacity")
+	if err != nil || !found {
+		return nil, err
+	}
+
+	capacity := corev1.ResourceList{}
+	for name, value := range raw {
+		quantity, err := resource.ParseQuantity(fmt.Sprint(value))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid quantity for %q", name)
+		}
+		capacity[corev1.ResourceName(name)] = quantity
+	}
+	return capacity, nil
+}
+
+// getCapacityAnnotations returns the capacity annotations for the Nodes created from a machine template, given the
+// capacity of the infrastructure machine template.
+func getCapacityAnnotations(capacity corev1.ResourceList, template *clusterv1.MachineTemplateSpec) map[string]string {
+	annotations := map[string]string{}
+
+	resources := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:              clusterv1.CPUCapacityAnnotation,
+		corev1.ResourceMemory:           clusterv1.MemoryCapacityAnnotation,
+		corev1.ResourceEphemeralStorage: clusterv1.EphemeralDiskCapacityAnnotation,
+		corev1.ResourcePods:             clusterv1.MaxPodsCapacityAnnotation,
+	}
+	for name, annotation := range resources {
+		if quantity, ok := capacity[name]; ok {
+			annotations[annotation] = quantity.String()
+		}
+	}
+
+	// The cluster autoscaler supports a single type of GPU for each node group; if more than one GPU resource
+	// is published, the first one in alphabetical order is used.
+	var gpus []string
+	for name := range capacity {
+		if strings.HasSuffix(string(name), "/gpu") {
+			gpus = append(gpus, string(name))
+		}
+	}
+	if len(gpus) > 0 {
+		sort.Strings(gpus)
+		quantity := capacity[corev1.ResourceName(gpus[0])]
+		annotations[clusterv1.GPUTypeCapacityAnnotation] = gpus[0]
+		annotations[clusterv1.GPUCountCapacityAnnotation] = quantity.String()
+	}
+
+	var labels []string
+	for k, v := range template.Labels {
+		if isNodeMetadataKey(k) {
+			labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+	if len(labels) > 0 {
+		sort.Strings(labels)
+		annotations[clusterv1.LabelsCapacityAnnotation] = strings.Join(labels, ",")
+	}
+
+	taints := make([]string, 0, len(template.Spec.Taints))
+	for _, taint := range template.Spec.Taints {
+		taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+	}
+	if len(taints) > 0 {
+		annotations[clusterv1.TaintsCapacityAnnotation] = strings.Join(taints, ",")
+	}
+
+	return annotations
+}
+
+// setCapacityAnnotations sets the given capacity annotations on the object, removing the capacity annotations
+// not included; it returns true if the annotations of the object have been changed.
+func setCapacityAnnotations(obj metav1.Object, desired map[string]string) bool {
+	annotations := obj.GetAnnotations()
+	changed := false
+	for _, key := range capacityAnnotations {
+		value, ok := desired[key]
+		current, exists := annotations[key]
+		switch {
+		case ok && (!exists || current != value):
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[key] = value
+			changed = true
+		case !ok && exists:
+			delete(annotations, key)
+			changed = true
+		}
+	}
+	if changed {
+		obj.SetAnnotations(annotations)
+	}
+	return changed
+}
+
+// referencesInfrastructureTemplate returns true if the machine template references the given infrastructure machine template.
+func referencesInfrastructureTemplate(template *clusterv1.MachineTemplateSpec, infraTemplate client.Object) bool {
+	ref := template.Spec.InfrastructureRef
+	return ref.Name == infraTemplate.GetName() &&
+		ref.GroupVersionKind().GroupKind() == infraTemplate.GetObjectKind().GroupVersionKind().GroupKind()
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func TestGetCapacityAnnotations(t *testing.T) {
+	template := &clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels: map[string]string{
+				"node.cluster.x-k8s.io/pool":         "blue",
+				"example.node.cluster.x-k8s.io/role": "gpu",
+				clusterv1.ClusterLabelName:           "test-cluster",
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		capacity corev1.ResourceList
+		template *clusterv1.MachineTemplateSpec
+		expected map[string]string
+	}{
+		{
+			name: "cpu, memory and pods",
+			capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			template: &clusterv1.MachineTemplateSpec{},
+			expected: map[string]string{
+				clusterv1.CPUCapacityAnnotation:     "4",
+				clusterv1.MemoryCapacityAnnotation:  "16Gi",
+				clusterv1.MaxPodsCapacityAnnotation: "110",
+			},
+		},
+		{
+			name: "ephemeral storage and GPUs",
+			capacity: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("100Gi"),
+				"nvidia.com/gpu":                resource.MustParse("2"),
+				"example.com/gpu":               resource.MustParse("1"),
+			},
+			template: &clusterv1.MachineTemplateSpec{},
+			expected: map[string]string{
+				clusterv1.EphemeralDiskCapacityAnnotation: "100Gi",
+				clusterv1.GPUTypeCapacityAnnotation:       "example.com/gpu",
+				clusterv1.GPUCountCapacityAnnotation:      "1",
+			},
+		},
+		{
+			name: "node labels and taints from the machine template",
+			capacity: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("2"),
+			},
+			template: template,
+			expected: map[string]string{
+				clusterv1.CPUCapacityAnnotation:    "2",
+				clusterv1.LabelsCapacityAnnotation: "example.node.cluster.x-k8s.io/role=gpu,node.cluster.x-k8s.io/pool=blue",
+				clusterv1.TaintsCapacityAnnotation: "dedicated=gpu:NoSchedule,spot=:PreferNoSchedule",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(getCapacityAnnotations(tt.capacity, tt.template)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestReconcileCapacityAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	newInfraTemplate := func(name string, capacity map[string]interface{}) *unstructured.Unstructured {
+		infraTemplate := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachineTemplate",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+			},
+		}
+		if capacity != nil {
+			g.Expect(unstructured.SetNestedMap(infraTemplate.Object, capacity, "status", "capacity")).To(Succeed())
+		}
+		return infraTemplate
+	}
+
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ms",
+			Namespace:   "default",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Spec: clusterv1.MachineSetSpec{
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+						Kind:       "GenericInfrastructureMachineTemplate",
+						Name:       "small",
+					},
+				},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithObjects(
+		external.TestGenericInfrastructureTemplateCRD.DeepCopy(),
+		newInfraTemplate("small", map[string]interface{}{"cpu": "2", "memory": "4Gi"}),
+		newInfraTemplate("large", map[string]interface{}{"cpu": "8", "memory": "32Gi", "pods": "110"}),
+		newInfraTemplate("unknown", nil),
+	).Build()
+
+	reconcileCapacity := func() bool {
+		changed, err := reconcileCapacityAnnotations(ctx, c, &external.ObjectTracker{}, &handler.EnqueueRequestForObject{}, &ms.Spec.Template, ms)
+		g.Expect(err).ToNot(HaveOccurred())
+		return changed
+	}
+
+	// The annotations are set using the capacity of the infrastructure machine template.
+	g.Expect(reconcileCapacity()).To(BeTrue())
+	g.Expect(ms.Annotations).To(Equal(map[string]string{
+		"foo":                              "bar",
+		clusterv1.CPUCapacityAnnotation:    "2",
+		clusterv1.MemoryCapacityAnnotation: "4Gi",
+	}))
+	g.Expect(reconcileCapacity()).To(BeFalse())
+
+	// The annotations are updated when the machine template changes.
+	ms.Spec.Template.Spec.InfrastructureRef.Name = "large"
+	ms.Spec.Template.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoExecute}}
+	g.Expect(reconcileCapacity()).To(BeTrue())
+	g.Expect(ms.Annotations).To(Equal(map[string]string{
+		"foo":                               "bar",
+		clusterv1.CPUCapacityAnnotation:     "8",
+		clusterv1.MemoryCapacityAnnotation:  "32Gi",
+		clusterv1.MaxPodsCapacityAnnotation: "110",
+		clusterv1.TaintsCapacityAnnotation:  "dedicated=db:NoExecute",
+	}))
+
+	// Resources no longer published by the infrastructure machine template are removed.
+	ms.Spec.Template.Spec.InfrastructureRef.Name = "small"
+	ms.Spec.Template.Spec.Taints = nil
+	g.Expect(reconcileCapacity()).To(BeTrue())
+	g.Expect(ms.Annotations).To(Equal(map[string]string{
+		"foo":                              "bar",
+		clusterv1.CPUCapacityAnnotation:    "2",
+		clusterv1.MemoryCapacityAnnotation: "4Gi",
+	}))
+
+	// The annotations are left untouched if the infrastructure machine template does not publish its capacity.
+	ms.Spec.Template.Spec.InfrastructureRef.Name = "unknown"
+	g.Expect(reconcileCapacity()).To(BeFalse())
+	g.Expect(ms.Annotations).To(HaveKeyWithValue(clusterv1.CPUCapacityAnnotation, "2"))
+
+	// The annotations are left untouched if the infrastructure machine template does not exist.
+	ms.Spec.Template.Spec.InfrastructureRef.Name = "deleted"
+	g.Expect(reconcileCapacity()).To(BeFalse())
+	g.Expect(ms.Annotations).To(HaveKeyWithValue(clusterv1.CPUCapacityAnnotation, "2"))
+}
+
+func TestInfrastructureTemplateToMachineSets(t *testing.T) {
+	g := NewWithT(t)
+
+	newMachineSet := func(name, kind, templateName string) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						InfrastructureRef: corev1.ObjectReference{
+							APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+							Kind:       kind,
+							Name:       templateName,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	infraTemplate := &unstructured.Unstructured{}
+	infraTemplate.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1alpha3")
+	infraTemplate.SetKind("GenericInfrastructureMachineTemplate")
+	infraTemplate.SetName("small")
+	infraTemplate.SetNamespace("default")
+
+	r := &MachineSetReconciler{
+		Client: fake.NewClientBuilder().WithObjects(
+			newMachineSet("ms1", "GenericInfrastructureMachineTemplate", "small"),
+			newMachineSet("ms2", "GenericInfrastructureMachineTemplate", "large"),
+			newMachineSet("ms3", "OtherInfrastructureMachineTemplate", "small"),
+		).Build(),
+	}
+
+	g.Expect(r.InfrastructureTemplateToMachineSets(infraTemplate)).To(ConsistOf(
+		ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "ms1"}},
+	))
+}
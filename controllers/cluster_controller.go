@@ -202,6 +202,12 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cl
 func (r *ClusterReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster) (reconcile.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
+	// When deleting in orphan mode the descendants are deleted as usual, but the infrastructure objects are orphaned,
+	// so the infrastructure is left running even by providers not supporting orphan deletion.
+	if annotations.IsOrphanDeletion(cluster) {
+		log.Info("Deleting Cluster in orphan mode, the infrastructure will be left running")
+	}
+
 	descendants, err := r.listDescendants(ctx, cluster)
 	if err != nil {
 		log.Error(err, "Failed to list descendants")
@@ -294,9 +300,15 @@ func (r *ClusterReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 				conditions.WithFallbackValue(false, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, ""),
 			)
 
-			// Issue a deletion request for the infrastructure object.
+			// Issue a deletion request for the infrastructure object, orphaning it in orphan mode.
 			// Once it's been deleted, the cluster will get processed again.
-			if err := r.Client.Delete(ctx, obj); err != nil {
+			if annotations.IsOrphanDeletion(cluster) {
+				if err := external.Orphan(ctx, r.Client, obj); err != nil {
+					return ctrl.Result{}, errors.Wrapf(err,
+						"failed to orphan %v %q for Cluster %q in namespace %q",
+						obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
+				}
+			} else if err := r.Client.Delete(ctx, obj); err != nil {
 				return ctrl.Result{}, errors.Wrapf(err,
 					"failed to delete %v %q for Cluster %q in namespace %q",
 					obj.GroupVersionKind(), obj.GetName(), cluster.Name, cluster.Namespace)
@@ -24,10 +24,13 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/test/helpers"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -622,6 +625,55 @@ func TestFilterOwnedDescendants(t *testing.T) {
 	g.Expect(actual).To(Equal(expected))
 }
 
+func TestClusterReconcileDeleteOrphan(t *testing.T) {
+	g := NewWithT(t)
+
+	deletionTimestamp := metav1.Now()
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-cluster",
+			Namespace:         "default",
+			DeletionTimestamp: &deletionTimestamp,
+			Annotations:       map[string]string{clusterv1.OrphanAnnotation: ""},
+			Finalizers:        []string{clusterv1.ClusterFinalizer},
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+				Kind:       "InfrastructureCluster",
+				Name:       "test-cluster",
+			},
+		},
+	}
+	infraCluster := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureCluster",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+			"metadata": map[string]interface{}{
+				"name":       "test-cluster",
+				"namespace":  "default",
+				"finalizers": []interface{}{"infrastructure.cluster.x-k8s.io/finalizer"},
+			},
+		},
+	}
+
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	r := &ClusterReconciler{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, infraCluster),
+	}
+
+	// The infrastructure cluster is orphaned, so it is gone even if its provider does not support orphan deletion.
+	_, err := r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Finalizers).To(ContainElement(clusterv1.ClusterFinalizer))
+	err = r.Client.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test-cluster"}, infraCluster.DeepCopy())
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	_, err = r.reconcileDelete(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Finalizers).NotTo(ContainElement(clusterv1.ClusterFinalizer))
+}
+
 func TestReconcileControlPlaneInitializedControlPlaneRef(t *testing.T) {
 	g := NewWithT(t)
 
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apiserver/pkg/storage/names"
@@ -49,6 +50,27 @@ func Get(ctx context.Context, c client.Client, ref *corev1.ObjectReference, name
 	return obj, nil
 }
 
+// Orphan deletes an external object without destroying the resources it manages, e.g. the infrastructure of a
+// Cluster being deleted in orphan mode: the object is paused, so its provider stops reconciling it, and its finalizers
+// are removed, so the provider's deletion logic never runs, before issuing the delete request.
+func Orphan(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	patch := client.MergeFrom(obj.DeepCopy())
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.PausedAnnotation] = "true"
+	obj.SetAnnotations(annotations)
+	obj.SetFinalizers(nil)
+	if err := c.Patch(ctx, obj, patch); err != nil {
+		return errors.Wrapf(err, "failed to remove finalizers from %s %q/%q", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+	if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %s %q/%q", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+	return nil
+}
+
 type CloneTemplateInput struct {
 	// Client is the controller runtime client.
 	// +required
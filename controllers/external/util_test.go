@@ -17,6 +17,7 @@ limitations under the License.
 package external
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -319,3 +320,42 @@ func TestCloneTemplateMissingSpecTemplate(t *testing.T) {
 	})
 	g.Expect(err).To(HaveOccurred())
 }
+
+// deleteRecorder is a client recording the objects as stored before deleting them.
+type deleteRecorder struct {
+	client.Client
+	deleted []*unstructured.Unstructured
+}
+
+func (c *deleteRecorder) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	stored := &unstructured.Unstructured{}
+	stored.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), stored); err != nil {
+		return err
+	}
+	c.deleted = append(c.deleted, stored)
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestOrphan(t *testing.T) {
+	g := NewWithT(t)
+
+	testResource := &unstructured.Unstructured{}
+	testResource.SetKind("GreenMachine")
+	testResource.SetAPIVersion("green.io/v1")
+	testResource.SetName("greenMachine")
+	testResource.SetNamespace("test")
+	testResource.SetFinalizers([]string{"green.io/finalizer"})
+	testResource.SetAnnotations(map[string]string{"foo": "bar"})
+
+	c := &deleteRecorder{Client: fake.NewFakeClientWithScheme(runtime.NewScheme(), testResource.DeepCopy())}
+	g.Expect(Orphan(ctx, c, testResource.DeepCopy())).To(Succeed())
+
+	// The object is paused and its finalizers are removed before deleting it.
+	g.Expect(c.deleted).To(HaveLen(1))
+	g.Expect(c.deleted[0].GetFinalizers()).To(BeEmpty())
+	g.Expect(c.deleted[0].GetAnnotations()).To(Equal(map[string]string{"foo": "bar", clusterv1.PausedAnnotation: "true"}))
+
+	err := c.Get(ctx, client.ObjectKeyFromObject(testResource), testResource.DeepCopy())
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+}
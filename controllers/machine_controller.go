@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -209,16 +210,21 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, machine *clust
 	// Always update the readyCondition by summarizing the state of other conditions.
 	// A step counter is added to represent progress during the provisioning process (instead we are hiding it
 	// after provisioning - e.g. when a MHC condition exists - or during the deletion process).
+	summaryConditions := []clusterv1.ConditionType{
+		// Infrastructure problems should take precedence over all the other conditions
+		clusterv1.InfrastructureReadyCondition,
+		// Boostrap comes after, but it is relevant only during initial machine provisioning.
+		clusterv1.BootstrapReadyCondition,
+		// MHC reported condition should take precedence over the remediation progress
+		clusterv1.MachineHealthCheckSuccededCondition,
+		clusterv1.MachineOwnerRemediatedCondition,
+	}
+	// Conditions of the readiness gates, set by external controllers, come last.
+	for _, gate := range machine.Spec.ReadinessGates {
+		summaryConditions = append(summaryConditions, gate.ConditionType)
+	}
 	conditions.SetSummary(machine,
-		conditions.WithConditions(
-			// Infrastructure problems should take precedence over all the other conditions
-			clusterv1.InfrastructureReadyCondition,
-			// Boostrap comes after, but it is relevant only during initial machine provisioning.
-			clusterv1.BootstrapReadyCondition,
-			// MHC reported condition should take precedence over the remediation progress
-			clusterv1.MachineHealthCheckSuccededCondition,
-			clusterv1.MachineOwnerRemediatedCondition,
-		),
+		conditions.WithConditions(summaryConditions...),
 		conditions.WithStepCounterIf(machine.ObjectMeta.DeletionTimestamp.IsZero()),
 		conditions.WithStepCounterIfOnly(
 			clusterv1.BootstrapReadyCondition,
@@ -226,6 +232,12 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, machine *clust
 		),
 	)
 
+	// Readiness gates whose condition has not been set yet by the external controllers hold back the Ready condition.
+	if pending := pendingReadinessGates(machine); len(pending) > 0 && conditions.IsTrue(machine, clusterv1.ReadyCondition) {
+		conditions.MarkFalse(machine, clusterv1.ReadyCondition, clusterv1.WaitingForReadinessGatesReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for readiness gates %s", strings.Join(pending, ", "))
+	}
+
 	// Patch the object, ignoring conflicts on the conditions owned by this controller.
 	// Also, if requested, we are adding additional options like e.g. Patch ObservedGeneration when issuing the
 	// patch at the end of the reconcile loop.
@@ -268,6 +280,7 @@ func (r *MachineReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cl
 		r.reconcileInfrastructure,
 		r.reconcileNode,
 		r.reconcileInterruptibleNodeLabel,
+		r.reconcileNodeMetadata,
 	}
 
 	res := ctrl.Result{}
@@ -303,8 +316,9 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 	if isDeleteNodeAllowed {
 		// pre-drain.delete lifecycle hook
 		// Return early without error, will requeue if/when the hook owner removes the annotation.
-		if annotations.HasWithPrefix(clusterv1.PreDrainDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations) {
-			conditions.MarkFalse(m, clusterv1.PreDrainDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
+		if hooks := annotations.GetWithPrefix(clusterv1.PreDrainDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations); len(hooks) > 0 {
+			log.Info("Waiting for pre-drain delete hooks to be removed", "hooks", hooks)
+			conditions.MarkFalse(m, clusterv1.PreDrainDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "Waiting for %s", strings.Join(hooks, ", "))
 			return ctrl.Result{}, nil
 		}
 		conditions.MarkTrue(m, clusterv1.PreDrainDeleteHookSucceededCondition)
@@ -341,8 +355,9 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 
 	// pre-term.delete lifecycle hook
 	// Return early without error, will requeue if/when the hook owner removes the annotation.
-	if annotations.HasWithPrefix(clusterv1.PreTerminateDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations) {
-		conditions.MarkFalse(m, clusterv1.PreTerminateDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "")
+	if hooks := annotations.GetWithPrefix(clusterv1.PreTerminateDeleteHookAnnotationPrefix, m.ObjectMeta.Annotations); len(hooks) > 0 {
+		log.Info("Waiting for pre-terminate delete hooks to be removed", "hooks", hooks)
+		conditions.MarkFalse(m, clusterv1.PreTerminateDeleteHookSucceededCondition, clusterv1.WaitingExternalHookReason, clusterv1.ConditionSeverityInfo, "Waiting for %s", strings.Join(hooks, ", "))
 		return ctrl.Result{}, nil
 	}
 	conditions.MarkTrue(m, clusterv1.PreTerminateDeleteHookSucceededCondition)
@@ -360,11 +375,11 @@ func (r *MachineReconciler) reconcileDelete(ctx context.Context, cluster *cluste
 		return ctrl.Result{}, errors.Wrap(err, "failed to patch Machine")
 	}
 
-	if ok, err := r.reconcileDeleteInfrastructure(ctx, m); !ok || err != nil {
+	if ok, err := r.reconcileDeleteInfrastructure(ctx, cluster, m); !ok || err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if ok, err := r.reconcileDeleteBootstrap(ctx, m); !ok || err != nil {
+	if ok, err := r.reconcileDeleteBootstrap(ctx, cluster, m); !ok || err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -425,7 +440,8 @@ func (r *MachineReconciler) nodeDrainTimeoutExceeded(machine *clusterv1.Machine)
 // and if the Machine is not the last control plane node in the cluster.
 func (r *MachineReconciler) isDeleteNodeAllowed(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) error {
 	log := ctrl.LoggerFrom(ctx, "cluster", cluster.Name)
-	// Return early if the cluster is being deleted.
+	// Return early if the cluster is being deleted; this includes orphan mode, where the Node must be left in place
+	// together with the infrastructure hosting it.
 	if !cluster.DeletionTimestamp.IsZero() {
 		return errClusterIsBeingDeleted
 	}
@@ -572,8 +588,8 @@ func (r *MachineReconciler) deleteNode(ctx context.Context, cluster *clusterv1.C
 	return nil
 }
 
-func (r *MachineReconciler) reconcileDeleteBootstrap(ctx context.Context, m *clusterv1.Machine) (bool, error) {
-	obj, err := r.reconcileDeleteExternal(ctx, m, m.Spec.Bootstrap.ConfigRef)
+func (r *MachineReconciler) reconcileDeleteBootstrap(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (bool, error) {
+	obj, err := r.reconcileDeleteExternal(ctx, cluster, m, m.Spec.Bootstrap.ConfigRef)
 	if err != nil {
 		return false, err
 	}
@@ -592,8 +608,8 @@ func (r *MachineReconciler) reconcileDeleteBootstrap(ctx context.Context, m *clu
 	return false, nil
 }
 
-func (r *MachineReconciler) reconcileDeleteInfrastructure(ctx context.Context, m *clusterv1.Machine) (bool, error) {
-	obj, err := r.reconcileDeleteExternal(ctx, m, &m.Spec.InfrastructureRef)
+func (r *MachineReconciler) reconcileDeleteInfrastructure(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (bool, error) {
+	obj, err := r.reconcileDeleteExternal(ctx, cluster, m, &m.Spec.InfrastructureRef)
 	if err != nil {
 		return false, err
 	}
@@ -612,8 +628,9 @@ func (r *MachineReconciler) reconcileDeleteInfrastructure(ctx context.Context, m
 	return false, nil
 }
 
-// reconcileDeleteExternal tries to delete external references.
-func (r *MachineReconciler) reconcileDeleteExternal(ctx context.Context, m *clusterv1.Machine, ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
+// reconcileDeleteExternal tries to delete external references; if the Cluster is being deleted in orphan mode
+// the external objects are orphaned, so their providers leave the underlying resources running.
+func (r *MachineReconciler) reconcileDeleteExternal(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine, ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
 	if ref == nil {
 		return nil, nil
 	}
@@ -625,7 +642,12 @@ func (r *MachineReconciler) reconcileDeleteExternal(ctx context.Context, m *clus
 			ref.GroupVersionKind(), ref.Name, m.Name, m.Namespace)
 	}
 
-	if obj != nil {
+	if obj != nil && annotations.IsOrphanDeletion(cluster) {
+		if err := external.Orphan(ctx, r.Client, obj); err != nil {
+			return obj, errors.Wrapf(err, "failed to orphan %v %q for Machine %q in namespace %q",
+				obj.GroupVersionKind(), obj.GetName(), m.Name, m.Namespace)
+		}
+	} else if obj != nil {
 		// Issue a delete request.
 		if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
 			return obj, errors.Wrapf(err,
@@ -18,8 +18,13 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
 	apicorev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/external"
@@ -92,3 +97,184 @@ func (r *MachineReconciler) setInterruptibleNodeLabel(ctx context.Context, remot
 
 	return patchHelper.Patch(ctx, node)
 }
+
+// reconcileNodeMetadata keeps the labels and annotations in the NodeMetadataDomain and the taints defined on the
+// Machine in sync with the corresponding Node.
+func (r *MachineReconciler) reconcileNodeMetadata(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (ctrl.Result, error) {
+	// Check that the Machine hasn't been deleted or in the process
+	// and that the Machine has a NodeRef.
+	if !machine.DeletionTimestamp.IsZero() || machine.Status.NodeRef == nil {
+		return ctrl.Result{}, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	node := &apicorev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: machine.Status.NodeRef.Name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The Node is gone or not yet visible; there is nothing to sync, the Machine will be reconciled again later.
+			log.V(3).Info("Node not found, skipping metadata sync", "nodename", machine.Status.NodeRef.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Use an optimistic lock, so changes to the Node taints applied by other actors in the meantime are not overridden.
+	patch := client.MergeFromWithOptions(node.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if !syncNodeMetadata(machine, node) {
+		return ctrl.Result{}, nil
+	}
+	if err := remoteClient.Patch(ctx, node, patch); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.V(3).Info("Synchronized labels, annotations and taints to Machine's Node", "nodename", node.Name)
+	r.recorder.Event(machine, apicorev1.EventTypeNormal, "SuccessfulSyncNodeMetadata", node.Name)
+
+	return ctrl.Result{}, nil
+}
+
+// syncNodeMetadata applies the labels and annotations in the NodeMetadataDomain and the taints defined on the
+// Machine to the Node, removing the ones previously applied and no longer defined on the Machine;
+// it returns true if the Node has been changed.
+func syncNodeMetadata(machine *clusterv1.Machine, node *apicorev1.Node) bool {
+	original := node.DeepCopy()
+
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+
+	// NOTE: the tracking annotations are read before being updated by syncNodeMap.
+	managedLabels := splitManagedKeys(node.Annotations[clusterv1.ManagedNodeLabelsAnnotation])
+	managedAnnotations := splitManagedKeys(node.Annotations[clusterv1.ManagedNodeAnnotationsAnnotation])
+	managedTaints := splitManagedKeys(node.Annotations[clusterv1.ManagedNodeTaintsAnnotation])
+
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	setManagedKeys(node.Annotations, clusterv1.ManagedNodeLabelsAnnotation, syncNodeMap(node.Labels, machine.Labels, managedLabels))
+	setManagedKeys(node.Annotations, clusterv1.ManagedNodeAnnotationsAnnotation, syncNodeMap(node.Annotations, machine.Annotations, managedAnnotations))
+	setManagedKeys(node.Annotations, clusterv1.ManagedNodeTaintsAnnotation, syncNodeTaints(node, machine.Spec.Taints, managedTaints))
+
+	if len(node.Labels) == 0 {
+		node.Labels = original.Labels
+	}
+	if len(node.Annotations) == 0 {
+		node.Annotations = original.Annotations
+	}
+
+	return !reflect.DeepEqual(original.Labels, node.Labels) ||
+		!reflect.DeepEqual(original.Annotations, node.Annotations) ||
+		!reflect.DeepEqual(original.Spec.Taints, node.Spec.Taints)
+}
+
+// syncNodeMap sets the entries in the NodeMetadataDomain from the Machine into the Node, and deletes the previously
+// managed entries no longer defined on the Machine; it returns the keys of the entries now managed.
+func syncNodeMap(nodeMap, machineMap map[string]string, managed []string) []string {
+	desired := map[string]string{}
+	for k, v := range machineMap {
+		if isNodeMetadataKey(k) {
+			desired[k] = v
+		}
+	}
+
+	for _, k := range managed {
+		if _, ok := desired[k]; !ok {
+			delete(nodeMap, k)
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for k, v := range desired {
+		nodeMap[k] = v
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// syncNodeTaints applies the taints from the Machine to the Node, and removes the previously managed taints
+// no longer defined on the Machine; it returns the key:effect of the taints now managed.
+func syncNodeTaints(node *apicorev1.Node, machineTaints []apicorev1.Taint, managed []string) []string {
+	desired := map[string]apicorev1.Taint{}
+	for _, t := range machineTaints {
+		desired[taintKey(t)] = t
+	}
+
+	managedSet := map[string]bool{}
+	for _, k := range managed {
+		managedSet[k] = true
+	}
+
+	taints := []apicorev1.Taint{}
+	for _, t := range node.Spec.Taints {
+		k := taintKey(t)
+		if d, ok := desired[k]; ok {
+			// Preserve the TimeAdded of existing taints, if not otherwise specified.
+			if d.TimeAdded == nil {
+				d.TimeAdded = t.TimeAdded
+			}
+			taints = append(taints, d)
+			delete(desired, k)
+			continue
+		}
+		if managedSet[k] {
+			continue
+		}
+		taints = append(taints, t)
+	}
+
+	// Taints not yet on the Node are appended in the order they are defined on the Machine.
+	for _, t := range machineTaints {
+		if _, ok := desired[taintKey(t)]; ok {
+			taints = append(taints, t)
+		}
+	}
+
+	if len(taints) == 0 {
+		taints = nil
+	}
+	node.Spec.Taints = taints
+
+	keys := make([]string, 0, len(machineTaints))
+	for _, t := range machineTaints {
+		keys = append(keys, taintKey(t))
+	}
+	return keys
+}
+
+// isNodeMetadataKey returns true if the label or annotation key has a prefix in the NodeMetadataDomain or in one of its subdomains.
+func isNodeMetadataKey(key string) bool {
+	i := strings.Index(key, "/")
+	if i < 0 {
+		return false
+	}
+	prefix := key[:i]
+	return prefix == clusterv1.NodeMetadataDomain || strings.HasSuffix(prefix, "."+clusterv1.NodeMetadataDomain)
+}
+
+func taintKey(t apicorev1.Taint) string {
+	return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+}
+
+func splitManagedKeys(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// setManagedKeys records the managed keys into the given tracking annotation, or removes it if there are no managed keys.
+func setManagedKeys(annotations map[string]string, annotation string, keys []string) {
+	if len(keys) == 0 {
+		delete(annotations, annotation)
+		return
+	}
+	keys = append([]string{}, keys...)
+	sort.Strings(keys)
+	annotations[annotation] = strings.Join(keys, ",")
+}
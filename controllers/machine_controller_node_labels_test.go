@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/remote"
@@ -138,3 +139,221 @@ func TestReconcileInterruptibleNodeLabel(t *testing.T) {
 		return ok
 	}, 10*time.Second).Should(BeTrue())
 }
+
+func TestReconcileNodeMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	ns, err := testEnv.CreateNamespace(ctx, "test-node-metadata")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-1",
+			Namespace: ns.Name,
+		},
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-metadata",
+		},
+	}
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-test",
+			Namespace: ns.Name,
+			Labels: map[string]string{
+				"node.cluster.x-k8s.io/pool": "gpu",
+				"not-propagated":             "",
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: cluster.Name,
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+				Kind:       "InfrastructureMachine",
+				Name:       "infra-config1",
+				Namespace:  ns.Name,
+			},
+			Bootstrap: clusterv1.Bootstrap{
+				DataSecretName: pointer.StringPtr("data"),
+			},
+			Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{
+				Name: node.Name,
+			},
+		},
+	}
+
+	g.Expect(testEnv.Create(ctx, cluster)).To(Succeed())
+	g.Expect(testEnv.Create(ctx, node)).To(Succeed())
+	defer func(do ...client.Object) {
+		g.Expect(testEnv.Cleanup(ctx, do...)).To(Succeed())
+	}(cluster, node)
+
+	r := &MachineReconciler{
+		Client:   testEnv.Client,
+		Tracker:  remote.NewTestClusterCacheTracker(log.NullLogger{}, testEnv.Client, scheme.Scheme, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	_, err = r.reconcileNodeMetadata(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	updatedNode := &corev1.Node{}
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, client.ObjectKey{Name: node.Name}, updatedNode); err != nil {
+			return false
+		}
+		return updatedNode.Labels["node.cluster.x-k8s.io/pool"] == "gpu" && len(updatedNode.Spec.Taints) == 1
+	}, 10*time.Second).Should(BeTrue())
+	g.Expect(updatedNode.Labels).ToNot(HaveKey("not-propagated"))
+
+	// Removing the label and the taint from the Machine removes them from the Node.
+	machine.Labels = nil
+	machine.Spec.Taints = nil
+	_, err = r.reconcileNodeMetadata(ctx, cluster, machine)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Eventually(func() bool {
+		if err := testEnv.Get(ctx, client.ObjectKey{Name: node.Name}, updatedNode); err != nil {
+			return false
+		}
+		_, hasLabel := updatedNode.Labels["node.cluster.x-k8s.io/pool"]
+		return !hasLabel && len(updatedNode.Spec.Taints) == 0
+	}, 10*time.Second).Should(BeTrue())
+}
+
+func TestSyncNodeMetadata(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	externalTaint := corev1.Taint{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoExecute}
+
+	tests := []struct {
+		name        string
+		machine     *clusterv1.Machine
+		node        *corev1.Node
+		wantChanged bool
+		wantNode    *corev1.Node
+	}{
+		{
+			name:        "no changes if there is nothing to sync",
+			machine:     &clusterv1.Machine{},
+			node:        &corev1.Node{},
+			wantChanged: false,
+			wantNode:    &corev1.Node{},
+		},
+		{
+			name: "adds labels and annotations in the node metadata domain and taints",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"node.cluster.x-k8s.io/pool":         "a",
+						"example.node.cluster.x-k8s.io/role": "b",
+						"cluster.x-k8s.io/cluster-name":      "c",
+						"not-a-prefixed-label":               "d",
+					},
+					Annotations: map[string]string{
+						"node.cluster.x-k8s.io/owner": "e",
+						"other.io/annotation":         "f",
+					},
+				},
+				Spec: clusterv1.MachineSpec{Taints: []corev1.Taint{gpuTaint}},
+			},
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"kubernetes.io/hostname": "node"}},
+				Spec:       corev1.NodeSpec{Taints: []corev1.Taint{externalTaint}},
+			},
+			wantChanged: true,
+			wantNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"kubernetes.io/hostname":             "node",
+						"node.cluster.x-k8s.io/pool":         "a",
+						"example.node.cluster.x-k8s.io/role": "b",
+					},
+					Annotations: map[string]string{
+						"node.cluster.x-k8s.io/owner":              "e",
+						clusterv1.ManagedNodeLabelsAnnotation:      "example.node.cluster.x-k8s.io/role,node.cluster.x-k8s.io/pool",
+						clusterv1.ManagedNodeAnnotationsAnnotation: "node.cluster.x-k8s.io/owner",
+						clusterv1.ManagedNodeTaintsAnnotation:      "dedicated:NoSchedule",
+					},
+				},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{externalTaint, gpuTaint}},
+			},
+		},
+		{
+			name: "removes managed labels, annotations and taints no longer defined on the machine",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"node.cluster.x-k8s.io/pool": "a"},
+				},
+			},
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"node.cluster.x-k8s.io/pool":         "a",
+						"example.node.cluster.x-k8s.io/role": "b",
+						"node.cluster.x-k8s.io/unmanaged":    "c",
+					},
+					Annotations: map[string]string{
+						"node.cluster.x-k8s.io/owner":              "e",
+						clusterv1.ManagedNodeLabelsAnnotation:      "example.node.cluster.x-k8s.io/role,node.cluster.x-k8s.io/pool",
+						clusterv1.ManagedNodeAnnotationsAnnotation: "node.cluster.x-k8s.io/owner",
+						clusterv1.ManagedNodeTaintsAnnotation:      "dedicated:NoSchedule",
+					},
+				},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{externalTaint, gpuTaint}},
+			},
+			wantChanged: true,
+			wantNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"node.cluster.x-k8s.io/pool":      "a",
+						"node.cluster.x-k8s.io/unmanaged": "c",
+					},
+					Annotations: map[string]string{
+						clusterv1.ManagedNodeLabelsAnnotation: "node.cluster.x-k8s.io/pool",
+					},
+				},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{externalTaint}},
+			},
+		},
+		{
+			name: "updates the value of managed taints",
+			machine: &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "cpu", Effect: corev1.TaintEffectNoSchedule}}},
+			},
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{clusterv1.ManagedNodeTaintsAnnotation: "dedicated:NoSchedule"},
+				},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{gpuTaint}},
+			},
+			wantChanged: true,
+			wantNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{clusterv1.ManagedNodeTaintsAnnotation: "dedicated:NoSchedule"},
+				},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "cpu", Effect: corev1.TaintEffectNoSchedule}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(syncNodeMetadata(tt.machine, tt.node)).To(Equal(tt.wantChanged))
+			g.Expect(tt.node.Labels).To(Equal(tt.wantNode.Labels))
+			g.Expect(tt.node.Annotations).To(Equal(tt.wantNode.Annotations))
+			g.Expect(tt.node.Spec.Taints).To(Equal(tt.wantNode.Spec.Taints))
+
+			// Syncing again is a no-op.
+			g.Expect(syncNodeMetadata(tt.machine, tt.node)).To(BeFalse())
+		})
+	}
+}
@@ -19,10 +19,12 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/metrics"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -82,6 +84,7 @@ func (r *MachineReconciler) reconcileNode(ctx context.Context, cluster *clusterv
 			UID:        node.UID,
 		}
 		log.Info("Set Machine's NodeRef", "noderef", machine.Status.NodeRef.Name)
+		metrics.RecordMachineProvisioned(machine, time.Now())
 		r.recorder.Event(machine, corev1.EventTypeNormal, "SuccessfulSetNodeRef", machine.Status.NodeRef.Name)
 	}
 
@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/external"
@@ -975,6 +976,42 @@ func TestMachineConditions(t *testing.T) {
 				conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.NodeNotFoundReason, clusterv1.ConditionSeverityWarning, ""),
 			},
 		},
+		{
+			name:           "ready condition summary waits for the readiness gates conditions to be set",
+			infraReady:     true,
+			bootstrapReady: true,
+			beforeFunc: func(bootstrap, infra *unstructured.Unstructured, m *clusterv1.Machine) {
+				m.Spec.ReadinessGates = []clusterv1.MachineReadinessGate{{ConditionType: "GPUDriverReady"}, {ConditionType: "SecurityAgentReady"}}
+				conditions.MarkTrue(m, "SecurityAgentReady")
+			},
+			conditionsToAssert: []*clusterv1.Condition{
+				conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.WaitingForReadinessGatesReason, clusterv1.ConditionSeverityInfo, "Waiting for readiness gates GPUDriverReady"),
+			},
+		},
+		{
+			name:           "ready condition summary consumes reason from the readiness gates conditions",
+			infraReady:     true,
+			bootstrapReady: true,
+			beforeFunc: func(bootstrap, infra *unstructured.Unstructured, m *clusterv1.Machine) {
+				m.Spec.ReadinessGates = []clusterv1.MachineReadinessGate{{ConditionType: "GPUDriverReady"}}
+				conditions.MarkFalse(m, "GPUDriverReady", "InstallingDriver", clusterv1.ConditionSeverityInfo, "")
+			},
+			conditionsToAssert: []*clusterv1.Condition{
+				conditions.FalseCondition(clusterv1.ReadyCondition, "InstallingDriver", clusterv1.ConditionSeverityInfo, ""),
+			},
+		},
+		{
+			name:           "ready condition summary is true when the readiness gates conditions are true",
+			infraReady:     true,
+			bootstrapReady: true,
+			beforeFunc: func(bootstrap, infra *unstructured.Unstructured, m *clusterv1.Machine) {
+				m.Spec.ReadinessGates = []clusterv1.MachineReadinessGate{{ConditionType: "GPUDriverReady"}}
+				conditions.MarkTrue(m, "GPUDriverReady")
+			},
+			conditionsToAssert: []*clusterv1.Condition{
+				conditions.TrueCondition(clusterv1.ReadyCondition),
+			},
+		},
 	}
 
 	for _, tt := range testcases {
@@ -1093,7 +1130,7 @@ func TestReconcileDeleteExternal(t *testing.T) {
 				Client: helpers.NewFakeClientWithScheme(scheme.Scheme, objs...),
 			}
 
-			obj, err := r.reconcileDeleteExternal(ctx, machine, machine.Spec.Bootstrap.ConfigRef)
+			obj, err := r.reconcileDeleteExternal(ctx, testCluster, machine, machine.Spec.Bootstrap.ConfigRef)
 			g.Expect(obj).To(Equal(tc.expected))
 			if tc.expectError {
 				g.Expect(err).To(HaveOccurred())
@@ -1104,6 +1141,62 @@ func TestReconcileDeleteExternal(t *testing.T) {
 	}
 }
 
+func TestReconcileDeleteExternalOrphan(t *testing.T) {
+	g := NewWithT(t)
+
+	deletionTimestamp := metav1.Now()
+	testCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "test-cluster",
+			DeletionTimestamp: &deletionTimestamp,
+			Annotations:       map[string]string{clusterv1.OrphanAnnotation: ""},
+		},
+	}
+
+	infraMachine := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+			"metadata": map[string]interface{}{
+				"name":       "orphan-infra",
+				"namespace":  "default",
+				"finalizers": []interface{}{"infrastructure.cluster.x-k8s.io/finalizer"},
+			},
+		},
+	}
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "orphan",
+			Namespace: "default",
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test-cluster",
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+				Kind:       "InfrastructureMachine",
+				Name:       "orphan-infra",
+			},
+		},
+	}
+
+	r := &MachineReconciler{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, testCluster, machine, infraMachine),
+	}
+
+	// The infrastructure machine is paused and its finalizers are removed, so the provider never destroys the infrastructure.
+	obj, err := r.reconcileDeleteExternal(ctx, testCluster, machine, &machine.Spec.InfrastructureRef)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(obj).NotTo(BeNil())
+	g.Expect(obj.GetFinalizers()).To(BeEmpty())
+	g.Expect(obj.GetAnnotations()).To(HaveKey(clusterv1.PausedAnnotation))
+
+	obj, err = r.reconcileDeleteExternal(ctx, testCluster, machine, &machine.Spec.InfrastructureRef)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(obj).To(BeNil())
+}
+
 func TestRemoveMachineFinalizerAfterDeleteReconcile(t *testing.T) {
 	g := NewWithT(t)
 
@@ -1460,6 +1553,19 @@ func TestIsDeleteNodeAllowed(t *testing.T) {
 			machine:       &clusterv1.Machine{},
 			expectedError: errClusterIsBeingDeleted,
 		},
+		{
+			name: "has nodeRef and cluster is being deleted in orphan mode",
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					DeletionTimestamp: &deletionts,
+					Annotations: map[string]string{
+						clusterv1.OrphanAnnotation: "",
+					},
+				},
+			},
+			machine:       &clusterv1.Machine{},
+			expectedError: errClusterIsBeingDeleted,
+		},
 		{
 			name: "has nodeRef and control plane is healthy and externally managed",
 			cluster: &clusterv1.Cluster{
@@ -1671,6 +1777,97 @@ func TestIsDeleteNodeAllowed(t *testing.T) {
 	}
 }
 
+func TestReconcileDeleteLifecycleHooks(t *testing.T) {
+	testCases := []struct {
+		name              string
+		annotations       map[string]string
+		expectedCondition clusterv1.ConditionType
+		expectedMessage   string
+	}{
+		{
+			name: "should wait for the pre-drain delete hooks",
+			annotations: map[string]string{
+				clusterv1.PreDrainDeleteHookAnnotationPrefix + "/storage":       "storage-operator",
+				clusterv1.PreDrainDeleteHookAnnotationPrefix + "/load-balancer": "lb-operator",
+				clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/storage":   "storage-operator",
+			},
+			expectedCondition: clusterv1.PreDrainDeleteHookSucceededCondition,
+			expectedMessage:   "Waiting for pre-drain.delete.hook.machine.cluster.x-k8s.io/load-balancer, pre-drain.delete.hook.machine.cluster.x-k8s.io/storage",
+		},
+		{
+			name: "should wait for the pre-terminate delete hooks",
+			annotations: map[string]string{
+				clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/storage": "storage-operator",
+			},
+			expectedCondition: clusterv1.PreTerminateDeleteHookSucceededCondition,
+			expectedMessage:   "Waiting for pre-terminate.delete.hook.machine.cluster.x-k8s.io/storage",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "default",
+				},
+			}
+			controlPlaneMachine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cp1",
+					Namespace: "default",
+					Labels: map[string]string{
+						clusterv1.ClusterLabelName:             "test-cluster",
+						clusterv1.MachineControlPlaneLabelName: "",
+					},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "test-cluster",
+				},
+			}
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "worker",
+					Namespace: "default",
+					Labels: map[string]string{
+						clusterv1.ClusterLabelName: "test-cluster",
+					},
+					Annotations: tc.annotations,
+					Finalizers:  []string{clusterv1.MachineFinalizer},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName:       "test-cluster",
+					InfrastructureRef: corev1.ObjectReference{},
+					Bootstrap:         clusterv1.Bootstrap{DataSecretName: pointer.StringPtr("data")},
+				},
+				Status: clusterv1.MachineStatus{
+					NodeRef: &corev1.ObjectReference{
+						Name: "worker-node",
+					},
+				},
+			}
+
+			r := &MachineReconciler{
+				Client:   helpers.NewFakeClientWithScheme(scheme.Scheme, cluster, controlPlaneMachine, machine),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			res, err := r.reconcileDelete(ctx, cluster, machine)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(res.IsZero()).To(BeTrue())
+			g.Expect(machine.Finalizers).To(ContainElement(clusterv1.MachineFinalizer))
+
+			condition := conditions.Get(machine, tc.expectedCondition)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			g.Expect(condition.Reason).To(Equal(clusterv1.WaitingExternalHookReason))
+			g.Expect(condition.Message).To(Equal(tc.expectedMessage))
+		})
+	}
+}
+
 // adds a condition list to an external object
 func addConditionsToExternal(u *unstructured.Unstructured, newConditions clusterv1.Conditions) {
 	existingConditions := clusterv1.Conditions{}
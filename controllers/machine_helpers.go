@@ -18,11 +18,13 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -66,3 +68,33 @@ func hasMatchingLabels(matchSelector metav1.LabelSelector, matchLabels map[strin
 	}
 	return true
 }
+
+// pendingReadinessGates returns the condition types of the readiness gates of the Machine whose condition is not true.
+func pendingReadinessGates(machine *clusterv1.Machine) []string {
+	var pending []string
+	for _, gate := range machine.Spec.ReadinessGates {
+		if !conditions.IsTrue(machine, gate.ConditionType) {
+			pending = append(pending, string(gate.ConditionType))
+		}
+	}
+	return pending
+}
+
+// isReadinessGatesAvailable returns true if the conditions of all the readiness gates of the Machine
+// have been true for at least minReadySeconds.
+func isReadinessGatesAvailable(machine *clusterv1.Machine, minReadySeconds int32, now metav1.Time) bool {
+	minReadySecondsDuration := time.Duration(minReadySeconds) * time.Second
+	for _, gate := range machine.Spec.ReadinessGates {
+		if !conditions.IsTrue(machine, gate.ConditionType) {
+			return false
+		}
+		if minReadySeconds == 0 {
+			continue
+		}
+		lastTransitionTime := conditions.GetLastTransitionTime(machine, gate.ConditionType)
+		if lastTransitionTime == nil || !lastTransitionTime.Add(minReadySecondsDuration).Before(now.Time) {
+			return false
+		}
+	}
+	return true
+}
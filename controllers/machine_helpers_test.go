@@ -18,14 +18,17 @@ package controllers
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func Test_getActiveMachinesInCluster(t *testing.T) {
@@ -198,3 +201,88 @@ func TestMachineHealthCheckHasMatchingLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestPendingReadinessGates(t *testing.T) {
+	g := NewWithT(t)
+
+	machine := &clusterv1.Machine{
+		Spec: clusterv1.MachineSpec{
+			ReadinessGates: []clusterv1.MachineReadinessGate{
+				{ConditionType: "GPUDriverReady"},
+				{ConditionType: "SecurityAgentReady"},
+			},
+		},
+	}
+	g.Expect(pendingReadinessGates(machine)).To(Equal([]string{"GPUDriverReady", "SecurityAgentReady"}))
+
+	conditions.MarkTrue(machine, "GPUDriverReady")
+	conditions.MarkFalse(machine, "SecurityAgentReady", "Installing", clusterv1.ConditionSeverityInfo, "")
+	g.Expect(pendingReadinessGates(machine)).To(Equal([]string{"SecurityAgentReady"}))
+
+	conditions.MarkTrue(machine, "SecurityAgentReady")
+	g.Expect(pendingReadinessGates(machine)).To(BeEmpty())
+}
+
+func TestIsReadinessGatesAvailable(t *testing.T) {
+	now := metav1.Now()
+	newMachine := func(lastTransitionTime time.Time) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			Spec: clusterv1.MachineSpec{
+				ReadinessGates: []clusterv1.MachineReadinessGate{{ConditionType: "GPUDriverReady"}},
+			},
+			Status: clusterv1.MachineStatus{
+				Conditions: clusterv1.Conditions{{
+					Type:               "GPUDriverReady",
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(lastTransitionTime),
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		machine         *clusterv1.Machine
+		minReadySeconds int32
+		expected        bool
+	}{
+		{
+			name:     "no readiness gates",
+			machine:  &clusterv1.Machine{},
+			expected: true,
+		},
+		{
+			name: "readiness gate condition not set",
+			machine: &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{
+					ReadinessGates: []clusterv1.MachineReadinessGate{{ConditionType: "GPUDriverReady"}},
+				},
+			},
+			expected: false,
+		},
+		{
+			name:     "readiness gate condition true without minReadySeconds",
+			machine:  newMachine(now.Time),
+			expected: true,
+		},
+		{
+			name:            "readiness gate condition true for less than minReadySeconds",
+			machine:         newMachine(now.Add(-5 * time.Second)),
+			minReadySeconds: 10,
+			expected:        false,
+		},
+		{
+			name:            "readiness gate condition true for more than minReadySeconds",
+			machine:         newMachine(now.Add(-15 * time.Second)),
+			minReadySeconds: 10,
+			expected:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(isReadinessGatesAvailable(tt.machine, tt.minReadySeconds, now)).To(Equal(tt.expected))
+		})
+	}
+}
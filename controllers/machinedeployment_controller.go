@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +30,8 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/patch"
@@ -50,13 +53,15 @@ var (
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments;machinedeployments/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch;update;patch
 
 // MachineDeploymentReconciler reconciles a MachineDeployment object
 type MachineDeploymentReconciler struct {
 	Client client.Client
 
-	recorder   record.EventRecorder
-	restConfig *rest.Config
+	recorder        record.EventRecorder
+	restConfig      *rest.Config
+	externalTracker external.ObjectTracker
 }
 
 func (r *MachineDeploymentReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -83,7 +88,11 @@ func (r *MachineDeploymentReconciler) SetupWithManager(ctx context.Context, mgr
 		&source.Kind{Type: &clusterv1.Cluster{}},
 		handler.EnqueueRequestsFromMapFunc(clusterToMachineDeployments),
 		// TODO: should this wait for Cluster.Status.InfrastructureReady similar to Infra Machine resources?
-		predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+		// Rollouts waiting for a maintenance window start as soon as the maintenance windows change.
+		predicates.Any(ctrl.LoggerFrom(ctx),
+			predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+			predicates.ClusterUpdateMaintenanceWindows(ctrl.LoggerFrom(ctx)),
+		),
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to add Watch for Clusters to controller manager")
@@ -91,6 +100,9 @@ func (r *MachineDeploymentReconciler) SetupWithManager(ctx context.Context, mgr
 
 	r.recorder = mgr.GetEventRecorderFor("machinedeployment-controller")
 	r.restConfig = mgr.GetConfig()
+	r.externalTracker = external.ObjectTracker{
+		Controller: c,
+	}
 	return nil
 }
 
@@ -185,6 +197,12 @@ func (r *MachineDeploymentReconciler) reconcile(ctx context.Context, cluster *cl
 		}
 	}
 
+	// Make sure the annotations used by the cluster autoscaler to scale from zero are up to date.
+	if _, err := reconcileCapacityAnnotations(ctx, r.Client, &r.externalTracker,
+		handler.EnqueueRequestsFromMapFunc(r.InfrastructureTemplateToDeployments), &d.Spec.Template, d); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile capacity annotations for MachineDeployment %s/%s", d.Namespace, d.Name)
+	}
+
 	msList, err := r.getMachineSetsForDeployment(ctx, d)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -195,12 +213,36 @@ func (r *MachineDeploymentReconciler) reconcile(ctx context.Context, cluster *cl
 	}
 
 	if d.Spec.Strategy.Type == clusterv1.RollingUpdateMachineDeploymentStrategyType {
-		return ctrl.Result{}, r.rolloutRolling(ctx, d, msList)
+		// Machines are replaced only while one of the Cluster's maintenance windows is open.
+		if result, waiting, err := r.waitForMaintenanceWindow(ctx, cluster, d, msList, time.Now()); err != nil || waiting {
+			return result, err
+		}
+		if err := r.rolloutRolling(ctx, d, msList); err != nil {
+			return ctrl.Result{}, err
+		}
+		return requeueAtProgressDeadline(d, time.Now()), nil
+	}
+
+	if d.Spec.Strategy.Type == clusterv1.OnDeleteMachineDeploymentStrategyType {
+		if err := r.rolloutOnDelete(ctx, d, msList); err != nil {
+			return ctrl.Result{}, err
+		}
+		return requeueAtProgressDeadline(d, time.Now()), nil
 	}
 
 	return ctrl.Result{}, errors.Errorf("unexpected deployment strategy type: %s", d.Spec.Strategy.Type)
 }
 
+// requeueAtProgressDeadline requeues a deployment whose rollout is in progress at the time its progress deadline
+// expires, so the Progressing condition is updated even if no other event triggers a reconcile.
+func requeueAtProgressDeadline(d *clusterv1.MachineDeployment, now time.Time) ctrl.Result {
+	timeLeft, ok := mdutil.DeploymentTimeUntilProgressDeadline(d, &d.Status, now)
+	if !ok || timeLeft <= 0 {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: timeLeft + time.Second}
+}
+
 // getMachineSetsForDeployment returns a list of MachineSets associated with a MachineDeployment.
 func (r *MachineDeploymentReconciler) getMachineSetsForDeployment(ctx context.Context, d *clusterv1.MachineDeployment) ([]*clusterv1.MachineSet, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -325,6 +367,24 @@ func (r *MachineDeploymentReconciler) MachineSetToDeployments(o client.Object) [
 	return result
 }
 
+// InfrastructureTemplateToDeployments is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for MachineDeployments using the infrastructure machine template.
+func (r *MachineDeploymentReconciler) InfrastructureTemplateToDeployments(o client.Object) []ctrl.Request {
+	mdList := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(context.TODO(), mdList, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for i := range mdList.Items {
+		md := &mdList.Items[i]
+		if referencesInfrastructureTemplate(&md.Spec.Template, o) {
+			result = append(result, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(md)})
+		}
+	}
+	return result
+}
+
 func (r *MachineDeploymentReconciler) shouldAdopt(md *clusterv1.MachineDeployment) bool {
 	return !util.HasOwner(md.OwnerReferences, clusterv1.GroupVersion.String(), []string{"Cluster"})
 }
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMachineDeploymentRolloutOnDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "md", Namespace: metav1.NamespaceDefault},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Replicas: pointer.Int32Ptr(3),
+			Strategy: &clusterv1.MachineDeploymentStrategy{
+				Type: clusterv1.OnDeleteMachineDeploymentStrategyType,
+			},
+		},
+	}
+
+	newMachineSet := func(name string, replicas int32) *clusterv1.MachineSet {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas: pointer.Int32Ptr(replicas),
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"machineset": name}},
+			},
+		}
+	}
+	oldMS := newMachineSet("old", 3)
+	newMS := newMachineSet("new", 0)
+
+	objs := []client.Object{oldMS, newMS}
+	for i := 0; i < 3; i++ {
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("old-%d", i),
+				Namespace: metav1.NamespaceDefault,
+				Labels:    map[string]string{"machineset": oldMS.Name},
+			},
+		}
+		if i == 0 {
+			machine.Annotations = map[string]string{clusterv1.DeleteMachineAnnotation: ""}
+		}
+		objs = append(objs, machine)
+	}
+
+	r := &MachineDeploymentReconciler{
+		Client:   fake.NewClientBuilder().WithObjects(objs...).Build(),
+		recorder: record.NewFakeRecorder(32),
+	}
+	allMSs := []*clusterv1.MachineSet{oldMS, newMS}
+
+	// Nothing to scale up until machines of the old MachineSet are deleted.
+	g.Expect(r.reconcileNewMachineSetOnDelete(ctx, allMSs, newMS, deployment)).To(Succeed())
+	g.Expect(*newMS.Spec.Replicas).To(Equal(int32(0)))
+
+	// The old MachineSet is scaled down to account for the machine marked for deletion,
+	// and it is prevented from creating new machines.
+	g.Expect(r.reconcileOldMachineSetsOnDelete(ctx, []*clusterv1.MachineSet{oldMS}, allMSs, deployment)).To(Succeed())
+	g.Expect(*oldMS.Spec.Replicas).To(Equal(int32(2)))
+	g.Expect(oldMS.Annotations).To(HaveKey(clusterv1.DisableMachineCreate))
+
+	// The new MachineSet replaces the deleted machine.
+	g.Expect(r.reconcileNewMachineSetOnDelete(ctx, allMSs, newMS, deployment)).To(Succeed())
+	g.Expect(*newMS.Spec.Replicas).To(Equal(int32(1)))
+
+	// Rolling back to the old MachineSet enables machine creation again.
+	g.Expect(r.reconcileNewMachineSetOnDelete(ctx, allMSs, oldMS, deployment)).To(Succeed())
+	g.Expect(oldMS.Annotations).ToNot(HaveKey(clusterv1.DisableMachineCreate))
+}
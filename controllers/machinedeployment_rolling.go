@@ -18,12 +18,16 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/utils/integer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/maintenance"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -70,6 +74,40 @@ func (r *MachineDeploymentReconciler) rolloutRolling(ctx context.Context, d *clu
 	return nil
 }
 
+// waitForMaintenanceWindow returns true if Machines of old MachineSets have to be replaced while the maintenance
+// windows of the Cluster are closed. In this case the rollout does not progress: the deployment is only scaled,
+// like when it is paused, and it is requeued when the next maintenance window opens.
+func (r *MachineDeploymentReconciler) waitForMaintenanceWindow(ctx context.Context, cluster *clusterv1.Cluster, d *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet, now time.Time) (ctrl.Result, bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	open, next, err := maintenance.IsOpen(cluster, now)
+	if err != nil {
+		return ctrl.Result{}, false, err
+	}
+	if open {
+		return ctrl.Result{}, false, nil
+	}
+	if oldMSs, _ := mdutil.FindOldMachineSets(d, msList); len(oldMSs) == 0 {
+		return ctrl.Result{}, false, nil
+	}
+
+	log.Info("Waiting for a maintenance window to open before rolling out Machines", "next", next)
+	if err := r.sync(ctx, d, msList); err != nil {
+		return ctrl.Result{}, true, err
+	}
+
+	// The time spent waiting for a maintenance window is not accounted against the progress deadline.
+	d.Status.LastProgressTime = nil
+	message := "Waiting for a maintenance window to open"
+	result := ctrl.Result{}
+	if !next.IsZero() {
+		message = fmt.Sprintf("Waiting for the maintenance window opening at %s", next.UTC().Format(time.RFC3339))
+		result.RequeueAfter = next.Sub(now)
+	}
+	conditions.MarkFalse(d, clusterv1.MachineDeploymentProgressingCondition, clusterv1.WaitingForMaintenanceWindowReason, clusterv1.ConditionSeverityInfo, message)
+	return result, true, nil
+}
+
 func (r *MachineDeploymentReconciler) reconcileNewMachineSet(ctx context.Context, allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) error {
 	if deployment.Spec.Replicas == nil {
 		return errors.Errorf("spec replicas for deployment set %v is nil, this is unexpected", deployment.Name)
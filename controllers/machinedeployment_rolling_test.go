@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMachineDeploymentWaitForMaintenanceWindow(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	nightly := clusterv1.MaintenanceWindow{
+		Schedule: "0 22 * * *",
+		Duration: metav1.Duration{Duration: 4 * time.Hour},
+	}
+
+	oldTemplate := clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels: map[string]string{"pool": "a", mdutil.DefaultMachineDeploymentUniqueLabelKey: "old"},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster",
+			Version:     pointer.StringPtr("v1.19.1"),
+		},
+	}
+	newTemplate := clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels: map[string]string{"pool": "a"},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster",
+			Version:     pointer.StringPtr("v1.20.0"),
+		},
+	}
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		template      clusterv1.MachineTemplateSpec
+		expectWaiting bool
+	}{
+		{
+			name:          "waits if Machines have to be replaced outside of the maintenance windows",
+			template:      newTemplate,
+			expectWaiting: true,
+		},
+		{
+			name:          "does not wait if the Cluster has the override annotation",
+			annotations:   map[string]string{clusterv1.MaintenanceWindowOverrideAnnotation: ""},
+			template:      newTemplate,
+			expectWaiting: false,
+		},
+		{
+			name:          "does not wait if no Machines have to be replaced",
+			template:      oldTemplate,
+			expectWaiting: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cluster",
+					Namespace:   metav1.NamespaceDefault,
+					Annotations: tt.annotations,
+				},
+				Spec: clusterv1.ClusterSpec{
+					MaintenanceWindows: []clusterv1.MaintenanceWindow{nightly},
+				},
+			}
+			ms := &clusterv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "md-old",
+					Namespace:   metav1.NamespaceDefault,
+					Annotations: map[string]string{clusterv1.RevisionAnnotation: "1"},
+				},
+				Spec: clusterv1.MachineSetSpec{
+					Replicas: pointer.Int32Ptr(2),
+					Selector: metav1.LabelSelector{MatchLabels: oldTemplate.Labels},
+					Template: *oldTemplate.DeepCopy(),
+				},
+			}
+			deployment := &clusterv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "md",
+					Namespace:   metav1.NamespaceDefault,
+					Annotations: map[string]string{clusterv1.RevisionAnnotation: "1"},
+				},
+				Spec: clusterv1.MachineDeploymentSpec{
+					Replicas:        pointer.Int32Ptr(2),
+					MinReadySeconds: pointer.Int32Ptr(0),
+					Selector:        metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}},
+					Template:        *tt.template.DeepCopy(),
+					Strategy: &clusterv1.MachineDeploymentStrategy{
+						Type: clusterv1.RollingUpdateMachineDeploymentStrategyType,
+						RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{
+							MaxSurge:       &intstr.IntOrString{IntVal: 1},
+							MaxUnavailable: &intstr.IntOrString{IntVal: 0},
+						},
+					},
+				},
+				Status: clusterv1.MachineDeploymentStatus{
+					LastProgressTime: &metav1.Time{Time: now.Add(-time.Minute)},
+				},
+			}
+
+			r := &MachineDeploymentReconciler{
+				Client:   fake.NewClientBuilder().WithObjects(cluster, deployment, ms).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			result, waiting, err := r.waitForMaintenanceWindow(ctx, cluster, deployment, []*clusterv1.MachineSet{ms}, now)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(waiting).To(Equal(tt.expectWaiting))
+			if !tt.expectWaiting {
+				g.Expect(result.IsZero()).To(BeTrue())
+				return
+			}
+
+			// The deployment is requeued when the window opens, and the time spent waiting is not accounted against the progress deadline.
+			g.Expect(result.RequeueAfter).To(Equal(10 * time.Hour))
+			g.Expect(deployment.Status.LastProgressTime).To(BeNil())
+			g.Expect(conditions.IsFalse(deployment, clusterv1.MachineDeploymentProgressingCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(deployment, clusterv1.MachineDeploymentProgressingCondition)).To(Equal(clusterv1.WaitingForMaintenanceWindowReason))
+
+			// No new MachineSet is created.
+			machineSets := &clusterv1.MachineSetList{}
+			g.Expect(r.Client.List(ctx, machineSets)).To(Succeed())
+			g.Expect(machineSets.Items).To(HaveLen(1))
+		})
+	}
+}
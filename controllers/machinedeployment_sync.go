@@ -21,9 +21,12 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apirand "k8s.io/apimachinery/pkg/util/rand"
@@ -32,6 +35,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/mdutil"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -109,12 +113,37 @@ func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *c
 			return nil, err
 		}
 
+		// Propagate in place to the MachineSet the changes to the machine template which do not require the Machines
+		// to be replaced, without bumping the revision; this is skipped for paused deployments. The MachineSet
+		// controller then propagates the changes to the Machines.
+		var inPlaceFields []string
+		if !d.Spec.Paused {
+			inPlaceFields = mdutil.InPlaceChangedFields(&msCopy.Spec.Template, &d.Spec.Template)
+		}
+		if len(inPlaceFields) > 0 {
+			// The MachineSet controller propagates to the Machines the changes since the template tracked by the
+			// in-place annotation; if it is not tracking any template yet, track the one the Machines have been
+			// created from, so these changes are not missed.
+			if _, ok := msCopy.Annotations[clusterv1.InPlaceTemplateAnnotation]; !ok {
+				if err := mdutil.SetInPlaceTemplateAnnotation(msCopy, &msCopy.Spec.Template); err != nil {
+					return nil, err
+				}
+			}
+			mdutil.UpdateMachineTemplateInPlace(&msCopy.Spec.Template, &d.Spec.Template)
+			d.Status.InPlaceUpdatedFields = inPlaceFields
+
+			log.Info("Propagated machine template changes in place", "machineset", msCopy.Name, "fields", inPlaceFields)
+			r.recorder.Eventf(d, corev1.EventTypeNormal, "SuccessfulUpdateInPlace", "Updated MachineSet %q in place: %s", msCopy.Name, strings.Join(inPlaceFields, ", "))
+		}
+
 		// Set existing new machine set's annotation
 		annotationsUpdated := mdutil.SetNewMachineSetAnnotations(d, msCopy, newRevision, true, log)
 
 		minReadySecondsNeedsUpdate := msCopy.Spec.MinReadySeconds != *d.Spec.MinReadySeconds
-		if annotationsUpdated || minReadySecondsNeedsUpdate {
+		failureDomainDistributionNeedsUpdate := !apiequality.Semantic.DeepEqual(msCopy.Spec.FailureDomainDistribution, d.Spec.FailureDomainDistribution)
+		if annotationsUpdated || minReadySecondsNeedsUpdate || failureDomainDistributionNeedsUpdate || len(inPlaceFields) > 0 {
 			msCopy.Spec.MinReadySeconds = *d.Spec.MinReadySeconds
+			msCopy.Spec.FailureDomainDistribution = d.Spec.FailureDomainDistribution.DeepCopy()
 			return nil, patchHelper.Patch(ctx, msCopy)
 		}
 
@@ -159,9 +188,17 @@ func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *c
 			MinReadySeconds: minReadySeconds,
 			Selector:        *newMSSelector,
 			Template:        newMSTemplate,
+
+			FailureDomainDistribution: d.Spec.FailureDomainDistribution.DeepCopy(),
 		},
 	}
 
+	// Track the machine template the Machines are created from, so the changes propagated in place later on are
+	// applied to all of them.
+	if err := mdutil.SetInPlaceTemplateAnnotation(&newMS, &newMSTemplate); err != nil {
+		return nil, err
+	}
+
 	// Add foregroundDeletion finalizer to MachineSet if the MachineDeployment has it
 	if sets.NewString(d.Finalizers...).Has(metav1.FinalizerDeleteDependents) {
 		newMS.Finalizers = []string{metav1.FinalizerDeleteDependents}
@@ -220,6 +257,9 @@ func (r *MachineDeploymentReconciler) getNewMachineSet(ctx context.Context, d *c
 		mdutil.SetDeploymentRevision(d, newRevision)
 	})
 
+	// The new MachineSet is rolled out with all the changes to the machine template.
+	d.Status.InPlaceUpdatedFields = nil
+
 	return createdMS, err
 }
 
@@ -342,10 +382,33 @@ func (r *MachineDeploymentReconciler) scale(ctx context.Context, deployment *clu
 
 // syncDeploymentStatus checks if the status is up-to-date and sync it if necessary
 func (r *MachineDeploymentReconciler) syncDeploymentStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet, d *clusterv1.MachineDeployment) error {
-	d.Status = calculateStatus(allMSs, newMS, d)
+	newStatus := calculateStatus(allMSs, newMS, d)
+	syncProgress(d, &newStatus, time.Now())
 	return nil
 }
 
+// syncProgress records the last time the deployment made progress and sets the Progressing condition accordingly.
+// The time spent while the deployment is paused or complete is not accounted against its progress deadline.
+func syncProgress(d *clusterv1.MachineDeployment, newStatus *clusterv1.MachineDeploymentStatus, now time.Time) {
+	switch {
+	case d.Spec.Paused, mdutil.DeploymentComplete(d, newStatus):
+		newStatus.LastProgressTime = nil
+	case newStatus.LastProgressTime == nil, d.Status.ObservedGeneration < d.Generation, mdutil.DeploymentProgressing(d, newStatus):
+		newStatus.LastProgressTime = &metav1.Time{Time: now}
+	}
+	d.Status = *newStatus
+
+	switch {
+	case d.Spec.Paused:
+		conditions.MarkFalse(d, clusterv1.MachineDeploymentProgressingCondition, clusterv1.MachineDeploymentPausedReason, clusterv1.ConditionSeverityInfo, "")
+	case mdutil.DeploymentTimedOut(d, &d.Status, now):
+		conditions.MarkFalse(d, clusterv1.MachineDeploymentProgressingCondition, clusterv1.ProgressDeadlineExceededReason, clusterv1.ConditionSeverityError,
+			"MachineDeployment %s has not made progress for more than %d seconds", d.Name, *d.Spec.ProgressDeadlineSeconds)
+	default:
+		conditions.MarkTrue(d, clusterv1.MachineDeploymentProgressingCondition)
+	}
+}
+
 // calculateStatus calculates the latest status for the provided deployment by looking into the provided machine sets.
 func calculateStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet, deployment *clusterv1.MachineDeployment) clusterv1.MachineDeploymentStatus {
 	availableReplicas := mdutil.GetAvailableReplicaCountForMachineSets(allMSs)
@@ -363,13 +426,16 @@ func calculateStatus(allMSs []*clusterv1.MachineSet, newMS *clusterv1.MachineSet
 
 	status := clusterv1.MachineDeploymentStatus{
 		// TODO: Ensure that if we start retrying status updates, we won't pick up a new Generation value.
-		ObservedGeneration:  deployment.Generation,
-		Selector:            selector.String(),
-		Replicas:            mdutil.GetActualReplicaCountForMachineSets(allMSs),
-		UpdatedReplicas:     mdutil.GetActualReplicaCountForMachineSets([]*clusterv1.MachineSet{newMS}),
-		ReadyReplicas:       mdutil.GetReadyReplicaCountForMachineSets(allMSs),
-		AvailableReplicas:   availableReplicas,
-		UnavailableReplicas: unavailableReplicas,
+		ObservedGeneration:   deployment.Generation,
+		Selector:             selector.String(),
+		Replicas:             mdutil.GetActualReplicaCountForMachineSets(allMSs),
+		UpdatedReplicas:      mdutil.GetActualReplicaCountForMachineSets([]*clusterv1.MachineSet{newMS}),
+		ReadyReplicas:        mdutil.GetReadyReplicaCountForMachineSets(allMSs),
+		AvailableReplicas:    availableReplicas,
+		UnavailableReplicas:  unavailableReplicas,
+		LastProgressTime:     deployment.Status.LastProgressTime,
+		Conditions:           deployment.Status.Conditions,
+		InPlaceUpdatedFields: deployment.Status.InPlaceUpdatedFields,
 	}
 
 	if *deployment.Spec.Replicas == status.ReadyReplicas {
@@ -18,13 +18,20 @@ package controllers
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestMachineDeploymentSyncStatus(t *testing.T) {
@@ -220,3 +227,203 @@ func TestMachineDeploymentSyncStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineDeploymentSyncProgress(t *testing.T) {
+	now := time.Now()
+	lastProgressTime := metav1.NewTime(now.Add(-20 * time.Minute))
+
+	deployment := func(paused bool, lastProgressTime *metav1.Time, status clusterv1.MachineDeploymentStatus) *clusterv1.MachineDeployment {
+		status.ObservedGeneration = 1
+		status.LastProgressTime = lastProgressTime
+		return &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "md",
+				Generation: 1,
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				Replicas:                pointer.Int32Ptr(2),
+				Paused:                  paused,
+				ProgressDeadlineSeconds: pointer.Int32Ptr(600),
+				Strategy: &clusterv1.MachineDeploymentStrategy{
+					Type: clusterv1.RollingUpdateMachineDeploymentStrategyType,
+					RollingUpdate: &clusterv1.MachineRollingUpdateDeployment{
+						MaxUnavailable: intOrStrPtr(0),
+						MaxSurge:       intOrStrPtr(1),
+					},
+				},
+			},
+			Status: status,
+		}
+	}
+	rollingOut := clusterv1.MachineDeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 1, ReadyReplicas: 2, AvailableReplicas: 2}
+	complete := clusterv1.MachineDeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2}
+
+	var tests = map[string]struct {
+		deployment               *clusterv1.MachineDeployment
+		newStatus                clusterv1.MachineDeploymentStatus
+		expectedLastProgressTime *metav1.Time
+		expectedStatus           corev1.ConditionStatus
+		expectedReason           string
+	}{
+		"rollout starts tracking progress": {
+			deployment:               deployment(false, nil, rollingOut),
+			newStatus:                rollingOut,
+			expectedLastProgressTime: &metav1.Time{Time: now},
+			expectedStatus:           corev1.ConditionTrue,
+		},
+		"rollout making progress": {
+			deployment:               deployment(false, &lastProgressTime, rollingOut),
+			newStatus:                clusterv1.MachineDeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2},
+			expectedLastProgressTime: &metav1.Time{Time: now},
+			expectedStatus:           corev1.ConditionTrue,
+		},
+		"rollout exceeding its progress deadline": {
+			deployment:               deployment(false, &lastProgressTime, rollingOut),
+			newStatus:                rollingOut,
+			expectedLastProgressTime: &lastProgressTime,
+			expectedStatus:           corev1.ConditionFalse,
+			expectedReason:           clusterv1.ProgressDeadlineExceededReason,
+		},
+		"rollout complete": {
+			deployment:               deployment(false, &lastProgressTime, rollingOut),
+			newStatus:                complete,
+			expectedLastProgressTime: nil,
+			expectedStatus:           corev1.ConditionTrue,
+		},
+		"paused rollout": {
+			deployment:               deployment(true, &lastProgressTime, rollingOut),
+			newStatus:                rollingOut,
+			expectedLastProgressTime: nil,
+			expectedStatus:           corev1.ConditionFalse,
+			expectedReason:           clusterv1.MachineDeploymentPausedReason,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			newStatus := test.newStatus
+			newStatus.LastProgressTime = test.deployment.Status.LastProgressTime
+			syncProgress(test.deployment, &newStatus, now)
+
+			g.Expect(test.deployment.Status.LastProgressTime).To(Equal(test.expectedLastProgressTime))
+			condition := conditions.Get(test.deployment, clusterv1.MachineDeploymentProgressingCondition)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition.Status).To(Equal(test.expectedStatus))
+			g.Expect(condition.Reason).To(Equal(test.expectedReason))
+		})
+	}
+}
+
+func TestMachineDeploymentGetNewMachineSetInPlace(t *testing.T) {
+	g := NewWithT(t)
+
+	template := clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels:      map[string]string{"pool": "a", "team": "x"},
+			Annotations: map[string]string{"description": "old"},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster",
+			Version:     pointer.StringPtr("v1.19.1"),
+		},
+	}
+
+	msTemplate := template.DeepCopy()
+	msTemplate.Labels = map[string]string{"pool": "a", "team": "x", mdutil.DefaultMachineDeploymentUniqueLabelKey: "hash"}
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "md-hash",
+			Namespace:   metav1.NamespaceDefault,
+			UID:         "ms-uid",
+			Annotations: map[string]string{clusterv1.RevisionAnnotation: "1"},
+		},
+		Spec: clusterv1.MachineSetSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a", mdutil.DefaultMachineDeploymentUniqueLabelKey: "hash"}},
+			Template: *msTemplate,
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "md-hash-1",
+			Namespace:       metav1.NamespaceDefault,
+			Labels:          map[string]string{"pool": "a", "team": "x", mdutil.DefaultMachineDeploymentUniqueLabelKey: "hash", "added-to-machine": ""},
+			Annotations:     map[string]string{"description": "old"},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ms, clusterv1.GroupVersion.WithKind("MachineSet"))},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster",
+			Version:     pointer.StringPtr("v1.19.1"),
+		},
+	}
+
+	// The deployment changes labels, annotations, node drain timeout and taints only.
+	deployment := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "md",
+			Namespace:   metav1.NamespaceDefault,
+			Annotations: map[string]string{clusterv1.RevisionAnnotation: "1"},
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Replicas:        pointer.Int32Ptr(1),
+			MinReadySeconds: pointer.Int32Ptr(0),
+			Selector:        metav1.LabelSelector{MatchLabels: map[string]string{"pool": "a"}},
+			Template:        *template.DeepCopy(),
+		},
+	}
+	deployment.Spec.Template.Labels = map[string]string{"pool": "a", "node.cluster.x-k8s.io/role": "worker"}
+	deployment.Spec.Template.Annotations = map[string]string{"description": "new"}
+	deployment.Spec.Template.Spec.NodeDrainTimeout = &metav1.Duration{Duration: time.Minute}
+	deployment.Spec.Template.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+
+	r := &MachineDeploymentReconciler{
+		Client:   fake.NewClientBuilder().WithObjects(deployment, ms, machine).Build(),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	_, err := r.getNewMachineSet(ctx, deployment, []*clusterv1.MachineSet{ms}, nil, true)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(deployment.Status.InPlaceUpdatedFields).To(ConsistOf(
+		mdutil.InPlaceLabelsField, mdutil.InPlaceAnnotationsField, mdutil.InPlaceNodeDrainTimeoutField, mdutil.InPlaceTaintsField,
+	))
+
+	// No new MachineSet is created, and the existing one is updated in place without bumping the revision.
+	machineSets := &clusterv1.MachineSetList{}
+	g.Expect(r.Client.List(ctx, machineSets)).To(Succeed())
+	g.Expect(machineSets.Items).To(HaveLen(1))
+	updatedMS := machineSets.Items[0]
+	g.Expect(updatedMS.Annotations).To(HaveKeyWithValue(clusterv1.RevisionAnnotation, "1"))
+	g.Expect(updatedMS.Spec.Template.Labels).To(Equal(map[string]string{
+		"pool": "a", "node.cluster.x-k8s.io/role": "worker", mdutil.DefaultMachineDeploymentUniqueLabelKey: "hash",
+	}))
+	g.Expect(updatedMS.Spec.Template.Annotations).To(Equal(deployment.Spec.Template.Annotations))
+	g.Expect(updatedMS.Spec.Template.Spec.NodeDrainTimeout).To(Equal(deployment.Spec.Template.Spec.NodeDrainTimeout))
+	g.Expect(updatedMS.Spec.Template.Spec.Taints).To(Equal(deployment.Spec.Template.Spec.Taints))
+
+	// The MachineSet tracks the machine template the Machine has been created from, and the Machine is left to the
+	// MachineSet controller.
+	g.Expect(updatedMS.Annotations).To(HaveKey(clusterv1.InPlaceTemplateAnnotation))
+	updatedMachine := &clusterv1.Machine{}
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(machine), updatedMachine)).To(Succeed())
+	g.Expect(updatedMachine.Labels).To(Equal(machine.Labels))
+	g.Expect(updatedMachine.Annotations).To(Equal(machine.Annotations))
+	g.Expect(updatedMachine.Spec.NodeDrainTimeout).To(BeNil())
+	g.Expect(updatedMachine.Spec.Taints).To(BeEmpty())
+
+	// The MachineSet controller updates the Machine in place, preserving the labels not defined in the machine template.
+	msr := &MachineSetReconciler{
+		Client:   r.Client,
+		recorder: record.NewFakeRecorder(32),
+	}
+	g.Expect(msr.syncMachinesInPlace(ctx, &updatedMS, []*clusterv1.Machine{updatedMachine})).To(Succeed())
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(machine), updatedMachine)).To(Succeed())
+	g.Expect(updatedMachine.Labels).To(Equal(map[string]string{
+		"pool": "a", "node.cluster.x-k8s.io/role": "worker", mdutil.DefaultMachineDeploymentUniqueLabelKey: "hash", "added-to-machine": "",
+	}))
+	g.Expect(updatedMachine.Annotations).To(HaveKeyWithValue("description", "new"))
+	g.Expect(updatedMachine.Spec.NodeDrainTimeout).To(Equal(deployment.Spec.Template.Spec.NodeDrainTimeout))
+	g.Expect(updatedMachine.Spec.Taints).To(Equal(deployment.Spec.Template.Spec.Taints))
+	g.Expect(updatedMachine.Spec.Version).To(Equal(pointer.StringPtr("v1.19.1")))
+}
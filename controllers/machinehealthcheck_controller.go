@@ -36,15 +36,20 @@ import (
 	"k8s.io/client-go/tools/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/metrics"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/maintenance"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -62,6 +67,8 @@ const (
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinehealthchecks;machinehealthchecks/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=exp.cluster.x-k8s.io,resources=machinepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;exp.infrastructure.cluster.x-k8s.io,resources=*,verbs=get;list;watch;patch
 
 // MachineHealthCheckReconciler reconciles a MachineHealthCheck object
 type MachineHealthCheckReconciler struct {
@@ -89,12 +96,26 @@ func (r *MachineHealthCheckReconciler) SetupWithManager(ctx context.Context, mgr
 		&source.Kind{Type: &clusterv1.Cluster{}},
 		handler.EnqueueRequestsFromMapFunc(r.clusterToMachineHealthCheck),
 		// TODO: should this wait for Cluster.Status.InfrastructureReady similar to Infra Machine resources?
-		predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+		// Remediations waiting for a maintenance window start as soon as the maintenance windows change.
+		predicates.Any(ctrl.LoggerFrom(ctx),
+			predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx)),
+			predicates.ClusterUpdateMaintenanceWindows(ctrl.LoggerFrom(ctx)),
+		),
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to add Watch for Clusters to controller manager")
 	}
 
+	if feature.Gates.Enabled(feature.MachinePool) {
+		err = controller.Watch(
+			&source.Kind{Type: &expv1.MachinePool{}},
+			handler.EnqueueRequestsFromMapFunc(r.machinePoolToMachineHealthCheck),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to add Watch for MachinePools to controller manager")
+		}
+	}
+
 	r.controller = controller
 	r.recorder = mgr.GetEventRecorderFor("machinehealthcheck-controller")
 	return nil
@@ -121,6 +142,11 @@ func (r *MachineHealthCheckReconciler) Reconcile(ctx context.Context, req ctrl.R
 	log = log.WithValues("cluster", m.Spec.ClusterName)
 	ctx = ctrl.LoggerInto(ctx, log)
 
+	// Handle deletion reconciliation loop; this happens before fetching the Cluster, which might already be gone.
+	if !m.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, m)
+	}
+
 	cluster, err := util.GetClusterByName(ctx, r.Client, m.Namespace, m.Spec.ClusterName)
 	if err != nil {
 		log.Error(err, "Failed to fetch Cluster for MachineHealthCheck")
@@ -198,18 +224,33 @@ func (r *MachineHealthCheckReconciler) reconcile(ctx context.Context, logger log
 		logger.Error(err, "Failed to fetch targets from MachineHealthCheck")
 		return ctrl.Result{}, err
 	}
-	totalTargets := len(targets)
+	machinePoolTargets, err := r.getMachinePoolTargetsFromMHC(ctx, remoteClient, m)
+	if err != nil {
+		logger.Error(err, "Failed to fetch machine pool targets from MachineHealthCheck")
+		return ctrl.Result{}, err
+	}
+	if err := r.clearMachinePoolRemediationRequests(ctx, m, machinePoolTargets); err != nil {
+		logger.Error(err, "Failed to clear remediation requests of machine pools no longer targeted")
+		return ctrl.Result{}, err
+	}
+	totalTargets := len(targets) + len(machinePoolTargets)
 	m.Status.ExpectedMachines = int32(totalTargets)
-	m.Status.Targets = make([]string, totalTargets)
-	for i, t := range targets {
-		m.Status.Targets[i] = t.Machine.Name
+	m.Status.Targets = make([]string, 0, totalTargets)
+	for _, t := range targets {
+		m.Status.Targets = append(m.Status.Targets, t.Machine.Name)
+	}
+	for _, t := range machinePoolTargets {
+		m.Status.Targets = append(m.Status.Targets, t.targetName())
 	}
 	// do sort to avoid keep changing m.Status as the returned machines are not in order
 	sort.Strings(m.Status.Targets)
 
 	// health check all targets and reconcile mhc status
 	healthy, unhealthy, nextCheckTimes := r.healthCheckTargets(targets, logger, m.Spec.NodeStartupTimeout.Duration)
-	m.Status.CurrentHealthy = int32(len(healthy))
+	healthyInstances, unhealthyInstances, instancesNextCheckTimes := r.healthCheckMachinePoolTargets(machinePoolTargets, logger, m.Spec.NodeStartupTimeout.Duration)
+	nextCheckTimes = append(nextCheckTimes, instancesNextCheckTimes...)
+	m.Status.CurrentHealthy = int32(len(healthy) + len(healthyInstances))
+	totalUnhealthy := len(unhealthy) + len(unhealthyInstances)
 
 	// check MHC current health against MaxUnhealthy
 	if !isAllowedRemediation(m) {
@@ -217,11 +258,11 @@ func (r *MachineHealthCheckReconciler) reconcile(ctx context.Context, logger log
 			"Short-circuiting remediation",
 			"total target", totalTargets,
 			"max unhealthy", m.Spec.MaxUnhealthy,
-			"unhealthy targets", len(unhealthy),
+			"unhealthy targets", totalUnhealthy,
 		)
 		message := fmt.Sprintf("Remediation is not allowed, the number of not started or unhealthy machines exceeds maxUnhealthy (total: %v, unhealthy: %v, maxUnhealthy: %v)",
 			totalTargets,
-			len(unhealthy),
+			totalUnhealthy,
 			m.Spec.MaxUnhealthy,
 		)
 
@@ -254,11 +295,18 @@ func (r *MachineHealthCheckReconciler) reconcile(ctx context.Context, logger log
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	// Unhealthy machines are remediated only while one of the Cluster's maintenance windows is open.
+	if totalUnhealthy > 0 {
+		if result, waiting, err := r.waitForMaintenanceWindow(ctx, logger, cluster, m, append(healthy, unhealthy...), time.Now()); err != nil || waiting {
+			return result, err
+		}
+	}
+
 	logger.V(3).Info(
 		"Remediations are allowed",
 		"total target", totalTargets,
 		"max unhealthy", m.Spec.MaxUnhealthy,
-		"unhealthy targets", len(unhealthy),
+		"unhealthy targets", totalUnhealthy,
 	)
 
 	maxUnhealthy, err := getMaxUnhealthy(m)
@@ -272,6 +320,7 @@ func (r *MachineHealthCheckReconciler) reconcile(ctx context.Context, logger log
 
 	errList := r.PatchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)
 	errList = append(errList, r.PatchHealthyTargets(ctx, logger, healthy, cluster, m)...)
+	errList = append(errList, r.remediateMachinePoolTargets(ctx, logger, machinePoolTargets, unhealthyInstances, cluster, m)...)
 
 	// handle update errors
 	if len(errList) > 0 {
@@ -289,6 +338,55 @@ func (r *MachineHealthCheckReconciler) reconcile(ctx context.Context, logger log
 	return ctrl.Result{}, nil
 }
 
+// reconcileDelete clears the remediation requests set by the MachineHealthCheck on infrastructure machine pools,
+// and then removes the MachineHealthCheck finalizer.
+func (r *MachineHealthCheckReconciler) reconcileDelete(ctx context.Context, m *clusterv1.MachineHealthCheck) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(m, MachineHealthCheckMachinePoolFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(m, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	clearErr := r.clearMachinePoolRemediationRequests(ctx, m, nil)
+	if err := patchHelper.Patch(ctx, m); err != nil {
+		return ctrl.Result{}, kerrors.NewAggregate([]error{clearErr, err})
+	}
+	return ctrl.Result{}, clearErr
+}
+
+// waitForMaintenanceWindow returns true if the maintenance windows of the Cluster are closed, so remediation can't start;
+// the health check results are recorded on the targets, and the MachineHealthCheck is requeued when the next maintenance
+// window opens.
+func (r *MachineHealthCheckReconciler) waitForMaintenanceWindow(ctx context.Context, logger logr.Logger, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck, targets []healthCheckTarget, now time.Time) (ctrl.Result, bool, error) {
+	open, next, err := maintenance.IsOpen(cluster, now)
+	if err != nil {
+		return ctrl.Result{}, false, err
+	}
+	if open {
+		return ctrl.Result{}, false, nil
+	}
+
+	logger.V(3).Info("Waiting for a maintenance window to open before remediating", "next", next)
+	message := "Remediation is not allowed, waiting for a maintenance window to open"
+	result := ctrl.Result{}
+	if !next.IsZero() {
+		message = fmt.Sprintf("Remediation is not allowed, waiting for the maintenance window opening at %s", next.UTC().Format(time.RFC3339))
+		result.RequeueAfter = next.Sub(now)
+	}
+	m.Status.RemediationsAllowed = 0
+	conditions.MarkFalse(m, clusterv1.RemediationAllowedCondition, clusterv1.WaitingForMaintenanceWindowReason, clusterv1.ConditionSeverityInfo, message)
+
+	errList := []error{}
+	for _, t := range targets {
+		if err := t.patchHelper.Patch(ctx, t.Machine); err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to patch machine status for machine: %s/%s", t.Machine.Namespace, t.Machine.Name))
+		}
+	}
+	return result, true, kerrors.NewAggregate(errList)
+}
+
 // PatchHealthyTargets patches healthy machines with MachineHealthCheckSuccededCondition.
 func (r *MachineHealthCheckReconciler) PatchHealthyTargets(ctx context.Context, logger logr.Logger, healthy []healthCheckTarget, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck) []error {
 	errList := []error{}
@@ -379,12 +477,14 @@ func (r *MachineHealthCheckReconciler) PatchUnhealthyTargets(ctx context.Context
 					errList = append(errList, errors.Wrapf(err, "error creating remediation request for machine %q in namespace %q within cluster %q", t.Machine.Name, t.Machine.Namespace, t.Machine.ClusterName))
 					return errList
 				}
+				metrics.RecordMachineHealthCheckRemediations(m, 1)
 			} else {
 				logger.Info("Target has failed health check, marking for remediation", "target", t.string(), "reason", condition.Reason, "message", condition.Message)
 				// NOTE: MHC is responsible for creating MachineOwnerRemediatedCondition if missing or to trigger another remediation if the previous one is completed;
 				// instead, if a remediation is in already progress, the remediation owner is responsible for completing the process and MHC should not overwrite the condition.
 				if !conditions.Has(t.Machine, clusterv1.MachineOwnerRemediatedCondition) || conditions.IsTrue(t.Machine, clusterv1.MachineOwnerRemediatedCondition) {
 					conditions.MarkFalse(t.Machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+					metrics.RecordMachineHealthCheckRemediations(m, 1)
 				}
 			}
 		}
@@ -474,6 +574,22 @@ func (r *MachineHealthCheckReconciler) nodeToMachineHealthCheck(o client.Object)
 	return r.machineToMachineHealthCheck(machine)
 }
 
+// clusterNodeToMachineHealthCheck returns a handler mapping the nodes of the given cluster to the MachineHealthChecks
+// monitoring them, either through the Machine or through the MachinePool backing each node.
+func (r *MachineHealthCheckReconciler) clusterNodeToMachineHealthCheck(cluster client.ObjectKey) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		if requests := r.nodeToMachineHealthCheck(o); len(requests) > 0 {
+			return requests
+		}
+
+		// Nodes created by a MachinePool are not backed by a Machine.
+		if mp := r.getMachinePoolFromNode(context.TODO(), cluster, o.(*corev1.Node)); mp != nil {
+			return r.machinePoolToMachineHealthCheck(mp)
+		}
+		return nil
+	}
+}
+
 func (r *MachineHealthCheckReconciler) getMachineFromNode(ctx context.Context, nodeName string) (*clusterv1.Machine, error) {
 	machineList := &clusterv1.MachineList{}
 	if err := r.Client.List(
@@ -509,7 +625,7 @@ func (r *MachineHealthCheckReconciler) watchClusterNodes(ctx context.Context, cl
 		Cluster:      util.ObjectKey(cluster),
 		Watcher:      r.controller,
 		Kind:         &corev1.Node{},
-		EventHandler: handler.EnqueueRequestsFromMapFunc(r.nodeToMachineHealthCheck),
+		EventHandler: handler.EnqueueRequestsFromMapFunc(r.clusterNodeToMachineHealthCheck(util.ObjectKey(cluster))),
 	}); err != nil {
 		return err
 	}
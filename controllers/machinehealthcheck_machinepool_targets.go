@@ -0,0 +1,531 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/metrics"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// EventMachinePoolInstanceMarkedUnhealthy is emitted when a machine pool instance was successfully
+	// marked as unhealthy on the infrastructure machine pool.
+	EventMachinePoolInstanceMarkedUnhealthy string = "MachinePoolInstanceMarkedUnhealthy"
+
+	// MachinePoolInstancesFirstSeenAnnotation is set on a MachineHealthCheck to track when the machine pool instances
+	// without a node have been seen for the first time; the value is a JSON object mapping provider IDs to RFC3339 times.
+	MachinePoolInstancesFirstSeenAnnotation = "machinehealthcheck.cluster.x-k8s.io/machine-pool-instances-first-seen"
+
+	// MachinePoolsRemediationRequestedAnnotation is set on a MachineHealthCheck to track the MachinePools, as a comma
+	// separated list of names, whose infrastructure machine pool has a remediation request set by the MachineHealthCheck;
+	// the requests are cleared when the MachinePools are no longer targeted or the MachineHealthCheck is deleted.
+	MachinePoolsRemediationRequestedAnnotation = "machinehealthcheck.cluster.x-k8s.io/machine-pools-remediation-requested"
+
+	// MachineHealthCheckMachinePoolFinalizer is set on a MachineHealthCheck while it has remediation requests set on
+	// infrastructure machine pools, so they can be cleared before the MachineHealthCheck is deleted.
+	MachineHealthCheckMachinePoolFinalizer = "machinehealthcheck.cluster.x-k8s.io/machine-pools"
+)
+
+// machinePoolInstanceTarget contains the information required to perform a health check
+// on the node of a MachinePool instance to determine if any remediation is required.
+type machinePoolInstanceTarget struct {
+	MachinePool *expv1.MachinePool
+	ProviderID  string
+	Node        *corev1.Node
+	MHC         *clusterv1.MachineHealthCheck
+
+	// FirstSeen is the time the instance has been seen for the first time without a node.
+	FirstSeen time.Time
+}
+
+func (t *machinePoolInstanceTarget) string() string {
+	return fmt.Sprintf("%s/%s/%s/%s",
+		t.MHC.GetNamespace(),
+		t.MHC.GetName(),
+		t.MachinePool.GetName(),
+		t.instanceName(),
+	)
+}
+
+// targetName returns the name of the target reported in the MachineHealthCheck status.
+func (t *machinePoolInstanceTarget) targetName() string {
+	return fmt.Sprintf("%s/%s", t.MachinePool.GetName(), t.instanceName())
+}
+
+// instanceName returns the node name if the instance has a node, the provider ID otherwise.
+func (t *machinePoolInstanceTarget) instanceName() string {
+	if t.Node != nil {
+		return t.Node.GetName()
+	}
+	return t.ProviderID
+}
+
+// Determine whether or not a given machine pool instance needs remediation.
+// The instance will need remediation if any of the following are true:
+// - The instance did not get a node before `timeoutForMachineToHaveNode` elapses
+// - Any condition on the node is matched for the given timeout
+// MachinePools do not track when each instance was created, so the startup timeout of an instance
+// is measured from the time the MachineHealthCheck has seen the instance for the first time.
+// If the target doesn't currently need remediation, provide a duration after
+// which the target should next be checked.
+func (t *machinePoolInstanceTarget) needsRemediation(logger logr.Logger, timeoutForMachineToHaveNode time.Duration) (bool, string, time.Duration) {
+	now := time.Now()
+
+	// the node has not been set yet
+	if t.Node == nil {
+		if t.FirstSeen.Add(timeoutForMachineToHaveNode).Before(now) {
+			logger.V(3).Info("Target is unhealthy: machine pool instance has no node", "duration", timeoutForMachineToHaveNode.String())
+			return true, fmt.Sprintf("Node failed to report startup in %s", timeoutForMachineToHaveNode.String()), time.Duration(0)
+		}
+		durationUnhealthy := now.Sub(t.FirstSeen)
+		nextCheck := timeoutForMachineToHaveNode - durationUnhealthy + time.Second
+		return false, "", nextCheck
+	}
+
+	// check conditions
+	c, nextCheck := unhealthyNodeCondition(t.Node, t.MHC.Spec.UnhealthyConditions, now)
+	if c != nil {
+		logger.V(3).Info("Target is unhealthy: condition is in state longer than allowed timeout", "condition", c.Type, "state", c.Status, "timeout", c.Timeout.Duration.String())
+		return true, fmt.Sprintf("Condition %s on node is reporting status %s for more than %s", c.Type, c.Status, c.Timeout.Duration.String()), time.Duration(0)
+	}
+	return false, "", nextCheck
+}
+
+// getMachinePoolTargetsFromMHC uses the MachineHealthCheck's selector to fetch the machine pools targeted by the
+// health check, and returns their instances and nodes ready for health checking; MachinePools are matched against
+// the labels of their machine template.
+// The time the instances without a node have been seen for the first time is tracked in the
+// MachinePoolInstancesFirstSeenAnnotation of the MachineHealthCheck.
+func (r *MachineHealthCheckReconciler) getMachinePoolTargetsFromMHC(ctx context.Context, clusterClient client.Reader, mhc *clusterv1.MachineHealthCheck) ([]machinePoolInstanceTarget, error) {
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return nil, nil
+	}
+
+	machinePools, err := r.getMachinePoolsFromMHC(ctx, mhc)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting machine pools from MachineHealthCheck")
+	}
+	if len(machinePools) == 0 {
+		delete(mhc.Annotations, MachinePoolInstancesFirstSeenAnnotation)
+		return nil, nil
+	}
+
+	nodes, err := getNodesByProviderID(ctx, clusterClient)
+	if err != nil {
+		return nil, err
+	}
+
+	firstSeen := map[string]time.Time{}
+	if value, ok := mhc.Annotations[MachinePoolInstancesFirstSeenAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &firstSeen); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "Failed to parse annotation, ignoring it", "annotation", MachinePoolInstancesFirstSeenAnnotation)
+			firstSeen = map[string]time.Time{}
+		}
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	nodeless := map[string]time.Time{}
+
+	targets := []machinePoolInstanceTarget{}
+	for i := range machinePools {
+		for _, providerID := range machinePools[i].Spec.ProviderIDList {
+			target := machinePoolInstanceTarget{
+				MachinePool: &machinePools[i],
+				ProviderID:  providerID,
+				MHC:         mhc,
+			}
+			if pid, err := noderefutil.NewProviderID(providerID); err == nil {
+				target.Node = nodes[pid.ID()]
+			}
+			if target.Node == nil {
+				target.FirstSeen = now
+				if t, ok := firstSeen[providerID]; ok {
+					target.FirstSeen = t
+				}
+				nodeless[providerID] = target.FirstSeen
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	// Track only the instances still without a node, so the annotation does not grow with the machine pools.
+	if len(nodeless) == 0 {
+		delete(mhc.Annotations, MachinePoolInstancesFirstSeenAnnotation)
+		return targets, nil
+	}
+	value, err := json.Marshal(nodeless)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal annotation %s", MachinePoolInstancesFirstSeenAnnotation)
+	}
+	if mhc.Annotations == nil {
+		mhc.Annotations = map[string]string{}
+	}
+	mhc.Annotations[MachinePoolInstancesFirstSeenAnnotation] = string(value)
+	return targets, nil
+}
+
+// getMachinePoolsFromMHC fetches the MachinePools whose machine template is matched
+// by the MachineHealthCheck's label selector.
+func (r *MachineHealthCheckReconciler) getMachinePoolsFromMHC(ctx context.Context, mhc *clusterv1.MachineHealthCheck) ([]expv1.MachinePool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&mhc.Spec.Selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build selector")
+	}
+
+	var machinePoolList expv1.MachinePoolList
+	if err := r.Client.List(ctx, &machinePoolList, client.InNamespace(mhc.GetNamespace())); err != nil {
+		return nil, errors.Wrap(err, "failed to list machine pools")
+	}
+
+	machinePools := []expv1.MachinePool{}
+	for _, mp := range machinePoolList.Items {
+		if mp.Spec.ClusterName != mhc.Spec.ClusterName || !mp.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if selector.Matches(labels.Set(mp.Spec.Template.Labels)) {
+			machinePools = append(machinePools, mp)
+		}
+	}
+	return machinePools, nil
+}
+
+// getNodesByProviderID fetches all the nodes of a local or remote cluster, indexed by provider ID.
+func getNodesByProviderID(ctx context.Context, clusterClient client.Reader) (map[string]*corev1.Node, error) {
+	nodeList := &corev1.NodeList{}
+	if err := clusterClient.List(ctx, nodeList); err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+
+	nodes := make(map[string]*corev1.Node, len(nodeList.Items))
+	for i := range nodeList.Items {
+		pid, err := noderefutil.NewProviderID(nodeList.Items[i].Spec.ProviderID)
+		if err != nil {
+			continue
+		}
+		nodes[pid.ID()] = &nodeList.Items[i]
+	}
+	return nodes, nil
+}
+
+// healthCheckMachinePoolTargets health checks a slice of machine pool instance targets, and returns the healthy
+// and the unhealthy targets, and the times after which the targets likely to go unhealthy should be checked again.
+func (r *MachineHealthCheckReconciler) healthCheckMachinePoolTargets(targets []machinePoolInstanceTarget, logger logr.Logger, timeoutForMachineToHaveNode time.Duration) ([]machinePoolInstanceTarget, []machinePoolInstanceTarget, []time.Duration) {
+	var nextCheckTimes []time.Duration
+	var unhealthy []machinePoolInstanceTarget
+	var healthy []machinePoolInstanceTarget
+
+	for _, t := range targets {
+		logger := logger.WithValues("Target", t.string())
+		logger.V(3).Info("Health checking target")
+		needsRemediation, message, nextCheck := t.needsRemediation(logger, timeoutForMachineToHaveNode)
+
+		if needsRemediation {
+			logger.Info("Target has failed health check", "message", message)
+			unhealthy = append(unhealthy, t)
+			continue
+		}
+
+		if nextCheck > 0 {
+			logger.V(3).Info("Target is likely to go unhealthy", "timeUntilUnhealthy", nextCheck.Truncate(time.Second).String())
+			// Instances with a node are checked again only when a node condition matches an unhealthy condition;
+			// instances without a node are just waiting for it.
+			if t.Node != nil {
+				r.recorder.Eventf(
+					t.MachinePool,
+					corev1.EventTypeNormal,
+					EventDetectedUnhealthy,
+					"MachinePool instance %v has unhealthy node %v",
+					t.ProviderID,
+					t.Node.GetName(),
+				)
+			}
+			nextCheckTimes = append(nextCheckTimes, nextCheck)
+			continue
+		}
+
+		healthy = append(healthy, t)
+	}
+	return healthy, unhealthy, nextCheckTimes
+}
+
+// remediateMachinePoolTargets requests the remediation of the unhealthy machine pool instances by setting their
+// provider IDs in the remediation annotation of the infrastructure machine pool; the annotation is removed once
+// all the instances of the machine pool are healthy.
+func (r *MachineHealthCheckReconciler) remediateMachinePoolTargets(ctx context.Context, logger logr.Logger, targets, unhealthy []machinePoolInstanceTarget, cluster *clusterv1.Cluster, m *clusterv1.MachineHealthCheck) []error {
+	machinePools := map[string]*expv1.MachinePool{}
+	for _, t := range targets {
+		machinePools[t.MachinePool.Name] = t.MachinePool
+	}
+	unhealthyProviderIDs := map[string][]string{}
+	for _, t := range unhealthy {
+		unhealthyProviderIDs[t.MachinePool.Name] = append(unhealthyProviderIDs[t.MachinePool.Name], t.ProviderID)
+	}
+
+	requested := machinePoolRemediationRequests(m)
+	defer setMachinePoolRemediationRequests(m, requested)
+
+	errList := []error{}
+	for name, mp := range machinePools {
+		providerIDs := unhealthyProviderIDs[name]
+		if len(providerIDs) > 0 {
+			if annotations.IsPaused(cluster, mp) {
+				logger.Info("MachinePool instances have failed health check, but machine pool is paused so skipping remediation", "machinePool", name, "instances", providerIDs)
+				continue
+			}
+			if m.Spec.RemediationTemplate != nil {
+				logger.Info("MachinePool instances have failed health check, but external remediation is not supported for machine pools so skipping remediation", "machinePool", name, "instances", providerIDs)
+				r.recorder.Eventf(
+					m,
+					corev1.EventTypeWarning,
+					EventRemediationRestricted,
+					"External remediation is not supported for machine pools, skipping remediation of MachinePool %s instances %v",
+					name,
+					strings.Join(providerIDs, ","),
+				)
+				continue
+			}
+		}
+
+		infraMachinePool, err := external.Get(ctx, r.Client, &mp.Spec.Template.Spec.InfrastructureRef, mp.Namespace)
+		if err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to get infrastructure machine pool for machine pool: %s/%s", mp.Namespace, mp.Name))
+			continue
+		}
+
+		value := strings.Join(providerIDs, ",")
+		previousValue := infraMachinePool.GetAnnotations()[expv1.RemediateMachinePoolInstancesAnnotation]
+		if previousValue == value {
+			if value == "" {
+				requested.Delete(name)
+			} else {
+				requested.Insert(name)
+			}
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(infraMachinePool, r.Client)
+		if err != nil {
+			errList = append(errList, errors.Wrap(err, "unable to initialize patch helper"))
+			continue
+		}
+		infraAnnotations := infraMachinePool.GetAnnotations()
+		if value == "" {
+			delete(infraAnnotations, expv1.RemediateMachinePoolInstancesAnnotation)
+		} else {
+			if infraAnnotations == nil {
+				infraAnnotations = map[string]string{}
+			}
+			infraAnnotations[expv1.RemediateMachinePoolInstancesAnnotation] = value
+		}
+		infraMachinePool.SetAnnotations(infraAnnotations)
+		if err := patchHelper.Patch(ctx, infraMachinePool); err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to patch infrastructure machine pool for machine pool: %s/%s", mp.Namespace, mp.Name))
+			continue
+		}
+
+		if value == "" {
+			requested.Delete(name)
+		} else {
+			requested.Insert(name)
+		}
+
+		if value != "" {
+			logger.Info("MachinePool instances have failed health check, marking for remediation", "machinePool", name, "instances", providerIDs)
+			r.recorder.Eventf(
+				mp,
+				corev1.EventTypeNormal,
+				EventMachinePoolInstanceMarkedUnhealthy,
+				"MachinePool instances %v have been marked as unhealthy",
+				value,
+			)
+			// Only count the instances that were not already marked for remediation.
+			metrics.RecordMachineHealthCheckRemediations(m, sets.NewString(providerIDs...).Difference(sets.NewString(strings.Split(previousValue, ",")...)).Len())
+		}
+	}
+	return errList
+}
+
+// clearMachinePoolRemediationRequests clears the remediation requests set by the MachineHealthCheck on the
+// infrastructure machine pools of the MachinePools not in the given targets, e.g. because they are no longer matched
+// by the selector; all the requests are cleared if there are no targets, e.g. when the MachineHealthCheck is deleted.
+func (r *MachineHealthCheckReconciler) clearMachinePoolRemediationRequests(ctx context.Context, m *clusterv1.MachineHealthCheck, targets []machinePoolInstanceTarget) error {
+	requested := machinePoolRemediationRequests(m)
+	defer setMachinePoolRemediationRequests(m, requested)
+
+	targeted := sets.NewString()
+	for _, t := range targets {
+		targeted.Insert(t.MachinePool.Name)
+	}
+
+	errList := []error{}
+	for _, name := range requested.Difference(targeted).List() {
+		if err := r.clearMachinePoolRemediationRequest(ctx, m.Namespace, name); err != nil {
+			errList = append(errList, err)
+			continue
+		}
+		ctrl.LoggerFrom(ctx).Info("Cleared remediation request of a machine pool no longer targeted", "machinePool", name)
+		requested.Delete(name)
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// clearMachinePoolRemediationRequest removes the remediation annotation from the infrastructure machine pool of a
+// MachinePool; a MachinePool or an infrastructure machine pool already gone has nothing to clear.
+func (r *MachineHealthCheckReconciler) clearMachinePoolRemediationRequest(ctx context.Context, namespace, name string) error {
+	mp := &expv1.MachinePool{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, mp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get machine pool: %s/%s", namespace, name)
+	}
+
+	infraMachinePool, err := external.Get(ctx, r.Client, &mp.Spec.Template.Spec.InfrastructureRef, mp.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get infrastructure machine pool for machine pool: %s/%s", namespace, name)
+	}
+	if _, ok := infraMachinePool.GetAnnotations()[expv1.RemediateMachinePoolInstancesAnnotation]; !ok {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(infraMachinePool, r.Client)
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize patch helper")
+	}
+	infraAnnotations := infraMachinePool.GetAnnotations()
+	delete(infraAnnotations, expv1.RemediateMachinePoolInstancesAnnotation)
+	infraMachinePool.SetAnnotations(infraAnnotations)
+	if err := patchHelper.Patch(ctx, infraMachinePool); err != nil {
+		return errors.Wrapf(err, "failed to patch infrastructure machine pool for machine pool: %s/%s", namespace, name)
+	}
+	return nil
+}
+
+// machinePoolRemediationRequests returns the names of the MachinePools with a remediation request set by the
+// MachineHealthCheck, as tracked in the MachinePoolsRemediationRequestedAnnotation.
+func machinePoolRemediationRequests(m *clusterv1.MachineHealthCheck) sets.String {
+	requested := sets.NewString()
+	if value := m.Annotations[MachinePoolsRemediationRequestedAnnotation]; value != "" {
+		requested.Insert(strings.Split(value, ",")...)
+	}
+	return requested
+}
+
+// setMachinePoolRemediationRequests tracks the names of the MachinePools with a remediation request set by the
+// MachineHealthCheck; the MachineHealthCheck has a finalizer as long as there is any.
+func setMachinePoolRemediationRequests(m *clusterv1.MachineHealthCheck, requested sets.String) {
+	if requested.Len() == 0 {
+		delete(m.Annotations, MachinePoolsRemediationRequestedAnnotation)
+		controllerutil.RemoveFinalizer(m, MachineHealthCheckMachinePoolFinalizer)
+		return
+	}
+	if m.Annotations == nil {
+		m.Annotations = map[string]string{}
+	}
+	m.Annotations[MachinePoolsRemediationRequestedAnnotation] = strings.Join(requested.List(), ",")
+	controllerutil.AddFinalizer(m, MachineHealthCheckMachinePoolFinalizer)
+}
+
+// machinePoolToMachineHealthCheck maps events from MachinePool objects to
+// MachineHealthCheck objects that monitor the instances of the given machine pool
+func (r *MachineHealthCheckReconciler) machinePoolToMachineHealthCheck(o client.Object) []reconcile.Request {
+	mp, ok := o.(*expv1.MachinePool)
+	if !ok {
+		panic(fmt.Sprintf("Expected a MachinePool, got %T", o))
+	}
+
+	mhcList := &clusterv1.MachineHealthCheckList{}
+	if err := r.Client.List(
+		context.TODO(),
+		mhcList,
+		client.InNamespace(mp.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: mp.Spec.ClusterName},
+	); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for k := range mhcList.Items {
+		mhc := &mhcList.Items[k]
+		if hasMatchingLabels(mhc.Spec.Selector, mp.Spec.Template.Labels) {
+			key := util.ObjectKey(mhc)
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+	}
+	return requests
+}
+
+// getMachinePoolFromNode returns the MachinePool of the given cluster the given node is an instance of, if any.
+func (r *MachineHealthCheckReconciler) getMachinePoolFromNode(ctx context.Context, cluster client.ObjectKey, node *corev1.Node) *expv1.MachinePool {
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return nil
+	}
+	nodeProviderID, err := noderefutil.NewProviderID(node.Spec.ProviderID)
+	if err != nil {
+		return nil
+	}
+
+	machinePoolList := &expv1.MachinePoolList{}
+	if err := r.Client.List(
+		ctx,
+		machinePoolList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name},
+	); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to list machine pools", "cluster", cluster.String(), "node", node.Name)
+		return nil
+	}
+	for i := range machinePoolList.Items {
+		for _, providerID := range machinePoolList.Items[i].Spec.ProviderIDList {
+			pid, err := noderefutil.NewProviderID(providerID)
+			if err != nil {
+				continue
+			}
+			if pid.Equals(nodeProviderID) {
+				return &machinePoolList.Items[i]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetMachinePoolTargetsFromMHC(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(feature.MutableGates.Set("MachinePool=true")).To(Succeed())
+	defer func() {
+		g.Expect(feature.MutableGates.Set("MachinePool=false")).To(Succeed())
+	}()
+	g.Expect(expv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	namespace := "test-mhc"
+	clusterName := "test-cluster"
+	mhcSelector := map[string]string{"cluster": clusterName, "machine-group": "foo"}
+
+	testMHC := &clusterv1.MachineHealthCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mhc",
+			Namespace: namespace,
+		},
+		Spec: clusterv1.MachineHealthCheckSpec{
+			ClusterName: clusterName,
+			Selector: metav1.LabelSelector{
+				MatchLabels: mhcSelector,
+			},
+		},
+	}
+
+	testNode1 := newTestNode("node1")
+	testNode1.Spec.ProviderID = "test:////node1"
+	testMachinePool1 := newTestMachinePool("pool1", namespace, clusterName, mhcSelector, "test:////node1", "test:////node2")
+	testMachinePool2 := newTestMachinePool("pool2", namespace, clusterName, map[string]string{"cluster": clusterName}, "test:////node3")
+	testMachinePool3 := newTestMachinePool("pool3", namespace, "other-cluster", mhcSelector, "test:////node4")
+
+	mgmtClient := fake.NewClientBuilder().WithObjects(testMHC, testMachinePool1, testMachinePool2, testMachinePool3).Build()
+	workloadClient := fake.NewClientBuilder().WithObjects(testNode1).Build()
+	r := &MachineHealthCheckReconciler{Client: mgmtClient}
+
+	targets, err := r.getMachinePoolTargetsFromMHC(ctx, workloadClient, testMHC)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(targets).To(HaveLen(2))
+	g.Expect(targets[0].MachinePool.Name).To(Equal(testMachinePool1.Name))
+	g.Expect(targets[0].ProviderID).To(Equal("test:////node1"))
+	g.Expect(targets[0].Node).ToNot(BeNil())
+	g.Expect(targets[0].Node.Name).To(Equal(testNode1.Name))
+	g.Expect(targets[0].targetName()).To(Equal("pool1/node1"))
+	g.Expect(targets[1].ProviderID).To(Equal("test:////node2"))
+	g.Expect(targets[1].Node).To(BeNil())
+	g.Expect(targets[1].targetName()).To(Equal("pool1/test:////node2"))
+
+	// Only the instances without a node are tracked, and the time they have been first seen is preserved.
+	g.Expect(testMHC.Annotations).To(HaveKey(MachinePoolInstancesFirstSeenAnnotation))
+	firstSeen := map[string]time.Time{}
+	g.Expect(json.Unmarshal([]byte(testMHC.Annotations[MachinePoolInstancesFirstSeenAnnotation]), &firstSeen)).To(Succeed())
+	g.Expect(firstSeen).To(HaveLen(1))
+	g.Expect(firstSeen).To(HaveKey("test:////node2"))
+
+	earlier := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	testMHC.Annotations[MachinePoolInstancesFirstSeenAnnotation] = fmt.Sprintf(`{"test:////node2":%q,"test:////gone":%q}`, earlier.Format(time.RFC3339), earlier.Format(time.RFC3339))
+	targets, err = r.getMachinePoolTargetsFromMHC(ctx, workloadClient, testMHC)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(targets[1].FirstSeen.Equal(earlier)).To(BeTrue())
+	firstSeen = map[string]time.Time{}
+	g.Expect(json.Unmarshal([]byte(testMHC.Annotations[MachinePoolInstancesFirstSeenAnnotation]), &firstSeen)).To(Succeed())
+	g.Expect(firstSeen).To(HaveLen(1))
+	g.Expect(firstSeen["test:////node2"].Equal(earlier)).To(BeTrue())
+}
+
+func TestGetMachinePoolFromNode(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(feature.MutableGates.Set("MachinePool=true")).To(Succeed())
+	defer func() {
+		g.Expect(feature.MutableGates.Set("MachinePool=false")).To(Succeed())
+	}()
+	g.Expect(expv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	node := newTestNode("node1")
+	node.Spec.ProviderID = "test:////node1"
+	otherCluster := newTestMachinePool("pool1", "test-mhc", "other-cluster", nil, "test:////node1")
+	testCluster := newTestMachinePool("pool2", "test-mhc", "test-cluster", nil, "test:////node1")
+	r := &MachineHealthCheckReconciler{Client: fake.NewClientBuilder().WithObjects(otherCluster, testCluster).Build()}
+
+	mp := r.getMachinePoolFromNode(ctx, client.ObjectKey{Namespace: "test-mhc", Name: "test-cluster"}, node)
+	g.Expect(mp).ToNot(BeNil())
+	g.Expect(mp.Name).To(Equal(testCluster.Name))
+
+	g.Expect(r.getMachinePoolFromNode(ctx, client.ObjectKey{Namespace: "other-namespace", Name: "test-cluster"}, node)).To(BeNil())
+}
+
+func TestMachinePoolInstanceTargetNeedsRemediation(t *testing.T) {
+	timeoutForMachineToHaveNode := 10 * time.Minute
+
+	testMHC := &clusterv1.MachineHealthCheck{
+		Spec: clusterv1.MachineHealthCheckSpec{
+			UnhealthyConditions: []clusterv1.UnhealthyCondition{
+				{
+					Type:    corev1.NodeReady,
+					Status:  corev1.ConditionUnknown,
+					Timeout: metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc              string
+		firstSeen         time.Duration
+		node              *corev1.Node
+		expectRemediation bool
+		expectNextCheck   bool
+	}{
+		{
+			desc:              "healthy node",
+			node:              newTestNode("node1"),
+			expectRemediation: false,
+			expectNextCheck:   false,
+		},
+		{
+			desc:              "node unhealthy for longer than the timeout",
+			node:              newTestUnhealthyNode("node1", corev1.NodeReady, corev1.ConditionUnknown, 10*time.Minute),
+			expectRemediation: true,
+		},
+		{
+			desc:              "node unhealthy for shorter than the timeout",
+			node:              newTestUnhealthyNode("node1", corev1.NodeReady, corev1.ConditionUnknown, time.Minute),
+			expectRemediation: false,
+			expectNextCheck:   true,
+		},
+		{
+			desc:              "instance without node after the node startup timeout",
+			firstSeen:         time.Hour,
+			expectRemediation: true,
+		},
+		{
+			desc:              "instance without node before the node startup timeout",
+			firstSeen:         time.Minute,
+			expectRemediation: false,
+			expectNextCheck:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			target := machinePoolInstanceTarget{
+				MachinePool: newTestMachinePool("pool1", "test-mhc", "test-cluster", nil, "test:////node1"),
+				ProviderID:  "test:////node1",
+				Node:        tc.node,
+				MHC:         testMHC,
+				FirstSeen:   time.Now().Add(-tc.firstSeen),
+			}
+			needsRemediation, _, nextCheck := target.needsRemediation(ctrl.LoggerFrom(ctx), timeoutForMachineToHaveNode)
+			g.Expect(needsRemediation).To(Equal(tc.expectRemediation))
+			g.Expect(nextCheck > 0).To(Equal(tc.expectNextCheck))
+		})
+	}
+}
+
+func TestHealthCheckMachinePoolTargets(t *testing.T) {
+	g := NewWithT(t)
+
+	testMHC := &clusterv1.MachineHealthCheck{
+		Spec: clusterv1.MachineHealthCheckSpec{
+			UnhealthyConditions: []clusterv1.UnhealthyCondition{
+				{
+					Type:    corev1.NodeReady,
+					Status:  corev1.ConditionUnknown,
+					Timeout: metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+		},
+	}
+	machinePool := newTestMachinePool("pool1", "test-mhc", "test-cluster", nil, "test:////node1", "test:////node2", "test:////node3")
+	targets := []machinePoolInstanceTarget{
+		{MachinePool: machinePool, MHC: testMHC, ProviderID: "test:////node1", Node: newTestNode("node1")},
+		{MachinePool: machinePool, MHC: testMHC, ProviderID: "test:////node2", Node: newTestUnhealthyNode("node2", corev1.NodeReady, corev1.ConditionUnknown, time.Minute)},
+		{MachinePool: machinePool, MHC: testMHC, ProviderID: "test:////node3", FirstSeen: time.Now()},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &MachineHealthCheckReconciler{recorder: recorder}
+
+	healthy, unhealthy, nextCheckTimes := r.healthCheckMachinePoolTargets(targets, ctrl.LoggerFrom(ctx), 10*time.Minute)
+	g.Expect(healthy).To(HaveLen(1))
+	g.Expect(unhealthy).To(BeEmpty())
+	g.Expect(nextCheckTimes).To(HaveLen(2))
+
+	// Only the instance with a node matching an unhealthy condition is reported.
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(Equal(fmt.Sprintf("Normal %s MachinePool instance test:////node2 has unhealthy node node2", EventDetectedUnhealthy)))
+}
+
+func TestRemediateMachinePoolTargets(t *testing.T) {
+	namespace := "test-mhc"
+	clusterName := "test-cluster"
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: clusterName}}
+
+	setup := func(g *WithT, infraAnnotations map[string]string) (*MachineHealthCheckReconciler, []machinePoolInstanceTarget, *unstructured.Unstructured) {
+		g.Expect(expv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+		testMHC := &clusterv1.MachineHealthCheck{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "test-mhc"}}
+
+		machinePool := newTestMachinePool("pool1", namespace, clusterName, nil, "test:////node1", "test:////node2")
+		infraMachinePool := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachinePool",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+				"metadata": map[string]interface{}{
+					"name":      "pool1-infra",
+					"namespace": namespace,
+				},
+			},
+		}
+		infraMachinePool.SetAnnotations(infraAnnotations)
+		machinePool.Spec.Template.Spec.InfrastructureRef = corev1.ObjectReference{
+			APIVersion: infraMachinePool.GetAPIVersion(),
+			Kind:       infraMachinePool.GetKind(),
+			Name:       infraMachinePool.GetName(),
+		}
+
+		r := &MachineHealthCheckReconciler{
+			Client:   fake.NewClientBuilder().WithObjects(machinePool, infraMachinePool).Build(),
+			recorder: record.NewFakeRecorder(5),
+		}
+		targets := []machinePoolInstanceTarget{
+			{MachinePool: machinePool, ProviderID: "test:////node1", MHC: testMHC},
+			{MachinePool: machinePool, ProviderID: "test:////node2", MHC: testMHC},
+		}
+		return r, targets, infraMachinePool
+	}
+
+	getAnnotations := func(g *WithT, r *MachineHealthCheckReconciler, infraMachinePool *unstructured.Unstructured) map[string]string {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(infraMachinePool.GroupVersionKind())
+		g.Expect(r.Client.Get(ctx, util.ObjectKey(infraMachinePool), obj)).To(Succeed())
+		return obj.GetAnnotations()
+	}
+
+	t.Run("should request the remediation of the unhealthy instances", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, targets, infraMachinePool := setup(g, nil)
+
+		errList := r.remediateMachinePoolTargets(ctx, ctrl.LoggerFrom(ctx), targets, targets, cluster, targets[0].MHC)
+		g.Expect(errList).To(BeEmpty())
+		g.Expect(getAnnotations(g, r, infraMachinePool)).To(HaveKeyWithValue(expv1.RemediateMachinePoolInstancesAnnotation, "test:////node1,test:////node2"))
+
+		// The MachineHealthCheck tracks the remediation request, so it can clear it later on.
+		g.Expect(targets[0].MHC.Annotations).To(HaveKeyWithValue(MachinePoolsRemediationRequestedAnnotation, "pool1"))
+		g.Expect(targets[0].MHC.Finalizers).To(ContainElement(MachineHealthCheckMachinePoolFinalizer))
+	})
+
+	t.Run("should clear the remediation request when all the instances are healthy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, targets, infraMachinePool := setup(g, map[string]string{expv1.RemediateMachinePoolInstancesAnnotation: "test:////node1"})
+
+		targets[0].MHC.Annotations = map[string]string{MachinePoolsRemediationRequestedAnnotation: "pool1"}
+		targets[0].MHC.Finalizers = []string{MachineHealthCheckMachinePoolFinalizer}
+
+		errList := r.remediateMachinePoolTargets(ctx, ctrl.LoggerFrom(ctx), targets, nil, cluster, targets[0].MHC)
+		g.Expect(errList).To(BeEmpty())
+		g.Expect(getAnnotations(g, r, infraMachinePool)).ToNot(HaveKey(expv1.RemediateMachinePoolInstancesAnnotation))
+		g.Expect(targets[0].MHC.Annotations).ToNot(HaveKey(MachinePoolsRemediationRequestedAnnotation))
+		g.Expect(targets[0].MHC.Finalizers).To(BeEmpty())
+	})
+
+	t.Run("should not request the remediation if the MachineHealthCheck uses external remediation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, targets, infraMachinePool := setup(g, nil)
+		targets[0].MHC.Spec.RemediationTemplate = &corev1.ObjectReference{Kind: "GenericExternalRemediationTemplate", Name: "remediation"}
+		recorder := record.NewFakeRecorder(5)
+		r.recorder = recorder
+
+		errList := r.remediateMachinePoolTargets(ctx, ctrl.LoggerFrom(ctx), targets, targets, cluster, targets[0].MHC)
+		g.Expect(errList).To(BeEmpty())
+		g.Expect(getAnnotations(g, r, infraMachinePool)).ToNot(HaveKey(expv1.RemediateMachinePoolInstancesAnnotation))
+		g.Expect(recorder.Events).To(Receive(ContainSubstring(EventRemediationRestricted)))
+	})
+
+	t.Run("should clear the remediation request when the machine pool is no longer targeted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, targets, infraMachinePool := setup(g, map[string]string{expv1.RemediateMachinePoolInstancesAnnotation: "test:////node1"})
+		mhc := targets[0].MHC
+		mhc.Annotations = map[string]string{MachinePoolsRemediationRequestedAnnotation: "pool1,deleted-pool"}
+		mhc.Finalizers = []string{MachineHealthCheckMachinePoolFinalizer}
+
+		// Requests on targeted machine pools are left untouched.
+		g.Expect(r.clearMachinePoolRemediationRequests(ctx, mhc, targets)).To(Succeed())
+		g.Expect(getAnnotations(g, r, infraMachinePool)).To(HaveKey(expv1.RemediateMachinePoolInstancesAnnotation))
+		g.Expect(mhc.Annotations).To(HaveKeyWithValue(MachinePoolsRemediationRequestedAnnotation, "pool1"))
+		g.Expect(mhc.Finalizers).To(ContainElement(MachineHealthCheckMachinePoolFinalizer))
+
+		g.Expect(r.clearMachinePoolRemediationRequests(ctx, mhc, nil)).To(Succeed())
+		g.Expect(getAnnotations(g, r, infraMachinePool)).ToNot(HaveKey(expv1.RemediateMachinePoolInstancesAnnotation))
+		g.Expect(mhc.Annotations).ToNot(HaveKey(MachinePoolsRemediationRequestedAnnotation))
+		g.Expect(mhc.Finalizers).To(BeEmpty())
+	})
+
+	t.Run("should clear the remediation requests when the MachineHealthCheck is deleted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, targets, infraMachinePool := setup(g, map[string]string{expv1.RemediateMachinePoolInstancesAnnotation: "test:////node1"})
+		mhc := targets[0].MHC
+		mhc.Annotations = map[string]string{MachinePoolsRemediationRequestedAnnotation: "pool1"}
+		mhc.Finalizers = []string{MachineHealthCheckMachinePoolFinalizer}
+		g.Expect(r.Client.Create(ctx, mhc)).To(Succeed())
+
+		_, err := r.reconcileDelete(ctx, mhc)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(getAnnotations(g, r, infraMachinePool)).ToNot(HaveKey(expv1.RemediateMachinePoolInstancesAnnotation))
+
+		updatedMHC := &clusterv1.MachineHealthCheck{}
+		g.Expect(r.Client.Get(ctx, util.ObjectKey(mhc), updatedMHC)).To(Succeed())
+		g.Expect(updatedMHC.Finalizers).To(BeEmpty())
+		g.Expect(updatedMHC.Annotations).ToNot(HaveKey(MachinePoolsRemediationRequestedAnnotation))
+	})
+
+	t.Run("should not request the remediation if the machine pool is paused", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, targets, infraMachinePool := setup(g, nil)
+		targets[0].MachinePool.Annotations = map[string]string{clusterv1.PausedAnnotation: ""}
+
+		errList := r.remediateMachinePoolTargets(ctx, ctrl.LoggerFrom(ctx), targets, targets[:1], cluster, targets[0].MHC)
+		g.Expect(errList).To(BeEmpty())
+		g.Expect(getAnnotations(g, r, infraMachinePool)).ToNot(HaveKey(expv1.RemediateMachinePoolInstancesAnnotation))
+	})
+}
+
+func newTestMachinePool(name, namespace, clusterName string, labels map[string]string, providerIDs ...string) *expv1.MachinePool {
+	mp := &expv1.MachinePool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: expv1.GroupVersion.String(),
+			Kind:       "MachinePool",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{clusterv1.ClusterLabelName: clusterName},
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: clusterName,
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{
+					Labels: labels,
+				},
+			},
+			ProviderIDList: providerIDs,
+		},
+	}
+	conditions.MarkTrue(mp, expv1.ReplicasReadyCondition)
+	return mp
+}
@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -30,8 +31,10 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/mdutil"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util"
@@ -70,8 +73,9 @@ type MachineSetReconciler struct {
 	Client  client.Client
 	Tracker *remote.ClusterCacheTracker
 
-	recorder   record.EventRecorder
-	restConfig *rest.Config
+	recorder        record.EventRecorder
+	restConfig      *rest.Config
+	externalTracker external.ObjectTracker
 }
 
 func (r *MachineSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -106,6 +110,9 @@ func (r *MachineSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 
 	r.recorder = mgr.GetEventRecorderFor("machineset-controller")
 	r.restConfig = mgr.GetConfig()
+	r.externalTracker = external.ObjectTracker{
+		Controller: c,
+	}
 	return nil
 }
 
@@ -183,6 +190,20 @@ func (r *MachineSetReconciler) reconcile(ctx context.Context, cluster *clusterv1
 		}
 	}
 
+	// Make sure the annotations used by the cluster autoscaler to scale from zero are up to date.
+	patch := client.MergeFrom(machineSet.DeepCopy())
+	capacityChanged, err := reconcileCapacityAnnotations(ctx, r.Client, &r.externalTracker,
+		handler.EnqueueRequestsFromMapFunc(r.InfrastructureTemplateToMachineSets), &machineSet.Spec.Template, machineSet)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile capacity annotations for MachineSet %s/%s", machineSet.Namespace, machineSet.Name)
+	}
+	if capacityChanged {
+		// Patch using a deep copy to avoid overwriting any unexpected Status changes from the returned result
+		if err := r.Client.Patch(ctx, machineSet.DeepCopy(), patch); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to patch capacity annotations for MachineSet %s/%s", machineSet.Namespace, machineSet.Name)
+		}
+	}
+
 	// Make sure selector and template to be in the same cluster.
 	machineSet.Spec.Selector.MatchLabels[clusterv1.ClusterLabelName] = machineSet.Spec.ClusterName
 	machineSet.Spec.Template.Labels[clusterv1.ClusterLabelName] = machineSet.Spec.ClusterName
@@ -252,7 +273,11 @@ func (r *MachineSetReconciler) reconcile(ctx context.Context, cluster *clusterv1
 		return ctrl.Result{}, errors.Wrap(err, "failed to remediate machines")
 	}
 
-	syncErr := r.syncReplicas(ctx, machineSet, filteredMachines)
+	if err := r.syncMachinesInPlace(ctx, machineSet, filteredMachines); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to propagate machine template changes in place")
+	}
+
+	syncErr := r.syncReplicas(ctx, cluster, machineSet, filteredMachines)
 
 	ms := machineSet.DeepCopy()
 	newStatus, err := r.calculateStatus(ctx, cluster, ms, filteredMachines)
@@ -301,29 +326,141 @@ func (r *MachineSetReconciler) reconcile(ctx context.Context, cluster *clusterv1
 	return ctrl.Result{}, nil
 }
 
+// syncMachinesInPlace applies to the Machines the changes to the fields of the machine template which can be
+// propagated in place, since the last time they have been propagated; the fields last propagated are tracked by the
+// InPlaceTemplateAnnotation. Machines are updated before the annotation, so in case of errors the changes are still
+// detected in the next reconcile.
+func (r *MachineSetReconciler) syncMachinesInPlace(ctx context.Context, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	current := mdutil.InPlaceTemplate(&ms.Spec.Template)
+	var last *clusterv1.MachineTemplateSpec
+	if value, ok := ms.Annotations[clusterv1.InPlaceTemplateAnnotation]; ok {
+		last = &clusterv1.MachineTemplateSpec{}
+		if err := json.Unmarshal([]byte(value), last); err != nil {
+			// The Machines can't be updated without knowing the values last propagated, so only the annotation is reset.
+			log.Error(err, "Failed to parse annotation, resetting it", "annotation", clusterv1.InPlaceTemplateAnnotation)
+			last = nil
+		}
+	}
+
+	var fields []string
+	if last != nil {
+		fields = mdutil.InPlaceChangedFields(last, current)
+		if len(fields) == 0 {
+			return nil
+		}
+	}
+
+	if len(fields) > 0 {
+		var errs []error
+		for _, m := range machines {
+			if !m.DeletionTimestamp.IsZero() {
+				continue
+			}
+			patchHelper, err := patch.NewHelper(m, r.Client)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !mdutil.UpdateMachineInPlace(m, last, current) {
+				continue
+			}
+			if err := patchHelper.Patch(ctx, m); err != nil {
+				errs = append(errs, errors.Wrapf(err, "failed to update Machine %q in place", m.Name))
+			}
+		}
+		if err := kerrors.NewAggregate(errs); err != nil {
+			return err
+		}
+		log.Info("Propagated machine template changes in place", "fields", fields)
+		r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulUpdateInPlace", "Updated Machines in place: %s", strings.Join(fields, ", "))
+	}
+
+	patch := client.MergeFrom(ms.DeepCopy())
+	if err := mdutil.SetInPlaceTemplateAnnotation(ms, current); err != nil {
+		return err
+	}
+	// Patch using a deep copy to avoid overwriting any unexpected Status changes from the returned result
+	if err := r.Client.Patch(ctx, ms.DeepCopy(), patch); err != nil {
+		return errors.Wrapf(err, "failed to patch MachineSet %s/%s", ms.Namespace, ms.Name)
+	}
+	return nil
+}
+
+// getOtherDeploymentMachines returns the Machines of the other MachineSets of the MachineDeployment controlling
+// the MachineSet, if any.
+func (r *MachineSetReconciler) getOtherDeploymentMachines(ctx context.Context, ms *clusterv1.MachineSet) ([]*clusterv1.Machine, error) {
+	controllerRef := metav1.GetControllerOf(ms)
+	if controllerRef == nil || controllerRef.Kind != "MachineDeployment" {
+		return nil, nil
+	}
+
+	machineList := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machineList, client.InNamespace(ms.Namespace), client.MatchingLabels{
+		clusterv1.ClusterLabelName:           ms.Spec.ClusterName,
+		clusterv1.MachineDeploymentLabelName: controllerRef.Name,
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list Machines of MachineDeployment %s/%s", ms.Namespace, controllerRef.Name)
+	}
+
+	var machines []*clusterv1.Machine
+	for i := range machineList.Items {
+		m := &machineList.Items[i]
+		if owner := metav1.GetControllerOf(m); owner == nil || owner.Kind != "MachineSet" || owner.Name == ms.Name {
+			continue
+		}
+		machines = append(machines, m)
+	}
+	return machines, nil
+}
+
 // syncReplicas scales Machine resources up or down.
-func (r *MachineSetReconciler) syncReplicas(ctx context.Context, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
+func (r *MachineSetReconciler) syncReplicas(ctx context.Context, cluster *clusterv1.Cluster, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
 	log := ctrl.LoggerFrom(ctx)
 	if ms.Spec.Replicas == nil {
 		return errors.Errorf("the Replicas field in Spec for machineset %v is nil, this should not be allowed", ms.Name)
 	}
 
+	// When spreading Machines across failure domains, new Machines are created in the failure domains with the fewest
+	// Machines, and Machines in the failure domains with the most Machines are deleted first.
+	// The Machines of the other MachineSets of the same MachineDeployment are counted as well, so the Machines
+	// of the MachineDeployment are spread evenly during and after a rollout.
+	failureDomains := eligibleFailureDomains(cluster, ms)
+	var otherMachines []*clusterv1.Machine
+	if len(failureDomains) > 0 {
+		var err error
+		if otherMachines, err = r.getOtherDeploymentMachines(ctx, ms); err != nil {
+			return err
+		}
+	}
+
 	diff := len(machines) - int(*(ms.Spec.Replicas))
 	switch {
 	case diff < 0:
 		diff *= -1
 		log.Info("Too few replicas", "need", *(ms.Spec.Replicas), "creating", diff)
+		if ms.Annotations != nil {
+			if _, ok := ms.Annotations[clusterv1.DisableMachineCreate]; ok {
+				log.V(2).Info("Automatic creation of new machines disabled for machine set")
+				return nil
+			}
+		}
 
 		var (
 			machineList []*clusterv1.Machine
 			errs        []error
 		)
+		counts := failureDomainCounts(failureDomains, machines, otherMachines)
 
 		for i := 0; i < diff; i++ {
 			log.Info(fmt.Sprintf("Creating machine %d of %d, ( spec.replicas(%d) > currentMachineCount(%d) )",
 				i+1, diff, *(ms.Spec.Replicas), len(machines)))
 
 			machine := r.getNewMachine(ms)
+			if len(failureDomains) > 0 {
+				machine.Spec.FailureDomain = pointer.StringPtr(pickFewestFailureDomain(failureDomains, counts))
+			}
 
 			// Clone and set the infrastructure and bootstrap references.
 			var (
@@ -374,6 +511,9 @@ func (r *MachineSetReconciler) syncReplicas(ctx context.Context, ms *clusterv1.M
 				continue
 			}
 
+			if len(failureDomains) > 0 {
+				counts[*machine.Spec.FailureDomain]++
+			}
 			log.Info(fmt.Sprintf("Created machine %d of %d with name %q", i+1, diff, machine.Name))
 			r.recorder.Eventf(ms, corev1.EventTypeNormal, "SuccessfulCreate", "Created machine %q", machine.Name)
 			machineList = append(machineList, machine)
@@ -386,14 +526,19 @@ func (r *MachineSetReconciler) syncReplicas(ctx context.Context, ms *clusterv1.M
 	case diff > 0:
 		log.Info("Too many replicas", "need", *(ms.Spec.Replicas), "deleting", diff)
 
-		deletePriorityFunc, err := getDeletePriorityFunc(ms)
+		deletePriorityFunc, err := r.getDeletePriorityFunc(ctx, ms, machines)
 		if err != nil {
 			return err
 		}
 		log.Info("Found delete policy", "delete-policy", ms.Spec.DeletePolicy)
 
 		var errs []error
-		machinesToDelete := getMachinesToDeletePrioritized(machines, diff, deletePriorityFunc)
+		var machinesToDelete []*clusterv1.Machine
+		if len(failureDomains) > 0 {
+			machinesToDelete = getMachinesToDeleteSpread(machines, diff, deletePriorityFunc, failureDomains, otherMachines)
+		} else {
+			machinesToDelete = getMachinesToDeletePrioritized(machines, diff, deletePriorityFunc)
+		}
 		for _, machine := range machinesToDelete {
 			if err := r.Client.Delete(ctx, machine); err != nil {
 				log.Error(err, "Unable to delete Machine", "machine", machine.Name)
@@ -536,6 +681,24 @@ func (r *MachineSetReconciler) MachineToMachineSets(o client.Object) []ctrl.Requ
 	return result
 }
 
+// InfrastructureTemplateToMachineSets is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for MachineSets using the infrastructure machine template.
+func (r *MachineSetReconciler) InfrastructureTemplateToMachineSets(o client.Object) []ctrl.Request {
+	msList := &clusterv1.MachineSetList{}
+	if err := r.Client.List(context.TODO(), msList, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for i := range msList.Items {
+		ms := &msList.Items[i]
+		if referencesInfrastructureTemplate(&ms.Spec.Template, o) {
+			result = append(result, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ms)})
+		}
+	}
+	return result
+}
+
 func (r *MachineSetReconciler) getMachineSetsForMachine(ctx context.Context, m *clusterv1.Machine) []*clusterv1.MachineSet {
 	log := ctrl.LoggerFrom(ctx, "machine", m.Name)
 
@@ -627,9 +790,11 @@ func (r *MachineSetReconciler) calculateStatus(ctx context.Context, cluster *clu
 			continue
 		}
 
-		if noderefutil.IsNodeReady(node) {
+		// A Machine is ready and available only if the conditions of its readiness gates are true as well.
+		if noderefutil.IsNodeReady(node) && len(pendingReadinessGates(machine)) == 0 {
 			readyReplicasCount++
-			if noderefutil.IsNodeAvailable(node, ms.Spec.MinReadySeconds, metav1.Now()) {
+			now := metav1.Now()
+			if noderefutil.IsNodeAvailable(node, ms.Spec.MinReadySeconds, now) && isReadinessGatesAvailable(machine, ms.Spec.MinReadySeconds, now) {
 				availableReplicasCount++
 			}
 		}
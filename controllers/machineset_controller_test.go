@@ -771,3 +771,51 @@ func newMachineSet(name, cluster string) *clusterv1.MachineSet {
 		},
 	}
 }
+
+func TestSyncMachinesInPlace(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := newMachineSet("machineset1", "test-cluster")
+	ms.Spec.Template.Annotations = map[string]string{"description": "old"}
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "machine1",
+			Namespace:   "default",
+			Labels:      map[string]string{"custom": "value"},
+			Annotations: map[string]string{"description": "old", "custom": "value"},
+		},
+	}
+
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	r := &MachineSetReconciler{
+		Client:   fake.NewFakeClientWithScheme(scheme.Scheme, ms, machine),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// The first sync only records the fields of the machine template which can be propagated in place.
+	g.Expect(r.syncMachinesInPlace(ctx, ms, []*clusterv1.Machine{machine})).To(Succeed())
+	g.Expect(r.Client.Get(ctx, util.ObjectKey(ms), ms)).To(Succeed())
+	g.Expect(ms.Annotations).To(HaveKey(clusterv1.InPlaceTemplateAnnotation))
+
+	// Changes to the machine template are applied to the Machines, preserving the values set directly on the Machines.
+	ms.Spec.Template.Annotations = map[string]string{"description": "new"}
+	ms.Spec.Template.Spec.NodeDrainTimeout = &metav1.Duration{Duration: time.Minute}
+	g.Expect(r.syncMachinesInPlace(ctx, ms, []*clusterv1.Machine{machine})).To(Succeed())
+
+	g.Expect(r.Client.Get(ctx, util.ObjectKey(machine), machine)).To(Succeed())
+	g.Expect(machine.Labels).To(HaveKeyWithValue("custom", "value"))
+	g.Expect(machine.Annotations).To(Equal(map[string]string{"description": "new", "custom": "value"}))
+	g.Expect(machine.Spec.NodeDrainTimeout).To(Equal(&metav1.Duration{Duration: time.Minute}))
+
+	// Values changed directly on the Machines are not overwritten when the machine template doesn't change.
+	machine.Annotations["description"] = "changed"
+	g.Expect(r.Client.Update(ctx, machine)).To(Succeed())
+	g.Expect(r.Client.Get(ctx, util.ObjectKey(ms), ms)).To(Succeed())
+	ms.Spec.Template.Annotations = map[string]string{"description": "new"}
+	ms.Spec.Template.Spec.NodeDrainTimeout = &metav1.Duration{Duration: time.Minute}
+	g.Expect(r.syncMachinesInPlace(ctx, ms, []*clusterv1.Machine{machine})).To(Succeed())
+	g.Expect(r.Client.Get(ctx, util.ObjectKey(machine), machine)).To(Succeed())
+	g.Expect(machine.Annotations).To(HaveKeyWithValue("description", "changed"))
+}
@@ -17,12 +17,19 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
 	"math"
 	"sort"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type (
@@ -37,6 +44,10 @@ const (
 	mustNotDelete deletePriority = 0.0
 
 	secondsPerTenDays float64 = 864000
+
+	// disruptivePodWeight is the weight of Pods protected by a PodDisruptionBudget or using local storage,
+	// compared to other Pods, when computing the workload of a Node.
+	disruptivePodWeight float64 = 10
 )
 
 // maps the creation timestamp onto the 0-100 priority range
@@ -95,6 +106,157 @@ func randomDeletePolicy(machine *clusterv1.Machine) deletePriority {
 	return couldDelete
 }
 
+// nodeWorkload describes the Pods running on a Node which are evicted when the corresponding Machine is deleted.
+type nodeWorkload struct {
+	// pods is the number of Pods not managed by a DaemonSet.
+	pods int
+	// protectedPods is the number of Pods protected by a PodDisruptionBudget.
+	protectedPods int
+	// localStoragePods is the number of Pods using local storage.
+	localStoragePods int
+}
+
+// cost returns the disruption caused by evicting the Pods running on a Node.
+func (w nodeWorkload) cost() float64 {
+	return float64(w.pods) + disruptivePodWeight*float64(w.protectedPods+w.localStoragePods)
+}
+
+// leastDisruptiveDeletePriority returns a delete priority function which maps the workload of the Nodes onto
+// the 0-50 priority range, giving the highest priority to the Machines whose Node runs the lowest workload.
+// Nodes missing in workloads are considered as not running any Pod.
+func leastDisruptiveDeletePriority(workloads map[string]nodeWorkload) deletePriorityFunc {
+	return func(machine *clusterv1.Machine) deletePriority {
+		if !machine.DeletionTimestamp.IsZero() {
+			return mustDelete
+		}
+		if _, ok := machine.ObjectMeta.Annotations[clusterv1.DeleteMachineAnnotation]; ok {
+			return mustDelete
+		}
+		if machine.Status.NodeRef == nil {
+			return mustDelete
+		}
+		if machine.Status.FailureReason != nil || machine.Status.FailureMessage != nil {
+			return mustDelete
+		}
+		return deletePriority(float64(betterDelete) / (1.0 + workloads[machine.Status.NodeRef.Name].cost()))
+	}
+}
+
+// getNodeWorkloads returns the workload of the Nodes of the given Machines, indexed by Node name.
+func (r *MachineSetReconciler) getNodeWorkloads(ctx context.Context, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) (map[string]nodeWorkload, error) {
+	remoteClient, err := r.Tracker.GetLiveClient(ctx, client.ObjectKey{Namespace: ms.Namespace, Name: ms.Spec.ClusterName})
+	if err != nil {
+		return nil, err
+	}
+
+	pdbs := &policyv1beta1.PodDisruptionBudgetList{}
+	if err := remoteClient.List(ctx, pdbs); err != nil {
+		return nil, errors.Wrap(err, "failed to list PodDisruptionBudgets")
+	}
+	localClaims, err := getLocalPersistentVolumeClaims(ctx, remoteClient)
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := map[string]nodeWorkload{}
+	for _, machine := range machines {
+		if machine.Status.NodeRef == nil {
+			continue
+		}
+		nodeName := machine.Status.NodeRef.Name
+		if _, ok := workloads[nodeName]; ok {
+			continue
+		}
+
+		// Only the Pods running on the Node are listed, to avoid listing all the Pods of the workload cluster.
+		pods := &corev1.PodList{}
+		if err := remoteClient.List(ctx, pods, client.MatchingFields{"spec.nodeName": nodeName}); err != nil {
+			return nil, errors.Wrapf(err, "failed to list Pods on Node %q", nodeName)
+		}
+
+		w := nodeWorkload{}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Spec.NodeName != nodeName || isPodTerminated(pod) || isDaemonSetPod(pod) || isMirrorPod(pod) {
+				continue
+			}
+
+			w.pods++
+			if isPodProtected(pod, pdbs.Items) {
+				w.protectedPods++
+			}
+			if hasLocalStorage(pod, localClaims) {
+				w.localStoragePods++
+			}
+		}
+		workloads[nodeName] = w
+	}
+	return workloads, nil
+}
+
+// getLocalPersistentVolumeClaims returns the PersistentVolumeClaims bound to local PersistentVolumes, in the namespace/name form.
+func getLocalPersistentVolumeClaims(ctx context.Context, c client.Client) (sets.String, error) {
+	pvs := &corev1.PersistentVolumeList{}
+	if err := c.List(ctx, pvs); err != nil {
+		return nil, errors.Wrap(err, "failed to list PersistentVolumes")
+	}
+
+	claims := sets.NewString()
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		if pv.Spec.Local == nil || pv.Spec.ClaimRef == nil {
+			continue
+		}
+		claims.Insert(pv.Spec.ClaimRef.Namespace + "/" + pv.Spec.ClaimRef.Name)
+	}
+	return claims, nil
+}
+
+func isPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	controllerRef := metav1.GetControllerOf(pod)
+	return controllerRef != nil && controllerRef.Kind == "DaemonSet"
+}
+
+func isMirrorPod(pod *corev1.Pod) bool {
+	_, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]
+	return ok
+}
+
+func isPodProtected(pod *corev1.Pod, pdbs []policyv1beta1.PodDisruptionBudget) bool {
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		if pdb.Namespace != pod.Namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLocalStorage returns true if the Pod uses storage local to the Node, which is lost when the Pod is evicted:
+// emptyDir and hostPath volumes, or PersistentVolumeClaims bound to local PersistentVolumes.
+func hasLocalStorage(pod *corev1.Pod, localClaims sets.String) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil || volume.HostPath != nil {
+			return true
+		}
+		if volume.PersistentVolumeClaim != nil && localClaims.Has(pod.Namespace+"/"+volume.PersistentVolumeClaim.ClaimName) {
+			return true
+		}
+	}
+	return false
+}
+
 type sortableMachines struct {
 	machines []*clusterv1.Machine
 	priority deletePriorityFunc
@@ -122,7 +284,9 @@ func getMachinesToDeletePrioritized(filteredMachines []*clusterv1.Machine, diff
 	return sortable.machines[:diff]
 }
 
-func getDeletePriorityFunc(ms *clusterv1.MachineSet) (deletePriorityFunc, error) {
+func (r *MachineSetReconciler) getDeletePriorityFunc(ctx context.Context, ms *clusterv1.MachineSet, machines []*clusterv1.Machine) (deletePriorityFunc, error) {
+	log := ctrl.LoggerFrom(ctx)
+
 	// Map the Spec.DeletePolicy value to the appropriate delete priority function
 	switch msdp := clusterv1.MachineSetDeletePolicy(ms.Spec.DeletePolicy); msdp {
 	case clusterv1.RandomMachineSetDeletePolicy:
@@ -131,9 +295,17 @@ func getDeletePriorityFunc(ms *clusterv1.MachineSet) (deletePriorityFunc, error)
 		return newestDeletePriority, nil
 	case clusterv1.OldestMachineSetDeletePolicy:
 		return oldestDeletePriority, nil
+	case clusterv1.LeastDisruptiveMachineSetDeletePolicy:
+		workloads, err := r.getNodeWorkloads(ctx, ms, machines)
+		if err != nil {
+			// Scaling down must not be blocked if the workload cluster is not reachable.
+			log.Error(err, "Failed to get the workload of the Nodes, falling back to the Random delete policy")
+			return randomDeletePolicy, nil
+		}
+		return leastDisruptiveDeletePriority(workloads), nil
 	case "":
 		return randomDeletePolicy, nil
 	default:
-		return nil, errors.Errorf("Unsupported delete policy %s. Must be one of 'Random', 'Newest', or 'Oldest'", msdp)
+		return nil, errors.Errorf("Unsupported delete policy %s. Must be one of 'Random', 'Newest', 'Oldest' or 'LeastDisruptive'", msdp)
 	}
 }
@@ -22,9 +22,16 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 func TestMachineToDelete(t *testing.T) {
@@ -396,3 +403,207 @@ func TestMachineOldestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineLeastDisruptiveDelete(t *testing.T) {
+	statusError := capierrors.MachineStatusError("I'm unhealthy!")
+	newMachine := func(nodeName string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			Status: clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: nodeName}},
+		}
+	}
+	idle := newMachine("idle-node")
+	lightlyLoaded := newMachine("lightly-loaded-node")
+	heavilyLoaded := newMachine("heavily-loaded-node")
+	protected := newMachine("protected-node")
+	localStorage := newMachine("local-storage-node")
+	deleteMachineWithMachineAnnotation := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{clusterv1.DeleteMachineAnnotation: ""}},
+		Status:     clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "heavily-loaded-node"}},
+	}
+	unhealthyMachine := &clusterv1.Machine{
+		Status: clusterv1.MachineStatus{FailureReason: &statusError, NodeRef: &corev1.ObjectReference{Name: "heavily-loaded-node"}},
+	}
+	deleteMachineWithoutNodeRef := &clusterv1.Machine{}
+
+	workloads := map[string]nodeWorkload{
+		"lightly-loaded-node": {pods: 2},
+		"heavily-loaded-node": {pods: 20},
+		"protected-node":      {pods: 3, protectedPods: 2},
+		"local-storage-node":  {pods: 1, localStoragePods: 1},
+	}
+
+	tests := []struct {
+		desc     string
+		machines []*clusterv1.Machine
+		diff     int
+		expect   []*clusterv1.Machine
+	}{
+		{
+			desc: "func=leastDisruptiveDeletePriority, diff=1",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				heavilyLoaded, lightlyLoaded, idle,
+			},
+			expect: []*clusterv1.Machine{idle},
+		},
+		{
+			desc: "func=leastDisruptiveDeletePriority, diff=2",
+			diff: 2,
+			machines: []*clusterv1.Machine{
+				heavilyLoaded, lightlyLoaded, idle,
+			},
+			expect: []*clusterv1.Machine{idle, lightlyLoaded},
+		},
+		{
+			desc: "func=leastDisruptiveDeletePriority, diff=3 (PodDisruptionBudgets and local storage)",
+			diff: 3,
+			machines: []*clusterv1.Machine{
+				protected, heavilyLoaded, localStorage, lightlyLoaded,
+			},
+			expect: []*clusterv1.Machine{lightlyLoaded, localStorage, heavilyLoaded},
+		},
+		{
+			desc: "func=leastDisruptiveDeletePriority, diff=1 (DeleteMachineAnnotation)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				idle, lightlyLoaded, deleteMachineWithMachineAnnotation,
+			},
+			expect: []*clusterv1.Machine{deleteMachineWithMachineAnnotation},
+		},
+		{
+			desc: "func=leastDisruptiveDeletePriority, diff=1 (deleteMachineWithoutNodeRef)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				idle, lightlyLoaded, deleteMachineWithoutNodeRef,
+			},
+			expect: []*clusterv1.Machine{deleteMachineWithoutNodeRef},
+		},
+		{
+			desc: "func=leastDisruptiveDeletePriority, diff=1 (unhealthy)",
+			diff: 1,
+			machines: []*clusterv1.Machine{
+				idle, lightlyLoaded, unhealthyMachine,
+			},
+			expect: []*clusterv1.Machine{unhealthyMachine},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result := getMachinesToDeletePrioritized(test.machines, test.diff, leastDisruptiveDeletePriority(workloads))
+			g.Expect(result).To(Equal(test.expect))
+		})
+	}
+}
+
+func TestGetNodeWorkloads(t *testing.T) {
+	g := NewWithT(t)
+
+	newPod := func(name, nodeName string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    labels,
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+
+	daemonSetPod := newPod("daemonset-pod", "node-1", nil)
+	daemonSetPod.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "apps/v1",
+		Kind:       "DaemonSet",
+		Name:       "daemonset",
+		UID:        "daemonset-uid",
+		Controller: pointer.BoolPtr(true),
+	}}
+	mirrorPod := newPod("mirror-pod", "node-1", nil)
+	mirrorPod.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: ""}
+	terminatedPod := newPod("terminated-pod", "node-1", nil)
+	terminatedPod.Status.Phase = corev1.PodSucceeded
+	localStoragePod := newPod("local-storage-pod", "node-2", nil)
+	localStoragePod.Spec.Volumes = []corev1.Volume{{
+		Name:         "data",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}}
+	hostPathPod := newPod("host-path-pod", "node-2", nil)
+	hostPathPod.Spec.Volumes = []corev1.Volume{{
+		Name:         "data",
+		VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/data"}},
+	}}
+	localPVPod := newPod("local-pv-pod", "node-3", nil)
+	localPVPod.Spec.Volumes = []corev1.Volume{{
+		Name:         "data",
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "local-data"}},
+	}}
+	networkPVPod := newPod("network-pv-pod", "node-3", nil)
+	networkPVPod.Spec.Volumes = []corev1.Volume{{
+		Name:         "data",
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "network-data"}},
+	}}
+	localPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "local-pv"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{Local: &corev1.LocalVolumeSource{Path: "/mnt/disks/data"}},
+			ClaimRef:               &corev1.ObjectReference{Namespace: "default", Name: "local-data"},
+		},
+	}
+	networkPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "network-pv"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{NFS: &corev1.NFSVolumeSource{Server: "nfs", Path: "/data"}},
+			ClaimRef:               &corev1.ObjectReference{Namespace: "default", Name: "network-data"},
+		},
+	}
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pdb",
+			Namespace: "default",
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+		},
+	}
+
+	cluster := client.ObjectKey{Namespace: "default", Name: "test-cluster"}
+	remoteClient := fake.NewClientBuilder().WithObjects(
+		newPod("pod", "node-1", nil),
+		newPod("protected-pod", "node-1", map[string]string{"app": "db"}),
+		daemonSetPod,
+		mirrorPod,
+		terminatedPod,
+		localStoragePod,
+		hostPathPod,
+		localPVPod,
+		networkPVPod,
+		newPod("pending-pod", "", nil),
+		newPod("other-node-pod", "node-4", nil),
+		pdb,
+		localPV,
+		networkPV,
+	).Build()
+	r := &MachineSetReconciler{
+		Tracker: remote.NewTestClusterCacheTracker(log.NullLogger{}, remoteClient, scheme.Scheme, cluster),
+	}
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace},
+		Spec:       clusterv1.MachineSetSpec{ClusterName: cluster.Name},
+	}
+
+	newMachine := func(nodeName string) *clusterv1.Machine {
+		return &clusterv1.Machine{Status: clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: nodeName}}}
+	}
+	machines := []*clusterv1.Machine{newMachine("node-1"), newMachine("node-2"), newMachine("node-3"), {}}
+
+	// Only the Nodes of the given Machines are considered.
+	workloads, err := r.getNodeWorkloads(ctx, ms, machines)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(workloads).To(Equal(map[string]nodeWorkload{
+		"node-1": {pods: 2, protectedPods: 1},
+		"node-2": {pods: 2, localStoragePods: 2},
+		"node-3": {pods: 2, localStoragePods: 1},
+	}))
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+// eligibleFailureDomains returns the sorted list of the Cluster's failure domains the Machines of the MachineSet
+// can be spread across; it returns nil if the MachineSet does not spread its Machines across failure domains.
+func eligibleFailureDomains(cluster *clusterv1.Cluster, ms *clusterv1.MachineSet) []string {
+	distribution := ms.Spec.FailureDomainDistribution
+	if distribution == nil || distribution.Policy != clusterv1.SpreadFailureDomainDistributionPolicy {
+		return nil
+	}
+
+	eligible := sets.NewString()
+	for id := range cluster.Status.FailureDomains {
+		eligible.Insert(id)
+	}
+	if len(distribution.FailureDomains) > 0 {
+		eligible = eligible.Intersection(sets.NewString(distribution.FailureDomains...))
+	}
+	if eligible.Len() == 0 {
+		return nil
+	}
+	return eligible.List()
+}
+
+// failureDomainCounts returns the number of Machines in each of the given failure domains, across all the given
+// lists of Machines. Machines being deleted or in other failure domains are not counted.
+func failureDomainCounts(failureDomains []string, machineLists ...[]*clusterv1.Machine) map[string]int {
+	counts := make(map[string]int, len(failureDomains))
+	for _, id := range failureDomains {
+		counts[id] = 0
+	}
+	for _, machines := range machineLists {
+		for _, m := range machines {
+			if id, ok := countedFailureDomain(counts, m); ok {
+				counts[id]++
+			}
+		}
+	}
+	return counts
+}
+
+// countedFailureDomain returns the failure domain of the Machine and whether the Machine is counted in it.
+func countedFailureDomain(counts map[string]int, machine *clusterv1.Machine) (string, bool) {
+	if machine.Spec.FailureDomain == nil || !machine.DeletionTimestamp.IsZero() {
+		return "", false
+	}
+	id := *machine.Spec.FailureDomain
+	_, ok := counts[id]
+	return id, ok
+}
+
+// pickFewestFailureDomain returns the failure domain with the fewest Machines; ties are broken by picking
+// the first failure domain in the given list.
+func pickFewestFailureDomain(failureDomains []string, counts map[string]int) string {
+	fewest := failureDomains[0]
+	for _, id := range failureDomains[1:] {
+		if counts[id] < counts[fewest] {
+			fewest = id
+		}
+	}
+	return fewest
+}
+
+// isMachineToDeleteFirst returns true for the Machines every delete policy deletes first: Machines being deleted,
+// marked with the DeleteMachineAnnotation, without a Node or failed.
+func isMachineToDeleteFirst(machine *clusterv1.Machine) bool {
+	if !machine.DeletionTimestamp.IsZero() {
+		return true
+	}
+	if _, ok := machine.ObjectMeta.Annotations[clusterv1.DeleteMachineAnnotation]; ok {
+		return true
+	}
+	return machine.Status.NodeRef == nil || machine.Status.FailureReason != nil || machine.Status.FailureMessage != nil
+}
+
+// getMachinesToDeleteSpread returns the Machines to delete to keep the remaining ones spread across the given
+// failure domains. Machines that every delete policy deletes first are picked first, then Machines outside of the
+// failure domains, then Machines in the failure domain with the most Machines; the delete priority function is used
+// to pick among the candidates. The otherMachines, e.g. the Machines of the other MachineSets of the same
+// MachineDeployment, are counted in the failure domains but never deleted.
+func getMachinesToDeleteSpread(filteredMachines []*clusterv1.Machine, diff int, fun deletePriorityFunc, failureDomains []string, otherMachines []*clusterv1.Machine) []*clusterv1.Machine {
+	if diff >= len(filteredMachines) {
+		return filteredMachines
+	} else if diff <= 0 {
+		return []*clusterv1.Machine{}
+	}
+
+	// Sort a copy of the Machines by delete priority, so the first candidate found is the one to delete.
+	remaining := make([]*clusterv1.Machine, len(filteredMachines))
+	copy(remaining, filteredMachines)
+	priorities := make(map[*clusterv1.Machine]deletePriority, len(remaining))
+	for _, m := range remaining {
+		priorities[m] = fun(m)
+	}
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return priorities[remaining[i]] > priorities[remaining[j]]
+	})
+
+	counts := failureDomainCounts(failureDomains, remaining, otherMachines)
+	machinesToDelete := make([]*clusterv1.Machine, 0, diff)
+	for len(machinesToDelete) < diff {
+		index := pickMachineToDeleteSpread(remaining, counts)
+		machine := remaining[index]
+		if id, ok := countedFailureDomain(counts, machine); ok {
+			counts[id]--
+		}
+		machinesToDelete = append(machinesToDelete, machine)
+		remaining = append(remaining[:index], remaining[index+1:]...)
+	}
+	return machinesToDelete
+}
+
+// pickMachineToDeleteSpread returns the index of the next Machine to delete among the Machines sorted by delete priority.
+func pickMachineToDeleteSpread(machines []*clusterv1.Machine, counts map[string]int) int {
+	for i, m := range machines {
+		if isMachineToDeleteFirst(m) {
+			return i
+		}
+	}
+	for i, m := range machines {
+		if _, ok := countedFailureDomain(counts, m); !ok {
+			return i
+		}
+	}
+
+	// The most populated failure domain might have no Machines among the candidates, so the candidate
+	// in the failure domain with the most Machines is picked.
+	index := 0
+	for i, m := range machines {
+		if id, _ := countedFailureDomain(counts, m); counts[id] > counts[*machines[index].Spec.FailureDomain] {
+			index = i
+		}
+	}
+	return index
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEligibleFailureDomains(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		Status: clusterv1.ClusterStatus{
+			FailureDomains: clusterv1.FailureDomains{
+				"zone-c": clusterv1.FailureDomainSpec{},
+				"zone-a": clusterv1.FailureDomainSpec{ControlPlane: true},
+				"zone-b": clusterv1.FailureDomainSpec{},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		cluster      *clusterv1.Cluster
+		distribution *clusterv1.FailureDomainDistribution
+		expected     []string
+	}{
+		{
+			name:         "no failure domains if the failure domain distribution is not set",
+			cluster:      cluster,
+			distribution: nil,
+			expected:     nil,
+		},
+		{
+			name:         "all the failure domains of the Cluster, sorted",
+			cluster:      cluster,
+			distribution: &clusterv1.FailureDomainDistribution{Policy: clusterv1.SpreadFailureDomainDistributionPolicy},
+			expected:     []string{"zone-a", "zone-b", "zone-c"},
+		},
+		{
+			name:    "only the listed failure domains existing in the Cluster",
+			cluster: cluster,
+			distribution: &clusterv1.FailureDomainDistribution{
+				Policy:         clusterv1.SpreadFailureDomainDistributionPolicy,
+				FailureDomains: []string{"zone-c", "zone-b", "zone-d"},
+			},
+			expected: []string{"zone-b", "zone-c"},
+		},
+		{
+			name:    "no failure domains if none of the listed failure domains exist in the Cluster",
+			cluster: cluster,
+			distribution: &clusterv1.FailureDomainDistribution{
+				Policy:         clusterv1.SpreadFailureDomainDistributionPolicy,
+				FailureDomains: []string{"zone-d"},
+			},
+			expected: nil,
+		},
+		{
+			name:         "no failure domains if the Cluster does not have failure domains",
+			cluster:      &clusterv1.Cluster{},
+			distribution: &clusterv1.FailureDomainDistribution{Policy: clusterv1.SpreadFailureDomainDistributionPolicy},
+			expected:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := &clusterv1.MachineSet{
+				Spec: clusterv1.MachineSetSpec{FailureDomainDistribution: tt.distribution},
+			}
+			g.Expect(eligibleFailureDomains(tt.cluster, ms)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestPickFewestFailureDomain(t *testing.T) {
+	g := NewWithT(t)
+
+	failureDomains := []string{"zone-a", "zone-b", "zone-c"}
+	deleting := metav1.Now()
+	machines := []*clusterv1.Machine{
+		spreadMachine("a-1", "zone-a"),
+		spreadMachine("a-2", "zone-a"),
+		spreadMachine("b-1", "zone-b"),
+		spreadMachine("c-1", "zone-c"),
+		spreadMachine("d-1", "zone-d"),
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-failure-domain"}},
+	}
+	deletingMachine := spreadMachine("c-2", "zone-c")
+	deletingMachine.DeletionTimestamp = &deleting
+	machines = append(machines, deletingMachine)
+
+	counts := failureDomainCounts(failureDomains, machines)
+	g.Expect(counts).To(Equal(map[string]int{"zone-a": 2, "zone-b": 1, "zone-c": 1}))
+	g.Expect(pickFewestFailureDomain(failureDomains, counts)).To(Equal("zone-b"))
+
+	counts["zone-b"]++
+	g.Expect(pickFewestFailureDomain(failureDomains, counts)).To(Equal("zone-c"))
+
+	// Machines in all the given lists are counted.
+	counts = failureDomainCounts(failureDomains, machines, []*clusterv1.Machine{spreadMachine("b-2", "zone-b"), spreadMachine("b-3", "zone-b")})
+	g.Expect(counts).To(Equal(map[string]int{"zone-a": 2, "zone-b": 3, "zone-c": 1}))
+	g.Expect(pickFewestFailureDomain(failureDomains, counts)).To(Equal("zone-c"))
+}
+
+func TestGetMachinesToDeleteSpread(t *testing.T) {
+	failureDomains := []string{"zone-a", "zone-b", "zone-c"}
+
+	a1 := spreadMachine("a-1", "zone-a")
+	a2 := spreadMachine("a-2", "zone-a")
+	a3 := spreadMachine("a-3", "zone-a")
+	b4 := spreadMachine("b-4", "zone-b")
+	b5 := spreadMachine("b-5", "zone-b")
+	c6 := spreadMachine("c-6", "zone-c")
+	d7 := spreadMachine("d-7", "zone-d")
+	annotated := spreadMachine("c-8", "zone-c")
+	annotated.Annotations = map[string]string{clusterv1.DeleteMachineAnnotation: ""}
+
+	otherA := spreadMachine("a-9", "zone-a")
+	otherC := spreadMachine("c-9", "zone-c")
+
+	tests := []struct {
+		name          string
+		machines      []*clusterv1.Machine
+		otherMachines []*clusterv1.Machine
+		diff          int
+		expected      []*clusterv1.Machine
+	}{
+		{
+			name:     "no Machines if diff is 0",
+			machines: []*clusterv1.Machine{a1, b4},
+			diff:     0,
+			expected: []*clusterv1.Machine{},
+		},
+		{
+			name:     "all the Machines if diff is greater than the number of Machines",
+			machines: []*clusterv1.Machine{a1, b4},
+			diff:     3,
+			expected: []*clusterv1.Machine{a1, b4},
+		},
+		{
+			name:     "Machines in the failure domain with the most Machines, by delete priority",
+			machines: []*clusterv1.Machine{c6, b4, a1, b5, a2, a3},
+			diff:     3,
+			expected: []*clusterv1.Machine{a3, b5, a2},
+		},
+		{
+			name:     "Machines outside of the failure domains before the other Machines",
+			machines: []*clusterv1.Machine{a1, a2, b4, d7},
+			diff:     2,
+			expected: []*clusterv1.Machine{d7, a2},
+		},
+		{
+			name:     "Machines marked for deletion before the other Machines",
+			machines: []*clusterv1.Machine{a1, a2, d7, annotated},
+			diff:     2,
+			expected: []*clusterv1.Machine{annotated, d7},
+		},
+		{
+			name:          "Machines in the failure domain with the most Machines, counting the other Machines",
+			machines:      []*clusterv1.Machine{a1, b4, c6},
+			otherMachines: []*clusterv1.Machine{otherC, otherA, spreadMachine("c-8", "zone-c")},
+			diff:          2,
+			expected:      []*clusterv1.Machine{c6, a1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(getMachinesToDeleteSpread(tt.machines, tt.diff, newestDeletePriority, failureDomains, tt.otherMachines)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestGetOtherDeploymentMachines(t *testing.T) {
+	g := NewWithT(t)
+
+	newMachine := func(name, deploymentName, machineSetName string) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName:           "test-cluster",
+					clusterv1.MachineDeploymentLabelName: deploymentName,
+				},
+			},
+		}
+		if machineSetName != "" {
+			m.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "MachineSet",
+				Name:       machineSetName,
+				Controller: pointer.BoolPtr(true),
+			}}
+		}
+		return m
+	}
+
+	ms := &clusterv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "md-1-new",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "MachineDeployment",
+				Name:       "md-1",
+				Controller: pointer.BoolPtr(true),
+			}},
+		},
+		Spec: clusterv1.MachineSetSpec{ClusterName: "test-cluster"},
+	}
+
+	r := &MachineSetReconciler{
+		Client: fake.NewClientBuilder().WithObjects(
+			newMachine("new-1", "md-1", "md-1-new"),
+			newMachine("old-1", "md-1", "md-1-old"),
+			newMachine("old-2", "md-1", "md-1-old"),
+			newMachine("orphan", "md-1", ""),
+			newMachine("other-1", "md-2", "md-2-new"),
+		).Build(),
+	}
+
+	// Only the Machines of the other MachineSets of the same MachineDeployment are returned.
+	machines, err := r.getOtherDeploymentMachines(ctx, ms)
+	g.Expect(err).ToNot(HaveOccurred())
+	var names []string
+	for _, m := range machines {
+		names = append(names, m.Name)
+	}
+	g.Expect(names).To(ConsistOf("old-1", "old-2"))
+
+	// MachineSets not controlled by a MachineDeployment have no other Machines.
+	ms.OwnerReferences = nil
+	machines, err = r.getOtherDeploymentMachines(ctx, ms)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(machines).To(BeEmpty())
+}
+
+// spreadMachine returns a healthy Machine in the given failure domain; Machines are created one hour apart
+// in the order of the last digit of their name.
+func spreadMachine(name, failureDomain string) *clusterv1.Machine {
+	created := time.Now().Add(-time.Duration(10-(name[len(name)-1]-'0')) * time.Hour)
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: clusterv1.MachineSpec{
+			FailureDomain: pointer.StringPtr(failureDomain),
+		},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Name: name},
+		},
+	}
+}
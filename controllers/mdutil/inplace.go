@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mdutil
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+// Fields of the machine template that can be propagated in place to the existing MachineSets and Machines;
+// changes to any other field of the machine template require the Machines to be replaced.
+const (
+	InPlaceLabelsField           = "metadata.labels"
+	InPlaceAnnotationsField      = "metadata.annotations"
+	InPlaceNodeDrainTimeoutField = "spec.nodeDrainTimeout"
+	InPlaceTaintsField           = "spec.taints"
+)
+
+// IsInPlaceCompatible returns true if the machine template of the MachineSet differs from the machine template
+// of the deployment only in fields that can be propagated in place.
+// Labels used in the MachineSet selector can't be propagated in place, because changing them would orphan the Machines.
+func IsInPlaceCompatible(deployment *clusterv1.MachineDeployment, ms *clusterv1.MachineSet) bool {
+	for k, v := range ms.Spec.Selector.MatchLabels {
+		if k == DefaultMachineDeploymentUniqueLabelKey {
+			continue
+		}
+		if deploymentValue, ok := deployment.Spec.Template.Labels[k]; !ok || deploymentValue != v {
+			return false
+		}
+	}
+
+	t1Copy := ms.Spec.Template.DeepCopy()
+	t2Copy := deployment.Spec.Template.DeepCopy()
+	clearInPlaceFields(t1Copy)
+	clearInPlaceFields(t2Copy)
+	return EqualMachineTemplate(t1Copy, t2Copy)
+}
+
+func clearInPlaceFields(template *clusterv1.MachineTemplateSpec) {
+	template.Labels = nil
+	template.Annotations = nil
+	template.Spec.NodeDrainTimeout = nil
+	template.Spec.Taints = nil
+}
+
+// InPlaceChangedFields returns the fields that can be propagated in place which differ between two machine templates.
+func InPlaceChangedFields(from, to *clusterv1.MachineTemplateSpec) []string {
+	var fields []string
+	if !apiequality.Semantic.DeepEqual(withoutHashLabel(from.Labels), withoutHashLabel(to.Labels)) {
+		fields = append(fields, InPlaceLabelsField)
+	}
+	if !apiequality.Semantic.DeepEqual(from.Annotations, to.Annotations) {
+		fields = append(fields, InPlaceAnnotationsField)
+	}
+	if !apiequality.Semantic.DeepEqual(from.Spec.NodeDrainTimeout, to.Spec.NodeDrainTimeout) {
+		fields = append(fields, InPlaceNodeDrainTimeoutField)
+	}
+	if !apiequality.Semantic.DeepEqual(from.Spec.Taints, to.Spec.Taints) {
+		fields = append(fields, InPlaceTaintsField)
+	}
+	return fields
+}
+
+// UpdateMachineTemplateInPlace sets the fields that can be propagated in place from the source machine template
+// into the target machine template, preserving the machine-template-hash label of the target.
+func UpdateMachineTemplateInPlace(target, source *clusterv1.MachineTemplateSpec) {
+	labels := withoutHashLabel(source.Labels)
+	if hash, ok := target.Labels[DefaultMachineDeploymentUniqueLabelKey]; ok {
+		labels = CloneAndAddLabel(labels, DefaultMachineDeploymentUniqueLabelKey, hash)
+	}
+	target.Labels = labels
+	target.Annotations = cloneStringMap(source.Annotations)
+	target.Spec.NodeDrainTimeout = source.Spec.NodeDrainTimeout.DeepCopy()
+	target.Spec.Taints = cloneTaints(source.Spec.Taints)
+}
+
+// InPlaceTemplate returns a machine template with only the fields which can be propagated in place
+// from the given machine template, without the machine-template-hash label.
+func InPlaceTemplate(template *clusterv1.MachineTemplateSpec) *clusterv1.MachineTemplateSpec {
+	ret := &clusterv1.MachineTemplateSpec{}
+	UpdateMachineTemplateInPlace(ret, template)
+	return ret
+}
+
+// SetInPlaceTemplateAnnotation sets on the MachineSet the InPlaceTemplateAnnotation tracking the fields of the given
+// machine template which can be propagated in place.
+func SetInPlaceTemplateAnnotation(ms *clusterv1.MachineSet, template *clusterv1.MachineTemplateSpec) error {
+	value, err := json.Marshal(InPlaceTemplate(template))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal machine template")
+	}
+	if ms.Annotations == nil {
+		ms.Annotations = map[string]string{}
+	}
+	ms.Annotations[clusterv1.InPlaceTemplateAnnotation] = string(value)
+	return nil
+}
+
+// UpdateMachineInPlace applies to the Machine the changes to the fields that can be propagated in place between
+// two machine templates; values not changed between the two machine templates are left untouched, so changes
+// applied directly to the Machine are preserved. It returns true if the Machine has been changed.
+func UpdateMachineInPlace(machine *clusterv1.Machine, from, to *clusterv1.MachineTemplateSpec) bool {
+	original := machine.DeepCopy()
+
+	machine.Labels = updateStringMap(machine.Labels, withoutHashLabel(from.Labels), withoutHashLabel(to.Labels))
+	machine.Annotations = updateStringMap(machine.Annotations, from.Annotations, to.Annotations)
+	if !apiequality.Semantic.DeepEqual(from.Spec.NodeDrainTimeout, to.Spec.NodeDrainTimeout) {
+		machine.Spec.NodeDrainTimeout = to.Spec.NodeDrainTimeout.DeepCopy()
+	}
+	if !apiequality.Semantic.DeepEqual(from.Spec.Taints, to.Spec.Taints) {
+		machine.Spec.Taints = cloneTaints(to.Spec.Taints)
+	}
+
+	return !apiequality.Semantic.DeepEqual(original.Labels, machine.Labels) ||
+		!apiequality.Semantic.DeepEqual(original.Annotations, machine.Annotations) ||
+		!apiequality.Semantic.DeepEqual(original.Spec, machine.Spec)
+}
+
+// updateStringMap deletes from m the keys defined in from but not in to, and sets the entries changed or added in to.
+func updateStringMap(m, from, to map[string]string) map[string]string {
+	for k := range from {
+		if _, ok := to[k]; !ok {
+			delete(m, k)
+		}
+	}
+	for k, v := range to {
+		if fromValue, ok := from[k]; ok && fromValue == v {
+			continue
+		}
+		if m == nil {
+			m = map[string]string{}
+		}
+		m[k] = v
+	}
+	return m
+}
+
+func withoutHashLabel(labels map[string]string) map[string]string {
+	if _, ok := labels[DefaultMachineDeploymentUniqueLabelKey]; !ok {
+		return labels
+	}
+	ret := cloneStringMap(labels)
+	delete(ret, DefaultMachineDeploymentUniqueLabelKey)
+	return ret
+}
+
+func cloneStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	ret := make(map[string]string, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
+
+func cloneTaints(taints []corev1.Taint) []corev1.Taint {
+	if taints == nil {
+		return nil
+	}
+	ret := make([]corev1.Taint, len(taints))
+	for i := range taints {
+		taints[i].DeepCopyInto(&ret[i])
+	}
+	return ret
+}
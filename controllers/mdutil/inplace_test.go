@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mdutil
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+func TestIsInPlaceCompatible(t *testing.T) {
+	deployment := generateDeployment("nginx")
+	deployment.Spec.Template.Spec.Version = pointer.StringPtr("v1.19.1")
+
+	tests := []struct {
+		name     string
+		modifyMS func(ms *clusterv1.MachineSet)
+		expected bool
+	}{
+		{
+			name:     "same machine template",
+			modifyMS: func(ms *clusterv1.MachineSet) {},
+			expected: true,
+		},
+		{
+			name: "different labels, annotations, node drain timeout and taints",
+			modifyMS: func(ms *clusterv1.MachineSet) {
+				ms.Spec.Template.Labels = map[string]string{"name": "nginx", "team": "x"}
+				ms.Spec.Template.Annotations = map[string]string{"description": "old"}
+				ms.Spec.Template.Spec.NodeDrainTimeout = &metav1.Duration{Duration: time.Minute}
+				ms.Spec.Template.Spec.Taints = []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule}}
+			},
+			expected: true,
+		},
+		{
+			name: "different version",
+			modifyMS: func(ms *clusterv1.MachineSet) {
+				ms.Spec.Template.Spec.Version = pointer.StringPtr("v1.19.2")
+			},
+			expected: false,
+		},
+		{
+			name: "different value of a label used in the MachineSet selector",
+			modifyMS: func(ms *clusterv1.MachineSet) {
+				ms.Spec.Template.Labels["name"] = "apache"
+				ms.Spec.Selector.MatchLabels["name"] = "apache"
+			},
+			expected: false,
+		},
+		{
+			name: "different machine-template-hash label",
+			modifyMS: func(ms *clusterv1.MachineSet) {
+				ms.Spec.Template.Labels[DefaultMachineDeploymentUniqueLabelKey] = "hash"
+				ms.Spec.Selector.MatchLabels[DefaultMachineDeploymentUniqueLabelKey] = "hash"
+			},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := generateMS(*deployment.DeepCopy())
+			tt.modifyMS(&ms)
+			g.Expect(IsInPlaceCompatible(&deployment, &ms)).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestFindNewMachineSetInPlace(t *testing.T) {
+	g := NewWithT(t)
+
+	now := metav1.Now()
+	later := metav1.Time{Time: now.Add(time.Minute)}
+
+	deployment := generateDeployment("nginx")
+
+	inPlaceMS := generateMS(deployment)
+	inPlaceMS.Spec.Template.Annotations = map[string]string{"description": "old"}
+	inPlaceMS.CreationTimestamp = now
+
+	oldMS := generateMS(deployment)
+	oldMS.Spec.Template.Spec.Version = pointer.StringPtr("v1.19.1")
+	oldMS.CreationTimestamp = now
+
+	// A MachineSet differing only in fields which can be propagated in place is the new MachineSet.
+	g.Expect(FindNewMachineSet(&deployment, []*clusterv1.MachineSet{&oldMS, &inPlaceMS})).To(Equal(&inPlaceMS))
+
+	// A MachineSet with the same machine template has the precedence.
+	newMS := generateMS(deployment)
+	newMS.CreationTimestamp = later
+	g.Expect(FindNewMachineSet(&deployment, []*clusterv1.MachineSet{&oldMS, &inPlaceMS, &newMS})).To(Equal(&newMS))
+}
+
+func TestFindNewMachineSetInPlaceNewest(t *testing.T) {
+	g := NewWithT(t)
+
+	now := metav1.Now()
+	later := metav1.Time{Time: now.Add(time.Minute)}
+	latest := metav1.Time{Time: now.Add(2 * time.Minute)}
+
+	deployment := generateDeployment("nginx")
+
+	olderMS := generateMS(deployment)
+	olderMS.Name = "older"
+	olderMS.Spec.Template.Annotations = map[string]string{"description": "older"}
+	olderMS.Spec.Replicas = pointer.Int32Ptr(1)
+	olderMS.CreationTimestamp = now
+
+	newerMS := generateMS(deployment)
+	newerMS.Name = "newer"
+	newerMS.Spec.Template.Annotations = map[string]string{"description": "newer"}
+	newerMS.Spec.Replicas = pointer.Int32Ptr(3)
+	newerMS.CreationTimestamp = later
+
+	// The newest MachineSet with replicas is chosen among the ones differing only in fields which can be propagated in place.
+	g.Expect(FindNewMachineSet(&deployment, []*clusterv1.MachineSet{&olderMS, &newerMS})).To(Equal(&newerMS))
+
+	// MachineSets scaled down to zero are chosen only if no other MachineSet has replicas.
+	scaledDownMS := generateMS(deployment)
+	scaledDownMS.Name = "scaled-down"
+	scaledDownMS.Spec.Template.Annotations = map[string]string{"description": "scaled-down"}
+	scaledDownMS.Spec.Replicas = pointer.Int32Ptr(0)
+	scaledDownMS.CreationTimestamp = latest
+	g.Expect(FindNewMachineSet(&deployment, []*clusterv1.MachineSet{&scaledDownMS, &olderMS, &newerMS})).To(Equal(&newerMS))
+
+	newerMS.Spec.Replicas = pointer.Int32Ptr(0)
+	olderMS.Spec.Replicas = pointer.Int32Ptr(0)
+	g.Expect(FindNewMachineSet(&deployment, []*clusterv1.MachineSet{&scaledDownMS, &olderMS, &newerMS})).To(Equal(&scaledDownMS))
+}
+
+func TestInPlaceChangedFields(t *testing.T) {
+	g := NewWithT(t)
+
+	from := &clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels: map[string]string{"pool": "a", DefaultMachineDeploymentUniqueLabelKey: "hash"},
+		},
+		Spec: clusterv1.MachineSpec{Taints: []corev1.Taint{}},
+	}
+	to := &clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels: map[string]string{"pool": "a"},
+		},
+	}
+
+	// The machine-template-hash label and nil vs empty values are ignored.
+	g.Expect(InPlaceChangedFields(from, to)).To(BeEmpty())
+
+	to.Annotations = map[string]string{"description": "new"}
+	to.Spec.NodeDrainTimeout = &metav1.Duration{Duration: time.Minute}
+	g.Expect(InPlaceChangedFields(from, to)).To(Equal([]string{InPlaceAnnotationsField, InPlaceNodeDrainTimeoutField}))
+
+	to.Labels["pool"] = "b"
+	to.Spec.Taints = []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule}}
+	g.Expect(InPlaceChangedFields(from, to)).To(Equal([]string{InPlaceLabelsField, InPlaceAnnotationsField, InPlaceNodeDrainTimeoutField, InPlaceTaintsField}))
+}
+
+func TestUpdateMachineTemplateInPlace(t *testing.T) {
+	g := NewWithT(t)
+
+	target := &clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels: map[string]string{"pool": "a", "team": "x", DefaultMachineDeploymentUniqueLabelKey: "hash"},
+		},
+		Spec: clusterv1.MachineSpec{Version: pointer.StringPtr("v1.19.1")},
+	}
+	source := &clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels:      map[string]string{"pool": "a"},
+			Annotations: map[string]string{"description": "new"},
+		},
+		Spec: clusterv1.MachineSpec{
+			Version:          pointer.StringPtr("v1.19.2"),
+			NodeDrainTimeout: &metav1.Duration{Duration: time.Minute},
+			Taints:           []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+
+	UpdateMachineTemplateInPlace(target, source)
+	g.Expect(target.Labels).To(Equal(map[string]string{"pool": "a", DefaultMachineDeploymentUniqueLabelKey: "hash"}))
+	g.Expect(target.Annotations).To(Equal(source.Annotations))
+	g.Expect(target.Spec.NodeDrainTimeout).To(Equal(source.Spec.NodeDrainTimeout))
+	g.Expect(target.Spec.Taints).To(Equal(source.Spec.Taints))
+	// Fields requiring the replacement of Machines are not changed.
+	g.Expect(target.Spec.Version).To(Equal(pointer.StringPtr("v1.19.1")))
+}
+
+func TestUpdateMachineInPlace(t *testing.T) {
+	g := NewWithT(t)
+
+	from := &clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels:      map[string]string{"pool": "a", "team": "x"},
+			Annotations: map[string]string{"description": "old"},
+		},
+	}
+	to := &clusterv1.MachineTemplateSpec{
+		ObjectMeta: clusterv1.ObjectMeta{
+			Labels:      map[string]string{"pool": "a", "role": "worker"},
+			Annotations: map[string]string{"description": "old"},
+		},
+		Spec: clusterv1.MachineSpec{
+			Taints: []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			// The pool label and the description annotation have been changed directly on the Machine.
+			Labels:      map[string]string{"pool": "b", "team": "x", "added-to-machine": ""},
+			Annotations: map[string]string{"description": "changed"},
+		},
+		Spec: clusterv1.MachineSpec{
+			NodeDrainTimeout: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+
+	g.Expect(UpdateMachineInPlace(machine, from, to)).To(BeTrue())
+	g.Expect(machine.Labels).To(Equal(map[string]string{"pool": "b", "role": "worker", "added-to-machine": ""}))
+	g.Expect(machine.Annotations).To(Equal(map[string]string{"description": "changed"}))
+	g.Expect(machine.Spec.NodeDrainTimeout).To(Equal(&metav1.Duration{Duration: time.Minute}))
+	g.Expect(machine.Spec.Taints).To(Equal(to.Spec.Taints))
+
+	// Applying the same changes again is a no-op.
+	g.Expect(UpdateMachineInPlace(machine, from, to)).To(BeFalse())
+}
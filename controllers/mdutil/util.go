@@ -23,6 +23,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-logr/logr"
@@ -146,6 +147,16 @@ var annotationsToSkip = map[string]bool{
 	clusterv1.DesiredReplicasAnnotation: true,
 	clusterv1.MaxReplicasAnnotation:     true,
 
+	// Exclude the capacity annotations, which are computed from the machine template of each MachineSet.
+	clusterv1.CPUCapacityAnnotation:           true,
+	clusterv1.MemoryCapacityAnnotation:        true,
+	clusterv1.EphemeralDiskCapacityAnnotation: true,
+	clusterv1.GPUTypeCapacityAnnotation:       true,
+	clusterv1.GPUCountCapacityAnnotation:      true,
+	clusterv1.MaxPodsCapacityAnnotation:       true,
+	clusterv1.LabelsCapacityAnnotation:        true,
+	clusterv1.TaintsCapacityAnnotation:        true,
+
 	// Exclude the conversion annotation, to avoid infinite loops between the conversion webhook
 	// and the MachineDeployment controller syncing the annotations between a MachineDeployment
 	// and its linked MachineSets.
@@ -414,6 +425,8 @@ func EqualMachineTemplate(template1, template2 *clusterv1.MachineTemplateSpec) b
 }
 
 // FindNewMachineSet returns the new MS this given deployment targets (the one with the same machine template).
+// If there is no MS with the same machine template, the MS with a machine template differing only in fields
+// which can be propagated in place is returned, if any.
 func FindNewMachineSet(deployment *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) *clusterv1.MachineSet {
 	sort.Sort(MachineSetsByCreationTimestamp(msList))
 	for i := range msList {
@@ -425,6 +438,24 @@ func FindNewMachineSet(deployment *clusterv1.MachineDeployment, msList []*cluste
 			return msList[i]
 		}
 	}
+	// The changes to the machine template can be propagated in place to a MachineSet differing only in fields
+	// which can be propagated in place, so there is no need for a new MachineSet. The newest MachineSet with
+	// replicas is chosen, falling back to the newest MachineSet, because it is the one rolled out last.
+	var inPlaceMS *clusterv1.MachineSet
+	for i := len(msList) - 1; i >= 0; i-- {
+		if !IsInPlaceCompatible(deployment, msList[i]) {
+			continue
+		}
+		if msList[i].Spec.Replicas != nil && *msList[i].Spec.Replicas > 0 {
+			return msList[i]
+		}
+		if inPlaceMS == nil {
+			inPlaceMS = msList[i]
+		}
+	}
+	if inPlaceMS != nil {
+		return inPlaceMS
+	}
 	// new MachineSet does not exist.
 	return nil
 }
@@ -510,6 +541,22 @@ func GetAvailableReplicaCountForMachineSets(machineSets []*clusterv1.MachineSet)
 	return totalAvailableReplicas
 }
 
+// GetDeletingMachineCount gets the number of machines that are in the process of being deleted
+// in a machineList, or that are marked for deletion with the DeleteMachineAnnotation.
+func GetDeletingMachineCount(machineList *clusterv1.MachineList) int32 {
+	var deletingMachineCount int32
+	for _, machine := range machineList.Items {
+		if !machine.GetDeletionTimestamp().IsZero() {
+			deletingMachineCount++
+			continue
+		}
+		if _, ok := machine.Annotations[clusterv1.DeleteMachineAnnotation]; ok {
+			deletingMachineCount++
+		}
+	}
+	return deletingMachineCount
+}
+
 // IsRollingUpdate returns true if the strategy type is a rolling update.
 func IsRollingUpdate(deployment *clusterv1.MachineDeployment) bool {
 	return deployment.Spec.Strategy.Type == clusterv1.RollingUpdateMachineDeploymentStrategyType
@@ -524,6 +571,41 @@ func DeploymentComplete(deployment *clusterv1.MachineDeployment, newStatus *clus
 		newStatus.ObservedGeneration >= deployment.Generation
 }
 
+// DeploymentProgressing reports progress for a deployment. Progress is estimated by comparing the
+// current with the new status of the deployment that the controller is observing. More specifically,
+// when new machines are scaled up or become ready or available, or old machines are scaled down,
+// then we consider the deployment is progressing.
+func DeploymentProgressing(deployment *clusterv1.MachineDeployment, newStatus *clusterv1.MachineDeploymentStatus) bool {
+	oldStatus := deployment.Status
+
+	// Old replicas that need to be scaled down
+	oldStatusOldReplicas := oldStatus.Replicas - oldStatus.UpdatedReplicas
+	newStatusOldReplicas := newStatus.Replicas - newStatus.UpdatedReplicas
+
+	return (newStatus.UpdatedReplicas > oldStatus.UpdatedReplicas) ||
+		(newStatusOldReplicas < oldStatusOldReplicas) ||
+		newStatus.ReadyReplicas > oldStatus.ReadyReplicas ||
+		newStatus.AvailableReplicas > oldStatus.AvailableReplicas
+}
+
+// DeploymentTimeUntilProgressDeadline returns the time left before a deployment exceeds its progress deadline,
+// measured from the last time the deployment made progress; it returns false if the deployment has no progress
+// deadline or its progress is not being tracked, e.g. because the deployment is complete or paused.
+func DeploymentTimeUntilProgressDeadline(deployment *clusterv1.MachineDeployment, newStatus *clusterv1.MachineDeploymentStatus, now time.Time) (time.Duration, bool) {
+	if deployment.Spec.ProgressDeadlineSeconds == nil || newStatus.LastProgressTime == nil {
+		return 0, false
+	}
+	deadline := newStatus.LastProgressTime.Add(time.Duration(*deployment.Spec.ProgressDeadlineSeconds) * time.Second)
+	return deadline.Sub(now), true
+}
+
+// DeploymentTimedOut considers a deployment to have timed out once it did not make progress
+// for longer than its progress deadline.
+func DeploymentTimedOut(deployment *clusterv1.MachineDeployment, newStatus *clusterv1.MachineDeploymentStatus, now time.Time) bool {
+	timeLeft, ok := DeploymentTimeUntilProgressDeadline(deployment, newStatus, now)
+	return ok && timeLeft <= 0
+}
+
 // NewMSNewReplicas calculates the number of replicas a deployment's new MS should have.
 // When one of the following is true, we're rolling out the deployment; otherwise, we're scaling it.
 // 1) The new MS is saturated: newMS's replicas == deployment's replicas
@@ -548,6 +630,18 @@ func NewMSNewReplicas(deployment *clusterv1.MachineDeployment, allMSs []*cluster
 		// Do not exceed the number of desired replicas.
 		scaleUpCount = integer.Int32Min(scaleUpCount, *(deployment.Spec.Replicas)-*(newMS.Spec.Replicas))
 		return *(newMS.Spec.Replicas) + scaleUpCount, nil
+	case clusterv1.OnDeleteMachineDeploymentStrategyType:
+		// Find the total number of machines
+		currentMachineCount := TotalMachineSetsReplicaSum(allMSs)
+		if currentMachineCount >= *(deployment.Spec.Replicas) {
+			// Cannot scale up as more replicas exist than the max possible number
+			return *(newMS.Spec.Replicas), nil
+		}
+		// The new replica count is the difference between desired and existing machines.
+		scaleUpCount := *(deployment.Spec.Replicas) - currentMachineCount
+		// Do not exceed the number of desired replicas.
+		scaleUpCount = integer.Int32Min(scaleUpCount, *(deployment.Spec.Replicas)-*(newMS.Spec.Replicas))
+		return *(newMS.Spec.Replicas) + scaleUpCount, nil
 	default:
 		return 0, fmt.Errorf("deployment strategy %v isn't supported", deployment.Spec.Strategy.Type)
 	}
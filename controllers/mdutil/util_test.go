@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/klog/klogr"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
@@ -528,6 +529,16 @@ func TestNewMSNewReplicas(t *testing.T) {
 			clusterv1.RollingUpdateMachineDeploymentStrategyType,
 			6, 2, 10, 6,
 		},
+		{
+			"on delete - can not scale up while old machines exist",
+			clusterv1.OnDeleteMachineDeploymentStrategyType,
+			5, 0, 0, 0,
+		},
+		{
+			"on delete - scale up to replace deleted machines",
+			clusterv1.OnDeleteMachineDeploymentStrategyType,
+			8, 1, 0, 4,
+		},
 	}
 	newDeployment := generateDeployment("nginx")
 	newRC := generateMS(newDeployment)
@@ -635,6 +646,141 @@ func TestDeploymentComplete(t *testing.T) {
 	}
 }
 
+func TestDeploymentProgressing(t *testing.T) {
+	deployment := func(current, updated, ready, available int32) *clusterv1.MachineDeployment {
+		return &clusterv1.MachineDeployment{
+			Status: clusterv1.MachineDeploymentStatus{
+				Replicas:          current,
+				UpdatedReplicas:   updated,
+				ReadyReplicas:     ready,
+				AvailableReplicas: available,
+			},
+		}
+	}
+	newStatus := func(current, updated, ready, available int32) clusterv1.MachineDeploymentStatus {
+		return deployment(current, updated, ready, available).Status
+	}
+
+	tests := []struct {
+		name string
+
+		d         *clusterv1.MachineDeployment
+		newStatus clusterv1.MachineDeploymentStatus
+
+		expected bool
+	}{
+		{
+			name: "progressing: updated machines",
+
+			d:         deployment(2, 1, 2, 2),
+			newStatus: newStatus(3, 2, 2, 2),
+			expected:  true,
+		},
+		{
+			name: "progressing: old machines removed",
+
+			d:         deployment(3, 2, 3, 3),
+			newStatus: newStatus(2, 2, 2, 2),
+			expected:  true,
+		},
+		{
+			name: "progressing: ready machines",
+
+			d:         deployment(3, 2, 2, 2),
+			newStatus: newStatus(3, 2, 3, 2),
+			expected:  true,
+		},
+		{
+			name: "progressing: available machines",
+
+			d:         deployment(3, 2, 3, 2),
+			newStatus: newStatus(3, 2, 3, 3),
+			expected:  true,
+		},
+		{
+			name: "not progressing",
+
+			d:         deployment(3, 2, 2, 2),
+			newStatus: newStatus(3, 2, 2, 2),
+			expected:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(DeploymentProgressing(test.d, &test.newStatus)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestDeploymentTimedOut(t *testing.T) {
+	now := time.Now()
+	deployment := func(progressDeadlineSeconds *int32, lastProgressTime *metav1.Time) *clusterv1.MachineDeployment {
+		return &clusterv1.MachineDeployment{
+			Spec: clusterv1.MachineDeploymentSpec{
+				ProgressDeadlineSeconds: progressDeadlineSeconds,
+			},
+			Status: clusterv1.MachineDeploymentStatus{
+				LastProgressTime: lastProgressTime,
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+
+		d *clusterv1.MachineDeployment
+
+		expectedTracked  bool
+		expectedTimeLeft time.Duration
+		expectedTimedOut bool
+	}{
+		{
+			name: "no progress deadline",
+
+			d:                deployment(nil, &metav1.Time{Time: now.Add(-time.Hour)}),
+			expectedTracked:  false,
+			expectedTimedOut: false,
+		},
+		{
+			name: "progress not tracked",
+
+			d:                deployment(pointer.Int32Ptr(600), nil),
+			expectedTracked:  false,
+			expectedTimedOut: false,
+		},
+		{
+			name: "within progress deadline",
+
+			d:                deployment(pointer.Int32Ptr(600), &metav1.Time{Time: now.Add(-time.Minute)}),
+			expectedTracked:  true,
+			expectedTimeLeft: 9 * time.Minute,
+			expectedTimedOut: false,
+		},
+		{
+			name: "progress deadline exceeded",
+
+			d:                deployment(pointer.Int32Ptr(600), &metav1.Time{Time: now.Add(-11 * time.Minute)}),
+			expectedTracked:  true,
+			expectedTimeLeft: -time.Minute,
+			expectedTimedOut: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			timeLeft, tracked := DeploymentTimeUntilProgressDeadline(test.d, &test.d.Status, now)
+			g.Expect(tracked).To(Equal(test.expectedTracked))
+			g.Expect(timeLeft).To(Equal(test.expectedTimeLeft))
+			g.Expect(DeploymentTimedOut(test.d, &test.d.Status, now)).To(Equal(test.expectedTimedOut))
+		})
+	}
+}
+
 func TestMaxUnavailable(t *testing.T) {
 	deployment := func(replicas int32, maxUnavailable intstr.IntOrString) clusterv1.MachineDeployment {
 		return clusterv1.MachineDeployment{
@@ -817,3 +963,18 @@ func TestReplicasAnnotationsNeedUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDeletingMachineCount(t *testing.T) {
+	g := NewWithT(t)
+
+	now := metav1.Now()
+	machineList := &clusterv1.MachineList{
+		Items: []clusterv1.Machine{
+			{ObjectMeta: metav1.ObjectMeta{Name: "running"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "deleting", DeletionTimestamp: &now}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "marked", Annotations: map[string]string{clusterv1.DeleteMachineAnnotation: ""}}},
+		},
+	}
+
+	g.Expect(GetDeletingMachineCount(machineList)).To(Equal(int32(2)))
+}
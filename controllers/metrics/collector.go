@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/feature"
+	utilmetrics "sigs.k8s.io/cluster-api/util/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	clusterPhases = []string{
+		string(clusterv1.ClusterPhasePending),
+		string(clusterv1.ClusterPhaseProvisioning),
+		string(clusterv1.ClusterPhaseProvisioned),
+		string(clusterv1.ClusterPhaseDeleting),
+		string(clusterv1.ClusterPhaseFailed),
+		string(clusterv1.ClusterPhaseUnknown),
+	}
+
+	machinePhases = []string{
+		string(clusterv1.MachinePhasePending),
+		string(clusterv1.MachinePhaseProvisioning),
+		string(clusterv1.MachinePhaseProvisioned),
+		string(clusterv1.MachinePhaseRunning),
+		string(clusterv1.MachinePhaseDeleting),
+		string(clusterv1.MachinePhaseDeleted),
+		string(clusterv1.MachinePhaseFailed),
+		string(clusterv1.MachinePhaseUnknown),
+	}
+
+	machineDeploymentPhases = []string{
+		string(clusterv1.MachineDeploymentPhaseScalingUp),
+		string(clusterv1.MachineDeploymentPhaseScalingDown),
+		string(clusterv1.MachineDeploymentPhaseRunning),
+		string(clusterv1.MachineDeploymentPhaseFailed),
+		string(clusterv1.MachineDeploymentPhaseUnknown),
+	}
+
+	machinePoolPhases = []string{
+		string(expv1.MachinePoolPhasePending),
+		string(expv1.MachinePoolPhaseProvisioning),
+		string(expv1.MachinePoolPhaseProvisioned),
+		string(expv1.MachinePoolPhaseRunning),
+		string(expv1.MachinePoolPhaseScalingUp),
+		string(expv1.MachinePoolPhaseScalingDown),
+		string(expv1.MachinePoolPhaseDeleting),
+		string(expv1.MachinePoolPhaseFailed),
+		string(expv1.MachinePoolPhaseUnknown),
+	}
+)
+
+// StateCollector collects the phase and the conditions of Clusters, Machines, MachineDeployments
+// and MachinePools. Objects are read on each scrape from the given reader, which is
+// expected to be backed by the manager cache.
+type StateCollector struct {
+	reader client.Reader
+
+	clusterPhase               *prometheus.Desc
+	clusterCondition           *prometheus.Desc
+	machinePhase               *prometheus.Desc
+	machineCondition           *prometheus.Desc
+	machineDeploymentPhase     *prometheus.Desc
+	machineDeploymentCondition *prometheus.Desc
+	machinePoolPhase           *prometheus.Desc
+	machinePoolCondition       *prometheus.Desc
+}
+
+// NewStateCollector returns a StateCollector reading objects from the given reader.
+func NewStateCollector(reader client.Reader) *StateCollector {
+	return &StateCollector{
+		reader:                     reader,
+		clusterPhase:               utilmetrics.NewPhaseDesc("cluster"),
+		clusterCondition:           utilmetrics.NewConditionDesc("cluster"),
+		machinePhase:               utilmetrics.NewPhaseDesc("machine", "cluster"),
+		machineCondition:           utilmetrics.NewConditionDesc("machine", "cluster"),
+		machineDeploymentPhase:     utilmetrics.NewPhaseDesc("machinedeployment", "cluster"),
+		machineDeploymentCondition: utilmetrics.NewConditionDesc("machinedeployment", "cluster"),
+		machinePoolPhase:           utilmetrics.NewPhaseDesc("machinepool", "cluster"),
+		machinePoolCondition:       utilmetrics.NewConditionDesc("machinepool", "cluster"),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *StateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.clusterPhase
+	ch <- c.clusterCondition
+	ch <- c.machinePhase
+	ch <- c.machineCondition
+	ch <- c.machineDeploymentPhase
+	ch <- c.machineDeploymentCondition
+	if feature.Gates.Enabled(feature.MachinePool) {
+		ch <- c.machinePoolPhase
+		ch <- c.machinePoolCondition
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *StateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := utilmetrics.CollectContext()
+	defer cancel()
+
+	clusters := &clusterv1.ClusterList{}
+	if err := c.reader.List(ctx, clusters); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.clusterPhase, err)
+	} else {
+		for i := range clusters.Items {
+			cluster := &clusters.Items[i]
+			utilmetrics.CollectPhase(ch, c.clusterPhase, cluster.Status.Phase, clusterPhases, cluster.Namespace, cluster.Name)
+			utilmetrics.CollectConditions(ch, c.clusterCondition, cluster, cluster.Namespace, cluster.Name)
+		}
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := c.reader.List(ctx, machines); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.machinePhase, err)
+	} else {
+		for i := range machines.Items {
+			machine := &machines.Items[i]
+			utilmetrics.CollectPhase(ch, c.machinePhase, machine.Status.Phase, machinePhases, machine.Namespace, machine.Name, machine.Spec.ClusterName)
+			utilmetrics.CollectConditions(ch, c.machineCondition, machine, machine.Namespace, machine.Name, machine.Spec.ClusterName)
+		}
+	}
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := c.reader.List(ctx, machineDeployments); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.machineDeploymentPhase, err)
+	} else {
+		for i := range machineDeployments.Items {
+			md := &machineDeployments.Items[i]
+			utilmetrics.CollectPhase(ch, c.machineDeploymentPhase, md.Status.Phase, machineDeploymentPhases, md.Namespace, md.Name, md.Spec.ClusterName)
+			utilmetrics.CollectConditions(ch, c.machineDeploymentCondition, md, md.Namespace, md.Name, md.Spec.ClusterName)
+		}
+	}
+
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return
+	}
+	machinePools := &expv1.MachinePoolList{}
+	if err := c.reader.List(ctx, machinePools); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.machinePoolPhase, err)
+	} else {
+		for i := range machinePools.Items {
+			mp := &machinePools.Items[i]
+			utilmetrics.CollectPhase(ch, c.machinePoolPhase, mp.Status.Phase, machinePoolPhases, mp.Namespace, mp.Name, mp.Spec.ClusterName)
+			utilmetrics.CollectConditions(ch, c.machinePoolCondition, mp, mp.Namespace, mp.Name, mp.Spec.ClusterName)
+		}
+	}
+}
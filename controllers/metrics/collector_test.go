@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStateCollector(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Status: clusterv1.ClusterStatus{
+			Phase: string(clusterv1.ClusterPhaseProvisioned),
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-machine"},
+		Spec:       clusterv1.MachineSpec{ClusterName: "test"},
+		Status: clusterv1.MachineStatus{
+			Phase: string(clusterv1.MachinePhaseProvisioning),
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.ReadyCondition, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-md"},
+		Spec:       clusterv1.MachineDeploymentSpec{ClusterName: "test"},
+		Status: clusterv1.MachineDeploymentStatus{
+			Phase: string(clusterv1.MachineDeploymentPhaseRunning),
+		},
+	}
+
+	collector := NewStateCollector(fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, machine, md).Build())
+
+	expected := `
+# HELP capi_cluster_status_condition The condition of the cluster; the value is 1 for the current status of the condition, 0 otherwise.
+# TYPE capi_cluster_status_condition gauge
+capi_cluster_status_condition{name="test",namespace="default",status="False",type="Ready"} 0
+capi_cluster_status_condition{name="test",namespace="default",status="True",type="Ready"} 1
+capi_cluster_status_condition{name="test",namespace="default",status="Unknown",type="Ready"} 0
+# HELP capi_machine_status_condition The condition of the machine; the value is 1 for the current status of the condition, 0 otherwise.
+# TYPE capi_machine_status_condition gauge
+capi_machine_status_condition{cluster="test",name="test-machine",namespace="default",status="False",type="Ready"} 1
+capi_machine_status_condition{cluster="test",name="test-machine",namespace="default",status="True",type="Ready"} 0
+capi_machine_status_condition{cluster="test",name="test-machine",namespace="default",status="Unknown",type="Ready"} 0
+`
+	g.Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected), "capi_cluster_status_condition", "capi_machine_status_condition")).To(Succeed())
+
+	// A sample is reported for each known phase, the current one is 1.
+	g.Expect(testutil.CollectAndCount(collector, "capi_cluster_status_phase")).To(Equal(len(clusterPhases)))
+	g.Expect(testutil.CollectAndCount(collector, "capi_machine_status_phase")).To(Equal(len(machinePhases)))
+	g.Expect(testutil.CollectAndCount(collector, "capi_machinedeployment_status_phase")).To(Equal(len(machineDeploymentPhases)))
+	g.Expect(testutil.CollectAndCount(collector, "capi_machinedeployment_status_condition")).To(Equal(0))
+
+	expected = `
+# HELP capi_machinedeployment_status_phase The current phase of the machinedeployment; the value is 1 for the current phase, 0 otherwise.
+# TYPE capi_machinedeployment_status_phase gauge
+capi_machinedeployment_status_phase{cluster="test",name="test-md",namespace="default",phase="Failed"} 0
+capi_machinedeployment_status_phase{cluster="test",name="test-md",namespace="default",phase="Running"} 1
+capi_machinedeployment_status_phase{cluster="test",name="test-md",namespace="default",phase="ScalingDown"} 0
+capi_machinedeployment_status_phase{cluster="test",name="test-md",namespace="default",phase="ScalingUp"} 0
+capi_machinedeployment_status_phase{cluster="test",name="test-md",namespace="default",phase="Unknown"} 0
+`
+	g.Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected), "capi_machinedeployment_status_phase")).To(Succeed())
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics implements the Prometheus metrics exposed by the Cluster API core controllers.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	utilmetrics "sigs.k8s.io/cluster-api/util/metrics"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	machineHealthCheckRemediations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: utilmetrics.Namespace,
+			Subsystem: "machinehealthcheck",
+			Name:      "remediations_total",
+			Help:      "Number of machines and machine pool instances marked for remediation by a MachineHealthCheck.",
+		},
+		[]string{"namespace", "name", "cluster"},
+	)
+
+	machineProvisioningDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: utilmetrics.Namespace,
+			Subsystem: "machine",
+			Name:      "provisioning_duration_seconds",
+			Help:      "Time elapsed between the creation of a Machine and its Node being linked to it.",
+			Buckets:   []float64{30, 60, 120, 180, 240, 300, 450, 600, 900, 1200, 1800, 3600},
+		},
+		[]string{"namespace", "cluster"},
+	)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		machineHealthCheckRemediations,
+		machineProvisioningDuration,
+	)
+}
+
+// RecordMachineHealthCheckRemediations records that a MachineHealthCheck marked the given number of targets for remediation.
+func RecordMachineHealthCheckRemediations(mhc *clusterv1.MachineHealthCheck, count int) {
+	machineHealthCheckRemediations.WithLabelValues(mhc.Namespace, mhc.Name, mhc.Spec.ClusterName).Add(float64(count))
+}
+
+// RecordMachineProvisioned records the time it took for a Machine to be provisioned, that is the time elapsed
+// between its creation and the moment its NodeRef is set.
+func RecordMachineProvisioned(machine *clusterv1.Machine, now time.Time) {
+	machineProvisioningDuration.WithLabelValues(machine.Namespace, machine.Spec.ClusterName).Observe(now.Sub(machine.CreationTimestamp.Time).Seconds())
+}
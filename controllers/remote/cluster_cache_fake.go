@@ -44,6 +44,7 @@ func NewTestClusterCacheTracker(log logr.Logger, cl client.Client, scheme *runti
 
 		cache:            nil,
 		delegatingClient: delegatingClient,
+		liveClient:       cl,
 		watches:          sets.NewString(watchObjects...),
 	}
 	return testCacheTracker
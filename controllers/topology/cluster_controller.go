@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// controlPlaneUpgradeRequeueAfter is how long to wait before checking again if the control plane
+	// completed the upgrade to the topology version, so the version can be propagated to the workers.
+	controlPlaneUpgradeRequeueAfter = 30 * time.Second
+)
+
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io;controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusterclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch;create;update;patch;delete
+
+// ClusterReconciler reconciles the managed topology of a Cluster object, generating
+// and keeping in sync the objects described by the referenced ClusterClass.
+type ClusterReconciler struct {
+	Client client.Client
+}
+
+func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, err := ctrl.NewControllerManagedBy(mgr).
+		For(&clusterv1.Cluster{}, builder.WithPredicates(predicates.ClusterHasTopology(ctrl.LoggerFrom(ctx)))).
+		Named("topology/cluster").
+		Watches(
+			&source.Kind{Type: &clusterv1.ClusterClass{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterClassToCluster),
+		).
+		Watches(
+			&source.Kind{Type: &clusterv1.MachineDeployment{}},
+			handler.EnqueueRequestsFromMapFunc(r.machineDeploymentToCluster),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPaused(ctrl.LoggerFrom(ctx))).
+		Build(r)
+
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the Cluster instance.
+	cluster := &clusterv1.Cluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	// Return early if the Cluster does not have a managed topology.
+	if cluster.Spec.Topology == nil {
+		return ctrl.Result{}, nil
+	}
+
+	// Return early if the object or Cluster is paused.
+	if annotations.IsPaused(cluster, cluster) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	// Return early if the Cluster is being deleted; the objects generated from the topology
+	// are deleted by the Cluster controller or garbage collected using owner references.
+	if !cluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper.
+	patchHelper, err := patch.NewHelper(cluster, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		// Always attempt to patch the Cluster, so the references to the objects generated from the
+		// topology and the TopologyReconciled condition are persisted.
+		if err := patchHelper.Patch(ctx, cluster, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{clusterv1.TopologyReconciledCondition}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	result, err := r.reconcile(ctx, cluster)
+	if err != nil {
+		conditions.MarkFalse(cluster, clusterv1.TopologyReconciledCondition, clusterv1.TopologyReconcileFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, err
+	}
+	conditions.MarkTrue(cluster, clusterv1.TopologyReconciledCondition)
+	return result, nil
+}
+
+// reconcile generates the objects described by the Cluster topology and the ClusterClass, or
+// updates them in case they already exist.
+func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	class := &clusterv1.ClusterClass{}
+	classKey := client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.Topology.Class}
+	if err := r.Client.Get(ctx, classKey, class); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve ClusterClass %q", classKey.Name)
+	}
+
+	if err := r.reconcileInfrastructureCluster(ctx, cluster, class); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	controlPlaneUpgrading, err := r.reconcileControlPlane(ctx, cluster, class)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileMachineDeployments(ctx, cluster, class, controlPlaneUpgrading); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if controlPlaneUpgrading {
+		return ctrl.Result{RequeueAfter: controlPlaneUpgradeRequeueAfter}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// clusterClassToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for the Clusters using a ClusterClass.
+func (r *ClusterReconciler) clusterClassToCluster(o client.Object) []ctrl.Request {
+	class, ok := o.(*clusterv1.ClusterClass)
+	if !ok {
+		panic(errors.Errorf("expected a ClusterClass but got a %T", o))
+	}
+
+	clusterList := &clusterv1.ClusterList{}
+	if err := r.Client.List(context.TODO(), clusterList, client.InNamespace(class.Namespace)); err != nil {
+		return nil
+	}
+
+	var result []ctrl.Request
+	for _, c := range clusterList.Items {
+		if c.Spec.Topology != nil && c.Spec.Topology.Class == class.Name {
+			result = append(result, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: c.Namespace, Name: c.Name}})
+		}
+	}
+	return result
+}
+
+// machineDeploymentToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for the Cluster owning a MachineDeployment generated from a managed topology.
+func (r *ClusterReconciler) machineDeploymentToCluster(o client.Object) []ctrl.Request {
+	md, ok := o.(*clusterv1.MachineDeployment)
+	if !ok {
+		panic(errors.Errorf("expected a MachineDeployment but got a %T", o))
+	}
+
+	if _, ok := md.Labels[clusterv1.ClusterTopologyOwnedLabel]; !ok {
+		return nil
+	}
+
+	return []reconcile.Request{{
+		NamespacedName: client.ObjectKey{Namespace: md.Namespace, Name: md.Spec.ClusterName},
+	}}
+}
@@ -0,0 +1,404 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/test/helpers"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTemplate(apiVersion, kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": metav1.NamespaceDefault,
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": spec,
+				},
+			},
+		},
+	}
+}
+
+func templateRef(template *unstructured.Unstructured) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: template.GetAPIVersion(),
+		Kind:       template.GetKind(),
+		Name:       template.GetName(),
+		Namespace:  template.GetNamespace(),
+	}
+}
+
+func newTestObjects() (*clusterv1.Cluster, *clusterv1.ClusterClass, []client.Object) {
+	infraClusterTemplate := newTemplate("infrastructure.cluster.x-k8s.io/v1alpha4", "GenericInfrastructureClusterTemplate", "infra-cluster", map[string]interface{}{"region": "eu"})
+	controlPlaneTemplate := newTemplate("controlplane.cluster.x-k8s.io/v1alpha4", "GenericControlPlaneTemplate", "control-plane", map[string]interface{}{"foo": "bar"})
+	infraMachineTemplate := newTemplate("infrastructure.cluster.x-k8s.io/v1alpha4", "GenericInfrastructureMachineTemplate", "infra-machine", map[string]interface{}{"size": "large"})
+	bootstrapTemplate := newTemplate("bootstrap.cluster.x-k8s.io/v1alpha4", "GenericBootstrapConfigTemplate", "bootstrap", map[string]interface{}{})
+
+	class := &clusterv1.ClusterClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class", Namespace: metav1.NamespaceDefault},
+		Spec: clusterv1.ClusterClassSpec{
+			Infrastructure: clusterv1.LocalObjectTemplate{Ref: templateRef(infraClusterTemplate)},
+			ControlPlane: clusterv1.ControlPlaneClass{
+				LocalObjectTemplate:   clusterv1.LocalObjectTemplate{Ref: templateRef(controlPlaneTemplate)},
+				MachineInfrastructure: &clusterv1.LocalObjectTemplate{Ref: templateRef(infraMachineTemplate)},
+			},
+			Workers: clusterv1.WorkersClass{
+				MachineDeployments: []clusterv1.MachineDeploymentClass{
+					{
+						Class: "default-worker",
+						Template: clusterv1.MachineDeploymentClassTemplate{
+							Metadata:       clusterv1.ObjectMeta{Labels: map[string]string{"class-label": "class"}},
+							Bootstrap:      clusterv1.LocalObjectTemplate{Ref: templateRef(bootstrapTemplate)},
+							Infrastructure: clusterv1.LocalObjectTemplate{Ref: templateRef(infraMachineTemplate)},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: metav1.NamespaceDefault},
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{
+				Class:   "class",
+				Version: "v1.21.1",
+				ControlPlane: clusterv1.ControlPlaneTopology{
+					Replicas: pointer.Int32Ptr(3),
+				},
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{
+							Class:    "default-worker",
+							Name:     "md1",
+							Replicas: pointer.Int32Ptr(2),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return cluster, class, []client.Object{cluster, class, infraClusterTemplate, controlPlaneTemplate, infraMachineTemplate, bootstrapTemplate}
+}
+
+func nestedField(obj *unstructured.Unstructured, fields ...string) interface{} {
+	value, _, _ := unstructured.NestedFieldCopy(obj.Object, fields...)
+	return value
+}
+
+func newScheme(g *WithT) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+func TestClusterReconcilerCreatesTopology(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster, _, objs := newTestObjects()
+	c := helpers.NewFakeClientWithScheme(newScheme(g), objs...)
+	r := &ClusterReconciler{Client: c}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	g.Expect(conditions.IsTrue(cluster, clusterv1.TopologyReconciledCondition)).To(BeTrue())
+
+	// The infrastructure cluster is generated from the ClusterClass template.
+	g.Expect(cluster.Spec.InfrastructureRef).ToNot(BeNil())
+	g.Expect(cluster.Spec.InfrastructureRef.Kind).To(Equal("GenericInfrastructureCluster"))
+	infraCluster, err := external.Get(ctx, c, cluster.Spec.InfrastructureRef, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(infraCluster.GetName()).To(Equal(cluster.Name))
+	g.Expect(infraCluster.GetLabels()).To(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+	g.Expect(nestedField(infraCluster, "spec", "region")).To(Equal("eu"))
+
+	// The control plane is generated from the ClusterClass template and the topology.
+	g.Expect(cluster.Spec.ControlPlaneRef).ToNot(BeNil())
+	controlPlane, err := external.Get(ctx, c, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(nestedField(controlPlane, "spec", "version")).To(Equal("v1.21.1"))
+	g.Expect(nestedField(controlPlane, "spec", "replicas")).To(Equal(int64(3)))
+	g.Expect(nestedField(controlPlane, "spec", "infrastructureTemplate", "kind")).To(Equal("GenericInfrastructureMachineTemplate"))
+
+	// The MachineDeployment is generated from the MachineDeployment class and the topology.
+	mds, err := r.getCurrentMachineDeployments(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mds).To(HaveLen(1))
+	md := mds["md1"]
+	g.Expect(md).ToNot(BeNil())
+	g.Expect(*md.Spec.Replicas).To(Equal(int32(2)))
+	g.Expect(*md.Spec.Template.Spec.Version).To(Equal("v1.21.1"))
+	g.Expect(md.Spec.Template.Labels).To(HaveKeyWithValue("class-label", "class"))
+	g.Expect(md.Spec.Template.Spec.Bootstrap.ConfigRef.Kind).To(Equal("GenericBootstrapConfigTemplate"))
+	g.Expect(md.Spec.Template.Spec.InfrastructureRef.Kind).To(Equal("GenericInfrastructureMachineTemplate"))
+
+	// Reconciling again does not generate new objects.
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+	mds, err = r.getCurrentMachineDeployments(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mds).To(HaveLen(1))
+	g.Expect(mds["md1"].Name).To(Equal(md.Name))
+}
+
+func TestClusterReconcilerUpdatesTopology(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster, _, objs := newTestObjects()
+	c := helpers.NewFakeClientWithScheme(newScheme(g), objs...)
+	r := &ClusterReconciler{Client: c}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+
+	// Upgrade the cluster and replace the MachineDeployment.
+	cluster.Spec.Topology.Version = "v1.21.2"
+	cluster.Spec.Topology.Workers.MachineDeployments = []clusterv1.MachineDeploymentTopology{
+		{
+			Class: "default-worker",
+			Name:  "md2",
+		},
+	}
+	g.Expect(c.Update(ctx, cluster)).To(Succeed())
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	controlPlane, err := external.Get(ctx, c, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(nestedField(controlPlane, "spec", "version")).To(Equal("v1.21.2"))
+	g.Expect(nestedField(controlPlane, "spec", "foo")).To(Equal("bar"))
+
+	mds, err := r.getCurrentMachineDeployments(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mds).To(HaveLen(1))
+	g.Expect(mds).To(HaveKey("md2"))
+	g.Expect(*mds["md2"].Spec.Template.Spec.Version).To(Equal("v1.21.2"))
+}
+
+func TestClusterReconcilerRemovesFieldsDroppedFromTemplates(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster, class, objs := newTestObjects()
+	c := helpers.NewFakeClientWithScheme(newScheme(g), objs...)
+	r := &ClusterReconciler{Client: c}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+
+	// Another controller sets a field on the control plane.
+	controlPlane, err := external.Get(ctx, c, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(unstructured.SetNestedField(controlPlane.Object, "defaulted", "spec", "other")).To(Succeed())
+	g.Expect(c.Update(ctx, controlPlane)).To(Succeed())
+
+	// Replace a field in the control plane template.
+	template, err := external.Get(ctx, c, class.Spec.ControlPlane.Ref, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(unstructured.SetNestedMap(template.Object, map[string]interface{}{"baz": "qux"}, "spec", "template", "spec")).To(Succeed())
+	g.Expect(c.Update(ctx, template)).To(Succeed())
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	controlPlane, err = external.Get(ctx, c, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(nestedField(controlPlane, "spec", "foo")).To(BeNil())
+	g.Expect(nestedField(controlPlane, "spec", "baz")).To(Equal("qux"))
+	g.Expect(nestedField(controlPlane, "spec", "other")).To(Equal("defaulted"))
+	g.Expect(nestedField(controlPlane, "spec", "version")).To(Equal("v1.21.1"))
+}
+
+func TestClusterReconcilerCleansUpPreviousTemplates(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster, class, objs := newTestObjects()
+	c := helpers.NewFakeClientWithScheme(newScheme(g), objs...)
+	r := &ClusterReconciler{Client: c}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+
+	controlPlane, err := external.Get(ctx, c, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	previousControlPlaneInfraRef, err := getNestedRef(controlPlane, "spec", "infrastructureTemplate")
+	g.Expect(err).ToNot(HaveOccurred())
+	mds, err := r.getCurrentMachineDeployments(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	previousWorkersInfraRef := mds["md1"].Spec.Template.Spec.InfrastructureRef
+
+	// Change the machine infrastructure template, so new copies are generated.
+	template, err := external.Get(ctx, c, class.Spec.ControlPlane.MachineInfrastructure.Ref, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(unstructured.SetNestedField(template.Object, "small", "spec", "template", "spec", "size")).To(Succeed())
+	g.Expect(c.Update(ctx, template)).To(Succeed())
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	controlPlane, err = external.Get(ctx, c, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	controlPlaneInfraRef, err := getNestedRef(controlPlane, "spec", "infrastructureTemplate")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(controlPlaneInfraRef.Name).ToNot(Equal(previousControlPlaneInfraRef.Name))
+	_, err = external.Get(ctx, c, controlPlaneInfraRef, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = external.Get(ctx, c, previousControlPlaneInfraRef, cluster.Namespace)
+	g.Expect(apierrors.IsNotFound(errors.Cause(err))).To(BeTrue())
+
+	mds, err = r.getCurrentMachineDeployments(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mds["md1"].Spec.Template.Spec.InfrastructureRef.Name).ToNot(Equal(previousWorkersInfraRef.Name))
+	_, err = external.Get(ctx, c, &previousWorkersInfraRef, cluster.Namespace)
+	g.Expect(apierrors.IsNotFound(errors.Cause(err))).To(BeTrue())
+
+	// The templates referenced by the ClusterClass are never deleted.
+	_, err = external.Get(ctx, c, class.Spec.ControlPlane.MachineInfrastructure.Ref, cluster.Namespace)
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func TestClusterReconcilerMissingClusterClass(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster, _, _ := newTestObjects()
+	c := helpers.NewFakeClientWithScheme(newScheme(g), cluster)
+	r := &ClusterReconciler{Client: c}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).To(HaveOccurred())
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	g.Expect(conditions.IsFalse(cluster, clusterv1.TopologyReconciledCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.TopologyReconciledCondition)).To(Equal(clusterv1.TopologyReconcileFailedReason))
+}
+
+func TestIsControlPlaneUpgrading(t *testing.T) {
+	tests := []struct {
+		name   string
+		status map[string]interface{}
+		want   bool
+	}{
+		{
+			name:   "no status",
+			status: nil,
+			want:   false,
+		},
+		{
+			name:   "generation not observed yet",
+			status: map[string]interface{}{"observedGeneration": int64(1)},
+			want:   true,
+		},
+		{
+			name:   "replicas not updated",
+			status: map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(1)},
+			want:   true,
+		},
+		{
+			name:   "replicas updated",
+			status: map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3)},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			controlPlane := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			controlPlane.SetGeneration(2)
+			if tt.status != nil {
+				controlPlane.Object["status"] = tt.status
+			}
+
+			got, err := isControlPlaneUpgrading(controlPlane)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMergeInto(t *testing.T) {
+	g := NewWithT(t)
+
+	dst := map[string]interface{}{
+		"a": "a",
+		"nested": map[string]interface{}{
+			"b": "b",
+			"c": "c",
+		},
+	}
+	mergeInto(dst, map[string]interface{}{
+		"nested": map[string]interface{}{
+			"c": "changed",
+		},
+		"d": "d",
+	})
+
+	g.Expect(dst).To(Equal(map[string]interface{}{
+		"a": "a",
+		"nested": map[string]interface{}{
+			"b": "b",
+			"c": "changed",
+		},
+		"d": "d",
+	}))
+}
+
+func TestFieldPaths(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(fieldPaths(map[string]interface{}{
+		"a": "a",
+		"nested": map[string]interface{}{
+			"c": []interface{}{"c"},
+			"b": int64(1),
+		},
+		"empty": map[string]interface{}{},
+	})).To(Equal([][]string{
+		{"a"},
+		{"empty"},
+		{"nested", "b"},
+		{"nested", "c"},
+	}))
+}
@@ -0,0 +1,502 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apiserver/pkg/storage/names"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileInfrastructureCluster creates the infrastructure cluster from the ClusterClass template if it does
+// not exist yet, otherwise it makes sure the spec of the existing object is in sync with the template.
+func (r *ClusterReconciler) reconcileInfrastructureCluster(ctx context.Context, cluster *clusterv1.Cluster, class *clusterv1.ClusterClass) error {
+	desired, err := r.computeObjectFromTemplate(ctx, cluster, class.Spec.Infrastructure.Ref, clusterv1.ObjectMeta{})
+	if err != nil {
+		return errors.Wrap(err, "failed to compute the desired infrastructure cluster")
+	}
+
+	ref, err := r.reconcileObject(ctx, cluster.Spec.InfrastructureRef, desired)
+	if err != nil {
+		return errors.Wrap(err, "failed to reconcile the infrastructure cluster")
+	}
+	cluster.Spec.InfrastructureRef = ref
+	return nil
+}
+
+// reconcileControlPlane creates the control plane from the ClusterClass template if it does not exist yet,
+// otherwise it makes sure the spec of the existing object is in sync with the template and the topology.
+// It returns true if the control plane is still rolling out the topology version.
+func (r *ClusterReconciler) reconcileControlPlane(ctx context.Context, cluster *clusterv1.Cluster, class *clusterv1.ClusterClass) (bool, error) {
+	desired, err := r.computeObjectFromTemplate(ctx, cluster, class.Spec.ControlPlane.Ref, mergeMetadata(class.Spec.ControlPlane.Metadata, cluster.Spec.Topology.ControlPlane.Metadata))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to compute the desired control plane")
+	}
+
+	if err := unstructured.SetNestedField(desired.Object, cluster.Spec.Topology.Version, "spec", "version"); err != nil {
+		return false, errors.Wrap(err, "failed to set spec.version in the desired control plane")
+	}
+	if cluster.Spec.Topology.ControlPlane.Replicas != nil {
+		if err := unstructured.SetNestedField(desired.Object, int64(*cluster.Spec.Topology.ControlPlane.Replicas), "spec", "replicas"); err != nil {
+			return false, errors.Wrap(err, "failed to set spec.replicas in the desired control plane")
+		}
+	}
+	var previousInfraRef, infraRef *corev1.ObjectReference
+	if class.Spec.ControlPlane.MachineInfrastructure != nil {
+		if cluster.Spec.ControlPlaneRef != nil {
+			current, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+			if err != nil {
+				return false, err
+			}
+			previousInfraRef, err = getNestedRef(current, "spec", "infrastructureTemplate")
+			if err != nil {
+				return false, err
+			}
+		}
+
+		infraRef, err = r.reconcileTemplate(ctx, cluster, class.Spec.ControlPlane.MachineInfrastructure.Ref, fmt.Sprintf("%s-control-plane", cluster.Name))
+		if err != nil {
+			return false, errors.Wrap(err, "failed to reconcile the control plane machine infrastructure template")
+		}
+		if err := unstructured.SetNestedMap(desired.Object, objectReferenceToMap(infraRef), "spec", "infrastructureTemplate"); err != nil {
+			return false, errors.Wrap(err, "failed to set spec.infrastructureTemplate in the desired control plane")
+		}
+	}
+
+	ref, err := r.reconcileObject(ctx, cluster.Spec.ControlPlaneRef, desired)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to reconcile the control plane")
+	}
+	cluster.Spec.ControlPlaneRef = ref
+
+	if err := r.cleanupTemplate(ctx, cluster, previousInfraRef, infraRef); err != nil {
+		return false, errors.Wrap(err, "failed to cleanup the previous control plane machine infrastructure template")
+	}
+
+	controlPlane, err := external.Get(ctx, r.Client, ref, cluster.Namespace)
+	if err != nil {
+		return false, err
+	}
+	return isControlPlaneUpgrading(controlPlane)
+}
+
+// reconcileMachineDeployments makes sure there is a MachineDeployment for each MachineDeploymentTopology in
+// the Cluster topology, and deletes the MachineDeployments that are no longer part of it.
+// When the control plane is upgrading, the version of existing MachineDeployments is left untouched,
+// so workers are upgraded only after the control plane.
+func (r *ClusterReconciler) reconcileMachineDeployments(ctx context.Context, cluster *clusterv1.Cluster, class *clusterv1.ClusterClass, controlPlaneUpgrading bool) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	current, err := r.getCurrentMachineDeployments(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	desiredNames := map[string]bool{}
+	if cluster.Spec.Topology.Workers != nil {
+		for i := range cluster.Spec.Topology.Workers.MachineDeployments {
+			mdTopology := cluster.Spec.Topology.Workers.MachineDeployments[i]
+			desiredNames[mdTopology.Name] = true
+
+			mdClass := getMachineDeploymentClass(class, mdTopology.Class)
+			if mdClass == nil {
+				return errors.Errorf("failed to find MachineDeployment class %q in ClusterClass %q", mdTopology.Class, class.Name)
+			}
+
+			if err := r.reconcileMachineDeployment(ctx, cluster, mdClass, mdTopology, current[mdTopology.Name], controlPlaneUpgrading); err != nil {
+				return errors.Wrapf(err, "failed to reconcile MachineDeployment for topology %q", mdTopology.Name)
+			}
+		}
+	}
+
+	for name, md := range current {
+		if desiredNames[name] {
+			continue
+		}
+		log.Info("Deleting MachineDeployment no longer part of the topology", "machinedeployment", md.Name)
+		if err := r.Client.Delete(ctx, md); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete MachineDeployment %q", md.Name)
+		}
+	}
+	return nil
+}
+
+func (r *ClusterReconciler) reconcileMachineDeployment(ctx context.Context, cluster *clusterv1.Cluster, mdClass *clusterv1.MachineDeploymentClass, mdTopology clusterv1.MachineDeploymentTopology, md *clusterv1.MachineDeployment, controlPlaneUpgrading bool) error {
+	prefix := fmt.Sprintf("%s-%s", cluster.Name, mdTopology.Name)
+
+	bootstrapRef, err := r.reconcileTemplate(ctx, cluster, mdClass.Template.Bootstrap.Ref, prefix+"-bootstrap")
+	if err != nil {
+		return errors.Wrap(err, "failed to reconcile bootstrap template")
+	}
+	infraRef, err := r.reconcileTemplate(ctx, cluster, mdClass.Template.Infrastructure.Ref, prefix+"-infra")
+	if err != nil {
+		return errors.Wrap(err, "failed to reconcile infrastructure template")
+	}
+
+	metadata := mergeMetadata(mdClass.Template.Metadata, mdTopology.Metadata)
+	selectorLabels := map[string]string{
+		clusterv1.ClusterLabelName:                          cluster.Name,
+		clusterv1.ClusterTopologyMachineDeploymentLabelName: mdTopology.Name,
+	}
+
+	if md == nil {
+		md = &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            names.SimpleNameGenerator.GenerateName(prefix + "-"),
+				Namespace:       cluster.Namespace,
+				OwnerReferences: []metav1.OwnerReference{*clusterOwnerReference(cluster)},
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				ClusterName: cluster.Name,
+				Selector:    metav1.LabelSelector{MatchLabels: selectorLabels},
+			},
+		}
+		setMachineDeploymentTopologyFields(md, cluster, metadata, selectorLabels, mdTopology, bootstrapRef, infraRef, true)
+		if err := r.Client.Create(ctx, md); err != nil {
+			return errors.Wrapf(err, "failed to create MachineDeployment %q", md.Name)
+		}
+		return nil
+	}
+
+	previousBootstrapRef := md.Spec.Template.Spec.Bootstrap.ConfigRef
+	previousInfraRef := md.Spec.Template.Spec.InfrastructureRef
+
+	patchHelper, err := patch.NewHelper(md, r.Client)
+	if err != nil {
+		return err
+	}
+	setMachineDeploymentTopologyFields(md, cluster, metadata, selectorLabels, mdTopology, bootstrapRef, infraRef, !controlPlaneUpgrading)
+	if err := patchHelper.Patch(ctx, md); err != nil {
+		return err
+	}
+
+	if err := r.cleanupTemplate(ctx, cluster, previousBootstrapRef, bootstrapRef); err != nil {
+		return errors.Wrap(err, "failed to cleanup the previous bootstrap template")
+	}
+	if err := r.cleanupTemplate(ctx, cluster, &previousInfraRef, infraRef); err != nil {
+		return errors.Wrap(err, "failed to cleanup the previous infrastructure template")
+	}
+	return nil
+}
+
+// setMachineDeploymentTopologyFields sets the fields of a MachineDeployment owned by the topology.
+// Labels and annotations are merged with the existing ones, so metadata added by other controllers is preserved.
+func setMachineDeploymentTopologyFields(md *clusterv1.MachineDeployment, cluster *clusterv1.Cluster, metadata clusterv1.ObjectMeta, selectorLabels map[string]string, mdTopology clusterv1.MachineDeploymentTopology, bootstrapRef, infraRef *corev1.ObjectReference, setVersion bool) {
+	md.Labels = mergeMaps(md.Labels, metadata.Labels, selectorLabels, map[string]string{clusterv1.ClusterTopologyOwnedLabel: ""})
+	md.Annotations = mergeMaps(md.Annotations, metadata.Annotations)
+	if mdTopology.Replicas != nil {
+		md.Spec.Replicas = mdTopology.Replicas
+	}
+
+	md.Spec.Template.Labels = mergeMaps(md.Spec.Template.Labels, metadata.Labels, selectorLabels)
+	md.Spec.Template.Annotations = mergeMaps(md.Spec.Template.Annotations, metadata.Annotations)
+	md.Spec.Template.Spec.ClusterName = cluster.Name
+	md.Spec.Template.Spec.Bootstrap.ConfigRef = bootstrapRef
+	md.Spec.Template.Spec.InfrastructureRef = *infraRef
+	if setVersion || md.Spec.Template.Spec.Version == nil {
+		version := cluster.Spec.Topology.Version
+		md.Spec.Template.Spec.Version = &version
+	}
+}
+
+// getCurrentMachineDeployments returns the MachineDeployments generated from the Cluster topology,
+// indexed by the name of the MachineDeploymentTopology they belong to.
+func (r *ClusterReconciler) getCurrentMachineDeployments(ctx context.Context, cluster *clusterv1.Cluster) (map[string]*clusterv1.MachineDeployment, error) {
+	mdList := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, mdList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name},
+		client.HasLabels{clusterv1.ClusterTopologyOwnedLabel},
+	); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineDeployments")
+	}
+
+	current := map[string]*clusterv1.MachineDeployment{}
+	for i := range mdList.Items {
+		md := &mdList.Items[i]
+		name, ok := md.Labels[clusterv1.ClusterTopologyMachineDeploymentLabelName]
+		if !ok || name == "" {
+			continue
+		}
+		current[name] = md
+	}
+	return current, nil
+}
+
+// computeObjectFromTemplate generates the object described by the template referenced in the ClusterClass.
+// The object is named after the Cluster, so generating the same object multiple times is idempotent.
+func (r *ClusterReconciler) computeObjectFromTemplate(ctx context.Context, cluster *clusterv1.Cluster, templateRef *corev1.ObjectReference, metadata clusterv1.ObjectMeta) (*unstructured.Unstructured, error) {
+	template, err := external.Get(ctx, r.Client, templateRef, cluster.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	desired, err := external.GenerateTemplate(&external.GenerateTemplateInput{
+		Template:    template,
+		TemplateRef: templateRef,
+		Namespace:   cluster.Namespace,
+		ClusterName: cluster.Name,
+		OwnerRef:    clusterOwnerReference(cluster),
+		Labels:      mergeMaps(metadata.Labels, map[string]string{clusterv1.ClusterTopologyOwnedLabel: ""}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	desired.SetName(cluster.Name)
+	desired.SetAnnotations(mergeMaps(desired.GetAnnotations(), metadata.Annotations))
+	return desired, nil
+}
+
+// reconcileObject creates the desired object if the current reference is nil, otherwise it patches the object
+// referenced by current with the spec, labels and annotations of the desired object.
+// The spec fields set from the desired object are tracked in the ClusterTopologyManagedFieldsAnnotation, so fields
+// removed from the desired object in a later reconcile are removed from the existing object as well.
+// It returns a reference to the object.
+func (r *ClusterReconciler) reconcileObject(ctx context.Context, current *corev1.ObjectReference, desired *unstructured.Unstructured) (*corev1.ObjectReference, error) {
+	desiredSpec, _, err := unstructured.NestedMap(desired.Object, "spec")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read spec from the desired %s", desired.GetKind())
+	}
+	desiredPaths := fieldPaths(desiredSpec)
+	managedFields, err := encodeFieldPaths(desiredPaths)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute the managed fields of the desired %s", desired.GetKind())
+	}
+	desired.SetAnnotations(mergeMaps(desired.GetAnnotations(), map[string]string{clusterv1.ClusterTopologyManagedFieldsAnnotation: managedFields}))
+
+	if current == nil {
+		if err := r.Client.Create(ctx, desired); err != nil && !apierrors.IsAlreadyExists(err) {
+			return nil, errors.Wrapf(err, "failed to create %s %q", desired.GetKind(), desired.GetName())
+		}
+		return objectReference(desired), nil
+	}
+
+	obj, err := external.Get(ctx, r.Client, current, desired.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetAPIVersion() != desired.GetAPIVersion() || obj.GetKind() != desired.GetKind() {
+		return nil, errors.Errorf("cannot change %s %q into a %s", obj.GetKind(), obj.GetName(), desired.GetKind())
+	}
+
+	previousPaths, err := decodeFieldPaths(obj.GetAnnotations())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the managed fields of %s %q", obj.GetKind(), obj.GetName())
+	}
+
+	patched := obj.DeepCopy()
+	patched.SetLabels(mergeMaps(patched.GetLabels(), desired.GetLabels()))
+	patched.SetAnnotations(mergeMaps(patched.GetAnnotations(), desired.GetAnnotations()))
+	patchedSpec, _, err := unstructured.NestedMap(patched.Object, "spec")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read spec from %s %q", obj.GetKind(), obj.GetName())
+	}
+	if patchedSpec == nil {
+		patchedSpec = map[string]interface{}{}
+	}
+
+	// Remove the fields set in a previous reconcile which are no longer part of the desired spec, e.g. because they
+	// have been removed from the ClusterClass template; fields set by other controllers are preserved.
+	desiredPathSet := map[string]bool{}
+	for _, p := range desiredPaths {
+		desiredPathSet[fmt.Sprintf("%q", p)] = true
+	}
+	for _, p := range previousPaths {
+		if !desiredPathSet[fmt.Sprintf("%q", p)] {
+			unstructured.RemoveNestedField(patchedSpec, p...)
+		}
+	}
+	mergeInto(patchedSpec, desiredSpec)
+	if err := unstructured.SetNestedMap(patched.Object, patchedSpec, "spec"); err != nil {
+		return nil, err
+	}
+
+	if !equality.Semantic.DeepEqual(obj.Object, patched.Object) {
+		if err := r.Client.Patch(ctx, patched, client.MergeFrom(obj)); err != nil {
+			return nil, errors.Wrapf(err, "failed to patch %s %q", obj.GetKind(), obj.GetName())
+		}
+	}
+	return objectReference(obj), nil
+}
+
+// reconcileTemplate makes sure a copy of the template referenced in the ClusterClass exists for the Cluster, and
+// returns a reference to it.
+// The name of the copy includes a hash of the template spec, so any change to the ClusterClass templates results
+// in a new reference, thus triggering a rollout of the machines using it; the previous copy is deleted by
+// cleanupTemplate once the object using it has been switched to the new one.
+func (r *ClusterReconciler) reconcileTemplate(ctx context.Context, cluster *clusterv1.Cluster, templateRef *corev1.ObjectReference, prefix string) (*corev1.ObjectReference, error) {
+	template, err := external.Get(ctx, r.Client, templateRef, cluster.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, _, err := unstructured.NestedMap(template.Object, "spec")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read spec from %s %q", template.GetKind(), template.GetName())
+	}
+	hash, err := computeHash(spec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute hash for %s %q", template.GetKind(), template.GetName())
+	}
+
+	clone := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	clone.SetAPIVersion(template.GetAPIVersion())
+	clone.SetKind(template.GetKind())
+	clone.SetNamespace(cluster.Namespace)
+	clone.SetName(fmt.Sprintf("%s-%s", prefix, hash))
+	clone.SetLabels(map[string]string{
+		clusterv1.ClusterLabelName:          cluster.Name,
+		clusterv1.ClusterTopologyOwnedLabel: "",
+	})
+	clone.SetAnnotations(map[string]string{
+		clusterv1.TemplateClonedFromNameAnnotation:      templateRef.Name,
+		clusterv1.TemplateClonedFromGroupKindAnnotation: templateRef.GroupVersionKind().GroupKind().String(),
+	})
+	clone.SetOwnerReferences([]metav1.OwnerReference{*clusterOwnerReference(cluster)})
+	if spec != nil {
+		if err := unstructured.SetNestedMap(clone.Object, spec, "spec"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.Client.Create(ctx, clone); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, errors.Wrapf(err, "failed to create %s %q", clone.GetKind(), clone.GetName())
+	}
+	return objectReference(clone), nil
+}
+
+// cleanupTemplate deletes the template copy previously generated by reconcileTemplate once the object using it
+// has been switched to a new copy, so the copies generated for previous versions of the ClusterClass templates
+// are not left behind. Templates not generated for the Cluster by the topology controller are never deleted.
+func (r *ClusterReconciler) cleanupTemplate(ctx context.Context, cluster *clusterv1.Cluster, previous, current *corev1.ObjectReference) error {
+	if previous == nil || current == nil || previous.Name == "" {
+		return nil
+	}
+	if previous.Kind == current.Kind && previous.Name == current.Name {
+		return nil
+	}
+
+	template, err := external.Get(ctx, r.Client, previous, cluster.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return err
+	}
+	labels := template.GetLabels()
+	if _, ok := labels[clusterv1.ClusterTopologyOwnedLabel]; !ok || labels[clusterv1.ClusterLabelName] != cluster.Name {
+		return nil
+	}
+
+	if err := r.Client.Delete(ctx, template); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %s %q", template.GetKind(), template.GetName())
+	}
+	return nil
+}
+
+// isControlPlaneUpgrading returns true if the control plane has not yet rolled out all of its machines
+// to the latest spec. Control plane providers not exposing replica counters are never considered upgrading.
+func isControlPlaneUpgrading(controlPlane *unstructured.Unstructured) (bool, error) {
+	observedGeneration, found, err := unstructured.NestedInt64(controlPlane.Object, "status", "observedGeneration")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read status.observedGeneration from %s %q", controlPlane.GetKind(), controlPlane.GetName())
+	}
+	if found && observedGeneration < controlPlane.GetGeneration() {
+		return true, nil
+	}
+
+	replicas, replicasFound, err := unstructured.NestedInt64(controlPlane.Object, "status", "replicas")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read status.replicas from %s %q", controlPlane.GetKind(), controlPlane.GetName())
+	}
+	updatedReplicas, updatedReplicasFound, err := unstructured.NestedInt64(controlPlane.Object, "status", "updatedReplicas")
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read status.updatedReplicas from %s %q", controlPlane.GetKind(), controlPlane.GetName())
+	}
+	if !replicasFound || !updatedReplicasFound {
+		return false, nil
+	}
+	return updatedReplicas != replicas, nil
+}
+
+func getMachineDeploymentClass(class *clusterv1.ClusterClass, name string) *clusterv1.MachineDeploymentClass {
+	for i := range class.Spec.Workers.MachineDeployments {
+		if class.Spec.Workers.MachineDeployments[i].Class == name {
+			return &class.Spec.Workers.MachineDeployments[i]
+		}
+	}
+	return nil
+}
+
+func clusterOwnerReference(cluster *clusterv1.Cluster) *metav1.OwnerReference {
+	return &metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "Cluster",
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+	}
+}
+
+// objectReference returns a reference to the object without the UID, so the references generated
+// for an existing object are stable across reconciles.
+func objectReference(obj *unstructured.Unstructured) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+	}
+}
+
+// getNestedRef returns the object reference stored in the given field of the object, if any.
+func getNestedRef(obj *unstructured.Unstructured, fields ...string) (*corev1.ObjectReference, error) {
+	m, found, err := unstructured.NestedStringMap(obj.Object, fields...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s from %s %q", strings.Join(fields, "."), obj.GetKind(), obj.GetName())
+	}
+	if !found {
+		return nil, nil
+	}
+	return &corev1.ObjectReference{
+		APIVersion: m["apiVersion"],
+		Kind:       m["kind"],
+		Name:       m["name"],
+		Namespace:  m["namespace"],
+	}, nil
+}
+
+func objectReferenceToMap(ref *corev1.ObjectReference) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": ref.APIVersion,
+		"kind":       ref.Kind,
+		"name":       ref.Name,
+		"namespace":  ref.Namespace,
+	}
+}
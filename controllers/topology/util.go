@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	apirand "k8s.io/apimachinery/pkg/util/rand"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+// mergeMaps returns a new map containing all the entries of the given maps;
+// in case of conflicts the entries from the latter maps win.
+func mergeMaps(maps ...map[string]string) map[string]string {
+	var merged map[string]string
+	for _, m := range maps {
+		for k, v := range m {
+			if merged == nil {
+				merged = map[string]string{}
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// mergeMetadata merges the metadata defined in a ClusterClass with the metadata defined in a Cluster topology;
+// in case of conflicts the values from the topology win.
+func mergeMetadata(class, topology clusterv1.ObjectMeta) clusterv1.ObjectMeta {
+	return clusterv1.ObjectMeta{
+		Labels:      mergeMaps(class.Labels, topology.Labels),
+		Annotations: mergeMaps(class.Annotations, topology.Annotations),
+	}
+}
+
+// mergeInto recursively copies the values from src into dst; nested maps are merged,
+// while any other value from src replaces the corresponding value in dst.
+func mergeInto(dst, src map[string]interface{}) {
+	for k, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeInto(dstMap, srcMap)
+			continue
+		}
+		dst[k] = srcValue
+	}
+}
+
+// fieldPaths returns the paths of all the leaf fields of the given object; lists and empty maps are considered leaf fields.
+func fieldPaths(obj map[string]interface{}) [][]string {
+	var paths [][]string
+	for k, v := range obj {
+		if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+			for _, p := range fieldPaths(m) {
+				paths = append(paths, append([]string{k}, p...))
+			}
+			continue
+		}
+		paths = append(paths, []string{k})
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		for k := 0; k < len(paths[i]) && k < len(paths[j]); k++ {
+			if paths[i][k] != paths[j][k] {
+				return paths[i][k] < paths[j][k]
+			}
+		}
+		return len(paths[i]) < len(paths[j])
+	})
+	return paths
+}
+
+// encodeFieldPaths returns the value for the ClusterTopologyManagedFieldsAnnotation tracking the given paths.
+func encodeFieldPaths(paths [][]string) (string, error) {
+	data, err := json.Marshal(paths)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// decodeFieldPaths returns the paths tracked in the ClusterTopologyManagedFieldsAnnotation of the given object, if any.
+func decodeFieldPaths(annotations map[string]string) ([][]string, error) {
+	value, ok := annotations[clusterv1.ClusterTopologyManagedFieldsAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+	var paths [][]string
+	if err := json.Unmarshal([]byte(value), &paths); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// computeHash returns a short, stable hash of the given object serialized as JSON.
+func computeHash(obj interface{}) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New32a()
+	if _, err := hasher.Write(data); err != nil {
+		return "", err
+	}
+	return apirand.SafeEncodeString(fmt.Sprint(hasher.Sum32())), nil
+}
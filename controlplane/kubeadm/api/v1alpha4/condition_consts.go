@@ -149,3 +149,13 @@ const (
 	// generate a machine object
 	MachineGenerationFailedReason = "MachineGenerationFailed"
 )
+
+const (
+	// EtcdBackupSucceededCondition documents that the last etcd snapshot has been taken and stored successfully.
+	// NOTE: This conditions exists only if the KubeadmControlPlane has etcd backup configured.
+	EtcdBackupSucceededCondition clusterv1.ConditionType = "EtcdBackupSucceeded"
+
+	// EtcdSnapshotFailedReason (Severity=Warning) documents a KubeadmControlPlane controller detecting
+	// an error while taking or storing an etcd snapshot.
+	EtcdSnapshotFailedReason = "EtcdSnapshotFailed"
+)
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cabpkv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+)
+
+// KubeadmControlPlaneTemplateSpec defines the desired state of KubeadmControlPlaneTemplate.
+type KubeadmControlPlaneTemplateSpec struct {
+	Template KubeadmControlPlaneTemplateResource `json:"template"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=kubeadmcontrolplanetemplates,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+
+// KubeadmControlPlaneTemplate is the Schema for the kubeadmcontrolplanetemplates API.
+type KubeadmControlPlaneTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KubeadmControlPlaneTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KubeadmControlPlaneTemplateList contains a list of KubeadmControlPlaneTemplate.
+type KubeadmControlPlaneTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KubeadmControlPlaneTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KubeadmControlPlaneTemplate{}, &KubeadmControlPlaneTemplateList{})
+}
+
+// KubeadmControlPlaneTemplateResource describes the data needed to create a KubeadmControlPlane from a template.
+type KubeadmControlPlaneTemplateResource struct {
+	Spec KubeadmControlPlaneTemplateResourceSpec `json:"spec"`
+}
+
+// KubeadmControlPlaneTemplateResourceSpec defines the KubeadmControlPlane fields that can be set in a template;
+// replicas, version and infrastructureTemplate are set when generating the KubeadmControlPlane, e.g. from the
+// Cluster topology.
+type KubeadmControlPlaneTemplateResourceSpec struct {
+	// KubeadmConfigSpec is a KubeadmConfigSpec
+	// to use for initializing and joining machines to the control plane.
+	KubeadmConfigSpec cabpkv1.KubeadmConfigSpec `json:"kubeadmConfigSpec"`
+
+	// RolloutBefore is a field to indicate a rollout should be performed
+	// if the specified criteria is met.
+	// +optional
+	RolloutBefore *RolloutBefore `json:"rolloutBefore,omitempty"`
+
+	// The RolloutStrategy to use to replace control plane machines with
+	// new ones.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// NodeDrainTimeout is the total amount of time that the controller will spend on draining a controlplane node
+	// The default value is 0, meaning that the node can be drained without any time limitations.
+	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
+	// +optional
+	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// EtcdBackup configures periodic snapshots of the etcd cluster managed by the KubeadmControlPlane.
+	// It cannot be used when the control plane relies on an external etcd cluster.
+	// +optional
+	EtcdBackup *EtcdBackup `json:"etcdBackup,omitempty"`
+}
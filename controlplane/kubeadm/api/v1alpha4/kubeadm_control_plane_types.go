@@ -19,12 +19,22 @@ package v1alpha4
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 
 	cabpkv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// RolloutStrategyType defines the rollout strategies for a KubeadmControlPlane.
+type RolloutStrategyType string
+
+const (
+	// RollingUpdateStrategyType replaces the old control planes by new one using rolling update
+	// i.e. gradually scale up or down the old control planes and scale up or down the new one.
+	RollingUpdateStrategyType RolloutStrategyType = "RollingUpdate"
+)
+
 const (
 	KubeadmControlPlaneFinalizer = "kubeadm.controlplane.cluster.x-k8s.io"
 
@@ -37,6 +47,19 @@ const (
 	// KubeadmClusterConfigurationAnnotation is a machine annotation that stores the json-marshalled string of KCP ClusterConfiguration.
 	// This annotation is used to detect any changes in ClusterConfiguration and trigger machine rollout in KCP.
 	KubeadmClusterConfigurationAnnotation = "controlplane.cluster.x-k8s.io/kubeadm-cluster-configuration"
+
+	// EtcdRestoreSnapshotAnnotation is a KubeadmControlPlane annotation that requests to rebuild the control plane
+	// from the etcd snapshot with the given name. The annotation is removed once the restore has been completed.
+	EtcdRestoreSnapshotAnnotation = "controlplane.cluster.x-k8s.io/restore-etcd-snapshot"
+
+	// EtcdRestoredFromSnapshotAnnotation is a machine annotation that stores the name of the etcd snapshot
+	// the machine has been restored from.
+	EtcdRestoredFromSnapshotAnnotation = "controlplane.cluster.x-k8s.io/restored-from-etcd-snapshot"
+
+	// CertificatesExpiryAnnotation is a machine annotation that stores the expiry date, in RFC3339 format,
+	// of the certificates issued by kubeadm on the control plane machine.
+	// The annotation can be removed in order to force the expiry date to be read again, e.g. after renewing the certificates.
+	CertificatesExpiryAnnotation = "controlplane.cluster.x-k8s.io/certificates-expiry"
 )
 
 // KubeadmControlPlaneSpec defines the desired state of KubeadmControlPlane.
@@ -64,11 +87,76 @@ type KubeadmControlPlaneSpec struct {
 	// +optional
 	UpgradeAfter *metav1.Time `json:"upgradeAfter,omitempty"`
 
+	// RolloutBefore is a field to indicate a rollout should be performed
+	// if the specified criteria is met.
+	// +optional
+	RolloutBefore *RolloutBefore `json:"rolloutBefore,omitempty"`
+
+	// The RolloutStrategy to use to replace control plane machines with
+	// new ones.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
 	// NodeDrainTimeout is the total amount of time that the controller will spend on draining a controlplane node
 	// The default value is 0, meaning that the node can be drained without any time limitations.
 	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
 	// +optional
 	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// EtcdBackup configures periodic snapshots of the etcd cluster managed by the KubeadmControlPlane.
+	// It cannot be used when the control plane relies on an external etcd cluster.
+	// +optional
+	EtcdBackup *EtcdBackup `json:"etcdBackup,omitempty"`
+}
+
+// EtcdBackup defines the periodic snapshots of the etcd cluster managed by a KubeadmControlPlane.
+type EtcdBackup struct {
+	// Interval is the time between two consecutive etcd snapshots.
+	Interval metav1.Duration `json:"interval"`
+
+	// Retention is the number of etcd snapshots to keep; the oldest snapshots are deleted
+	// when this number is exceeded. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Retention *int32 `json:"retention,omitempty"`
+}
+
+// RolloutBefore describes when a rollout should be performed on the KCP machines.
+type RolloutBefore struct {
+	// CertificatesExpiryDays indicates a rollout needs to be performed if the
+	// certificates of the machine will expire within the specified days.
+	// +kubebuilder:validation:Minimum=7
+	// +optional
+	CertificatesExpiryDays *int32 `json:"certificatesExpiryDays,omitempty"`
+}
+
+// RolloutStrategy describes how to replace existing machines
+// with new ones.
+type RolloutStrategy struct {
+	// Type of rollout. Currently the only supported strategy is
+	// "RollingUpdate".
+	// Default is RollingUpdate.
+	// +kubebuilder:validation:Enum=RollingUpdate
+	// +optional
+	Type RolloutStrategyType `json:"type,omitempty"`
+
+	// Rolling update config params. Present only if
+	// RolloutStrategyType = RollingUpdate.
+	// +optional
+	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// RollingUpdate is used to control the desired behavior of rolling update.
+type RollingUpdate struct {
+	// The maximum number of control planes that can be scheduled above or under the
+	// desired number of control planes.
+	// Value can be an absolute number 1 or 0.
+	// Defaults to 1.
+	// Example: when this is set to 0, an old control plane machine is deleted first,
+	// after checking that the etcd quorum is preserved without it, and then its
+	// replacement is created; this requires at least 3 replicas.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
 }
 
 // KubeadmControlPlaneStatus defines the observed state of KubeadmControlPlane.
@@ -128,6 +216,15 @@ type KubeadmControlPlaneStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// LastEtcdSnapshotTime is the time the last etcd snapshot has been successfully taken and stored.
+	// +optional
+	LastEtcdSnapshotTime *metav1.Time `json:"lastEtcdSnapshotTime,omitempty"`
+
+	// CertificatesExpiryDate is the earliest expiry date of the certificates issued by kubeadm
+	// on the control plane machines.
+	// +optional
+	CertificatesExpiryDate *metav1.Time `json:"certificatesExpiryDate,omitempty"`
+
 	// Conditions defines current service state of the KubeadmControlPlane.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
@@ -28,7 +28,9 @@ import (
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/container"
@@ -64,6 +66,23 @@ func (in *KubeadmControlPlane) Default() {
 	if !strings.HasPrefix(in.Spec.Version, "v") {
 		in.Spec.Version = "v" + in.Spec.Version
 	}
+
+	if in.Spec.EtcdBackup != nil && in.Spec.EtcdBackup.Retention == nil {
+		in.Spec.EtcdBackup.Retention = pointer.Int32Ptr(3)
+	}
+
+	if in.Spec.RolloutStrategy != nil {
+		if in.Spec.RolloutStrategy.Type == "" {
+			in.Spec.RolloutStrategy.Type = RollingUpdateStrategyType
+		}
+		if in.Spec.RolloutStrategy.RollingUpdate == nil {
+			in.Spec.RolloutStrategy.RollingUpdate = &RollingUpdate{}
+		}
+		if in.Spec.RolloutStrategy.RollingUpdate.MaxSurge == nil {
+			maxSurge := intstr.FromInt(1)
+			in.Spec.RolloutStrategy.RollingUpdate.MaxSurge = &maxSurge
+		}
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
@@ -112,7 +131,10 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, "replicas"},
 		{spec, "version"},
 		{spec, "upgradeAfter"},
+		{spec, "rolloutBefore", "*"},
+		{spec, "rolloutStrategy", "*"},
 		{spec, "nodeDrainTimeout"},
+		{spec, "etcdBackup", "*"},
 	}
 
 	allErrs := in.validateCommon()
@@ -267,6 +289,99 @@ func (in *KubeadmControlPlane) validateCommon() (allErrs field.ErrorList) {
 	}
 
 	allErrs = append(allErrs, in.validateCoreDNSImage()...)
+	allErrs = append(allErrs, in.validateEtcdBackup(externalEtcd)...)
+	allErrs = append(allErrs, in.validateRolloutBefore()...)
+	allErrs = append(allErrs, in.validateRolloutStrategy()...)
+
+	return allErrs
+}
+
+func (in *KubeadmControlPlane) validateRolloutStrategy() (allErrs field.ErrorList) {
+	if in.Spec.RolloutStrategy == nil || in.Spec.RolloutStrategy.RollingUpdate == nil || in.Spec.RolloutStrategy.RollingUpdate.MaxSurge == nil {
+		return allErrs
+	}
+
+	maxSurge := *in.Spec.RolloutStrategy.RollingUpdate.MaxSurge
+	if maxSurge != intstr.FromInt(0) && maxSurge != intstr.FromInt(1) {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				field.NewPath("spec", "rolloutStrategy", "rollingUpdate", "maxSurge"),
+				maxSurge.String(),
+				"must be either 0 or 1",
+			),
+		)
+		return allErrs
+	}
+
+	if maxSurge.IntValue() == 0 && in.Spec.Replicas != nil && *in.Spec.Replicas < 3 {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				field.NewPath("spec", "rolloutStrategy", "rollingUpdate", "maxSurge"),
+				"cannot be 0 with less than 3 replicas, because deleting a control plane machine first would break the control plane",
+			),
+		)
+	}
+
+	return allErrs
+}
+
+func (in *KubeadmControlPlane) validateRolloutBefore() (allErrs field.ErrorList) {
+	if in.Spec.RolloutBefore == nil || in.Spec.RolloutBefore.CertificatesExpiryDays == nil {
+		return allErrs
+	}
+
+	if *in.Spec.RolloutBefore.CertificatesExpiryDays < 7 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				field.NewPath("spec", "rolloutBefore", "certificatesExpiryDays"),
+				*in.Spec.RolloutBefore.CertificatesExpiryDays,
+				"must be greater than or equal to 7",
+			),
+		)
+	}
+
+	return allErrs
+}
+
+func (in *KubeadmControlPlane) validateEtcdBackup(externalEtcd bool) (allErrs field.ErrorList) {
+	if in.Spec.EtcdBackup == nil {
+		return allErrs
+	}
+
+	if externalEtcd {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				field.NewPath("spec", "etcdBackup"),
+				"cannot be used with external etcd",
+			),
+		)
+	}
+
+	if in.Spec.EtcdBackup.Interval.Duration <= 0 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				field.NewPath("spec", "etcdBackup", "interval"),
+				in.Spec.EtcdBackup.Interval.Duration.String(),
+				"must be greater than 0",
+			),
+		)
+	}
+
+	if in.Spec.EtcdBackup.Retention != nil && *in.Spec.EtcdBackup.Retention < 1 {
+		allErrs = append(
+			allErrs,
+			field.Invalid(
+				field.NewPath("spec", "etcdBackup", "retention"),
+				*in.Spec.EtcdBackup.Retention,
+				"must be greater than or equal to 1",
+			),
+		)
+	}
 
 	return allErrs
 }
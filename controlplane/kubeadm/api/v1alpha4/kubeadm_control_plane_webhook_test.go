@@ -24,6 +24,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
 	kubeadmv1beta1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
@@ -45,6 +46,19 @@ func TestKubeadmControlPlaneDefault(t *testing.T) {
 
 	g.Expect(kcp.Spec.InfrastructureTemplate.Namespace).To(Equal(kcp.Namespace))
 	g.Expect(kcp.Spec.Version).To(Equal("v1.18.3"))
+	g.Expect(kcp.Spec.EtcdBackup).To(BeNil())
+
+	kcp.Spec.EtcdBackup = &EtcdBackup{Interval: metav1.Duration{Duration: time.Hour}}
+	kcp.Default()
+
+	g.Expect(kcp.Spec.EtcdBackup.Retention).To(Equal(pointer.Int32Ptr(3)))
+	g.Expect(kcp.Spec.RolloutStrategy).To(BeNil())
+
+	kcp.Spec.RolloutStrategy = &RolloutStrategy{}
+	kcp.Default()
+
+	g.Expect(kcp.Spec.RolloutStrategy.Type).To(Equal(RollingUpdateStrategyType))
+	g.Expect(kcp.Spec.RolloutStrategy.RollingUpdate.MaxSurge.IntValue()).To(Equal(1))
 }
 
 func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
@@ -92,6 +106,42 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	invalidVersion2 := valid.DeepCopy()
 	invalidVersion2.Spec.Version = "1.16.6"
 
+	validEtcdBackup := valid.DeepCopy()
+	validEtcdBackup.Spec.EtcdBackup = &EtcdBackup{
+		Interval:  metav1.Duration{Duration: time.Hour},
+		Retention: pointer.Int32Ptr(3),
+	}
+
+	invalidEtcdBackupInterval := validEtcdBackup.DeepCopy()
+	invalidEtcdBackupInterval.Spec.EtcdBackup.Interval = metav1.Duration{}
+
+	invalidEtcdBackupRetention := validEtcdBackup.DeepCopy()
+	invalidEtcdBackupRetention.Spec.EtcdBackup.Retention = pointer.Int32Ptr(0)
+
+	etcdBackupExternalEtcd := validEtcdBackup.DeepCopy()
+	etcdBackupExternalEtcd.Spec.KubeadmConfigSpec = evenReplicasExternalEtcd.Spec.KubeadmConfigSpec
+
+	validRolloutBefore := valid.DeepCopy()
+	validRolloutBefore.Spec.RolloutBefore = &RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(21)}
+
+	invalidRolloutBefore := valid.DeepCopy()
+	invalidRolloutBefore.Spec.RolloutBefore = &RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(5)}
+
+	maxSurge0 := intstr.FromInt(0)
+	validScaleInRolloutStrategy := valid.DeepCopy()
+	validScaleInRolloutStrategy.Spec.Replicas = pointer.Int32Ptr(3)
+	validScaleInRolloutStrategy.Spec.RolloutStrategy = &RolloutStrategy{
+		Type:          RollingUpdateStrategyType,
+		RollingUpdate: &RollingUpdate{MaxSurge: &maxSurge0},
+	}
+
+	scaleInRolloutStrategyTooFewReplicas := validScaleInRolloutStrategy.DeepCopy()
+	scaleInRolloutStrategyTooFewReplicas.Spec.Replicas = pointer.Int32Ptr(1)
+
+	maxSurge2 := intstr.FromInt(2)
+	invalidMaxSurge := validScaleInRolloutStrategy.DeepCopy()
+	invalidMaxSurge.Spec.RolloutStrategy.RollingUpdate.MaxSurge = &maxSurge2
+
 	tests := []struct {
 		name      string
 		expectErr bool
@@ -142,6 +192,51 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidVersion1,
 		},
+		{
+			name:      "should succeed when given a valid etcd backup",
+			expectErr: false,
+			kcp:       validEtcdBackup,
+		},
+		{
+			name:      "should return error when the etcd backup interval is zero",
+			expectErr: true,
+			kcp:       invalidEtcdBackupInterval,
+		},
+		{
+			name:      "should return error when the etcd backup retention is zero",
+			expectErr: true,
+			kcp:       invalidEtcdBackupRetention,
+		},
+		{
+			name:      "should return error when using etcd backup with external etcd",
+			expectErr: true,
+			kcp:       etcdBackupExternalEtcd,
+		},
+		{
+			name:      "should succeed when given a valid certificates expiry days",
+			expectErr: false,
+			kcp:       validRolloutBefore,
+		},
+		{
+			name:      "should return error when the certificates expiry days is less than 7",
+			expectErr: true,
+			kcp:       invalidRolloutBefore,
+		},
+		{
+			name:      "should succeed when scaling in with at least 3 replicas",
+			expectErr: false,
+			kcp:       validScaleInRolloutStrategy,
+		},
+		{
+			name:      "should return error when scaling in with less than 3 replicas",
+			expectErr: true,
+			kcp:       scaleInRolloutStrategyTooFewReplicas,
+		},
+		{
+			name:      "should return error when max surge is neither 0 nor 1",
+			expectErr: true,
+			kcp:       invalidMaxSurge,
+		},
 	}
 
 	for _, tt := range tests {
@@ -454,6 +549,23 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	disallowedUpgrade119Version := before.DeepCopy()
 	disallowedUpgrade119Version.Spec.Version = "v1.19.0"
 
+	etcdBackup := before.DeepCopy()
+	etcdBackup.Spec.EtcdBackup = &EtcdBackup{
+		Interval:  metav1.Duration{Duration: time.Hour},
+		Retention: pointer.Int32Ptr(5),
+	}
+
+	rolloutBefore := before.DeepCopy()
+	rolloutBefore.Spec.RolloutBefore = &RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(21)}
+
+	maxSurge0 := intstr.FromInt(0)
+	rolloutStrategy := before.DeepCopy()
+	rolloutStrategy.Spec.Replicas = pointer.Int32Ptr(3)
+	rolloutStrategy.Spec.RolloutStrategy = &RolloutStrategy{
+		Type:          RollingUpdateStrategyType,
+		RollingUpdate: &RollingUpdate{MaxSurge: &maxSurge0},
+	}
+
 	tests := []struct {
 		name      string
 		expectErr bool
@@ -466,6 +578,24 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    before,
 			kcp:       validUpdate,
 		},
+		{
+			name:      "should succeed when setting the certificates expiry days",
+			expectErr: false,
+			before:    before,
+			kcp:       rolloutBefore,
+		},
+		{
+			name:      "should succeed when setting the rollout strategy",
+			expectErr: false,
+			before:    before,
+			kcp:       rolloutStrategy,
+		},
+		{
+			name:      "should succeed when enabling etcd backup",
+			expectErr: false,
+			before:    before,
+			kcp:       etcdBackup,
+		},
 		{
 			name:      "should return error when trying to mutate the kubeadmconfigspec initconfiguration",
 			expectErr: true,
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/machinefilters"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// certificatesExpiryRefreshWindow is the time before the expiry of the certificates of a machine from which
+// the expiry date is read again on each reconciliation, in order to detect certificates renewed on the machine.
+const certificatesExpiryRefreshWindow = 30 * 24 * time.Hour
+
+// reconcileCertificateExpiries reads the expiry date of the certificates of the control plane machines that
+// don't have it yet, or whose certificates are about to expire, and it stores the date in the
+// CertificatesExpiryAnnotation of the machines; the annotation is then used to expose the certificates expiry
+// in the KCP status, and to roll out machines whose certificates are about to expire.
+func (r *KubeadmControlPlaneReconciler) reconcileCertificateExpiries(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	log := ctrl.LoggerFrom(ctx, "cluster", controlPlane.Cluster.Name)
+
+	machines := controlPlane.Machines.Filter(
+		machinefilters.Not(machinefilters.HasDeletionTimestamp),
+		needsCertificatesExpiryRefresh(controlPlane.KCP, time.Now()),
+	)
+	if len(machines) == 0 {
+		return nil
+	}
+
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
+	if err != nil {
+		return errors.Wrap(err, "failed to create client to workload cluster")
+	}
+
+	for _, machine := range machines {
+		// Certificates are issued when the node joins the cluster.
+		if machine.Status.NodeRef == nil {
+			continue
+		}
+
+		expiry, err := workloadCluster.GetAPIServerCertificateExpiry(ctx, machine.Status.NodeRef.Name, controlPlane.APIServerBindPort(machine))
+		if err != nil {
+			// The kube-apiserver might not be serving yet; the expiry date is read again on the next reconciliation.
+			log.V(2).Info("Failed to read the certificates expiry date of the control plane machine", "machine", machine.Name, "cause", err.Error())
+			continue
+		}
+
+		value := expiry.UTC().Format(time.RFC3339)
+		if machine.Annotations[controlplanev1.CertificatesExpiryAnnotation] == value {
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(machine, r.Client)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create patch helper for machine %s", machine.Name)
+		}
+		annotations := machine.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[controlplanev1.CertificatesExpiryAnnotation] = value
+		machine.SetAnnotations(annotations)
+		if err := patchHelper.Patch(ctx, machine); err != nil {
+			return errors.Wrapf(err, "failed to set the certificates expiry date on machine %s", machine.Name)
+		}
+	}
+	return nil
+}
+
+// needsCertificatesExpiryRefresh returns a filter to find the machines whose certificates expiry date is not known,
+// or is within the refresh window; the window is extended so the date is always read again before it triggers a rollout.
+func needsCertificatesExpiryRefresh(kcp *controlplanev1.KubeadmControlPlane, now time.Time) machinefilters.Func {
+	window := certificatesExpiryRefreshWindow
+	if kcp.Spec.RolloutBefore != nil && kcp.Spec.RolloutBefore.CertificatesExpiryDays != nil {
+		if rolloutWindow := time.Duration(*kcp.Spec.RolloutBefore.CertificatesExpiryDays+1) * 24 * time.Hour; rolloutWindow > window {
+			window = rolloutWindow
+		}
+	}
+	return func(machine *clusterv1.Machine) bool {
+		if machine == nil {
+			return false
+		}
+		value, ok := machine.Annotations[controlplanev1.CertificatesExpiryAnnotation]
+		if !ok {
+			return true
+		}
+		expiry, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return true
+		}
+		return expiry.Before(now.Add(window))
+	}
+}
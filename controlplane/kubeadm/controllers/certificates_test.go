@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+func TestKubeadmControlPlaneReconciler_reconcileCertificateExpiries(t *testing.T) {
+	expiry := time.Now().Add(365 * 24 * time.Hour).UTC().Truncate(time.Second)
+	farExpiry := time.Now().Add(200 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	setup := func(g *WithT, workload fakeWorkloadCluster) (*KubeadmControlPlaneReconciler, *internal.ControlPlane) {
+		cluster, kcp, _ := createClusterWithControlPlane()
+
+		withNode, _ := createMachineNodePair("with-node", cluster, kcp, true)
+		withExpiry, _ := createMachineNodePair("with-expiry", cluster, kcp, true)
+		withExpiry.SetAnnotations(map[string]string{controlplanev1.CertificatesExpiryAnnotation: farExpiry.Format(time.RFC3339)})
+		aboutToExpire, _ := createMachineNodePair("about-to-expire", cluster, kcp, true)
+		aboutToExpire.SetAnnotations(map[string]string{controlplanev1.CertificatesExpiryAnnotation: time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)})
+		withoutNode, _ := createMachineNodePair("without-node", cluster, kcp, true)
+		withoutNode.Status.NodeRef = nil
+
+		r := &KubeadmControlPlaneReconciler{
+			Client:            newFakeClient(g, cluster.DeepCopy(), kcp.DeepCopy(), withNode.DeepCopy(), withExpiry.DeepCopy(), aboutToExpire.DeepCopy(), withoutNode.DeepCopy()),
+			managementCluster: &fakeManagementCluster{Workload: workload},
+		}
+		controlPlane := &internal.ControlPlane{
+			Cluster:  cluster,
+			KCP:      kcp,
+			Machines: internal.NewFilterableMachineCollection(withNode, withExpiry, aboutToExpire, withoutNode),
+		}
+		return r, controlPlane
+	}
+
+	getAnnotation := func(g *WithT, r *KubeadmControlPlaneReconciler, controlPlane *internal.ControlPlane, name string) string {
+		machine := &clusterv1.Machine{}
+		g.Expect(r.Client.Get(ctx, util.ObjectKey(controlPlane.Machines[name]), machine)).To(Succeed())
+		return machine.Annotations[controlplanev1.CertificatesExpiryAnnotation]
+	}
+
+	t.Run("should store the certificates expiry date of the machines with a node", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane := setup(g, fakeWorkloadCluster{APIServerCertificateExpiry: &expiry})
+		g.Expect(r.reconcileCertificateExpiries(ctx, controlPlane)).To(Succeed())
+
+		g.Expect(getAnnotation(g, r, controlPlane, "with-node")).To(Equal(expiry.Format(time.RFC3339)))
+		g.Expect(getAnnotation(g, r, controlPlane, "with-expiry")).To(Equal(farExpiry.Format(time.RFC3339)))
+		g.Expect(getAnnotation(g, r, controlPlane, "without-node")).To(BeEmpty())
+		g.Expect(controlPlane.CertificatesExpiryDate().Time).To(Equal(farExpiry))
+	})
+
+	t.Run("should refresh the certificates expiry date of the machines about to expire", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane := setup(g, fakeWorkloadCluster{APIServerCertificateExpiry: &expiry})
+		g.Expect(r.reconcileCertificateExpiries(ctx, controlPlane)).To(Succeed())
+
+		g.Expect(getAnnotation(g, r, controlPlane, "about-to-expire")).To(Equal(expiry.Format(time.RFC3339)))
+	})
+
+	t.Run("should not fail if the certificates expiry date cannot be read", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane := setup(g, fakeWorkloadCluster{})
+		g.Expect(r.reconcileCertificateExpiries(ctx, controlPlane)).To(Succeed())
+
+		g.Expect(getAnnotation(g, r, controlPlane, "with-node")).To(BeEmpty())
+	})
+}
+
+func TestNeedsCertificatesExpiryRefresh(t *testing.T) {
+	now := time.Now()
+	withExpiry := func(expiry time.Time) *clusterv1.Machine {
+		return &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{controlplanev1.CertificatesExpiryAnnotation: expiry.UTC().Format(time.RFC3339)}}}
+	}
+	days := func(n int) time.Duration {
+		return time.Duration(n) * 24 * time.Hour
+	}
+
+	tests := []struct {
+		name          string
+		rolloutBefore *controlplanev1.RolloutBefore
+		machine       *clusterv1.Machine
+		want          bool
+	}{
+		{
+			name:    "machine without expiry date",
+			machine: &clusterv1.Machine{},
+			want:    true,
+		},
+		{
+			name:    "machine with an invalid expiry date",
+			machine: &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{controlplanev1.CertificatesExpiryAnnotation: "invalid"}}},
+			want:    true,
+		},
+		{
+			name:    "machine expiring after the refresh window",
+			machine: withExpiry(now.Add(days(60))),
+			want:    false,
+		},
+		{
+			name:    "machine expiring within the refresh window",
+			machine: withExpiry(now.Add(days(20))),
+			want:    true,
+		},
+		{
+			name:          "machine expiring within the rollout window",
+			rolloutBefore: &controlplanev1.RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(90)},
+			machine:       withExpiry(now.Add(days(60))),
+			want:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kcp := &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{RolloutBefore: tt.rolloutBefore}}
+			g.Expect(needsCertificatesExpiryRefresh(kcp, now)(tt.machine)).To(Equal(tt.want))
+		})
+	}
+}
@@ -51,8 +51,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io;controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch;create;update;patch;delete
@@ -65,6 +66,10 @@ type KubeadmControlPlaneReconciler struct {
 
 	managementCluster         internal.ManagementCluster
 	managementClusterUncached internal.ManagementCluster
+
+	// EtcdSnapshotStore stores the etcd snapshots taken by the etcd backup and restored by KCP; etcd backups
+	// and restores are disabled if it is not set.
+	EtcdSnapshotStore internal.EtcdSnapshotStore
 }
 
 func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -81,7 +86,11 @@ func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mg
 	err = c.Watch(
 		&source.Kind{Type: &clusterv1.Cluster{}},
 		handler.EnqueueRequestsFromMapFunc(r.ClusterToKubeadmControlPlane),
-		predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
+		// Rollouts waiting for a maintenance window start as soon as the maintenance windows change.
+		predicates.Any(ctrl.LoggerFrom(ctx),
+			predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
+			predicates.ClusterUpdateMaintenanceWindows(ctrl.LoggerFrom(ctx)),
+		),
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed adding Watch for Clusters to controller manager")
@@ -229,6 +238,7 @@ func patchKubeadmControlPlane(ctx context.Context, patchHelper *patch.Helper, kc
 			controlplanev1.MachinesReadyCondition,
 			controlplanev1.AvailableCondition,
 			controlplanev1.CertificatesAvailableCondition,
+			controlplanev1.EtcdBackupSucceededCondition,
 		}},
 	)
 }
@@ -299,6 +309,12 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 	// source ref (reason@machine/name) so the problem can be easily tracked down to its source machine.
 	conditions.SetAggregate(controlPlane.KCP, controlplanev1.MachinesReadyCondition, ownedMachines.ConditionGetters(), conditions.AddSourceRef(), conditions.WithStepCounterIf(false))
 
+	// Restoring the etcd cluster from a snapshot takes precedence over any other operation, given that it replaces
+	// all the existing control plane machines.
+	if _, ok := kcp.Annotations[controlplanev1.EtcdRestoreSnapshotAnnotation]; ok {
+		return r.reconcileEtcdRestore(ctx, controlPlane)
+	}
+
 	// Updates conditions reporting the status of static pods and the status of the etcd cluster.
 	// NOTE: Conditions reporting KCP operation progress like e.g. Resized or SpecUpToDate are inlined with the rest of the execution.
 	if result, err := r.reconcileControlPlaneConditions(ctx, controlPlane); err != nil || !result.IsZero() {
@@ -317,10 +333,25 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return result, err
 	}
 
-	// Control plane machines rollout due to configuration changes (e.g. upgrades) takes precedence over other operations.
+	// Reads the certificates expiry date of the control plane machines, so machines with certificates about to expire are rolled out.
+	if err := r.reconcileCertificateExpiries(ctx, controlPlane); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Control plane machines rollout due to configuration changes (e.g. upgrades) or expiring certificates takes precedence over other operations.
 	needRollout := controlPlane.MachinesNeedingRollout()
 	switch {
 	case len(needRollout) > 0:
+		// A new rollout step starts only while one of the Cluster's maintenance windows is open, except for machines
+		// whose certificates are about to expire; a step in progress, i.e. a machine already created to replace an
+		// outdated one or an outdated machine already deleted before creating its replacement, is always completed.
+		if int32(len(controlPlane.Machines)) == *kcp.Spec.Replicas {
+			var result ctrl.Result
+			result, needRollout, err = r.waitForMaintenanceWindow(ctx, cluster, controlPlane, needRollout, time.Now())
+			if err != nil || len(needRollout) == 0 {
+				return result, err
+			}
+		}
 		log.Info("Rolling out Control Plane machines", "needRollout", needRollout.Names())
 		conditions.MarkFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityWarning, "Rolling %d replicas with outdated spec (%d replicas up to date)", len(needRollout), len(controlPlane.Machines)-len(needRollout))
 		return r.upgradeControlPlane(ctx, cluster, kcp, controlPlane, needRollout)
@@ -379,7 +410,8 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return ctrl.Result{}, errors.Wrap(err, "failed to update CoreDNS deployment")
 	}
 
-	return ctrl.Result{}, nil
+	// Take a snapshot of the etcd cluster if an etcd backup is configured.
+	return r.reconcileEtcdBackup(ctx, controlPlane, workloadCluster)
 }
 
 // reconcileDelete handles KubeadmControlPlane deletion.
@@ -408,10 +440,13 @@ func (r *KubeadmControlPlaneReconciler) reconcileDelete(ctx context.Context, clu
 		return ctrl.Result{}, err
 	}
 
-	// Updates conditions reporting the status of static pods and the status of the etcd cluster.
+	// Updates conditions reporting the status of static pods and the status of the etcd cluster; this is skipped when
+	// the Cluster is deleted in orphan mode, because the workload cluster must be left untouched.
 	// NOTE: Ignoring failures given that we are deleting
-	if _, err := r.reconcileControlPlaneConditions(ctx, controlPlane); err != nil {
-		log.Info("failed to reconcile conditions", "error", err.Error())
+	if !annotations.IsOrphanDeletion(cluster) {
+		if _, err := r.reconcileControlPlaneConditions(ctx, controlPlane); err != nil {
+			log.Info("failed to reconcile conditions", "error", err.Error())
+		}
 	}
 
 	// Aggregate the operational state of all the machines; while aggregating we are adding the
@@ -1139,6 +1139,52 @@ func TestKubeadmControlPlaneReconciler_reconcileDelete(t *testing.T) {
 		g.Expect(kcp.Finalizers).To(BeEmpty())
 	})
 
+	t.Run("does not connect to the workload cluster when the Cluster is orphaned", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster, kcp, _ := createClusterWithControlPlane()
+		now := metav1.Now()
+		cluster.DeletionTimestamp = &now
+		cluster.Annotations = map[string]string{clusterv1.OrphanAnnotation: ""}
+		kcp.Status.Initialized = true
+		controllerutil.AddFinalizer(kcp, controlplanev1.KubeadmControlPlaneFinalizer)
+		initObjs := []client.Object{cluster.DeepCopy(), kcp.DeepCopy()}
+
+		for i := 0; i < 3; i++ {
+			m, _ := createMachineNodePair(fmt.Sprintf("test-%d", i), cluster, kcp, true)
+			initObjs = append(initObjs, m)
+		}
+
+		fakeClient := newFakeClient(g, initObjs...)
+
+		managementCluster := &workloadClusterCountingManagementCluster{
+			fakeManagementCluster: &fakeManagementCluster{
+				Management: &internal.Management{Client: fakeClient},
+				Workload:   fakeWorkloadCluster{},
+			},
+		}
+		r := &KubeadmControlPlaneReconciler{
+			Client:            fakeClient,
+			managementCluster: managementCluster,
+
+			recorder: record.NewFakeRecorder(32),
+		}
+
+		result, err := r.reconcileDelete(ctx, cluster, kcp)
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: deleteRequeueAfter}))
+		g.Expect(err).To(BeNil())
+
+		controlPlaneMachines := clusterv1.MachineList{}
+		g.Expect(fakeClient.List(ctx, &controlPlaneMachines)).To(Succeed())
+		g.Expect(controlPlaneMachines.Items).To(BeEmpty())
+
+		result, err = r.reconcileDelete(ctx, cluster, kcp)
+		g.Expect(result).To(Equal(ctrl.Result{}))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(kcp.Finalizers).To(BeEmpty())
+		g.Expect(managementCluster.workloadClusterCalls).To(BeZero())
+	})
+
 	t.Run("does not remove any control plane Machines if other Machines exist", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -1392,3 +1438,14 @@ func newCluster(namespacedName *types.NamespacedName) *clusterv1.Cluster {
 		},
 	}
 }
+
+// workloadClusterCountingManagementCluster counts the attempts to connect to the workload cluster.
+type workloadClusterCountingManagementCluster struct {
+	*fakeManagementCluster
+	workloadClusterCalls int
+}
+
+func (f *workloadClusterCountingManagementCluster) GetWorkloadCluster(ctx context.Context, key client.ObjectKey) (internal.WorkloadCluster, error) {
+	f.workloadClusterCalls++
+	return f.fakeManagementCluster.GetWorkloadCluster(ctx, key)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// reconcileEtcdBackup takes a snapshot of the etcd cluster when the interval defined in the KubeadmControlPlane
+// etcd backup has elapsed since the last snapshot, and it deletes the snapshots exceeding the retention.
+func (r *KubeadmControlPlaneReconciler) reconcileEtcdBackup(ctx context.Context, controlPlane *internal.ControlPlane, workloadCluster internal.WorkloadCluster) (ctrl.Result, error) {
+	logger := controlPlane.Logger()
+	kcp := controlPlane.KCP
+
+	// Snapshots are taken only for etcd clusters managed by KCP, after the control plane is initialized.
+	if kcp.Spec.EtcdBackup == nil || !controlPlane.IsEtcdManaged() || !kcp.Status.Initialized {
+		return ctrl.Result{}, nil
+	}
+
+	if r.EtcdSnapshotStore == nil {
+		logger.Info("Skipping etcd snapshot, no etcd snapshot store is configured")
+		conditions.MarkFalse(kcp, controlplanev1.EtcdBackupSucceededCondition, controlplanev1.EtcdSnapshotFailedReason, clusterv1.ConditionSeverityWarning,
+			"No etcd snapshot store is configured for the KubeadmControlPlane controller")
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	interval := kcp.Spec.EtcdBackup.Interval.Duration
+	if kcp.Status.LastEtcdSnapshotTime != nil {
+		if next := kcp.Status.LastEtcdSnapshotTime.Add(interval); now.Before(next) {
+			return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+		}
+	}
+
+	name := fmt.Sprintf("%s-etcd-%s", kcp.Name, now.UTC().Format("20060102150405"))
+	logger.Info("Taking etcd snapshot", "snapshot", name)
+
+	// Stream the snapshot from the workload cluster to the snapshot store, so the snapshot is never
+	// entirely held in memory unless the store requires it.
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(workloadCluster.EtcdSnapshot(ctx, writer))
+	}()
+	err := r.EtcdSnapshotStore.Save(ctx, controlPlane.Cluster, kcp, name, reader)
+	reader.Close()
+	if err != nil {
+		logger.Error(err, "Failed to save etcd snapshot", "snapshot", name)
+		conditions.MarkFalse(kcp, controlplanev1.EtcdBackupSucceededCondition, controlplanev1.EtcdSnapshotFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedEtcdSnapshot", "Failed to save etcd snapshot %s: %v", name, err)
+		return ctrl.Result{}, err
+	}
+
+	kcp.Status.LastEtcdSnapshotTime = &metav1.Time{Time: now}
+	conditions.MarkTrue(kcp, controlplanev1.EtcdBackupSucceededCondition)
+	r.recorder.Eventf(kcp, corev1.EventTypeNormal, "SuccessfulEtcdSnapshot", "Saved etcd snapshot %s", name)
+
+	if err := r.pruneEtcdSnapshots(ctx, kcp); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// pruneEtcdSnapshots deletes the oldest etcd snapshots exceeding the retention defined in the KubeadmControlPlane etcd backup.
+func (r *KubeadmControlPlaneReconciler) pruneEtcdSnapshots(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane) error {
+	retention := 3
+	if kcp.Spec.EtcdBackup.Retention != nil {
+		retention = int(*kcp.Spec.EtcdBackup.Retention)
+	}
+
+	snapshots, err := r.EtcdSnapshotStore.List(ctx, kcp)
+	if err != nil {
+		return errors.Wrap(err, "failed to list etcd snapshots")
+	}
+	for i := 0; i < len(snapshots)-retention; i++ {
+		// Never delete the snapshot a restore is in progress from.
+		if snapshots[i].Name == kcp.Annotations[controlplanev1.EtcdRestoreSnapshotAnnotation] {
+			continue
+		}
+		if err := r.EtcdSnapshotStore.Delete(ctx, kcp, snapshots[i].Name); err != nil {
+			return errors.Wrapf(err, "failed to delete etcd snapshot %s", snapshots[i].Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestKubeadmControlPlaneReconciler_reconcileEtcdBackup(t *testing.T) {
+	setup := func(g *WithT) (*KubeadmControlPlaneReconciler, *internal.ControlPlane) {
+		cluster, kcp, _ := createClusterWithControlPlane()
+		kcp.Spec.EtcdBackup = &controlplanev1.EtcdBackup{
+			Interval:  metav1.Duration{Duration: time.Hour},
+			Retention: pointer.Int32Ptr(2),
+		}
+		kcp.Status.Initialized = true
+
+		fakeClient := newFakeClient(g, cluster.DeepCopy(), kcp.DeepCopy())
+		r := &KubeadmControlPlaneReconciler{
+			Client:            fakeClient,
+			recorder:          record.NewFakeRecorder(32),
+			EtcdSnapshotStore: &internal.SecretEtcdSnapshotStore{Client: fakeClient},
+		}
+		controlPlane := &internal.ControlPlane{
+			Cluster: cluster,
+			KCP:     kcp,
+		}
+		return r, controlPlane
+	}
+
+	t.Run("should not take a snapshot if the etcd backup is not configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane := setup(g)
+		controlPlane.KCP.Spec.EtcdBackup = nil
+
+		result, err := r.reconcileEtcdBackup(ctx, controlPlane, fakeWorkloadCluster{EtcdSnapshotResult: []byte("snapshot")})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{}))
+
+		snapshots, err := r.EtcdSnapshotStore.List(ctx, controlPlane.KCP)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(BeEmpty())
+	})
+
+	t.Run("should not take a snapshot before the interval is elapsed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane := setup(g)
+		controlPlane.KCP.Status.LastEtcdSnapshotTime = &metav1.Time{Time: time.Now().Add(-30 * time.Minute)}
+
+		result, err := r.reconcileEtcdBackup(ctx, controlPlane, fakeWorkloadCluster{EtcdSnapshotResult: []byte("snapshot")})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Minute, time.Minute))
+
+		snapshots, err := r.EtcdSnapshotStore.List(ctx, controlPlane.KCP)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(BeEmpty())
+	})
+
+	t.Run("should take a snapshot and delete the snapshots exceeding the retention", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane := setup(g)
+		for i := 0; i < 2; i++ {
+			controlPlane.KCP.Status.LastEtcdSnapshotTime = nil
+			_, err := r.reconcileEtcdBackup(ctx, controlPlane, fakeWorkloadCluster{EtcdSnapshotResult: []byte("snapshot")})
+			g.Expect(err).ToNot(HaveOccurred())
+			// Snapshot names have a one second resolution.
+			time.Sleep(time.Second)
+		}
+		oldest, err := r.EtcdSnapshotStore.List(ctx, controlPlane.KCP)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(oldest).To(HaveLen(2))
+
+		controlPlane.KCP.Status.LastEtcdSnapshotTime = nil
+		result, err := r.reconcileEtcdBackup(ctx, controlPlane, fakeWorkloadCluster{EtcdSnapshotResult: []byte("snapshot")})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+		g.Expect(controlPlane.KCP.Status.LastEtcdSnapshotTime).ToNot(BeNil())
+		g.Expect(conditions.IsTrue(controlPlane.KCP, controlplanev1.EtcdBackupSucceededCondition)).To(BeTrue())
+
+		snapshots, err := r.EtcdSnapshotStore.List(ctx, controlPlane.KCP)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(HaveLen(2))
+		g.Expect(snapshots[0].Name).To(Equal(oldest[1].Name))
+	})
+
+	t.Run("should report that no snapshot store is configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane := setup(g)
+		r.EtcdSnapshotStore = nil
+
+		result, err := r.reconcileEtcdBackup(ctx, controlPlane, fakeWorkloadCluster{EtcdSnapshotResult: []byte("snapshot")})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{}))
+		g.Expect(controlPlane.KCP.Status.LastEtcdSnapshotTime).To(BeNil())
+		g.Expect(conditions.IsFalse(controlPlane.KCP, controlplanev1.EtcdBackupSucceededCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(controlPlane.KCP, controlplanev1.EtcdBackupSucceededCondition)).To(Equal(controlplanev1.EtcdSnapshotFailedReason))
+	})
+
+	t.Run("should report a failure to take a snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane := setup(g)
+
+		_, err := r.reconcileEtcdBackup(ctx, controlPlane, fakeWorkloadCluster{EtcdSnapshotErr: errors.New("etcd is down")})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(controlPlane.KCP.Status.LastEtcdSnapshotTime).To(BeNil())
+		g.Expect(conditions.IsFalse(controlPlane.KCP, controlplanev1.EtcdBackupSucceededCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(controlPlane.KCP, controlplanev1.EtcdBackupSucceededCondition)).To(Equal(controlplanev1.EtcdSnapshotFailedReason))
+
+		snapshots, err := r.EtcdSnapshotStore.List(ctx, controlPlane.KCP)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(BeEmpty())
+	})
+
+	t.Run("should not delete the snapshot being restored", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane := setup(g)
+		controlPlane.KCP.Spec.EtcdBackup.Retention = pointer.Int32Ptr(1)
+		_, err := r.reconcileEtcdBackup(ctx, controlPlane, fakeWorkloadCluster{EtcdSnapshotResult: []byte("snapshot")})
+		g.Expect(err).ToNot(HaveOccurred())
+		restoring, err := r.EtcdSnapshotStore.List(ctx, controlPlane.KCP)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(restoring).To(HaveLen(1))
+		controlPlane.KCP.Annotations = map[string]string{controlplanev1.EtcdRestoreSnapshotAnnotation: restoring[0].Name}
+		time.Sleep(time.Second)
+
+		controlPlane.KCP.Status.LastEtcdSnapshotTime = nil
+		_, err = r.reconcileEtcdBackup(ctx, controlPlane, fakeWorkloadCluster{EtcdSnapshotResult: []byte("snapshot")})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		snapshots, err := r.EtcdSnapshotStore.List(ctx, controlPlane.KCP)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(HaveLen(2))
+		g.Expect(snapshots[0].Name).To(Equal(restoring[0].Name))
+	})
+}
+
+func TestKubeadmControlPlaneReconciler_reconcileEtcdBackupSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster, kcp, _ := createClusterWithControlPlane()
+	kcp.Spec.EtcdBackup = &controlplanev1.EtcdBackup{Interval: metav1.Duration{Duration: time.Hour}}
+	kcp.Status.Initialized = true
+
+	fakeClient := newFakeClient(g, cluster.DeepCopy(), kcp.DeepCopy())
+	r := &KubeadmControlPlaneReconciler{
+		Client:            fakeClient,
+		recorder:          record.NewFakeRecorder(32),
+		EtcdSnapshotStore: &internal.SecretEtcdSnapshotStore{Client: fakeClient},
+	}
+
+	_, err := r.reconcileEtcdBackup(ctx, &internal.ControlPlane{Cluster: cluster, KCP: kcp}, fakeWorkloadCluster{EtcdSnapshotResult: []byte("snapshot")})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	secrets := &corev1.SecretList{}
+	g.Expect(fakeClient.List(ctx, secrets)).To(Succeed())
+	g.Expect(secrets.Items).To(HaveLen(1))
+	g.Expect(secrets.Items[0].Name).To(HavePrefix(kcp.Name + "-etcd-"))
+	g.Expect(secrets.Items[0].Labels).To(HaveKeyWithValue(internal.EtcdSnapshotLabelName, kcp.Name))
+	g.Expect(secrets.Items[0].OwnerReferences).To(HaveLen(1))
+	g.Expect(secrets.Items[0].OwnerReferences[0].Name).To(Equal(kcp.Name))
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// etcdSnapshotRestorePath is the path where the etcd snapshot is downloaded on the machine restoring it.
+	etcdSnapshotRestorePath = "/var/lib/etcd-snapshot.db"
+
+	// etcdRestoreScriptPath is the path of the script restoring the etcd snapshot on the machine restoring it.
+	etcdRestoreScriptPath = "/run/kubeadm/etcd-restore.sh"
+
+	// defaultEtcdDataDir is the etcd data directory used by kubeadm if not otherwise specified.
+	defaultEtcdDataDir = "/var/lib/etcd"
+)
+
+// etcdRestoreScript restores an etcd snapshot in the etcd data directory before kubeadm initializes the control plane.
+// The member name and peer URL of the restored member are the ones kubeadm uses for the etcd static pod: the node name,
+// defaulting to the lowercase hostname, and the advertise address, defaulting to the address of the default route
+// interface; this allows KCP to find the restored member by node name.
+// etcdctl is run from the etcd image used by kubeadm when it is not installed on the machine.
+const etcdRestoreScript = `#!/bin/bash
+set -euo pipefail
+
+SNAPSHOT=%s
+DATA_DIR=%s
+NAME=%s
+ADVERTISE_ADDRESS=%s
+
+if [ -z "${NAME}" ]; then
+  NAME="$(hostname | tr '[:upper:]' '[:lower:]')"
+fi
+if [ -z "${ADVERTISE_ADDRESS}" ]; then
+  DEVICE="$(ip -4 route show default | awk '{ print $5; exit }')"
+  ADVERTISE_ADDRESS="$(ip -4 -o addr show dev "${DEVICE}" scope global | awk '{ split($4, a, "/"); print a[1]; exit }')"
+fi
+PEER_URL="https://${ADVERTISE_ADDRESS}:2380"
+
+ARGS=(snapshot restore "${SNAPSHOT}" --data-dir "${DATA_DIR}" --name "${NAME}" --initial-cluster "${NAME}=${PEER_URL}" --initial-advertise-peer-urls "${PEER_URL}")
+
+if command -v etcdctl >/dev/null 2>&1; then
+  ETCDCTL_API=3 etcdctl "${ARGS[@]}"
+  exit 0
+fi
+
+for CONFIG in /run/kubeadm/kubeadm.yaml /etc/kubeadm/kubeadm.yaml; do
+  if [ -f "${CONFIG}" ]; then
+    IMAGE="$(kubeadm config images list --config "${CONFIG}" | grep '/etcd:')"
+  fi
+done
+ctr --namespace k8s.io images pull "${IMAGE}"
+ctr --namespace k8s.io run --rm --env ETCDCTL_API=3 \
+  --mount "type=bind,src=$(dirname "${SNAPSHOT}"),dst=$(dirname "${SNAPSHOT}"),options=rbind:rw" \
+  --mount "type=bind,src=$(dirname "${DATA_DIR}"),dst=$(dirname "${DATA_DIR}"),options=rbind:rw" \
+  "${IMAGE}" etcd-restore etcdctl "${ARGS[@]}"
+`
+
+// reconcileEtcdRestore restores the etcd cluster from the snapshot defined in the KubeadmControlPlane restore annotation.
+// The restore replaces all the existing control plane machines with a single machine initializing a new control plane
+// from the snapshot; once the restore is completed, the control plane is scaled up to the desired number of replicas
+// and rolled out as usual.
+func (r *KubeadmControlPlaneReconciler) reconcileEtcdRestore(ctx context.Context, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
+	logger := controlPlane.Logger()
+	kcp := controlPlane.KCP
+	snapshotName := kcp.Annotations[controlplanev1.EtcdRestoreSnapshotAnnotation]
+
+	// Snapshots can be restored only for etcd clusters managed by KCP.
+	if !controlPlane.IsEtcdManaged() {
+		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedEtcdRestore", "Ignoring the request to restore etcd snapshot %s, etcd is not managed by the control plane", snapshotName)
+		delete(kcp.Annotations, controlplanev1.EtcdRestoreSnapshotAnnotation)
+		return ctrl.Result{}, nil
+	}
+
+	var restoreMachine *clusterv1.Machine
+	for _, m := range controlPlane.Machines {
+		if m.Annotations[controlplanev1.EtcdRestoredFromSnapshotAnnotation] == snapshotName {
+			restoreMachine = m
+		}
+	}
+
+	// Generate the bootstrap configuration of the machine restoring the snapshot before deleting any machine,
+	// so the control plane is left untouched if the snapshot cannot be restored.
+	var bootstrapSpec *bootstrapv1.KubeadmConfigSpec
+	if restoreMachine == nil {
+		var err error
+		bootstrapSpec, err = r.etcdRestoreConfig(ctx, controlPlane, snapshotName)
+		if errors.Is(err, internal.ErrEtcdSnapshotDownloadNotSupported) {
+			r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedEtcdRestore", "Ignoring the request to restore etcd snapshot %s: %v", snapshotName, err)
+			delete(kcp.Annotations, controlplanev1.EtcdRestoreSnapshotAnnotation)
+			return ctrl.Result{}, nil
+		}
+		if err != nil {
+			r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedEtcdRestore", "Failed to restore etcd snapshot %s: %v", snapshotName, err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Delete all the machines not restoring the snapshot, and wait for them to go away.
+	machinesToDelete := 0
+	for _, m := range controlPlane.Machines {
+		if m == restoreMachine {
+			continue
+		}
+		machinesToDelete++
+		if !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.deleteMachineForEtcdRestore(ctx, m); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if machinesToDelete > 0 {
+		logger.Info("Waiting for control plane machines to be deleted before restoring etcd snapshot", "snapshot", snapshotName, "machines", machinesToDelete)
+		return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
+	}
+
+	// Create the machine restoring the snapshot.
+	if restoreMachine == nil {
+		logger.Info("Restoring etcd snapshot", "snapshot", snapshotName)
+		configAnnotations := map[string]string{bootstrapv1.ForceInitAnnotation: ""}
+		machineAnnotations := map[string]string{controlplanev1.EtcdRestoredFromSnapshotAnnotation: snapshotName}
+		fd := controlPlane.NextFailureDomainForScaleUp()
+		if err := r.cloneConfigsAndGenerateMachine(ctx, controlPlane.Cluster, kcp, bootstrapSpec, fd, configAnnotations, machineAnnotations); err != nil {
+			logger.Error(err, "Failed to create control plane Machine restoring etcd snapshot", "snapshot", snapshotName)
+			r.recorder.Eventf(kcp, corev1.EventTypeWarning, "FailedEtcdRestore", "Failed to create control plane Machine restoring etcd snapshot %s: %v", snapshotName, err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Wait for the machine restoring the snapshot to come up.
+	if restoreMachine.Status.NodeRef == nil {
+		logger.Info("Waiting for control plane Machine restoring etcd snapshot to have a node", "snapshot", snapshotName, "machine", restoreMachine.Name)
+		return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, nil
+	}
+
+	// Clean up the state of the workload cluster inherited from the snapshot.
+	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
+	if err != nil {
+		logger.V(2).Info("cannot get remote client to workload cluster, will requeue", "cause", err)
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if err := workloadCluster.ReconcileRestoredControlPlane(ctx, restoreMachine.Status.NodeRef.Name); err != nil {
+		logger.Info("Waiting for the restored control plane to become available", "snapshot", snapshotName, "cause", err.Error())
+		return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, nil
+	}
+
+	logger.Info("Restored etcd snapshot", "snapshot", snapshotName)
+	r.recorder.Eventf(kcp, corev1.EventTypeNormal, "SuccessfulEtcdRestore", "Restored etcd snapshot %s", snapshotName)
+	delete(kcp.Annotations, controlplanev1.EtcdRestoreSnapshotAnnotation)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// deleteMachineForEtcdRestore deletes a control plane machine being replaced by the etcd restore; node draining
+// is skipped, because the workload cluster is not expected to be operational.
+func (r *KubeadmControlPlaneReconciler) deleteMachineForEtcdRestore(ctx context.Context, machine *clusterv1.Machine) error {
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return err
+	}
+	if machine.Annotations == nil {
+		machine.Annotations = map[string]string{}
+	}
+	machine.Annotations[clusterv1.ExcludeNodeDrainingAnnotation] = ""
+	if err := patchHelper.Patch(ctx, machine); err != nil {
+		return errors.Wrapf(err, "failed to patch control plane Machine %s", machine.Name)
+	}
+	if err := r.Client.Delete(ctx, machine); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete control plane Machine %s", machine.Name)
+	}
+	return nil
+}
+
+// etcdRestoreConfig returns the bootstrap configuration for the machine restoring an etcd snapshot; the machine
+// downloads the snapshot from the snapshot store and restores it in the etcd data directory, then it initializes
+// a new control plane; the kubeadm preflight check on the etcd data directory is skipped by the bootstrap provider
+// thanks to the force init annotation.
+func (r *KubeadmControlPlaneReconciler) etcdRestoreConfig(ctx context.Context, controlPlane *internal.ControlPlane, snapshotName string) (*bootstrapv1.KubeadmConfigSpec, error) {
+	if r.EtcdSnapshotStore == nil {
+		return nil, errors.Wrap(internal.ErrEtcdSnapshotDownloadNotSupported, "no etcd snapshot store is configured")
+	}
+
+	downloadCommand, err := r.EtcdSnapshotStore.DownloadCommand(ctx, controlPlane.KCP, snapshotName, etcdSnapshotRestorePath)
+	if err != nil {
+		return nil, err
+	}
+
+	bootstrapSpec := controlPlane.InitialControlPlaneConfig()
+
+	bootstrapSpec.Files = append(bootstrapSpec.Files, bootstrapv1.File{
+		Path:        etcdRestoreScriptPath,
+		Owner:       "root:root",
+		Permissions: "0700",
+		Content:     etcdRestoreScriptFor(bootstrapSpec),
+	})
+	bootstrapSpec.PreKubeadmCommands = append(bootstrapSpec.PreKubeadmCommands,
+		downloadCommand,
+		fmt.Sprintf("bash %s", etcdRestoreScriptPath),
+		fmt.Sprintf("rm -f %s", etcdSnapshotRestorePath),
+	)
+	return bootstrapSpec, nil
+}
+
+// etcdRestoreScriptFor returns the script restoring the etcd snapshot for the given bootstrap configuration.
+func etcdRestoreScriptFor(bootstrapSpec *bootstrapv1.KubeadmConfigSpec) string {
+	dataDir := defaultEtcdDataDir
+	if bootstrapSpec.ClusterConfiguration != nil && bootstrapSpec.ClusterConfiguration.Etcd.Local != nil && bootstrapSpec.ClusterConfiguration.Etcd.Local.DataDir != "" {
+		dataDir = bootstrapSpec.ClusterConfiguration.Etcd.Local.DataDir
+	}
+	var name, advertiseAddress string
+	if bootstrapSpec.InitConfiguration != nil {
+		name = bootstrapSpec.InitConfiguration.NodeRegistration.Name
+		advertiseAddress = bootstrapSpec.InitConfiguration.LocalAPIEndpoint.AdvertiseAddress
+	}
+	return fmt.Sprintf(etcdRestoreScript,
+		internal.ShellQuote(etcdSnapshotRestorePath),
+		internal.ShellQuote(dataDir),
+		internal.ShellQuote(name),
+		internal.ShellQuote(advertiseAddress),
+	)
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
+	etcdutil "sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd/util"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestKubeadmControlPlaneReconciler_reconcileEtcdRestore(t *testing.T) {
+	const snapshotName = "snapshot-1"
+
+	objectStore := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer objectStore.Close()
+
+	setup := func(g *WithT, workload fakeWorkloadCluster, machines ...*clusterv1.Machine) (*KubeadmControlPlaneReconciler, *internal.ControlPlane, client.Client) {
+		cluster, kcp, genericMachineTemplate := createClusterWithControlPlane()
+		kcp.Annotations = map[string]string{controlplanev1.EtcdRestoreSnapshotAnnotation: snapshotName}
+
+		objs := []client.Object{cluster.DeepCopy(), kcp.DeepCopy(), genericMachineTemplate.DeepCopy()}
+		for _, m := range machines {
+			m.Namespace = cluster.Namespace
+			objs = append(objs, m.DeepCopy())
+		}
+		fakeClient := newFakeClient(g, objs...)
+
+		store := &internal.HTTPEtcdSnapshotStore{Client: fakeClient, URL: objectStore.URL}
+		g.Expect(store.Save(ctx, cluster, kcp, snapshotName, bytes.NewReader([]byte("snapshot")))).To(Succeed())
+
+		r := &KubeadmControlPlaneReconciler{
+			Client:            fakeClient,
+			recorder:          record.NewFakeRecorder(32),
+			EtcdSnapshotStore: store,
+			managementCluster: &fakeManagementCluster{
+				Management: &internal.Management{Client: fakeClient},
+				Workload:   workload,
+			},
+		}
+		controlPlane := &internal.ControlPlane{
+			Cluster:  cluster,
+			KCP:      kcp,
+			Machines: internal.NewFilterableMachineCollection(machines...),
+		}
+		return r, controlPlane, fakeClient
+	}
+
+	t.Run("should ignore the restore if etcd is external", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, _ := setup(g, fakeWorkloadCluster{})
+		controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration = &kubeadmv1.ClusterConfiguration{
+			Etcd: kubeadmv1.Etcd{External: &kubeadmv1.ExternalEtcd{Endpoints: []string{"https://etcd:2379"}}},
+		}
+
+		result, err := r.reconcileEtcdRestore(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{}))
+		g.Expect(controlPlane.KCP.Annotations).ToNot(HaveKey(controlplanev1.EtcdRestoreSnapshotAnnotation))
+	})
+
+	t.Run("should delete the existing machines without draining them", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, fakeClient := setup(g, fakeWorkloadCluster{}, machine("m1"), machine("m2"))
+
+		result, err := r.reconcileEtcdRestore(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: deleteRequeueAfter}))
+
+		for _, m := range controlPlane.Machines {
+			g.Expect(m.Annotations).To(HaveKey(clusterv1.ExcludeNodeDrainingAnnotation))
+			err := fakeClient.Get(ctx, util.ObjectKey(m), &clusterv1.Machine{})
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+	})
+
+	t.Run("should create a machine restoring the snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, fakeClient := setup(g, fakeWorkloadCluster{})
+
+		result, err := r.reconcileEtcdRestore(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{Requeue: true}))
+
+		machines := &clusterv1.MachineList{}
+		g.Expect(fakeClient.List(ctx, machines)).To(Succeed())
+		g.Expect(machines.Items).To(HaveLen(1))
+		g.Expect(machines.Items[0].Annotations).To(HaveKeyWithValue(controlplanev1.EtcdRestoredFromSnapshotAnnotation, snapshotName))
+
+		config := &bootstrapv1.KubeadmConfig{}
+		g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: machines.Items[0].Namespace, Name: machines.Items[0].Spec.Bootstrap.ConfigRef.Name}, config)).To(Succeed())
+		g.Expect(config.Annotations).To(HaveKey(bootstrapv1.ForceInitAnnotation))
+		g.Expect(config.Spec.JoinConfiguration).To(BeNil())
+		g.Expect(config.Spec.Files).To(HaveLen(1))
+		g.Expect(config.Spec.Files[0].Path).To(Equal(etcdRestoreScriptPath))
+		g.Expect(config.Spec.Files[0].ContentFrom).To(BeNil())
+		g.Expect(config.Spec.PreKubeadmCommands).To(Equal([]string{
+			fmt.Sprintf("curl --fail --silent --show-error --location --retry 5 --output '/var/lib/etcd-snapshot.db' '%s/%s/%s/%s'", objectStore.URL, controlPlane.KCP.Namespace, controlPlane.KCP.Name, snapshotName),
+			"bash /run/kubeadm/etcd-restore.sh",
+			"rm -f /var/lib/etcd-snapshot.db",
+		}))
+	})
+
+	t.Run("should not delete the existing machines if the snapshot cannot be downloaded", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, fakeClient := setup(g, fakeWorkloadCluster{}, machine("m1"))
+		r.EtcdSnapshotStore = &internal.SecretEtcdSnapshotStore{Client: fakeClient}
+
+		result, err := r.reconcileEtcdRestore(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{}))
+		g.Expect(controlPlane.KCP.Annotations).ToNot(HaveKey(controlplanev1.EtcdRestoreSnapshotAnnotation))
+
+		for _, m := range controlPlane.Machines {
+			g.Expect(fakeClient.Get(ctx, util.ObjectKey(m), &clusterv1.Machine{})).To(Succeed())
+		}
+	})
+
+	t.Run("should ignore the restore if no snapshot store is configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, fakeClient := setup(g, fakeWorkloadCluster{}, machine("m1"))
+		r.EtcdSnapshotStore = nil
+
+		result, err := r.reconcileEtcdRestore(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{}))
+		g.Expect(controlPlane.KCP.Annotations).ToNot(HaveKey(controlplanev1.EtcdRestoreSnapshotAnnotation))
+
+		for _, m := range controlPlane.Machines {
+			g.Expect(fakeClient.Get(ctx, util.ObjectKey(m), &clusterv1.Machine{})).To(Succeed())
+		}
+	})
+
+	t.Run("should fail if the snapshot does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, controlPlane, fakeClient := setup(g, fakeWorkloadCluster{}, machine("m1"))
+		controlPlane.KCP.Annotations[controlplanev1.EtcdRestoreSnapshotAnnotation] = "does-not-exist"
+
+		_, err := r.reconcileEtcdRestore(ctx, controlPlane)
+		g.Expect(err).To(HaveOccurred())
+
+		for _, m := range controlPlane.Machines {
+			g.Expect(fakeClient.Get(ctx, util.ObjectKey(m), &clusterv1.Machine{})).To(Succeed())
+		}
+	})
+
+	t.Run("should wait for the restored control plane to become available", func(t *testing.T) {
+		g := NewWithT(t)
+
+		restoreMachine := machine("m1", withRestoredFromSnapshot(snapshotName))
+		r, controlPlane, _ := setup(g, fakeWorkloadCluster{ReconcileRestoredControlPlaneErr: errors.New("etcd is not ready")}, restoreMachine)
+
+		result, err := r.reconcileEtcdRestore(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}))
+		g.Expect(controlPlane.KCP.Annotations).To(HaveKey(controlplanev1.EtcdRestoreSnapshotAnnotation))
+	})
+
+	t.Run("should complete the restore when the restored control plane is available", func(t *testing.T) {
+		g := NewWithT(t)
+
+		restoreMachine := machine("m1", withRestoredFromSnapshot(snapshotName))
+		r, controlPlane, _ := setup(g, fakeWorkloadCluster{}, restoreMachine)
+
+		result, err := r.reconcileEtcdRestore(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{Requeue: true}))
+		g.Expect(controlPlane.KCP.Annotations).ToNot(HaveKey(controlplanev1.EtcdRestoreSnapshotAnnotation))
+	})
+}
+
+func TestEtcdRestoreScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is required to run the etcd restore script")
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		spec     *bootstrapv1.KubeadmConfigSpec
+		nodeName string
+		peerURL  string
+	}{
+		{
+			name: "should name the member after the node name defined in the init configuration",
+			spec: &bootstrapv1.KubeadmConfigSpec{
+				InitConfiguration: &kubeadmv1.InitConfiguration{
+					NodeRegistration: kubeadmv1.NodeRegistrationOptions{Name: "control-plane-0"},
+					LocalAPIEndpoint: kubeadmv1.APIEndpoint{AdvertiseAddress: "10.0.0.1"},
+				},
+				ClusterConfiguration: &kubeadmv1.ClusterConfiguration{
+					Etcd: kubeadmv1.Etcd{Local: &kubeadmv1.LocalEtcd{DataDir: "/data/etcd"}},
+				},
+			},
+			nodeName: "control-plane-0",
+			peerURL:  "https://10.0.0.1:2380",
+		},
+		{
+			name: "should name the member after the lowercase hostname, like kubeadm",
+			spec: &bootstrapv1.KubeadmConfigSpec{
+				InitConfiguration: &kubeadmv1.InitConfiguration{
+					LocalAPIEndpoint: kubeadmv1.APIEndpoint{AdvertiseAddress: "10.0.0.1"},
+				},
+			},
+			nodeName: strings.ToLower(hostname),
+			peerURL:  "https://10.0.0.1:2380",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Run the script with a fake etcdctl recording its arguments.
+			dir := t.TempDir()
+			args := filepath.Join(dir, "args")
+			etcdctl := fmt.Sprintf("#!/bin/bash\nprintf '%%s\\n' \"$@\" > %s\n", args)
+			g.Expect(ioutil.WriteFile(filepath.Join(dir, "etcdctl"), []byte(etcdctl), 0700)).To(Succeed())
+			script := filepath.Join(dir, "etcd-restore.sh")
+			g.Expect(ioutil.WriteFile(script, []byte(etcdRestoreScriptFor(tt.spec)), 0600)).To(Succeed())
+
+			cmd := exec.Command("bash", script)
+			cmd.Env = append(os.Environ(), "PATH="+dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+			out, err := cmd.CombinedOutput()
+			g.Expect(err).ToNot(HaveOccurred(), string(out))
+
+			data, err := ioutil.ReadFile(args)
+			g.Expect(err).ToNot(HaveOccurred())
+			flags := map[string]string{}
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			for i := 0; i < len(lines)-1; i++ {
+				if strings.HasPrefix(lines[i], "--") {
+					flags[lines[i]] = lines[i+1]
+				}
+			}
+			g.Expect(flags).To(HaveKeyWithValue("--initial-cluster", tt.nodeName+"="+tt.peerURL))
+			g.Expect(flags).To(HaveKeyWithValue("--initial-advertise-peer-urls", tt.peerURL))
+
+			// KCP finds the restored member by the name of the node of the machine restoring the snapshot.
+			members := []*etcd.Member{{Name: flags["--name"], PeerURLs: []string{flags["--initial-advertise-peer-urls"]}}}
+			g.Expect(etcdutil.MemberForName(members, tt.nodeName)).ToNot(BeNil())
+		})
+	}
+}
+
+func withRestoredFromSnapshot(snapshotName string) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.Annotations = map[string]string{controlplanev1.EtcdRestoredFromSnapshotAnnotation: snapshotName}
+		m.Status.NodeRef = &corev1.ObjectReference{Kind: "Node", Name: m.Name}
+	}
+}
@@ -18,8 +18,11 @@ package controllers
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/blang/semver"
+	"github.com/pkg/errors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/machinefilters"
@@ -55,8 +58,12 @@ func (f *fakeManagementCluster) GetMachinesForCluster(c context.Context, n clien
 
 type fakeWorkloadCluster struct {
 	*internal.Workload
-	Status            internal.ClusterStatus
-	EtcdMembersResult []string
+	Status                           internal.ClusterStatus
+	EtcdMembersResult                []string
+	EtcdSnapshotResult               []byte
+	EtcdSnapshotErr                  error
+	ReconcileRestoredControlPlaneErr error
+	APIServerCertificateExpiry       *time.Time
 }
 
 func (f fakeWorkloadCluster) ForwardEtcdLeadership(_ context.Context, _ *clusterv1.Machine, _ *clusterv1.Machine) error {
@@ -107,6 +114,25 @@ func (f fakeWorkloadCluster) EtcdMembers(_ context.Context) ([]string, error) {
 	return f.EtcdMembersResult, nil
 }
 
+func (f fakeWorkloadCluster) EtcdSnapshot(_ context.Context, out io.Writer) error {
+	if f.EtcdSnapshotErr != nil {
+		return f.EtcdSnapshotErr
+	}
+	_, err := out.Write(f.EtcdSnapshotResult)
+	return err
+}
+
+func (f fakeWorkloadCluster) ReconcileRestoredControlPlane(_ context.Context, _ string) error {
+	return f.ReconcileRestoredControlPlaneErr
+}
+
+func (f fakeWorkloadCluster) GetAPIServerCertificateExpiry(_ context.Context, _ string, _ int32) (*time.Time, error) {
+	if f.APIServerCertificateExpiry == nil {
+		return nil, errors.New("no certificates served")
+	}
+	return f.APIServerCertificateExpiry, nil
+}
+
 type fakeMigrator struct {
 	migrateCalled    bool
 	migrateErr       error
@@ -18,13 +18,19 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/machinefilters"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/maintenance"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -91,9 +97,68 @@ func (r *KubeadmControlPlaneReconciler) upgradeControlPlane(
 		return ctrl.Result{}, err
 	}
 
-	if status.Nodes <= *kcp.Spec.Replicas {
+	maxSurge := rolloutMaxSurge(kcp)
+	if status.Nodes < *kcp.Spec.Replicas+maxSurge {
 		// scaleUp ensures that we don't continue scaling up while waiting for Machines to have NodeRefs
 		return r.scaleUpControlPlane(ctx, cluster, kcp, controlPlane)
 	}
+
+	// When scaling in, the outdated machine is deleted before its replacement gets created, so we must
+	// make sure that the etcd cluster does not lose quorum without it.
+	if maxSurge == 0 && controlPlane.IsEtcdManaged() {
+		machineToDelete, err := selectMachineForScaleDown(controlPlane, machinesRequireUpgrade)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to select machine for scale down")
+		}
+		canSafelyRemove, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, machineToDelete)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !canSafelyRemove {
+			logger.Info("Waiting for etcd to tolerate the removal of an outdated member before scaling in", "machine", machineToDelete.Name)
+			return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, nil
+		}
+	}
 	return r.scaleDownControlPlane(ctx, cluster, kcp, controlPlane, machinesRequireUpgrade)
 }
+
+// rolloutMaxSurge returns the number of machines that can be created above the desired number of replicas
+// while rolling out the control plane; it defaults to 1 when no rollout strategy is defined.
+func rolloutMaxSurge(kcp *controlplanev1.KubeadmControlPlane) int32 {
+	if kcp.Spec.RolloutStrategy == nil || kcp.Spec.RolloutStrategy.RollingUpdate == nil || kcp.Spec.RolloutStrategy.RollingUpdate.MaxSurge == nil {
+		return 1
+	}
+	return int32(kcp.Spec.RolloutStrategy.RollingUpdate.MaxSurge.IntValue())
+}
+
+// waitForMaintenanceWindow returns the machines with an outdated spec whose rollout can start: all of them while one
+// of the maintenance windows of the Cluster is open, otherwise only the ones whose certificates are about to expire
+// according to KCP.Spec.RolloutBefore, because waiting for the next maintenance window could let them expire.
+// If no rollout can start, the KubeadmControlPlane is requeued when the next maintenance window opens.
+func (r *KubeadmControlPlaneReconciler) waitForMaintenanceWindow(ctx context.Context, cluster *clusterv1.Cluster, controlPlane *internal.ControlPlane, machinesRequireUpgrade internal.FilterableMachineCollection, now time.Time) (ctrl.Result, internal.FilterableMachineCollection, error) {
+	logger := controlPlane.Logger()
+
+	open, next, err := maintenance.IsOpen(cluster, now)
+	if err != nil {
+		return ctrl.Result{}, nil, err
+	}
+	if open {
+		return ctrl.Result{}, machinesRequireUpgrade, nil
+	}
+
+	expiring := machinesRequireUpgrade.Filter(machinefilters.ShouldRolloutBefore(&metav1.Time{Time: now}, controlPlane.KCP.Spec.RolloutBefore))
+	if len(expiring) > 0 {
+		logger.Info("Rolling out Control Plane machines with expiring certificates outside of the maintenance windows", "machines", expiring.Names())
+		return ctrl.Result{}, expiring, nil
+	}
+
+	logger.Info("Waiting for a maintenance window to open before rolling out Control Plane machines", "needRollout", machinesRequireUpgrade.Names(), "next", next)
+	message := fmt.Sprintf("Waiting for a maintenance window to open to roll %d replicas with outdated spec", len(machinesRequireUpgrade))
+	result := ctrl.Result{}
+	if !next.IsZero() {
+		message = fmt.Sprintf("Waiting for the maintenance window opening at %s to roll %d replicas with outdated spec", next.UTC().Format(time.RFC3339), len(machinesRequireUpgrade))
+		result.RequeueAfter = next.Sub(now)
+	}
+	conditions.MarkFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition, clusterv1.WaitingForMaintenanceWindowReason, clusterv1.ConditionSeverityInfo, message)
+	return result, nil, nil
+}
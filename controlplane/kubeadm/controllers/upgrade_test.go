@@ -18,15 +18,20 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -120,6 +125,96 @@ func TestKubeadmControlPlaneReconciler_upgradeControlPlane(t *testing.T) {
 	g.Expect(finalMachine.Items[0].CreationTimestamp.Time).To(BeTemporally(">", initialMachine.Items[0].CreationTimestamp.Time))
 }
 
+func TestKubeadmControlPlaneReconciler_upgradeControlPlaneScaleIn(t *testing.T) {
+	setup := func(g *WithT, nodes int32, etcdMembers []string) (*KubeadmControlPlaneReconciler, *clusterv1.Cluster, *controlplanev1.KubeadmControlPlane, *internal.ControlPlane, client.Client) {
+		cluster, kcp, genericMachineTemplate := createClusterWithControlPlane()
+		cluster.Spec.ControlPlaneEndpoint.Host = "nodomain.example.com"
+		cluster.Spec.ControlPlaneEndpoint.Port = 6443
+		kcp.Spec.Version = "v1.17.4"
+		kcp.Spec.KubeadmConfigSpec.ClusterConfiguration = nil
+		kcp.Spec.Replicas = pointer.Int32Ptr(3)
+		maxSurge := intstr.FromInt(0)
+		kcp.Spec.RolloutStrategy = &controlplanev1.RolloutStrategy{
+			Type: controlplanev1.RollingUpdateStrategyType,
+			RollingUpdate: &controlplanev1.RollingUpdate{
+				MaxSurge: &maxSurge,
+			},
+		}
+		setKCPHealthy(kcp)
+
+		objs := []client.Object{cluster.DeepCopy(), kcp.DeepCopy(), genericMachineTemplate.DeepCopy()}
+		machines := internal.FilterableMachineCollection{}
+		for i := 0; i < 3; i++ {
+			m, _ := createMachineNodePair(fmt.Sprintf("test-%d", i), cluster, kcp, true)
+			setMachineHealthy(m)
+			objs = append(objs, m.DeepCopy())
+			machines[m.Name] = m
+		}
+		fakeClient := newFakeClient(g, objs...)
+
+		r := &KubeadmControlPlaneReconciler{
+			Client:   fakeClient,
+			recorder: record.NewFakeRecorder(32),
+			managementCluster: &fakeManagementCluster{
+				Management: &internal.Management{Client: fakeClient},
+				Workload: fakeWorkloadCluster{
+					Status:            internal.ClusterStatus{Nodes: nodes},
+					EtcdMembersResult: etcdMembers,
+				},
+			},
+		}
+		controlPlane := &internal.ControlPlane{
+			KCP:      kcp,
+			Cluster:  cluster,
+			Machines: machines,
+		}
+		return r, cluster, kcp, controlPlane, fakeClient
+	}
+
+	t.Run("deletes an outdated machine before creating its replacement", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, cluster, kcp, controlPlane, fakeClient := setup(g, 3, []string{"test-0", "test-1", "test-2"})
+
+		result, err := r.upgradeControlPlane(ctx, cluster, kcp, controlPlane, controlPlane.Machines)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{Requeue: true}))
+
+		machines := &clusterv1.MachineList{}
+		g.Expect(fakeClient.List(ctx, machines, client.InNamespace(cluster.Namespace))).To(Succeed())
+		g.Expect(machines.Items).To(HaveLen(2))
+	})
+
+	t.Run("creates the replacement once the outdated machine is gone", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, cluster, kcp, controlPlane, fakeClient := setup(g, 2, []string{"test-0", "test-1", "test-2"})
+
+		result, err := r.upgradeControlPlane(ctx, cluster, kcp, controlPlane, controlPlane.Machines)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{Requeue: true}))
+
+		machines := &clusterv1.MachineList{}
+		g.Expect(fakeClient.List(ctx, machines, client.InNamespace(cluster.Namespace))).To(Succeed())
+		g.Expect(machines.Items).To(HaveLen(4))
+	})
+
+	t.Run("waits when removing an etcd member would lose quorum", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The member without a corresponding machine is considered unhealthy.
+		r, cluster, kcp, controlPlane, fakeClient := setup(g, 3, []string{"test-0", "test-1", "unknown"})
+
+		result, err := r.upgradeControlPlane(ctx, cluster, kcp, controlPlane, controlPlane.Machines)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}))
+
+		machines := &clusterv1.MachineList{}
+		g.Expect(fakeClient.List(ctx, machines, client.InNamespace(cluster.Namespace))).To(Succeed())
+		g.Expect(machines.Items).To(HaveLen(3))
+	})
+}
+
 type machineOpt func(*clusterv1.Machine)
 
 func machine(name string, opts ...machineOpt) *clusterv1.Machine {
@@ -134,3 +229,65 @@ func machine(name string, opts ...machineOpt) *clusterv1.Machine {
 	}
 	return m
 }
+
+func TestKubeadmControlPlaneReconciler_waitForMaintenanceWindow(t *testing.T) {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	setup := func(annotations map[string]string) (*KubeadmControlPlaneReconciler, *clusterv1.Cluster, *internal.ControlPlane) {
+		cluster, kcp, _ := createClusterWithControlPlane()
+		cluster.Annotations = annotations
+		cluster.Spec.MaintenanceWindows = []clusterv1.MaintenanceWindow{
+			{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		}
+		machines := internal.FilterableMachineCollection{}
+		for i := 0; i < 3; i++ {
+			m, _ := createMachineNodePair(fmt.Sprintf("test-%d", i), cluster, kcp, true)
+			machines[m.Name] = m
+		}
+		controlPlane := &internal.ControlPlane{
+			KCP:      kcp,
+			Cluster:  cluster,
+			Machines: machines,
+		}
+		return &KubeadmControlPlaneReconciler{recorder: record.NewFakeRecorder(32)}, cluster, controlPlane
+	}
+
+	t.Run("waits for the next maintenance window", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, cluster, controlPlane := setup(nil)
+
+		result, machines, err := r.waitForMaintenanceWindow(ctx, cluster, controlPlane, controlPlane.Machines, now)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(machines).To(BeEmpty())
+		g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: 10 * time.Hour}))
+		g.Expect(conditions.IsFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition)).To(Equal(clusterv1.WaitingForMaintenanceWindowReason))
+	})
+
+	t.Run("does not wait if the Cluster has the override annotation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, cluster, controlPlane := setup(map[string]string{clusterv1.MaintenanceWindowOverrideAnnotation: ""})
+
+		result, machines, err := r.waitForMaintenanceWindow(ctx, cluster, controlPlane, controlPlane.Machines, now)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(machines).To(Equal(controlPlane.Machines))
+		g.Expect(result.IsZero()).To(BeTrue())
+	})
+
+	t.Run("does not wait for machines whose certificates are about to expire", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r, cluster, controlPlane := setup(nil)
+		controlPlane.KCP.Spec.RolloutBefore = &controlplanev1.RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(7)}
+		expiring := controlPlane.Machines["test-0"]
+		expiring.Annotations = map[string]string{controlplanev1.CertificatesExpiryAnnotation: now.Add(3 * 24 * time.Hour).Format(time.RFC3339)}
+		controlPlane.Machines["test-1"].Annotations = map[string]string{controlplanev1.CertificatesExpiryAnnotation: now.Add(300 * 24 * time.Hour).Format(time.RFC3339)}
+
+		result, machines, err := r.waitForMaintenanceWindow(ctx, cluster, controlPlane, controlPlane.Machines, now)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(machines.Names()).To(ConsistOf(expiring.Name))
+		g.Expect(result.IsZero()).To(BeTrue())
+	})
+}
@@ -132,6 +132,7 @@ func (m *Management) GetWorkloadCluster(ctx context.Context, clusterKey client.O
 		Client:              c,
 		CoreDNSMigrator:     &CoreDNSMigrator{},
 		etcdClientGenerator: NewEtcdClientGenerator(restConfig, tlsConfig),
+		restConfig:          restConfig,
 	}, nil
 }
 
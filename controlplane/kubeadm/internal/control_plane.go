@@ -18,6 +18,7 @@ package internal
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -255,6 +256,8 @@ func (c *ControlPlane) MachinesNeedingRollout() FilterableMachineCollection {
 	return machines.AnyFilter(
 		// Machines that are scheduled for rollout (KCP.Spec.UpgradeAfter set, the UpgradeAfter deadline is expired, and the machine was created before the deadline).
 		machinefilters.ShouldRolloutAfter(&c.reconciliationTime, c.KCP.Spec.UpgradeAfter),
+		// Machines whose certificates are about to expire (KCP.Spec.RolloutBefore.CertificatesExpiryDays set and the certificates expire within the specified days).
+		machinefilters.ShouldRolloutBefore(&c.reconciliationTime, c.KCP.Spec.RolloutBefore),
 		// Machines that do not match with KCP config.
 		machinefilters.Not(machinefilters.MatchesKCPConfiguration(c.infraResources, c.kubeadmConfigs, c.KCP)),
 	)
@@ -266,6 +269,43 @@ func (c *ControlPlane) UpToDateMachines() FilterableMachineCollection {
 	return c.Machines.Difference(c.MachinesNeedingRollout())
 }
 
+// CertificatesExpiryDate returns the earliest expiry date of the certificates of the control plane machines,
+// as stored in the CertificatesExpiryAnnotation of the machines, or nil if it is not known for any machine.
+func (c *ControlPlane) CertificatesExpiryDate() *metav1.Time {
+	var earliest *metav1.Time
+	for _, m := range c.Machines {
+		value, ok := m.Annotations[controlplanev1.CertificatesExpiryAnnotation]
+		if !ok {
+			continue
+		}
+		expiry, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			continue
+		}
+		if earliest == nil || expiry.Before(earliest.Time) {
+			earliest = &metav1.Time{Time: expiry}
+		}
+	}
+	return earliest
+}
+
+// DefaultAPIServerBindPort is the port the kube-apiserver binds to if not otherwise specified in the kubeadm configuration.
+const DefaultAPIServerBindPort = 6443
+
+// APIServerBindPort returns the port the kube-apiserver of the given machine binds to, as defined in the
+// kubeadm configuration the machine has been bootstrapped with.
+func (c *ControlPlane) APIServerBindPort(machine *clusterv1.Machine) int32 {
+	if config, ok := c.kubeadmConfigs[machine.Name]; ok {
+		if config.Spec.InitConfiguration != nil && config.Spec.InitConfiguration.LocalAPIEndpoint.BindPort != 0 {
+			return config.Spec.InitConfiguration.LocalAPIEndpoint.BindPort
+		}
+		if config.Spec.JoinConfiguration != nil && config.Spec.JoinConfiguration.ControlPlane != nil && config.Spec.JoinConfiguration.ControlPlane.LocalAPIEndpoint.BindPort != 0 {
+			return config.Spec.JoinConfiguration.ControlPlane.LocalAPIEndpoint.BindPort
+		}
+	}
+	return DefaultAPIServerBindPort
+}
+
 // getInfraResources fetches the external infrastructure resource for each machine in the collection and returns a map of machine.Name -> infraResource.
 func getInfraResources(ctx context.Context, cl client.Client, machines FilterableMachineCollection) (map[string]*unstructured.Unstructured, error) {
 	result := map[string]*unstructured.Unstructured{}
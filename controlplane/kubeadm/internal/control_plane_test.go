@@ -18,6 +18,7 @@ package internal
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -27,6 +28,7 @@ import (
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1alpha4"
+	kubeadmv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
@@ -131,3 +133,54 @@ func TestHasUnhealthyMachine(t *testing.T) {
 	g := NewWithT(t)
 	g.Expect(c.HasUnhealthyMachine()).To(BeTrue())
 }
+
+func TestCertificatesExpiryDate(t *testing.T) {
+	g := NewWithT(t)
+
+	c := ControlPlane{
+		Machines: NewFilterableMachineCollection(
+			machine("machine-1", withCertificatesExpiry("2022-03-01T00:00:00Z")),
+			machine("machine-2", withCertificatesExpiry("2022-01-01T00:00:00Z")),
+			machine("machine-3", withCertificatesExpiry("invalid")),
+			machine("machine-4"),
+		),
+	}
+	g.Expect(c.CertificatesExpiryDate().Time).To(Equal(time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)))
+
+	c = ControlPlane{
+		Machines: NewFilterableMachineCollection(machine("machine-1")),
+	}
+	g.Expect(c.CertificatesExpiryDate()).To(BeNil())
+}
+
+func withCertificatesExpiry(expiry string) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.SetAnnotations(map[string]string{controlplanev1.CertificatesExpiryAnnotation: expiry})
+	}
+}
+
+func TestAPIServerBindPort(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := &ControlPlane{
+		kubeadmConfigs: map[string]*bootstrapv1.KubeadmConfig{
+			"init": {Spec: bootstrapv1.KubeadmConfigSpec{
+				InitConfiguration: &kubeadmv1.InitConfiguration{LocalAPIEndpoint: kubeadmv1.APIEndpoint{BindPort: 8443}},
+			}},
+			"join": {Spec: bootstrapv1.KubeadmConfigSpec{
+				JoinConfiguration: &kubeadmv1.JoinConfiguration{ControlPlane: &kubeadmv1.JoinControlPlane{LocalAPIEndpoint: kubeadmv1.APIEndpoint{BindPort: 9443}}},
+			}},
+			"default": {Spec: bootstrapv1.KubeadmConfigSpec{
+				JoinConfiguration: &kubeadmv1.JoinConfiguration{ControlPlane: &kubeadmv1.JoinControlPlane{}},
+			}},
+		},
+	}
+	machine := func(name string) *clusterv1.Machine {
+		return &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	g.Expect(controlPlane.APIServerBindPort(machine("init"))).To(Equal(int32(8443)))
+	g.Expect(controlPlane.APIServerBindPort(machine("join"))).To(Equal(int32(9443)))
+	g.Expect(controlPlane.APIServerBindPort(machine("default"))).To(Equal(int32(DefaultAPIServerBindPort)))
+	g.Expect(controlPlane.APIServerBindPort(machine("without-config"))).To(Equal(int32(DefaultAPIServerBindPort)))
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package fake
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"io/ioutil"
 
 	"go.etcd.io/etcd/clientv3"
 )
@@ -30,6 +33,7 @@ type FakeEtcdClient struct {
 	MemberUpdateResponse *clientv3.MemberUpdateResponse
 	MoveLeaderResponse   *clientv3.MoveLeaderResponse
 	StatusResponse       *clientv3.StatusResponse
+	SnapshotResponse     []byte
 	ErrorResponse        error
 	MovedLeader          uint64
 	RemovedMember        uint64
@@ -62,6 +66,9 @@ func (c *FakeEtcdClient) MemberRemove(_ context.Context, i uint64) (*clientv3.Me
 func (c *FakeEtcdClient) MemberUpdate(_ context.Context, _ uint64, _ []string) (*clientv3.MemberUpdateResponse, error) {
 	return c.MemberUpdateResponse, c.ErrorResponse
 }
+func (c *FakeEtcdClient) Snapshot(_ context.Context) (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(c.SnapshotResponse)), c.ErrorResponse
+}
 func (c *FakeEtcdClient) Status(_ context.Context, _ string) (*clientv3.StatusResponse, error) {
 	return c.StatusResponse, nil
 }
@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// EtcdSnapshotLabelName is the label set on the objects storing the etcd snapshots of a KubeadmControlPlane;
+	// the label value is the name of the KubeadmControlPlane.
+	EtcdSnapshotLabelName = "controlplane.cluster.x-k8s.io/etcd-snapshot"
+
+	// etcdSnapshotSecretKey is the key of the Secret data holding the gzipped etcd snapshot.
+	etcdSnapshotSecretKey = "snapshot"
+
+	// maxEtcdSnapshotSecretSize is the maximum size of a gzipped etcd snapshot stored in a Secret; it is slightly
+	// lower than the maximum size of a Secret in order to leave room for the object metadata.
+	maxEtcdSnapshotSecretSize = 1000 * 1024
+)
+
+var (
+	// ErrEtcdSnapshotTooLarge is returned when an etcd snapshot exceeds the size supported by a snapshot store.
+	ErrEtcdSnapshotTooLarge = errors.New("etcd snapshot exceeds the maximum size supported by the snapshot store")
+
+	// ErrEtcdSnapshotDownloadNotSupported is returned by snapshot stores whose snapshots cannot be downloaded
+	// by the machines restoring them.
+	ErrEtcdSnapshotDownloadNotSupported = errors.New("etcd snapshots cannot be downloaded from the snapshot store by the machines")
+)
+
+// EtcdSnapshot describes an etcd snapshot saved in an EtcdSnapshotStore.
+type EtcdSnapshot struct {
+	// Name is the name of the snapshot.
+	Name string
+
+	// CreationTimestamp is the time the snapshot has been saved.
+	CreationTimestamp metav1.Time
+}
+
+// EtcdSnapshotStore defines the storage for the etcd snapshots of a KubeadmControlPlane.
+type EtcdSnapshotStore interface {
+	// Save stores an etcd snapshot with the given name.
+	Save(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, name string, snapshot io.Reader) error
+
+	// List returns the etcd snapshots stored for a KubeadmControlPlane, from the oldest to the newest.
+	List(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane) ([]EtcdSnapshot, error)
+
+	// Delete deletes the etcd snapshot with the given name.
+	Delete(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, name string) error
+
+	// DownloadCommand returns the shell command downloading the etcd snapshot with the given name to the given path
+	// on the machine restoring it.
+	DownloadCommand(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, name, path string) (string, error)
+}
+
+// SecretEtcdSnapshotStore is an EtcdSnapshotStore storing each etcd snapshot in a Secret in the management cluster,
+// in the same namespace of the KubeadmControlPlane; snapshots are gzipped, and they must not exceed the size of a Secret.
+// The machines cannot reach the management cluster, so the snapshots of this store cannot be restored by KCP; for this
+// reason the store must be explicitly enabled, and it is meant for small or test clusters only.
+type SecretEtcdSnapshotStore struct {
+	Client ctrlclient.Client
+}
+
+var _ EtcdSnapshotStore = &SecretEtcdSnapshotStore{}
+
+// Save stores an etcd snapshot in a Secret owned by the KubeadmControlPlane.
+func (s *SecretEtcdSnapshotStore) Save(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, name string, snapshot io.Reader) error {
+	// Secret data is stored as is, so the gzipped snapshot doesn't need any further encoding.
+	data := &limitedBuffer{max: maxEtcdSnapshotSecretSize}
+	compressor := gzip.NewWriter(data)
+	if _, err := io.Copy(compressor, snapshot); err != nil {
+		return errors.Wrapf(err, "failed to compress etcd snapshot %s", name)
+	}
+	if err := compressor.Close(); err != nil {
+		return errors.Wrapf(err, "failed to compress etcd snapshot %s", name)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: kcp.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: cluster.Name,
+				EtcdSnapshotLabelName:      kcp.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind("KubeadmControlPlane")),
+			},
+		},
+		Type: clusterv1.ClusterSecretType,
+		Data: map[string][]byte{
+			etcdSnapshotSecretKey: data.Bytes(),
+		},
+	}
+	if err := s.Client.Create(ctx, secret); err != nil {
+		return errors.Wrapf(err, "failed to create Secret for etcd snapshot %s", name)
+	}
+	return nil
+}
+
+// List returns the etcd snapshots stored in Secrets for a KubeadmControlPlane, from the oldest to the newest.
+func (s *SecretEtcdSnapshotStore) List(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane) ([]EtcdSnapshot, error) {
+	secrets := &corev1.SecretList{}
+	if err := s.Client.List(ctx, secrets, ctrlclient.InNamespace(kcp.Namespace), ctrlclient.MatchingLabels{EtcdSnapshotLabelName: kcp.Name}); err != nil {
+		return nil, errors.Wrap(err, "failed to list etcd snapshot Secrets")
+	}
+
+	snapshots := make([]EtcdSnapshot, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		snapshots = append(snapshots, EtcdSnapshot{
+			Name:              secret.Name,
+			CreationTimestamp: secret.CreationTimestamp,
+		})
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if snapshots[i].CreationTimestamp.Equal(&snapshots[j].CreationTimestamp) {
+			return snapshots[i].Name < snapshots[j].Name
+		}
+		return snapshots[i].CreationTimestamp.Before(&snapshots[j].CreationTimestamp)
+	})
+	return snapshots, nil
+}
+
+// Delete deletes the Secret storing an etcd snapshot.
+func (s *SecretEtcdSnapshotStore) Delete(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, name string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: kcp.Namespace,
+		},
+	}
+	if err := s.Client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete Secret for etcd snapshot %s", name)
+	}
+	return nil
+}
+
+// DownloadCommand always fails, because the machines cannot read the Secrets in the management cluster.
+func (s *SecretEtcdSnapshotStore) DownloadCommand(_ context.Context, _ *controlplanev1.KubeadmControlPlane, name, _ string) (string, error) {
+	return "", errors.Wrapf(ErrEtcdSnapshotDownloadNotSupported, "failed to download etcd snapshot %s stored in a Secret", name)
+}
+
+// limitedBuffer is a bytes.Buffer failing writes exceeding the given maximum size.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		return 0, ErrEtcdSnapshotTooLarge
+	}
+	return b.Buffer.Write(p)
+}
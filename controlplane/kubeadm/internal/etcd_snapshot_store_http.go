@@ -0,0 +1,303 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// etcdSnapshotURLKey is the key of the ConfigMap data holding the URL of an etcd snapshot.
+	etcdSnapshotURLKey = "url"
+
+	// etcdSnapshotIVKey is the key of the ConfigMap data holding the hex encoded initialization vector of an
+	// encrypted etcd snapshot.
+	etcdSnapshotIVKey = "iv"
+
+	// EtcdSnapshotStoreAuthorizationKey is the key of the credentials Secret data holding the value of the
+	// Authorization header sent to the object store.
+	EtcdSnapshotStoreAuthorizationKey = "authorization"
+
+	// EtcdSnapshotStoreEncryptionKeyKey is the key of the credentials Secret data holding the 32 bytes AES-256 key
+	// used to encrypt the etcd snapshots before uploading them.
+	EtcdSnapshotStoreEncryptionKeyKey = "encryptionKey"
+)
+
+// HTTPEtcdSnapshotStore is an EtcdSnapshotStore uploading each etcd snapshot to an object store exposing an HTTP
+// API, e.g. a bucket accepting PUT, GET and DELETE requests; the snapshot of a KubeadmControlPlane is stored at
+// <URL>/<namespace>/<kcp name>/<snapshot name>.
+// The snapshots are indexed by ConfigMaps in the management cluster, in the same namespace of the KubeadmControlPlane,
+// and they are downloaded from the object store by the machines restoring them, which must be able to reach it.
+// Snapshots contain all the secrets of the workload cluster, so the object store must not be publicly accessible:
+// the credentials Secret can provide the Authorization header sent with each request, including the downloads,
+// and a key encrypting the snapshots with AES-256-CBC before they leave the management cluster.
+type HTTPEtcdSnapshotStore struct {
+	Client ctrlclient.Client
+
+	// HTTPClient is the client used to reach the object store; it defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// URL is the base URL of the object store.
+	URL string
+
+	// CredentialsSecret is the optional Secret holding the credentials for the object store and the snapshot
+	// encryption key, respectively in the EtcdSnapshotStoreAuthorizationKey and EtcdSnapshotStoreEncryptionKeyKey keys.
+	CredentialsSecret *ctrlclient.ObjectKey
+}
+
+// httpEtcdSnapshotStoreCredentials are the credentials read from the HTTPEtcdSnapshotStore credentials Secret.
+type httpEtcdSnapshotStoreCredentials struct {
+	authorization string
+	encryptionKey []byte
+}
+
+var _ EtcdSnapshotStore = &HTTPEtcdSnapshotStore{}
+
+// Save uploads an etcd snapshot to the object store and indexes it with a ConfigMap owned by the KubeadmControlPlane.
+func (s *HTTPEtcdSnapshotStore) Save(ctx context.Context, cluster *clusterv1.Cluster, kcp *controlplanev1.KubeadmControlPlane, name string, snapshot io.Reader) error {
+	credentials, err := s.credentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The snapshot is buffered, so the request has a Content-Length as required by most object stores.
+	data, err := ioutil.ReadAll(snapshot)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read etcd snapshot %s", name)
+	}
+	var iv []byte
+	if credentials.encryptionKey != nil {
+		data, iv, err = encryptEtcdSnapshot(credentials.encryptionKey, data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encrypt etcd snapshot %s", name)
+		}
+	}
+
+	url := s.snapshotURL(kcp, name)
+	if err := s.do(ctx, credentials, http.MethodPut, url, data); err != nil {
+		return errors.Wrapf(err, "failed to upload etcd snapshot %s", name)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: kcp.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: cluster.Name,
+				EtcdSnapshotLabelName:      kcp.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind("KubeadmControlPlane")),
+			},
+		},
+		Data: map[string]string{
+			etcdSnapshotURLKey: url,
+		},
+	}
+	if iv != nil {
+		configMap.Data[etcdSnapshotIVKey] = hex.EncodeToString(iv)
+	}
+	if err := s.Client.Create(ctx, configMap); err != nil {
+		return errors.Wrapf(err, "failed to create ConfigMap for etcd snapshot %s", name)
+	}
+	return nil
+}
+
+// List returns the etcd snapshots indexed by ConfigMaps for a KubeadmControlPlane, from the oldest to the newest.
+func (s *HTTPEtcdSnapshotStore) List(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane) ([]EtcdSnapshot, error) {
+	configMaps := &corev1.ConfigMapList{}
+	if err := s.Client.List(ctx, configMaps, ctrlclient.InNamespace(kcp.Namespace), ctrlclient.MatchingLabels{EtcdSnapshotLabelName: kcp.Name}); err != nil {
+		return nil, errors.Wrap(err, "failed to list etcd snapshot ConfigMaps")
+	}
+
+	snapshots := make([]EtcdSnapshot, 0, len(configMaps.Items))
+	for _, configMap := range configMaps.Items {
+		snapshots = append(snapshots, EtcdSnapshot{
+			Name:              configMap.Name,
+			CreationTimestamp: configMap.CreationTimestamp,
+		})
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if snapshots[i].CreationTimestamp.Equal(&snapshots[j].CreationTimestamp) {
+			return snapshots[i].Name < snapshots[j].Name
+		}
+		return snapshots[i].CreationTimestamp.Before(&snapshots[j].CreationTimestamp)
+	})
+	return snapshots, nil
+}
+
+// Delete deletes an etcd snapshot from the object store, and then the ConfigMap indexing it.
+func (s *HTTPEtcdSnapshotStore) Delete(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, name string) error {
+	credentials, err := s.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.do(ctx, credentials, http.MethodDelete, s.snapshotURL(kcp, name), nil); err != nil {
+		return errors.Wrapf(err, "failed to delete etcd snapshot %s", name)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: kcp.Namespace,
+		},
+	}
+	if err := s.Client.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete ConfigMap for etcd snapshot %s", name)
+	}
+	return nil
+}
+
+// DownloadCommand returns a curl command downloading the etcd snapshot from the object store, followed by an openssl
+// command decrypting it if the snapshot is encrypted.
+// NOTE: the credentials are embedded in the command, and therefore in the bootstrap data of the machine restoring the
+// snapshot; that machine has access to the whole content of the snapshot anyway.
+func (s *HTTPEtcdSnapshotStore) DownloadCommand(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, name, path string) (string, error) {
+	credentials, err := s.credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := s.Client.Get(ctx, ctrlclient.ObjectKey{Namespace: kcp.Namespace, Name: name}, configMap); err != nil {
+		return "", errors.Wrapf(err, "failed to get ConfigMap for etcd snapshot %s", name)
+	}
+	url, ok := configMap.Data[etcdSnapshotURLKey]
+	if !ok {
+		return "", errors.Errorf("ConfigMap %s does not reference an etcd snapshot", name)
+	}
+
+	curl := "curl --fail --silent --show-error --location --retry 5"
+	if credentials.authorization != "" {
+		curl += " --header " + ShellQuote("Authorization: "+credentials.authorization)
+	}
+
+	iv, encrypted := configMap.Data[etcdSnapshotIVKey]
+	if !encrypted {
+		return fmt.Sprintf("%s --output %s %s", curl, ShellQuote(path), ShellQuote(url)), nil
+	}
+	if credentials.encryptionKey == nil {
+		return "", errors.Errorf("etcd snapshot %s is encrypted, but no encryption key is configured", name)
+	}
+	encryptedPath := path + ".enc"
+	return fmt.Sprintf("%s --output %s %s && openssl enc -d -aes-256-cbc -K %s -iv %s -in %s -out %s && rm -f %s",
+		curl, ShellQuote(encryptedPath), ShellQuote(url),
+		hex.EncodeToString(credentials.encryptionKey), ShellQuote(iv),
+		ShellQuote(encryptedPath), ShellQuote(path), ShellQuote(encryptedPath)), nil
+}
+
+// credentials reads the credentials for the object store from the credentials Secret, if any.
+func (s *HTTPEtcdSnapshotStore) credentials(ctx context.Context) (*httpEtcdSnapshotStoreCredentials, error) {
+	credentials := &httpEtcdSnapshotStoreCredentials{}
+	if s.CredentialsSecret == nil {
+		return credentials, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := s.Client.Get(ctx, *s.CredentialsSecret, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to get etcd snapshot store credentials Secret %s", s.CredentialsSecret)
+	}
+	credentials.authorization = string(secret.Data[EtcdSnapshotStoreAuthorizationKey])
+	if key, ok := secret.Data[EtcdSnapshotStoreEncryptionKeyKey]; ok {
+		if len(key) != 32 {
+			return nil, errors.Errorf("etcd snapshot encryption key in Secret %s must be 32 bytes long", s.CredentialsSecret)
+		}
+		credentials.encryptionKey = key
+	}
+	return credentials, nil
+}
+
+func (s *HTTPEtcdSnapshotStore) snapshotURL(kcp *controlplanev1.KubeadmControlPlane, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", strings.TrimSuffix(s.URL, "/"), kcp.Namespace, kcp.Name, name)
+}
+
+// do sends a request to the object store; a missing object is not an error when deleting it.
+func (s *HTTPEtcdSnapshotStore) do(ctx context.Context, credentials *httpEtcdSnapshotStoreCredentials, method, url string, body []byte) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	if credentials.authorization != "" {
+		req.Header.Set("Authorization", credentials.authorization)
+	}
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("%s %s returned %s", method, url, resp.Status)
+	}
+	return nil
+}
+
+// encryptEtcdSnapshot encrypts an etcd snapshot with AES-256-CBC and PKCS#7 padding, the format decrypted by
+// openssl enc; it returns the encrypted snapshot and the random initialization vector used.
+func encryptEtcdSnapshot(key, snapshot []byte) ([]byte, []byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, err
+	}
+
+	padding := aes.BlockSize - len(snapshot)%aes.BlockSize
+	data := make([]byte, len(snapshot), len(snapshot)+padding)
+	copy(data, snapshot)
+	data = append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+	return data, iv, nil
+}
+
+// ShellQuote quotes a string so that it is interpreted literally by a POSIX shell.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
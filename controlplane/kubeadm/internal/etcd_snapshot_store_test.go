@@ -0,0 +1,327 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSecretEtcdSnapshotStore(t *testing.T) {
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster"}}
+	kcp := &controlplanev1.KubeadmControlPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kcp", UID: "uid"}}
+
+	t.Run("should save, list and delete snapshots", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := fake.NewClientBuilder().Build()
+		store := &SecretEtcdSnapshotStore{Client: fakeClient}
+
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader([]byte("snapshot-1")))).To(Succeed())
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-2", bytes.NewReader([]byte("snapshot-2")))).To(Succeed())
+
+		snapshots, err := store.List(ctx, kcp)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(HaveLen(2))
+		g.Expect(snapshots[0].Name).To(Equal("kcp-etcd-1"))
+		g.Expect(snapshots[1].Name).To(Equal("kcp-etcd-2"))
+
+		secret := &corev1.Secret{}
+		g.Expect(fakeClient.Get(ctx, ctrlclient.ObjectKey{Namespace: "default", Name: "kcp-etcd-1"}, secret)).To(Succeed())
+		g.Expect(secret.Type).To(Equal(clusterv1.ClusterSecretType))
+		g.Expect(secret.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "cluster"))
+		g.Expect(secret.Labels).To(HaveKeyWithValue(EtcdSnapshotLabelName, "kcp"))
+		g.Expect(decodeSnapshot(g, secret.Data[etcdSnapshotSecretKey])).To(Equal([]byte("snapshot-1")))
+
+		g.Expect(store.Delete(ctx, kcp, "kcp-etcd-1")).To(Succeed())
+		g.Expect(store.Delete(ctx, kcp, "kcp-etcd-1")).To(Succeed())
+
+		snapshots, err = store.List(ctx, kcp)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(HaveLen(1))
+		g.Expect(snapshots[0].Name).To(Equal("kcp-etcd-2"))
+	})
+
+	t.Run("should fail to save a snapshot exceeding the size of a Secret", func(t *testing.T) {
+		g := NewWithT(t)
+
+		store := &SecretEtcdSnapshotStore{Client: fake.NewClientBuilder().Build()}
+
+		// Random data can't be compressed.
+		data := make([]byte, maxEtcdSnapshotSecretSize)
+		_, err := rand.Read(data)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader(data))
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring(ErrEtcdSnapshotTooLarge.Error()))
+	})
+
+	t.Run("should not support downloading snapshots", func(t *testing.T) {
+		g := NewWithT(t)
+
+		store := &SecretEtcdSnapshotStore{Client: fake.NewClientBuilder().Build()}
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader([]byte("snapshot-1")))).To(Succeed())
+
+		_, err := store.DownloadCommand(ctx, kcp, "kcp-etcd-1", "/tmp/snapshot.db")
+		g.Expect(errors.Is(err, ErrEtcdSnapshotDownloadNotSupported)).To(BeTrue())
+	})
+}
+
+func TestHTTPEtcdSnapshotStore(t *testing.T) {
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster"}}
+	kcp := &controlplanev1.KubeadmControlPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kcp", UID: "uid"}}
+
+	// objectStore is a minimal object store accepting PUT, GET and DELETE requests; uploads must have a Content-Length,
+	// and all the requests must be authorized if an authorization is set.
+	objectStore := func(authorization ...string) (*httptest.Server, map[string][]byte) {
+		var lock sync.Mutex
+		objects := map[string][]byte{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+			if len(authorization) > 0 && r.Header.Get("Authorization") != authorization[0] {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch r.Method {
+			case http.MethodPut:
+				if r.ContentLength < 0 {
+					w.WriteHeader(http.StatusLengthRequired)
+					return
+				}
+				data, _ := ioutil.ReadAll(r.Body)
+				objects[r.URL.Path] = data
+			case http.MethodGet:
+				data, ok := objects[r.URL.Path]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write(data)
+			case http.MethodDelete:
+				if _, ok := objects[r.URL.Path]; !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				delete(objects, r.URL.Path)
+			}
+		}))
+		return server, objects
+	}
+
+	t.Run("should save, list and delete snapshots", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server, objects := objectStore()
+		defer server.Close()
+		fakeClient := fake.NewClientBuilder().Build()
+		store := &HTTPEtcdSnapshotStore{Client: fakeClient, URL: server.URL + "/snapshots/"}
+
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader([]byte("snapshot-1")))).To(Succeed())
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-2", bytes.NewReader([]byte("snapshot-2")))).To(Succeed())
+		g.Expect(objects).To(HaveKeyWithValue("/snapshots/default/kcp/kcp-etcd-1", []byte("snapshot-1")))
+
+		snapshots, err := store.List(ctx, kcp)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(HaveLen(2))
+		g.Expect(snapshots[0].Name).To(Equal("kcp-etcd-1"))
+		g.Expect(snapshots[1].Name).To(Equal("kcp-etcd-2"))
+
+		configMap := &corev1.ConfigMap{}
+		g.Expect(fakeClient.Get(ctx, ctrlclient.ObjectKey{Namespace: "default", Name: "kcp-etcd-1"}, configMap)).To(Succeed())
+		g.Expect(configMap.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "cluster"))
+		g.Expect(configMap.Labels).To(HaveKeyWithValue(EtcdSnapshotLabelName, "kcp"))
+
+		g.Expect(store.Delete(ctx, kcp, "kcp-etcd-1")).To(Succeed())
+		g.Expect(store.Delete(ctx, kcp, "kcp-etcd-1")).To(Succeed())
+		g.Expect(objects).ToNot(HaveKey("/snapshots/default/kcp/kcp-etcd-1"))
+
+		snapshots, err = store.List(ctx, kcp)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(HaveLen(1))
+		g.Expect(snapshots[0].Name).To(Equal("kcp-etcd-2"))
+	})
+
+	t.Run("should not index a snapshot that failed to upload", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+		store := &HTTPEtcdSnapshotStore{Client: fake.NewClientBuilder().Build(), URL: server.URL}
+
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader([]byte("snapshot-1")))).ToNot(Succeed())
+		snapshots, err := store.List(ctx, kcp)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(snapshots).To(BeEmpty())
+	})
+
+	t.Run("should return the command downloading a snapshot", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server, _ := objectStore()
+		defer server.Close()
+		store := &HTTPEtcdSnapshotStore{Client: fake.NewClientBuilder().Build(), URL: server.URL}
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader([]byte("snapshot-1")))).To(Succeed())
+
+		command, err := store.DownloadCommand(ctx, kcp, "kcp-etcd-1", "/tmp/snapshot.db")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(command).To(Equal(fmt.Sprintf("curl --fail --silent --show-error --location --retry 5 --output '/tmp/snapshot.db' '%s/default/kcp/kcp-etcd-1'", server.URL)))
+
+		_, err = store.DownloadCommand(ctx, kcp, "does-not-exist", "/tmp/snapshot.db")
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should authorize the requests with the credentials Secret", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server, objects := objectStore("Bearer token")
+		defer server.Close()
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "capi-system", Name: "etcd-snapshot-store"},
+			Data:       map[string][]byte{EtcdSnapshotStoreAuthorizationKey: []byte("Bearer token")},
+		}
+		store := &HTTPEtcdSnapshotStore{
+			Client:            fake.NewClientBuilder().WithObjects(credentials).Build(),
+			URL:               server.URL,
+			CredentialsSecret: &ctrlclient.ObjectKey{Namespace: "capi-system", Name: "etcd-snapshot-store"},
+		}
+
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader([]byte("snapshot-1")))).To(Succeed())
+		g.Expect(objects).To(HaveKeyWithValue("/default/kcp/kcp-etcd-1", []byte("snapshot-1")))
+
+		command, err := store.DownloadCommand(ctx, kcp, "kcp-etcd-1", "/tmp/snapshot.db")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(command).To(Equal(fmt.Sprintf("curl --fail --silent --show-error --location --retry 5 --header 'Authorization: Bearer token' --output '/tmp/snapshot.db' '%s/default/kcp/kcp-etcd-1'", server.URL)))
+
+		g.Expect(store.Delete(ctx, kcp, "kcp-etcd-1")).To(Succeed())
+		g.Expect(objects).ToNot(HaveKey("/default/kcp/kcp-etcd-1"))
+	})
+
+	t.Run("should fail if the credentials Secret does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server, objects := objectStore()
+		defer server.Close()
+		store := &HTTPEtcdSnapshotStore{
+			Client:            fake.NewClientBuilder().Build(),
+			URL:               server.URL,
+			CredentialsSecret: &ctrlclient.ObjectKey{Namespace: "capi-system", Name: "etcd-snapshot-store"},
+		}
+
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader([]byte("snapshot-1")))).ToNot(Succeed())
+		g.Expect(objects).To(BeEmpty())
+	})
+
+	t.Run("should encrypt snapshots with the encryption key", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server, objects := objectStore()
+		defer server.Close()
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		g.Expect(err).ToNot(HaveOccurred())
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "capi-system", Name: "etcd-snapshot-store"},
+			Data:       map[string][]byte{EtcdSnapshotStoreEncryptionKeyKey: key},
+		}
+		store := &HTTPEtcdSnapshotStore{
+			Client:            fake.NewClientBuilder().WithObjects(credentials).Build(),
+			URL:               server.URL,
+			CredentialsSecret: &ctrlclient.ObjectKey{Namespace: "capi-system", Name: "etcd-snapshot-store"},
+		}
+
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader([]byte("snapshot-1")))).To(Succeed())
+		g.Expect(objects).To(HaveKey("/default/kcp/kcp-etcd-1"))
+		g.Expect(objects["/default/kcp/kcp-etcd-1"]).To(HaveLen(16))
+		g.Expect(bytes.Contains(objects["/default/kcp/kcp-etcd-1"], []byte("snapshot-1"))).To(BeFalse())
+
+		command, err := store.DownloadCommand(ctx, kcp, "kcp-etcd-1", "/tmp/snapshot.db")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(command).To(ContainSubstring("openssl enc -d -aes-256-cbc"))
+
+		// Run the download command, if the tools it relies on are available, to check that the snapshot is decrypted.
+		for _, tool := range []string{"bash", "curl", "openssl"} {
+			if _, err := exec.LookPath(tool); err != nil {
+				t.Skipf("%s is not available", tool)
+			}
+		}
+		dir, err := ioutil.TempDir("", "etcd-snapshot")
+		g.Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "snapshot.db")
+		command, err = store.DownloadCommand(ctx, kcp, "kcp-etcd-1", path)
+		g.Expect(err).ToNot(HaveOccurred())
+		out, err := exec.Command("bash", "-c", command).CombinedOutput()
+		g.Expect(err).ToNot(HaveOccurred(), string(out))
+		g.Expect(ioutil.ReadFile(path)).To(Equal([]byte("snapshot-1")))
+		g.Expect(path + ".enc").ToNot(BeAnExistingFile())
+	})
+
+	t.Run("should reject an encryption key of the wrong size", func(t *testing.T) {
+		g := NewWithT(t)
+
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "capi-system", Name: "etcd-snapshot-store"},
+			Data:       map[string][]byte{EtcdSnapshotStoreEncryptionKeyKey: []byte("too-short")},
+		}
+		store := &HTTPEtcdSnapshotStore{
+			Client:            fake.NewClientBuilder().WithObjects(credentials).Build(),
+			URL:               "https://objects.example.com",
+			CredentialsSecret: &ctrlclient.ObjectKey{Namespace: "capi-system", Name: "etcd-snapshot-store"},
+		}
+
+		g.Expect(store.Save(ctx, cluster, kcp, "kcp-etcd-1", bytes.NewReader([]byte("snapshot-1")))).ToNot(Succeed())
+	})
+}
+
+func TestShellQuote(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(ShellQuote("/tmp/snapshot.db")).To(Equal("'/tmp/snapshot.db'"))
+	g.Expect(ShellQuote("it's")).To(Equal(`'it'\''s'`))
+}
+
+func decodeSnapshot(g *WithT, data []byte) []byte {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	g.Expect(err).ToNot(HaveOccurred())
+	snapshot, err := ioutil.ReadAll(reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	return snapshot
+}
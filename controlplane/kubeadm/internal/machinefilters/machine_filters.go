@@ -19,6 +19,7 @@ package machinefilters
 import (
 	"encoding/json"
 	"reflect"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -171,6 +172,26 @@ func ShouldRolloutAfter(reconciliationTime, rolloutAfter *metav1.Time) Func {
 	}
 }
 
+// ShouldRolloutBefore returns a filter to find all machines whose certificates, according to the
+// CertificatesExpiryAnnotation, expire within rolloutBefore.CertificatesExpiryDays from reconciliationTime.
+func ShouldRolloutBefore(reconciliationTime *metav1.Time, rolloutBefore *controlplanev1.RolloutBefore) Func {
+	return func(machine *clusterv1.Machine) bool {
+		if machine == nil || reconciliationTime == nil || rolloutBefore == nil || rolloutBefore.CertificatesExpiryDays == nil {
+			return false
+		}
+		value, ok := machine.Annotations[controlplanev1.CertificatesExpiryAnnotation]
+		if !ok {
+			return false
+		}
+		expiry, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return false
+		}
+		rolloutTime := expiry.Add(-time.Duration(*rolloutBefore.CertificatesExpiryDays) * 24 * time.Hour)
+		return !reconciliationTime.Time.Before(rolloutTime)
+	}
+}
+
 // HasAnnotationKey returns a filter to find all machines that have the
 // specified Annotation key present
 func HasAnnotationKey(key string) Func {
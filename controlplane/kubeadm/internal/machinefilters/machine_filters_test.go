@@ -167,6 +167,47 @@ func TestShouldRolloutAfter(t *testing.T) {
 	})
 }
 
+func TestShouldRolloutBefore(t *testing.T) {
+	reconciliationTime := metav1.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	rolloutBefore := &controlplanev1.RolloutBefore{CertificatesExpiryDays: pointer.Int32Ptr(7)}
+	machineWithCertificatesExpiry := func(expiry time.Time) *clusterv1.Machine {
+		m := &clusterv1.Machine{}
+		m.SetAnnotations(map[string]string{controlplanev1.CertificatesExpiryAnnotation: expiry.Format(time.RFC3339)})
+		return m
+	}
+	t.Run("if the machine is nil it returns false", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(machinefilters.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(nil)).To(BeFalse())
+	})
+	t.Run("if rolloutBefore is nil it returns false", func(t *testing.T) {
+		g := NewWithT(t)
+		m := machineWithCertificatesExpiry(reconciliationTime.Add(24 * time.Hour))
+		g.Expect(machinefilters.ShouldRolloutBefore(&reconciliationTime, nil)(m)).To(BeFalse())
+		g.Expect(machinefilters.ShouldRolloutBefore(&reconciliationTime, &controlplanev1.RolloutBefore{})(m)).To(BeFalse())
+	})
+	t.Run("if the machine has no certificates expiry it returns false", func(t *testing.T) {
+		g := NewWithT(t)
+		m := &clusterv1.Machine{}
+		g.Expect(machinefilters.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(m)).To(BeFalse())
+	})
+	t.Run("if the certificates expiry is invalid it returns false", func(t *testing.T) {
+		g := NewWithT(t)
+		m := &clusterv1.Machine{}
+		m.SetAnnotations(map[string]string{controlplanev1.CertificatesExpiryAnnotation: "invalid"})
+		g.Expect(machinefilters.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(m)).To(BeFalse())
+	})
+	t.Run("if the certificates expire after the rollout window it returns false", func(t *testing.T) {
+		g := NewWithT(t)
+		m := machineWithCertificatesExpiry(reconciliationTime.Add(8 * 24 * time.Hour))
+		g.Expect(machinefilters.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(m)).To(BeFalse())
+	})
+	t.Run("if the certificates expire within the rollout window it returns true", func(t *testing.T) {
+		g := NewWithT(t)
+		m := machineWithCertificatesExpiry(reconciliationTime.Add(6 * 24 * time.Hour))
+		g.Expect(machinefilters.ShouldRolloutBefore(&reconciliationTime, rolloutBefore)(m)).To(BeTrue())
+	})
+}
+
 func TestHashAnnotationKey(t *testing.T) {
 	t.Run("machine with specified annotation returns true", func(t *testing.T) {
 		g := NewWithT(t)
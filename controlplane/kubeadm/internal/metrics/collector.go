@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	utilmetrics "sigs.k8s.io/cluster-api/util/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StateCollector collects the conditions and the replicas of KubeadmControlPlanes. Objects are read
+// on each scrape from the given reader, which is expected to be backed by the manager cache.
+type StateCollector struct {
+	reader client.Reader
+
+	condition *prometheus.Desc
+	replicas  *prometheus.Desc
+}
+
+// NewStateCollector returns a StateCollector reading objects from the given reader.
+func NewStateCollector(reader client.Reader) *StateCollector {
+	return &StateCollector{
+		reader:    reader,
+		condition: utilmetrics.NewConditionDesc("kubeadmcontrolplane", "cluster"),
+		replicas: prometheus.NewDesc(
+			prometheus.BuildFQName(utilmetrics.Namespace, "kubeadmcontrolplane", "status_replicas"),
+			"The number of control plane Machines of the kubeadmcontrolplane, by state.",
+			[]string{"namespace", "name", "cluster", "state"},
+			nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *StateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.condition
+	ch <- c.replicas
+}
+
+// Collect implements prometheus.Collector.
+func (c *StateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := utilmetrics.CollectContext()
+	defer cancel()
+
+	kcps := &controlplanev1.KubeadmControlPlaneList{}
+	if err := c.reader.List(ctx, kcps); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.condition, err)
+		return
+	}
+	for i := range kcps.Items {
+		kcp := &kcps.Items[i]
+		cluster := clusterName(kcp)
+		utilmetrics.CollectConditions(ch, c.condition, kcp, kcp.Namespace, kcp.Name, cluster)
+		for state, value := range map[string]int32{
+			"desired":     desiredReplicas(kcp),
+			"current":     kcp.Status.Replicas,
+			"updated":     kcp.Status.UpdatedReplicas,
+			"ready":       kcp.Status.ReadyReplicas,
+			"unavailable": kcp.Status.UnavailableReplicas,
+		} {
+			ch <- prometheus.MustNewConstMetric(c.replicas, prometheus.GaugeValue, float64(value), kcp.Namespace, kcp.Name, cluster, state)
+		}
+	}
+}
+
+// clusterName returns the name of the Cluster owning the KubeadmControlPlane, if any.
+func clusterName(kcp *controlplanev1.KubeadmControlPlane) string {
+	for _, ref := range kcp.OwnerReferences {
+		if ref.Kind != "Cluster" {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil && gv.Group == clusterv1.GroupVersion.Group {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+func desiredReplicas(kcp *controlplanev1.KubeadmControlPlane) int32 {
+	if kcp.Spec.Replicas == nil {
+		return 0
+	}
+	return *kcp.Spec.Replicas
+}
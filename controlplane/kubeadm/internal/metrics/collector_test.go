@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStateCollector(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(controlplanev1.AddToScheme(scheme)).To(Succeed())
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-cp",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "test"},
+			},
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Replicas: pointer.Int32Ptr(3),
+		},
+		Status: controlplanev1.KubeadmControlPlaneStatus{
+			Replicas:            3,
+			UpdatedReplicas:     2,
+			ReadyReplicas:       3,
+			UnavailableReplicas: 0,
+			Conditions: clusterv1.Conditions{
+				{Type: controlplanev1.MachinesSpecUpToDateCondition, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+
+	collector := NewStateCollector(fake.NewClientBuilder().WithScheme(scheme).WithObjects(kcp).Build())
+
+	expected := `
+# HELP capi_kubeadmcontrolplane_status_condition The condition of the kubeadmcontrolplane; the value is 1 for the current status of the condition, 0 otherwise.
+# TYPE capi_kubeadmcontrolplane_status_condition gauge
+capi_kubeadmcontrolplane_status_condition{cluster="test",name="test-cp",namespace="default",status="False",type="MachinesSpecUpToDate"} 1
+capi_kubeadmcontrolplane_status_condition{cluster="test",name="test-cp",namespace="default",status="True",type="MachinesSpecUpToDate"} 0
+capi_kubeadmcontrolplane_status_condition{cluster="test",name="test-cp",namespace="default",status="Unknown",type="MachinesSpecUpToDate"} 0
+# HELP capi_kubeadmcontrolplane_status_replicas The number of control plane Machines of the kubeadmcontrolplane, by state.
+# TYPE capi_kubeadmcontrolplane_status_replicas gauge
+capi_kubeadmcontrolplane_status_replicas{cluster="test",name="test-cp",namespace="default",state="current"} 3
+capi_kubeadmcontrolplane_status_replicas{cluster="test",name="test-cp",namespace="default",state="desired"} 3
+capi_kubeadmcontrolplane_status_replicas{cluster="test",name="test-cp",namespace="default",state="ready"} 3
+capi_kubeadmcontrolplane_status_replicas{cluster="test",name="test-cp",namespace="default",state="unavailable"} 0
+capi_kubeadmcontrolplane_status_replicas{cluster="test",name="test-cp",namespace="default",state="updated"} 2
+`
+	g.Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected))).To(Succeed())
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics implements the Prometheus metrics exposed by the KubeadmControlPlane controller.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
+	utilmetrics "sigs.k8s.io/cluster-api/util/metrics"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Operation is an operation performed by the KubeadmControlPlane controller on control plane Machines.
+type Operation string
+
+const (
+	// InitializeOperation is recorded when the first control plane Machine is created.
+	InitializeOperation = Operation("initialize")
+
+	// ScaleUpOperation is recorded when an additional control plane Machine is created,
+	// either to reach the desired replicas or to replace an outdated Machine.
+	ScaleUpOperation = Operation("scale_up")
+
+	// ScaleDownOperation is recorded when a control plane Machine is deleted,
+	// either to reach the desired replicas or because it is outdated.
+	ScaleDownOperation = Operation("scale_down")
+
+	// UpgradeOperation is recorded when an outdated control plane Machine is deleted
+	// after being replaced by an up-to-date one.
+	UpgradeOperation = Operation("upgrade")
+)
+
+var operations = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: utilmetrics.Namespace,
+		Subsystem: "kubeadmcontrolplane",
+		Name:      "operations_total",
+		Help:      "Number of operations performed by a KubeadmControlPlane on its control plane Machines.",
+	},
+	[]string{"namespace", "name", "operation"},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(operations)
+}
+
+// RecordOperation records that the KubeadmControlPlane performed the given operation.
+func RecordOperation(kcp *controlplanev1.KubeadmControlPlane, operation Operation) {
+	operations.WithLabelValues(kcp.Namespace, kcp.Name, string(operation)).Inc()
+}
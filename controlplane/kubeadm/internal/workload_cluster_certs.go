@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/proxy"
+)
+
+// tlsHandshakeTimeout is the maximum time allowed for the TLS handshake with a kube-apiserver pod.
+const tlsHandshakeTimeout = 10 * time.Second
+
+// GetAPIServerCertificateExpiry returns the expiry date of the serving certificate of the kube-apiserver static pod
+// running on the given control plane node and listening on the given port. Given that kubeadm issues all the
+// certificates of a control plane node at the same time, this is also the expiry date of the etcd and kubelet
+// certificates of the node.
+func (w *Workload) GetAPIServerCertificateExpiry(ctx context.Context, nodeName string, port int32) (*time.Time, error) {
+	if w.restConfig == nil {
+		return nil, errors.New("unable to connect to the kube-apiserver pods: missing REST config")
+	}
+
+	dialer, err := proxy.NewDialer(proxy.Proxy{
+		Kind:       "pods",
+		Namespace:  metav1.NamespaceSystem,
+		KubeConfig: w.restConfig,
+		Port:       int(port),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dialer for the kube-apiserver pods")
+	}
+
+	podName := staticPodName("kube-apiserver", nodeName)
+	conn, err := dialer.DialContextWithAddr(ctx, podName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to pod %s", podName)
+	}
+
+	// The connection is only used to read the certificate served by the kube-apiserver, so there is no need to verify it.
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	defer tlsConn.Close()
+
+	// The proxied connection does not support deadlines, so a stuck handshake is interrupted by closing the connection.
+	ctx, cancel := context.WithTimeout(ctx, tlsHandshakeTimeout)
+	defer cancel()
+	handshakeErr := make(chan error, 1)
+	go func() {
+		handshakeErr <- tlsConn.Handshake()
+	}()
+	select {
+	case err := <-handshakeErr:
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to complete the TLS handshake with pod %s", podName)
+		}
+	case <-ctx.Done():
+		_ = tlsConn.Close()
+		return nil, errors.Wrapf(ctx.Err(), "failed to complete the TLS handshake with pod %s", podName)
+	}
+
+	certificates := tlsConn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return nil, errors.Errorf("pod %s did not serve any certificate", podName)
+	}
+	expiry := certificates[0].NotAfter
+	return &expiry, nil
+}
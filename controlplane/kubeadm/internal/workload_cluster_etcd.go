@@ -18,8 +18,10 @@ package internal
 
 import (
 	"context"
+	"io"
 
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
@@ -234,3 +236,60 @@ func (w *Workload) EtcdMembers(ctx context.Context) ([]string, error) {
 	}
 	return names, nil
 }
+
+// EtcdSnapshot streams a snapshot of the etcd cluster, taken from the first available member, to the given writer.
+func (w *Workload) EtcdSnapshot(ctx context.Context, out io.Writer) error {
+	nodes, err := w.getControlPlaneNodes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list control plane nodes")
+	}
+	nodeNames := make([]string, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeNames = append(nodeNames, node.Name)
+	}
+	etcdClient, err := w.etcdClientGenerator.forFirstAvailableNode(ctx, nodeNames)
+	if err != nil {
+		return errors.Wrap(err, "failed to create etcd client")
+	}
+	defer etcdClient.Close()
+
+	return etcdClient.Snapshot(ctx, out)
+}
+
+// ReconcileRestoredControlPlane aligns the workload cluster state restored from an etcd snapshot with the control plane
+// node rebuilt from it: the stale control plane nodes restored from the snapshot are removed, and the restored etcd
+// member is checked to be available. The restored member already advertises its own peer URL, because the restore script passes it
+// to etcdctl snapshot restore, so other members can join without further changes.
+func (w *Workload) ReconcileRestoredControlPlane(ctx context.Context, nodeName string) error {
+	nodes, err := w.getControlPlaneNodes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list control plane nodes")
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.Name == nodeName {
+			continue
+		}
+		if err := w.RemoveNodeFromKubeadmConfigMap(ctx, node.Name); err != nil {
+			return err
+		}
+		if err := w.Client.Delete(ctx, node); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete stale control plane node %q", node.Name)
+		}
+	}
+
+	etcdClient, err := w.etcdClientGenerator.forFirstAvailableNode(ctx, []string{nodeName})
+	if err != nil {
+		return errors.Wrap(err, "failed to create etcd client")
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.Members(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list etcd members using etcd client")
+	}
+	if etcdutil.MemberForName(members, nodeName) == nil {
+		return errors.Errorf("failed to get etcd member for node %q", nodeName)
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -35,8 +36,12 @@ import (
 	"sigs.k8s.io/cluster-api/cmd/version"
 	kcpv1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1alpha4"
 	kubeadmcontrolplanecontrollers "sigs.k8s.io/cluster-api/controlplane/kubeadm/controllers"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	kubeadmcontrolplanemetrics "sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/metrics"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -66,6 +71,9 @@ var (
 	kubeadmControlPlaneConcurrency int
 	syncPeriod                     time.Duration
 	webhookPort                    int
+	etcdSnapshotStore              string
+	etcdSnapshotStoreURL           string
+	etcdSnapshotStoreCredentials   string
 )
 
 // InitFlags initializes the flags.
@@ -99,6 +107,15 @@ func InitFlags(fs *pflag.FlagSet) {
 
 	fs.IntVar(&webhookPort, "webhook-port", 0,
 		"Webhook Server port, disabled by default. When enabled, the manager will only work as webhook server, no reconcilers are installed.")
+
+	fs.StringVar(&etcdSnapshotStore, "etcd-snapshot-store", "",
+		"Storage of the etcd snapshots, either http or secret; etcd backups and restores are disabled if unset. Snapshots stored in Secrets must not exceed 1MiB once gzipped, and they cannot be restored by the controller.")
+
+	fs.StringVar(&etcdSnapshotStoreURL, "etcd-snapshot-store-url", "",
+		"Base URL of the object store used by the http etcd snapshot store (e.g. https://objects.example.com/etcd-snapshots); it must be reachable from the control plane machines, and it must not be publicly accessible.")
+
+	fs.StringVar(&etcdSnapshotStoreCredentials, "etcd-snapshot-store-credentials", "",
+		"Secret holding the credentials of the http etcd snapshot store, in the <namespace>/<name> format; the Secret may contain the value of the Authorization header sent to the object store in the authorization key, and a 32 bytes key encrypting the snapshots in the encryptionKey key.")
 }
 func main() {
 	rand.Seed(time.Now().UnixNano())
@@ -136,6 +153,7 @@ func main() {
 	// Setup the context that's going to be used in controllers and for the manager.
 	ctx := ctrl.SetupSignalHandler()
 
+	setupMetrics(mgr)
 	setupReconcilers(ctx, mgr)
 	setupWebhooks(mgr)
 
@@ -147,19 +165,60 @@ func main() {
 	}
 }
 
+func setupMetrics(mgr ctrl.Manager) {
+	if webhookPort != 0 {
+		return
+	}
+
+	// The state of KubeadmControlPlanes is read from the manager cache on each scrape.
+	if err := ctrlmetrics.Registry.Register(kubeadmcontrolplanemetrics.NewStateCollector(mgr.GetCache())); err != nil {
+		setupLog.Error(err, "unable to register metrics collector")
+		os.Exit(1)
+	}
+}
+
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	if webhookPort != 0 {
 		return
 	}
 
 	if err := (&kubeadmcontrolplanecontrollers.KubeadmControlPlaneReconciler{
-		Client: mgr.GetClient(),
+		Client:            mgr.GetClient(),
+		EtcdSnapshotStore: newEtcdSnapshotStore(mgr),
 	}).SetupWithManager(ctx, mgr, concurrency(kubeadmControlPlaneConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeadmControlPlane")
 		os.Exit(1)
 	}
 }
 
+func newEtcdSnapshotStore(mgr ctrl.Manager) internal.EtcdSnapshotStore {
+	switch etcdSnapshotStore {
+	case "":
+		return nil
+	case "secret":
+		return &internal.SecretEtcdSnapshotStore{Client: mgr.GetClient()}
+	case "http":
+		if etcdSnapshotStoreURL == "" {
+			setupLog.Error(nil, "--etcd-snapshot-store-url is required when using the http etcd snapshot store")
+			os.Exit(1)
+		}
+		store := &internal.HTTPEtcdSnapshotStore{Client: mgr.GetClient(), URL: etcdSnapshotStoreURL}
+		if etcdSnapshotStoreCredentials != "" {
+			parts := strings.Split(etcdSnapshotStoreCredentials, "/")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				setupLog.Error(nil, "invalid etcd snapshot store credentials, must be <namespace>/<name>", "etcd-snapshot-store-credentials", etcdSnapshotStoreCredentials)
+				os.Exit(1)
+			}
+			store.CredentialsSecret = &client.ObjectKey{Namespace: parts[0], Name: parts[1]}
+		}
+		return store
+	default:
+		setupLog.Error(nil, "invalid etcd snapshot store, must be http or secret", "etcd-snapshot-store", etcdSnapshotStore)
+		os.Exit(1)
+	}
+	return nil
+}
+
 func setupWebhooks(mgr ctrl.Manager) {
 	if webhookPort == 0 {
 		return
@@ -43,9 +43,15 @@ type ClusterResourceSetSpec struct {
 	Resources []ResourceRef `json:"resources,omitempty"`
 
 	// Strategy is the strategy to be used during applying resources. Defaults to ApplyOnce. This field is immutable.
-	// +kubebuilder:validation:Enum=ApplyOnce
+	// +kubebuilder:validation:Enum=ApplyOnce;Reconcile
 	// +optional
 	Strategy string `json:"strategy,omitempty"`
+
+	// Prune enables the deletion from the matching clusters of the objects removed from the resources,
+	// and of the objects of the resources removed from the ClusterResourceSet.
+	// Prune is supported only by the "Reconcile" strategy.
+	// +optional
+	Prune bool `json:"prune,omitempty"`
 }
 
 // ANCHOR_END: ClusterResourceSetSpec
@@ -76,6 +82,10 @@ const (
 	// ClusterResourceSetStrategyApplyOnce is the default strategy a ClusterResourceSet strategy is assigned by
 	// ClusterResourceSet controller after being created if not specified by user.
 	ClusterResourceSetStrategyApplyOnce ClusterResourceSetStrategy = "ApplyOnce"
+
+	// ClusterResourceSetStrategyReconcile is the strategy that applies the resources again to all the matching
+	// clusters every time their content changes.
+	ClusterResourceSetStrategyReconcile ClusterResourceSetStrategy = "Reconcile"
 )
 
 // SetTypedStrategy sets the Strategy field to the string representation of ClusterResourceSetStrategy.
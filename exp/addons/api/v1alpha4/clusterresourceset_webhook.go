@@ -94,6 +94,13 @@ func (m *ClusterResourceSet) validate(old *ClusterResourceSet) error {
 		)
 	}
 
+	if m.Spec.Prune && m.Spec.Strategy != string(ClusterResourceSetStrategyReconcile) {
+		allErrs = append(
+			allErrs,
+			field.Invalid(field.NewPath("spec", "prune"), m.Spec.Prune, fmt.Sprintf("prune is supported only by the %s strategy", ClusterResourceSetStrategyReconcile)),
+		)
+	}
+
 	if old != nil && !reflect.DeepEqual(old.Spec.ClusterSelector, m.Spec.ClusterSelector) {
 		allErrs = append(
 			allErrs,
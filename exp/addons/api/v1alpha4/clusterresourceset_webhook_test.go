@@ -185,3 +185,51 @@ func TestClusterResourceSetSelectorNotEmptyValidation(t *testing.T) {
 	g.Expect(err).ToNot(BeNil())
 	g.Expect(err.Error()).To(ContainSubstring("selector must not be empty"))
 }
+
+func TestClusterResourceSetPruneValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string
+		prune     bool
+		expectErr bool
+	}{
+		{
+			name:      "should not return error when prune is not set",
+			strategy:  string(ClusterResourceSetStrategyApplyOnce),
+			prune:     false,
+			expectErr: false,
+		},
+		{
+			name:      "should not return error when prune is set with the Reconcile strategy",
+			strategy:  string(ClusterResourceSetStrategyReconcile),
+			prune:     true,
+			expectErr: false,
+		},
+		{
+			name:      "should return error when prune is set with the ApplyOnce strategy",
+			strategy:  string(ClusterResourceSetStrategyApplyOnce),
+			prune:     true,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &ClusterResourceSet{
+				Spec: ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Strategy: tt.strategy,
+					Prune:    tt.prune,
+				},
+			}
+			if tt.expectErr {
+				g.Expect(clusterResourceSet.ValidateCreate()).NotTo(Succeed())
+			} else {
+				g.Expect(clusterResourceSet.ValidateCreate()).To(Succeed())
+			}
+		})
+	}
+}
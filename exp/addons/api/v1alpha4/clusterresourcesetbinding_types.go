@@ -19,6 +19,7 @@ package v1alpha4
 import (
 	"reflect"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,6 +32,7 @@ type ResourceBinding struct {
 
 	// Hash is the hash of a resource's data. This can be used to decide if a resource is changed.
 	// For "ApplyOnce" ClusterResourceSet.spec.strategy, this is no-op as that strategy does not act on change.
+	// For "Reconcile" ClusterResourceSet.spec.strategy, the resource is applied again when its hash changes.
 	Hash string `json:"hash,omitempty"`
 
 	// LastAppliedTime identifies when this resource was last applied to the cluster.
@@ -39,6 +41,15 @@ type ResourceBinding struct {
 
 	// Applied is to track if a resource is applied to the cluster or not.
 	Applied bool `json:"applied"`
+
+	// Objects is the list of the objects last applied to the cluster from the resource.
+	// It is used to prune the objects removed from the resource.
+	// +optional
+	Objects []corev1.ObjectReference `json:"objects,omitempty"`
+
+	// Message is the error message of the last failed attempt to apply the resource to the cluster, if any.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ANCHOR_END: ResourceBinding
@@ -64,6 +75,26 @@ func (r *ResourceSetBinding) IsApplied(resourceRef ResourceRef) bool {
 	return false
 }
 
+// GetBinding returns the resourceBinding of a resource in resourceSetBinding if exists, nil otherwise.
+func (r *ResourceSetBinding) GetBinding(resourceRef ResourceRef) *ResourceBinding {
+	for i := range r.Resources {
+		if reflect.DeepEqual(r.Resources[i].ResourceRef, resourceRef) {
+			return &r.Resources[i]
+		}
+	}
+	return nil
+}
+
+// DeleteBinding removes the resourceBinding of a resource from resourceSetBinding.
+func (r *ResourceSetBinding) DeleteBinding(resourceRef ResourceRef) {
+	for i := range r.Resources {
+		if reflect.DeepEqual(r.Resources[i].ResourceRef, resourceRef) {
+			r.Resources = append(r.Resources[:i], r.Resources[i+1:]...)
+			return
+		}
+	}
+}
+
 // SetBinding sets resourceBinding for a resource in resourceSetbinding either by updating the existing one or
 // creating a new one.
 func (r *ResourceSetBinding) SetBinding(resourceBinding ResourceBinding) {
@@ -168,3 +168,27 @@ func TestSetResourceBinding(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAndDeleteResourceBinding(t *testing.T) {
+	g := NewWithT(t)
+
+	resourceRef1 := ResourceRef{Name: "resource1", Kind: "Secret"}
+	resourceRef2 := ResourceRef{Name: "resource2", Kind: "ConfigMap"}
+
+	CRSBinding := &ResourceSetBinding{
+		ClusterResourceSetName: "test-clusterResourceSet",
+		Resources: []ResourceBinding{
+			{ResourceRef: resourceRef1, Applied: true, Hash: "abc"},
+			{ResourceRef: resourceRef2, Applied: true, Hash: "xyz"},
+		},
+	}
+
+	g.Expect(CRSBinding.GetBinding(resourceRef2)).ToNot(BeNil())
+	g.Expect(CRSBinding.GetBinding(resourceRef2).Hash).To(Equal("xyz"))
+	g.Expect(CRSBinding.GetBinding(ResourceRef{Name: "resource3", Kind: "Secret"})).To(BeNil())
+
+	CRSBinding.DeleteBinding(resourceRef1)
+	g.Expect(CRSBinding.Resources).To(HaveLen(1))
+	g.Expect(CRSBinding.GetBinding(resourceRef1)).To(BeNil())
+	g.Expect(CRSBinding.GetBinding(resourceRef2)).ToNot(BeNil())
+}
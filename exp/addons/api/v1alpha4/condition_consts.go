@@ -34,6 +34,10 @@ const (
 	// ApplyFailedReason (Severity=Warning) documents applying at least one of the resources to one of the matching clusters is failed.
 	ApplyFailedReason = "ApplyFailed"
 
+	// PruneFailedReason (Severity=Warning) documents deleting at least one of the objects removed from the resources
+	// from one of the matching clusters is failed.
+	PruneFailedReason = "PruneFailed"
+
 	// RetrievingResourceFailedReason (Severity=Warning) documents at least one of the resources are not successfully retrieved.
 	RetrievingResourceFailedReason = "RetrievingResourceFailed"
 
@@ -76,14 +76,14 @@ func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr
 			&source.Kind{Type: &corev1.ConfigMap{}},
 			handler.EnqueueRequestsFromMapFunc(r.resourceToClusterResourceSet),
 			builder.WithPredicates(
-				resourcepredicates.ResourceCreate(ctrl.LoggerFrom(ctx)),
+				resourcepredicates.ResourceCreateOrUpdate(ctrl.LoggerFrom(ctx)),
 			),
 		).
 		Watches(
 			&source.Kind{Type: &corev1.Secret{}},
 			handler.EnqueueRequestsFromMapFunc(r.resourceToClusterResourceSet),
 			builder.WithPredicates(
-				resourcepredicates.AddonsSecretCreate(ctrl.LoggerFrom(ctx)),
+				resourcepredicates.AddonsSecretCreateOrUpdate(ctrl.LoggerFrom(ctx)),
 			),
 		).
 		WithOptions(options).
@@ -227,6 +227,8 @@ func (r *ClusterResourceSetReconciler) getClustersByClusterResourceSetSelector(c
 // ApplyClusterResourceSet applies resources in a ClusterResourceSet to a Cluster. Once applied, a record will be added to the
 // cluster's ClusterResourceSetBinding.
 // In ApplyOnce strategy, resources are applied only once to a particular cluster. ClusterResourceSetBinding is used to check if a resource is applied before.
+// In Reconcile strategy, resources are applied again every time the hash of their data recorded in ClusterResourceSetBinding changes;
+// if pruning is enabled, the objects removed from the resources are deleted from the cluster.
 // It applies resources best effort and continue on scenarios like: unsupported resource types, failure during creation, missing resources.
 // TODO: If a resource already exists in the cluster but not applied by ClusterResourceSet, the resource will be updated ?
 func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) error {
@@ -260,11 +262,18 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 
 	errList := []error{}
 	resourceSetBinding := clusterResourceSetBinding.GetOrCreateBinding(clusterResourceSet)
+	reconcileStrategy := clusterResourceSet.Spec.Strategy == string(addonsv1.ClusterResourceSetStrategyReconcile)
+	pruneEnabled := reconcileStrategy && clusterResourceSet.Spec.Prune
+
+	// Delete the objects of the resources removed from the ClusterResourceSet, if pruning is enabled.
+	if pruneEnabled {
+		errList = append(errList, r.pruneRemovedResources(ctx, remoteClient, clusterResourceSet, resourceSetBinding)...)
+	}
 
 	// Iterate all resources and apply them to the cluster and update the resource status in the ClusterResourceSetBinding object.
 	for _, resource := range clusterResourceSet.Spec.Resources {
 		// If resource is already applied successfully and clusterResourceSet mode is "ApplyOnce", continue. (No need to check hash changes here)
-		if resourceSetBinding.IsApplied(resource) {
+		if !reconcileStrategy && resourceSetBinding.IsApplied(resource) {
 			continue
 		}
 
@@ -284,6 +293,22 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			continue
 		}
 
+		dataList, err := resourceData(unstructuredObj)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+		hash := computeHash(dataList)
+
+		// If resource is already applied successfully and its data did not change, continue.
+		var previousObjects []corev1.ObjectReference
+		if previous := resourceSetBinding.GetBinding(resource); previous != nil {
+			if previous.Applied && previous.Hash == hash {
+				continue
+			}
+			previousObjects = previous.Objects
+		}
+
 		// Set status in ClusterResourceSetBinding in case of early continue due to a failure.
 		// Set only when resource is retrieved successfully.
 		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
@@ -291,6 +316,7 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			Hash:            "",
 			Applied:         false,
 			LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+			Objects:         previousObjects,
 		})
 
 		if err := r.patchOwnerRefToResource(ctx, clusterResourceSet, unstructuredObj); err != nil {
@@ -299,57 +325,45 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			errList = append(errList, err)
 		}
 
-		// Since maps are not ordered, we need to order them to get the same hash at each reconcile.
-		keys := make([]string, 0)
-		data, ok := unstructuredObj.UnstructuredContent()["data"]
-		if !ok {
-			errList = append(errList, errors.New("failed to get data field from the resource"))
-			continue
-		}
-
-		unstructuredData := data.(map[string]interface{})
-		for key := range unstructuredData {
-			keys = append(keys, key)
-		}
-		sort.Strings(keys)
-
-		dataList := make([][]byte, 0)
-		for _, key := range keys {
-			val, ok, err := unstructured.NestedString(unstructuredData, key)
-			if !ok || err != nil {
-				errList = append(errList, errors.New("failed to get value field from the resource"))
-				continue
-			}
-
-			byteArr := []byte(val)
-			// If the resource is a Secret, data needs to be decoded.
-			if unstructuredObj.GetKind() == string(addonsv1.SecretClusterResourceSetResourceKind) {
-				byteArr, _ = base64.StdEncoding.DecodeString(val)
-			}
-
-			dataList = append(dataList, byteArr)
-		}
-
 		// Apply all values in the key-value pair of the resource to the cluster.
 		// As there can be multiple key-value pairs in a resource, each value may have multiple objects in it.
-		isSuccessful := true
+		resourceErrList := []error{}
+		objects := []corev1.ObjectReference{}
 		for i := range dataList {
 			data := dataList[i]
 
-			if err := apply(ctx, remoteClient, data); err != nil {
-				isSuccessful = false
+			refs, err := apply(ctx, remoteClient, data, reconcileStrategy)
+			objects = append(objects, refs...)
+			if err != nil {
 				log.Error(err, "failed to apply ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
 				conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-				errList = append(errList, err)
+				resourceErrList = append(resourceErrList, err)
 			}
 		}
 
-		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
+		// Delete the objects removed from the resource, only if all the objects in the resource are applied successfully.
+		if pruneEnabled && len(resourceErrList) == 0 {
+			if err := prune(ctx, remoteClient, previousObjects, objects); err != nil {
+				log.Error(err, "failed to prune ClusterResourceSet resource objects", "Resource kind", resource.Kind, "Resource name", resource.Name)
+				conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.PruneFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+				resourceErrList = append(resourceErrList, err)
+			}
+		}
+
+		resourceBinding := addonsv1.ResourceBinding{
 			ResourceRef:     resource,
-			Hash:            computeHash(dataList),
-			Applied:         isSuccessful,
+			Hash:            hash,
+			Applied:         len(resourceErrList) == 0,
 			LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
-		})
+			Objects:         objects,
+		}
+		if len(resourceErrList) > 0 {
+			// Keep track of the objects previously applied, so they are pruned on the next successful attempt.
+			resourceBinding.Objects = mergeObjectReferences(previousObjects, objects)
+			resourceBinding.Message = kerrors.NewAggregate(resourceErrList).Error()
+		}
+		resourceSetBinding.SetBinding(resourceBinding)
+		errList = append(errList, resourceErrList...)
 	}
 	if len(errList) > 0 {
 		return kerrors.NewAggregate(errList)
@@ -360,6 +374,65 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 	return nil
 }
 
+// pruneRemovedResources deletes from the cluster the objects of the resources that are recorded in the ClusterResourceSetBinding,
+// but no longer in the ClusterResourceSet, and it removes the resources from the ClusterResourceSetBinding.
+func (r *ClusterResourceSetReconciler) pruneRemovedResources(ctx context.Context, remoteClient client.Client, clusterResourceSet *addonsv1.ClusterResourceSet, resourceSetBinding *addonsv1.ResourceSetBinding) []error {
+	log := ctrl.LoggerFrom(ctx)
+
+	resources := map[addonsv1.ResourceRef]bool{}
+	for _, resource := range clusterResourceSet.Spec.Resources {
+		resources[resource] = true
+	}
+
+	errList := []error{}
+	for _, resourceBinding := range append([]addonsv1.ResourceBinding{}, resourceSetBinding.Resources...) {
+		if resources[resourceBinding.ResourceRef] {
+			continue
+		}
+		if err := prune(ctx, remoteClient, resourceBinding.Objects, nil); err != nil {
+			log.Error(err, "failed to prune removed ClusterResourceSet resource objects", "Resource kind", resourceBinding.Kind, "Resource name", resourceBinding.Name)
+			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.PruneFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			errList = append(errList, err)
+			continue
+		}
+		resourceSetBinding.DeleteBinding(resourceBinding.ResourceRef)
+	}
+	return errList
+}
+
+// resourceData returns the values in the key-value pairs of the resource data.
+func resourceData(unstructuredObj *unstructured.Unstructured) ([][]byte, error) {
+	// Since maps are not ordered, we need to order them to get the same hash at each reconcile.
+	keys := make([]string, 0)
+	data, ok := unstructuredObj.UnstructuredContent()["data"]
+	if !ok {
+		return nil, errors.New("failed to get data field from the resource")
+	}
+
+	unstructuredData := data.(map[string]interface{})
+	for key := range unstructuredData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	dataList := make([][]byte, 0)
+	for _, key := range keys {
+		val, ok, err := unstructured.NestedString(unstructuredData, key)
+		if !ok || err != nil {
+			return nil, errors.New("failed to get value field from the resource")
+		}
+
+		byteArr := []byte(val)
+		// If the resource is a Secret, data needs to be decoded.
+		if unstructuredObj.GetKind() == string(addonsv1.SecretClusterResourceSetResourceKind) {
+			byteArr, _ = base64.StdEncoding.DecodeString(val)
+		}
+
+		dataList = append(dataList, byteArr)
+	}
+	return dataList, nil
+}
+
 // getResource retrieves the requested resource and convert it to unstructured type.
 // Unsupported resource kinds are not denied by validation webhook, hence no need to check here.
 // Only supports Secrets/Configmaps as resource types and allow using resources in the same namespace with the cluster.
@@ -423,4 +423,99 @@ metadata:
 			return false
 		}, timeout).Should(BeTrue())
 	})
+
+	It("Should apply the resources again when they change and prune the removed objects with the Reconcile strategy", func() {
+		resourceConfigMap := func(name, value string) string {
+			return fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+ name: %s
+ namespace: default
+data:
+ key: %s`, name, value)
+		}
+
+		By("Creating a ConfigMap with two ConfigMaps in its data field")
+		sourceConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-configmap-reconcile",
+				Namespace: defaultNamespaceName,
+			},
+			Data: map[string]string{
+				"cm-a": resourceConfigMap("reconcile-configmap-a", "v1"),
+				"cm-b": resourceConfigMap("reconcile-configmap-b", "v1"),
+			},
+		}
+		Expect(testEnv.Create(ctx, sourceConfigMap)).To(Succeed())
+		defer func() {
+			Expect(testEnv.Delete(ctx, sourceConfigMap)).To(Succeed())
+		}()
+
+		By("Updating the cluster with labels")
+		labels := map[string]string{"foo": "bar"}
+		testCluster.SetLabels(labels)
+		Expect(testEnv.Update(ctx, testCluster)).To(Succeed())
+
+		By("Creating a ClusterResourceSet instance with the Reconcile strategy and prune enabled")
+		clusterResourceSetInstance := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-clusterresourceset",
+				Namespace: defaultNamespaceName,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				Resources: []addonsv1.ResourceRef{{Name: sourceConfigMap.Name, Kind: "ConfigMap"}},
+				Strategy:  string(addonsv1.ClusterResourceSetStrategyReconcile),
+				Prune:     true,
+			},
+		}
+		Expect(testEnv.Create(ctx, clusterResourceSetInstance)).To(Succeed())
+
+		By("Verifying the objects are created in the cluster")
+		Eventually(func() bool {
+			cmA := &corev1.ConfigMap{}
+			cmB := &corev1.ConfigMap{}
+			if err := testEnv.Get(ctx, client.ObjectKey{Namespace: defaultNamespaceName, Name: "reconcile-configmap-a"}, cmA); err != nil {
+				return false
+			}
+			if err := testEnv.Get(ctx, client.ObjectKey{Namespace: defaultNamespaceName, Name: "reconcile-configmap-b"}, cmB); err != nil {
+				return false
+			}
+			return cmA.Data["key"] == "v1" && cmB.Data["key"] == "v1"
+		}, timeout).Should(BeTrue())
+
+		By("Updating the ConfigMap removing one of the objects")
+		sourceConfigMap.Data = map[string]string{
+			"cm-a": resourceConfigMap("reconcile-configmap-a", "v2"),
+		}
+		Expect(testEnv.Update(ctx, sourceConfigMap)).To(Succeed())
+
+		By("Verifying the changed object is updated and the removed object is deleted from the cluster")
+		Eventually(func() bool {
+			cmA := &corev1.ConfigMap{}
+			if err := testEnv.Get(ctx, client.ObjectKey{Namespace: defaultNamespaceName, Name: "reconcile-configmap-a"}, cmA); err != nil {
+				return false
+			}
+			err := testEnv.Get(ctx, client.ObjectKey{Namespace: defaultNamespaceName, Name: "reconcile-configmap-b"}, &corev1.ConfigMap{})
+			return cmA.Data["key"] == "v2" && apierrors.IsNotFound(err)
+		}, timeout).Should(BeTrue())
+
+		By("Verifying the ClusterResourceSetBinding tracks the applied objects")
+		Eventually(func() bool {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			if err := testEnv.Get(ctx, client.ObjectKey{Namespace: testCluster.Namespace, Name: testCluster.Name}, binding); err != nil {
+				return false
+			}
+			if len(binding.Spec.Bindings) != 1 || len(binding.Spec.Bindings[0].Resources) != 1 {
+				return false
+			}
+			resourceBinding := binding.Spec.Bindings[0].Resources[0]
+			return resourceBinding.Applied && len(resourceBinding.Objects) == 1 && resourceBinding.Objects[0].Name == "reconcile-configmap-a"
+		}, timeout).Should(BeTrue())
+
+		By("Deleting the Cluster")
+		Expect(testEnv.Delete(ctx, testCluster)).To(Succeed())
+	})
 })
@@ -42,6 +42,9 @@ import (
 
 var jsonListPrefix = []byte("[")
 
+// clusterResourceSetFieldOwner is the field manager used when applying the resources of a ClusterResourceSet.
+const clusterResourceSetFieldOwner = "clusterresourceset"
+
 // isJSONList returns whether the data is in JSON list format.
 func isJSONList(data []byte) (bool, error) {
 	const peekSize = 32
@@ -54,10 +57,13 @@ func isJSONList(data []byte) (bool, error) {
 	return bytes.HasPrefix(trim, jsonListPrefix), nil
 }
 
-func apply(ctx context.Context, c client.Client, data []byte) error {
+// apply creates the objects defined in data in the cluster, and returns the references to the objects.
+// If update is true, the objects already existing in the cluster are updated with the content of data,
+// otherwise they are left untouched.
+func apply(ctx context.Context, c client.Client, data []byte, update bool) ([]corev1.ObjectReference, error) {
 	isJSONList, err := isJSONList(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	objs := []unstructured.Unstructured{}
 	// If it is a json list, convert each list element to an unstructured object.
@@ -75,18 +81,32 @@ func apply(ctx context.Context, c client.Client, data []byte) error {
 		// If it is not a json list, data is either json or yaml format.
 		objs, err = utilyaml.ToUnstructured(data)
 		if err != nil {
-			return errors.Wrapf(err, "failed converting data to unstructured objects")
+			return nil, errors.Wrapf(err, "failed converting data to unstructured objects")
 		}
 	}
 
 	errList := []error{}
+	refs := []corev1.ObjectReference{}
 	sortedObjs := utilresource.SortForCreate(objs)
 	for i := range sortedObjs {
-		if err := applyUnstructured(ctx, c, &objs[i]); err != nil {
+		obj := &sortedObjs[i]
+		refs = append(refs, corev1.ObjectReference{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		})
+		if update {
+			if err := createOrPatchUnstructured(ctx, c, obj); err != nil {
+				errList = append(errList, err)
+			}
+			continue
+		}
+		if err := applyUnstructured(ctx, c, obj); err != nil {
 			errList = append(errList, err)
 		}
 	}
-	return kerrors.NewAggregate(errList)
+	return refs, kerrors.NewAggregate(errList)
 }
 
 func applyUnstructured(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
@@ -107,6 +127,68 @@ func applyUnstructured(ctx context.Context, c client.Client, obj *unstructured.U
 	return nil
 }
 
+// createOrPatchUnstructured creates the object on the API server, or it merge patches the existing object
+// with the given content; the fields of the existing object not defined in the given content, e.g. the fields
+// defaulted by the API server or set by other controllers, are preserved.
+func createOrPatchUnstructured(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	err := c.Create(ctx, obj.DeepCopy(), client.FieldOwner(clusterResourceSetFieldOwner))
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create object %s %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	patched := obj.DeepCopy()
+	patched.SetResourceVersion("")
+	data, err := patched.MarshalJSON()
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal object %s %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	}
+	if err := c.Patch(ctx, patched, client.RawPatch(types.MergePatchType, data), client.FieldOwner(clusterResourceSetFieldOwner)); err != nil {
+		return errors.Wrapf(err, "failed to patch object %s %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	}
+	return nil
+}
+
+// prune deletes from the cluster the objects in previous that are not in current.
+func prune(ctx context.Context, c client.Client, previous, current []corev1.ObjectReference) error {
+	keep := map[corev1.ObjectReference]bool{}
+	for _, ref := range current {
+		keep[ref] = true
+	}
+
+	errList := []error{}
+	for _, ref := range previous {
+		if keep[ref] {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(ref.APIVersion)
+		obj.SetKind(ref.Kind)
+		obj.SetNamespace(ref.Namespace)
+		obj.SetName(ref.Name)
+		if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			errList = append(errList, errors.Wrapf(err, "failed to delete object %s %s/%s", obj.GroupVersionKind(), ref.Namespace, ref.Name))
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// mergeObjectReferences returns the union of the given lists of object references.
+func mergeObjectReferences(a, b []corev1.ObjectReference) []corev1.ObjectReference {
+	seen := map[corev1.ObjectReference]bool{}
+	merged := []corev1.ObjectReference{}
+	for _, ref := range append(append([]corev1.ObjectReference{}, a...), b...) {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		merged = append(merged, ref)
+	}
+	return merged
+}
+
 // getOrCreateClusterResourceSetBinding retrieves ClusterResourceSetBinding resource owned by the cluster or create a new one if not found.
 func (r *ClusterResourceSetReconciler) getOrCreateClusterResourceSetBinding(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) (*addonsv1.ClusterResourceSetBinding, error) {
 	clusterResourceSetBinding := &addonsv1.ClusterResourceSetBinding{}
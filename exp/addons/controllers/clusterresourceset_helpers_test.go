@@ -29,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -227,3 +228,108 @@ func TestGetConfigMapFromNamespacedName(t *testing.T) {
 		})
 	}
 }
+
+func TestApply(t *testing.T) {
+	configMap := func(value string) []byte {
+		return []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-configmap
+  namespace: default
+data:
+  key: ` + value)
+	}
+	expectedRefs := []corev1.ObjectReference{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "my-configmap"}}
+
+	tests := []struct {
+		name          string
+		update        bool
+		expectedValue string
+	}{
+		{
+			name:          "should not update existing objects",
+			update:        false,
+			expectedValue: "old",
+		},
+		{
+			name:          "should update existing objects",
+			update:        true,
+			expectedValue: "new",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			gs.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			refs, err := apply(context.TODO(), c, configMap("old"), tt.update)
+			gs.Expect(err).NotTo(HaveOccurred())
+			gs.Expect(refs).To(Equal(expectedRefs))
+
+			refs, err = apply(context.TODO(), c, configMap("new"), tt.update)
+			gs.Expect(err).NotTo(HaveOccurred())
+			gs.Expect(refs).To(Equal(expectedRefs))
+
+			got := &corev1.ConfigMap{}
+			gs.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "my-configmap"}, got)).To(Succeed())
+			gs.Expect(got.Data).To(HaveKeyWithValue("key", tt.expectedValue))
+		})
+	}
+}
+
+func TestApplyPreservesFieldsNotInResource(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-configmap",
+			Namespace:   "default",
+			Annotations: map[string]string{"set-by": "another-controller"},
+		},
+		Data: map[string]string{"key": "old", "other-key": "other-value"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+	_, err := apply(context.TODO(), c, []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-configmap
+  namespace: default
+data:
+  key: new`), true)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	got := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "my-configmap"}, got)).To(Succeed())
+	g.Expect(got.Data).To(Equal(map[string]string{"key": "new", "other-key": "other-value"}))
+	g.Expect(got.Annotations).To(HaveKeyWithValue("set-by", "another-controller"))
+}
+
+func TestPrune(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	kept := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "default"}}
+	removed := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "removed", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kept, removed).Build()
+
+	keptRef := corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "kept"}
+	removedRef := corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "removed"}
+	missingRef := corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "missing"}
+
+	g.Expect(prune(context.TODO(), c, []corev1.ObjectReference{keptRef, removedRef, missingRef}, []corev1.ObjectReference{keptRef})).To(Succeed())
+
+	configMaps := &corev1.ConfigMapList{}
+	g.Expect(c.List(context.TODO(), configMaps)).To(Succeed())
+	g.Expect(configMaps.Items).To(HaveLen(1))
+	g.Expect(configMaps.Items[0].Name).To(Equal("kept"))
+}
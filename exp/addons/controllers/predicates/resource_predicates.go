@@ -20,6 +20,7 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -58,3 +59,39 @@ func AddonsSecretCreate(logger logr.Logger) predicate.Funcs {
 		GenericFunc: func(e event.GenericEvent) bool { return false },
 	}
 }
+
+// ResourceCreateOrUpdate returns a predicate that returns true for a create or update event
+func ResourceCreateOrUpdate(logger logr.Logger) predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
+// AddonsSecretCreateOrUpdate returns a predicate that returns true for a Secret create or update event if in addons Secret type
+func AddonsSecretCreateOrUpdate(logger logr.Logger) predicate.Funcs {
+	log := logger.WithValues("predicate", "SecretCreateOrUpdate")
+
+	isAddonsSecret := func(o client.Object) bool {
+		s, ok := o.(*corev1.Secret)
+		if !ok {
+			log.V(4).Info("Expected Secret", "secret", o.GetObjectKind().GroupVersionKind().String())
+			return false
+		}
+		if string(s.Type) != string(addonsv1.ClusterResourceSetSecretType) {
+			log.V(4).Info("Expected Secret Type", "type", addonsv1.SecretClusterResourceSetResourceKind,
+				"got", string(s.Type))
+			return false
+		}
+		return true
+	}
+
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isAddonsSecret(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isAddonsSecret(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
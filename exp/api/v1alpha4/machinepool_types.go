@@ -26,6 +26,12 @@ import (
 const (
 	// MachinePoolFinalizer is used to ensure deletion of dependencies (nodes, infra).
 	MachinePoolFinalizer = "machinepool.exp.cluster.x-k8s.io"
+
+	// RemediateMachinePoolInstancesAnnotation is set by the MachineHealthCheck controller on infrastructure
+	// machine pools to request the remediation of the instances identified by the comma separated list of
+	// provider IDs; infrastructure providers are expected to replace the instances and to drop their provider IDs
+	// from the ProviderIDList, while the annotation is owned by the MachineHealthCheck controller.
+	RemediateMachinePoolInstancesAnnotation = "machinepool.exp.cluster.x-k8s.io/remediate-instances"
 )
 
 // ANCHOR: MachinePoolSpec
@@ -118,6 +118,14 @@ func (m *MachinePool) validate(old *MachinePool) error {
 		)
 	}
 
+	// MachinePools do not create Machines, so there is no controller applying the taints to the Nodes.
+	if len(m.Spec.Template.Spec.Taints) > 0 {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(field.NewPath("spec", "template", "spec", "taints"), "taints are not supported on MachinePools"),
+		)
+	}
+
 	if old != nil && old.Spec.ClusterName != m.Spec.ClusterName {
 		allErrs = append(
 			allErrs,
@@ -218,3 +218,46 @@ func TestMachinePoolClusterNameImmutable(t *testing.T) {
 		})
 	}
 }
+
+func TestMachinePoolTaintsValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		taints    []corev1.Taint
+		expectErr bool
+	}{
+		{
+			name:      "should succeed without taints",
+			expectErr: false,
+		},
+		{
+			name:      "should fail with taints",
+			taints:    []corev1.Taint{{Key: "foo", Effect: corev1.TaintEffectNoSchedule}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &MachinePool{
+				Spec: MachinePoolSpec{
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Bootstrap: clusterv1.Bootstrap{ConfigRef: &corev1.ObjectReference{}},
+							Taints:    tt.taints,
+						},
+					},
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
@@ -126,7 +126,6 @@ func (r *MachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	defer func() {
 		r.reconcilePhase(mp)
-		// TODO(jpang): add support for metrics.
 
 		// Always update the readyCondition with the summary of the machinepool conditions.
 		conditions.SetSummary(mp,
@@ -210,15 +209,18 @@ func (r *MachinePoolReconciler) reconcile(ctx context.Context, cluster *clusterv
 }
 
 func (r *MachinePoolReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster, mp *expv1.MachinePool) (ctrl.Result, error) {
-	if ok, err := r.reconcileDeleteExternal(ctx, mp); !ok || err != nil {
+	if ok, err := r.reconcileDeleteExternal(ctx, cluster, mp); !ok || err != nil {
 		// Return early and don't remove the finalizer if we got an error or
 		// the external reconciliation deletion isn't ready.
 		return ctrl.Result{}, err
 	}
 
-	if err := r.reconcileDeleteNodes(ctx, cluster, mp); err != nil {
-		// Return early and don't remove the finalizer if we got an error.
-		return ctrl.Result{}, err
+	// The Nodes are left in place when the Cluster is deleted in orphan mode.
+	if !annotations.IsOrphanDeletion(cluster) {
+		if err := r.reconcileDeleteNodes(ctx, cluster, mp); err != nil {
+			// Return early and don't remove the finalizer if we got an error.
+			return ctrl.Result{}, err
+		}
 	}
 
 	controllerutil.RemoveFinalizer(mp, expv1.MachinePoolFinalizer)
@@ -241,8 +243,9 @@ func (r *MachinePoolReconciler) reconcileDeleteNodes(ctx context.Context, cluste
 	return nil
 }
 
-// reconcileDeleteExternal tries to delete external references, returning true if it cannot find any.
-func (r *MachinePoolReconciler) reconcileDeleteExternal(ctx context.Context, m *expv1.MachinePool) (bool, error) {
+// reconcileDeleteExternal tries to delete external references, returning true if it cannot find any; if the Cluster
+// is being deleted in orphan mode the external objects are orphaned, so their providers leave the underlying resources running.
+func (r *MachinePoolReconciler) reconcileDeleteExternal(ctx context.Context, cluster *clusterv1.Cluster, m *expv1.MachinePool) (bool, error) {
 	objects := []*unstructured.Unstructured{}
 	references := []*corev1.ObjectReference{
 		m.Spec.Template.Spec.Bootstrap.ConfigRef,
@@ -267,6 +270,14 @@ func (r *MachinePoolReconciler) reconcileDeleteExternal(ctx context.Context, m *
 
 	// Issue a delete request for any object that has been found.
 	for _, obj := range objects {
+		if annotations.IsOrphanDeletion(cluster) {
+			if err := external.Orphan(ctx, r.Client, obj); err != nil {
+				return false, errors.Wrapf(err,
+					"failed to orphan %v %q for MachinePool %q in namespace %q",
+					obj.GroupVersionKind(), obj.GetName(), m.Name, m.Namespace)
+			}
+			continue
+		}
 		if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
 			return false, errors.Wrapf(err,
 				"failed to delete %v %q for MachinePool %q in namespace %q",
@@ -553,7 +553,7 @@ func TestReconcileMachinePoolDeleteExternal(t *testing.T) {
 				Client: helpers.NewFakeClientWithScheme(scheme.Scheme, objs...),
 			}
 
-			ok, err := r.reconcileDeleteExternal(ctx, machinePool)
+			ok, err := r.reconcileDeleteExternal(ctx, testCluster, machinePool)
 			g.Expect(ok).To(Equal(tc.expected))
 			if tc.expectError {
 				g.Expect(err).To(HaveOccurred())
@@ -564,6 +564,67 @@ func TestReconcileMachinePoolDeleteExternal(t *testing.T) {
 	}
 }
 
+func TestReconcileMachinePoolDeleteExternalOrphan(t *testing.T) {
+	g := NewWithT(t)
+
+	deletionTimestamp := metav1.Now()
+	testCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "test-cluster",
+			DeletionTimestamp: &deletionTimestamp,
+			Annotations:       map[string]string{clusterv1.OrphanAnnotation: ""},
+		},
+	}
+
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "InfrastructureConfig",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1alpha4",
+			"metadata": map[string]interface{}{
+				"name":       "orphan-infra",
+				"namespace":  "default",
+				"finalizers": []interface{}{"infrastructure.cluster.x-k8s.io/finalizer"},
+			},
+		},
+	}
+
+	machinePool := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "orphan",
+			Namespace: "default",
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: "test-cluster",
+			Replicas:    pointer.Int32Ptr(1),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+						Kind:       "InfrastructureConfig",
+						Name:       "orphan-infra",
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	r := &MachinePoolReconciler{
+		Client: helpers.NewFakeClientWithScheme(scheme.Scheme, testCluster, machinePool, infraConfig),
+	}
+
+	// The infrastructure machine pool is orphaned, and it is gone at the next reconcile even if it had a finalizer.
+	ok, err := r.reconcileDeleteExternal(ctx, testCluster, machinePool)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ok).To(BeFalse())
+
+	ok, err = r.reconcileDeleteExternal(ctx, testCluster, machinePool)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ok).To(BeTrue())
+}
+
 func TestRemoveMachinePoolFinalizerAfterDeleteReconcile(t *testing.T) {
 	g := NewWithT(t)
 
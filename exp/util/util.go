@@ -18,6 +18,7 @@ package util
 
 import (
 	"context"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -57,6 +58,16 @@ func GetMachinePoolByName(ctx context.Context, c client.Client, namespace, name
 	return m, nil
 }
 
+// GetMachinePoolInstancesToRemediate returns the provider IDs of the instances the MachineHealthCheck controller
+// requested to remediate on the given infrastructure machine pool.
+func GetMachinePoolInstancesToRemediate(obj metav1.Object) []string {
+	value := obj.GetAnnotations()[clusterv1exp.RemediateMachinePoolInstancesAnnotation]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
 // MachinePoolToInfrastructureMapFunc returns a handler.MapFunc that watches for
 // MachinePool events and returns reconciliation requests for an infrastructure provider object.
 func MachinePoolToInfrastructureMapFunc(gvk schema.GroupVersionKind, log logr.Logger) handler.MapFunc {
@@ -33,6 +33,9 @@ const (
 
 	// alpha: v0.3
 	ClusterResourceSet featuregate.Feature = "ClusterResourceSet"
+
+	// alpha: v0.4
+	ClusterTopology featuregate.Feature = "ClusterTopology"
 )
 
 func init() {
@@ -45,4 +48,5 @@ var defaultClusterAPIFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	// Every feature should be initiated here:
 	MachinePool:        {Default: false, PreRelease: featuregate.Alpha},
 	ClusterResourceSet: {Default: false, PreRelease: featuregate.Alpha},
+	ClusterTopology:    {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -33,7 +33,9 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/cmd/version"
 	"sigs.k8s.io/cluster-api/controllers"
+	"sigs.k8s.io/cluster-api/controllers/metrics"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/controllers/topology"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1alpha4"
 	addonscontrollers "sigs.k8s.io/cluster-api/exp/addons/controllers"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
@@ -42,6 +44,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -58,6 +61,7 @@ var (
 	watchNamespace                string
 	profilerAddress               string
 	clusterConcurrency            int
+	clusterTopologyConcurrency    int
 	machineConcurrency            int
 	machineSetConcurrency         int
 	machineDeploymentConcurrency  int
@@ -106,6 +110,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterConcurrency, "cluster-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.IntVar(&clusterTopologyConcurrency, "clustertopology-concurrency", 10,
+		"Number of clusters with a managed topology to process simultaneously")
+
 	fs.IntVar(&machineConcurrency, "machine-concurrency", 10,
 		"Number of machines to process simultaneously")
 
@@ -174,6 +181,7 @@ func main() {
 	ctx := ctrl.SetupSignalHandler()
 
 	setupChecks(mgr)
+	setupMetrics(mgr)
 	setupReconcilers(ctx, mgr)
 	setupWebhooks(mgr)
 
@@ -185,6 +193,18 @@ func main() {
 	}
 }
 
+func setupMetrics(mgr ctrl.Manager) {
+	if webhookPort != 0 {
+		return
+	}
+
+	// The state of Cluster API objects is read from the manager cache on each scrape.
+	if err := ctrlmetrics.Registry.Register(metrics.NewStateCollector(mgr.GetCache())); err != nil {
+		setupLog.Error(err, "unable to register metrics collector")
+		os.Exit(1)
+	}
+}
+
 func setupChecks(mgr ctrl.Manager) {
 	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to create ready check")
@@ -248,6 +268,15 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	if feature.Gates.Enabled(feature.ClusterTopology) {
+		if err := (&topology.ClusterReconciler{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(ctx, mgr, concurrency(clusterTopologyConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterTopology")
+			os.Exit(1)
+		}
+	}
+
 	if feature.Gates.Enabled(feature.MachinePool) {
 		if err := (&expcontrollers.MachinePoolReconciler{
 			Client: mgr.GetClient(),
@@ -292,6 +321,13 @@ func setupWebhooks(mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	// NOTE: ClusterClass webhook is always registered, so the validation rejects ClusterClass objects
+	// when the ClusterTopology feature gate is disabled.
+	if err := (&clusterv1.ClusterClass{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ClusterClass")
+		os.Exit(1)
+	}
+
 	if err := (&clusterv1.Machine{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Machine")
 		os.Exit(1)
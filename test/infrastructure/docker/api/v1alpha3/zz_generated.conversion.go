@@ -132,11 +132,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.DockerMachineTemplate)(nil), (*DockerMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_DockerMachineTemplate_To_v1alpha3_DockerMachineTemplate(a.(*v1alpha4.DockerMachineTemplate), b.(*DockerMachineTemplate), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*DockerMachineTemplateList)(nil), (*v1alpha4.DockerMachineTemplateList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_DockerMachineTemplateList_To_v1alpha4_DockerMachineTemplateList(a.(*DockerMachineTemplateList), b.(*v1alpha4.DockerMachineTemplateList), scope)
 	}); err != nil {
@@ -177,6 +172,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.DockerMachineTemplate)(nil), (*DockerMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_DockerMachineTemplate_To_v1alpha3_DockerMachineTemplate(a.(*v1alpha4.DockerMachineTemplate), b.(*DockerMachineTemplate), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -580,17 +580,23 @@ func autoConvert_v1alpha4_DockerMachineTemplate_To_v1alpha3_DockerMachineTemplat
 	if err := Convert_v1alpha4_DockerMachineTemplateSpec_To_v1alpha3_DockerMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
 	}
+	// WARNING: in.Status requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_DockerMachineTemplate_To_v1alpha3_DockerMachineTemplate is an autogenerated conversion function.
-func Convert_v1alpha4_DockerMachineTemplate_To_v1alpha3_DockerMachineTemplate(in *v1alpha4.DockerMachineTemplate, out *DockerMachineTemplate, s conversion.Scope) error {
-	return autoConvert_v1alpha4_DockerMachineTemplate_To_v1alpha3_DockerMachineTemplate(in, out, s)
-}
-
 func autoConvert_v1alpha3_DockerMachineTemplateList_To_v1alpha4_DockerMachineTemplateList(in *DockerMachineTemplateList, out *v1alpha4.DockerMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha4.DockerMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha4.DockerMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_DockerMachineTemplate_To_v1alpha4_DockerMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -601,7 +607,17 @@ func Convert_v1alpha3_DockerMachineTemplateList_To_v1alpha4_DockerMachineTemplat
 
 func autoConvert_v1alpha4_DockerMachineTemplateList_To_v1alpha3_DockerMachineTemplateList(in *v1alpha4.DockerMachineTemplateList, out *DockerMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]DockerMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DockerMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_DockerMachineTemplate_To_v1alpha3_DockerMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,16 +26,26 @@ type DockerMachineTemplateSpec struct {
 	Template DockerMachineTemplateResource `json:"template"`
 }
 
+// DockerMachineTemplateStatus defines the observed state of DockerMachineTemplate
+type DockerMachineTemplateStatus struct {
+	// Capacity defines the resources available on the machines created from this template;
+	// it is used by the cluster autoscaler to scale from zero replicas.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=dockermachinetemplates,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 
 // DockerMachineTemplate is the Schema for the dockermachinetemplates API
 type DockerMachineTemplate struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec DockerMachineTemplateSpec `json:"spec,omitempty"`
+	Spec   DockerMachineTemplateSpec   `json:"spec,omitempty"`
+	Status DockerMachineTemplateStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
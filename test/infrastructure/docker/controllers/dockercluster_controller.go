@@ -26,6 +26,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/docker"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
@@ -105,7 +106,7 @@ func (r *DockerClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	// Handle deleted clusters
 	if !dockerCluster.DeletionTimestamp.IsZero() {
-		return r.reconcileDelete(ctx, dockerCluster, externalLoadBalancer)
+		return r.reconcileDelete(ctx, cluster, dockerCluster, externalLoadBalancer)
 	}
 
 	// Handle non-deleted clusters
@@ -159,7 +160,7 @@ func (r *DockerClusterReconciler) reconcileNormal(ctx context.Context, dockerClu
 	return ctrl.Result{}, nil
 }
 
-func (r *DockerClusterReconciler) reconcileDelete(ctx context.Context, dockerCluster *infrav1.DockerCluster, externalLoadBalancer *docker.LoadBalancer) (ctrl.Result, error) {
+func (r *DockerClusterReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster, dockerCluster *infrav1.DockerCluster, externalLoadBalancer *docker.LoadBalancer) (ctrl.Result, error) {
 	// Set the LoadBalancerAvailableCondition reporting delete is started, and issue a patch in order to make
 	// this visible to the users.
 	// NB. The operation in docker is fast, so there is the chance the user will not notice the status change;
@@ -173,6 +174,13 @@ func (r *DockerClusterReconciler) reconcileDelete(ctx context.Context, dockerClu
 		return ctrl.Result{}, errors.Wrap(err, "failed to patch DockerCluster")
 	}
 
+	// If the cluster is being deleted in orphan mode, leave the load balancer running and only remove the finalizer.
+	if annotations.IsOrphanDeletion(cluster) {
+		ctrl.LoggerFrom(ctx).Info("Cluster is being deleted in orphan mode, leaving the load balancer container running")
+		controllerutil.RemoveFinalizer(dockerCluster, infrav1.ClusterFinalizer)
+		return ctrl.Result{}, nil
+	}
+
 	// Delete the docker container hosting the load balancer
 	if err := externalLoadBalancer.Delete(ctx); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to delete load balancer")
@@ -29,6 +29,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/docker"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
@@ -147,7 +148,7 @@ func (r *DockerMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	// Handle deleted machines
 	if !dockerMachine.ObjectMeta.DeletionTimestamp.IsZero() {
-		return r.reconcileDelete(ctx, machine, dockerMachine, externalMachine, externalLoadBalancer)
+		return r.reconcileDelete(ctx, cluster, machine, dockerMachine, externalMachine, externalLoadBalancer)
 	}
 
 	// Handle non-deleted machines
@@ -311,7 +312,7 @@ func (r *DockerMachineReconciler) reconcileNormal(ctx context.Context, cluster *
 	return ctrl.Result{}, nil
 }
 
-func (r *DockerMachineReconciler) reconcileDelete(ctx context.Context, machine *clusterv1.Machine, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine, externalLoadBalancer *docker.LoadBalancer) (ctrl.Result, error) {
+func (r *DockerMachineReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, dockerMachine *infrav1.DockerMachine, externalMachine *docker.Machine, externalLoadBalancer *docker.LoadBalancer) (ctrl.Result, error) {
 	// Set the ContainerProvisionedCondition reporting delete is started, and issue a patch in order to make
 	// this visible to the users.
 	// NB. The operation in docker is fast, so there is the chance the user will not notice the status change;
@@ -325,6 +326,13 @@ func (r *DockerMachineReconciler) reconcileDelete(ctx context.Context, machine *
 		return ctrl.Result{}, errors.Wrap(err, "failed to patch DockerMachine")
 	}
 
+	// if the cluster is being deleted in orphan mode, leave the container running and only remove the finalizer.
+	if annotations.IsOrphanDeletion(cluster) {
+		ctrl.LoggerFrom(ctx).Info("Cluster is being deleted in orphan mode, leaving the machine container running")
+		controllerutil.RemoveFinalizer(dockerMachine, infrav1.MachineFinalizer)
+		return ctrl.Result{}, nil
+	}
+
 	// delete the machine
 	if err := externalMachine.Delete(ctx); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to delete DockerMachine")
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/test/infrastructure/docker/docker"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// defaultMaxPods is the default maximum number of Pods that can run on a kubelet.
+const defaultMaxPods = 110
+
+// DockerMachineTemplateReconciler reconciles a DockerMachineTemplate object
+type DockerMachineTemplateReconciler struct {
+	client.Client
+
+	// hostCapacity returns the number of CPUs and the amount of memory of the docker host;
+	// it defaults to docker.HostCapacity.
+	hostCapacity func() (int64, int64, error)
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachinetemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=dockermachinetemplates/status,verbs=get;update;patch
+
+// Reconcile publishes the capacity of the machines created from a DockerMachineTemplate.
+func (r *DockerMachineTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// Fetch the DockerMachineTemplate instance.
+	dockerMachineTemplate := &infrav1.DockerMachineTemplate{}
+	if err := r.Client.Get(ctx, req.NamespacedName, dockerMachineTemplate); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(dockerMachineTemplate, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	hostCapacity := r.hostCapacity
+	if hostCapacity == nil {
+		hostCapacity = docker.HostCapacity
+	}
+	cpus, memory, err := hostCapacity()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to get the capacity of the docker host")
+	}
+
+	dockerMachineTemplate.Status.Capacity = corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewQuantity(cpus, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(memory, resource.BinarySI),
+		corev1.ResourcePods:   *resource.NewQuantity(defaultMaxPods, resource.DecimalSI),
+	}
+
+	return ctrl.Result{}, patchHelper.Patch(ctx, dockerMachineTemplate)
+}
+
+// SetupWithManager will add watches for this controller
+func (r *DockerMachineTemplateReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.DockerMachineTemplate{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPaused(ctrl.LoggerFrom(ctx))).
+		Complete(r)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api/test/infrastructure/docker/api/v1alpha4"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDockerMachineTemplateReconciler_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	dockerMachineTemplate := &infrav1.DockerMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-docker-machine-template",
+			Namespace: "default",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(dockerMachineTemplate).Build()
+	r := DockerMachineTemplateReconciler{
+		Client: c,
+		hostCapacity: func() (int64, int64, error) {
+			return 4, 8 * 1024 * 1024 * 1024, nil
+		},
+	}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(dockerMachineTemplate)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(dockerMachineTemplate), dockerMachineTemplate)).To(Succeed())
+	g.Expect(dockerMachineTemplate.Status.Capacity).To(HaveLen(3))
+	g.Expect(dockerMachineTemplate.Status.Capacity.Cpu().Equal(resource.MustParse("4"))).To(BeTrue())
+	g.Expect(dockerMachineTemplate.Status.Capacity.Memory().Equal(resource.MustParse("8Gi"))).To(BeTrue())
+	g.Expect(dockerMachineTemplate.Status.Capacity.Pods().Equal(resource.MustParse("110"))).To(BeTrue())
+
+	// A missing DockerMachineTemplate is ignored.
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "missing"}})
+	g.Expect(err).ToNot(HaveOccurred())
+}
@@ -18,6 +18,7 @@ package docker
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -132,3 +133,27 @@ func list(visit func(string, *types.Node), filters ...string) error {
 	}
 	return nil
 }
+
+// HostCapacity returns the number of CPUs and the amount of memory, in bytes, of the docker host.
+// The containers running the machines are not resource constrained, so this is the capacity of each machine.
+func HostCapacity() (cpus int64, memory int64, err error) {
+	cmd := exec.Command("docker", "info", "--format", "{{.NCPU}} {{.MemTotal}}")
+	lines, err := exec.CombinedOutputLines(cmd)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to get docker info. Output: %s", lines)
+	}
+	if len(lines) != 1 {
+		return 0, 0, errors.Errorf("invalid output when getting docker info: %s", lines)
+	}
+	parts := strings.Fields(lines[0])
+	if len(parts) != 2 {
+		return 0, 0, errors.Errorf("invalid output when getting docker info: %s", lines[0])
+	}
+	if cpus, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid number of CPUs: %s", parts[0])
+	}
+	if memory, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid amount of memory: %s", parts[1])
+	}
+	return cpus, memory, nil
+}
@@ -32,6 +32,7 @@ import (
 	infrav1exp "sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/test/infrastructure/docker/exp/docker"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -150,6 +151,13 @@ func (r *DockerMachinePoolReconciler) SetupWithManager(mgr ctrl.Manager, options
 }
 
 func (r *DockerMachinePoolReconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster, machinePool *clusterv1exp.MachinePool, dockerMachinePool *infrav1exp.DockerMachinePool, log logr.Logger) (ctrl.Result, error) {
+	// if the cluster is being deleted in orphan mode, leave the machine containers running and only remove the finalizer.
+	if annotations.IsOrphanDeletion(cluster) {
+		log.Info("Cluster is being deleted in orphan mode, leaving the machine pool containers running")
+		controllerutil.RemoveFinalizer(dockerMachinePool, infrav1exp.MachinePoolFinalizer)
+		return ctrl.Result{}, nil
+	}
+
 	pool, err := docker.NewNodePool(r.Client, cluster, machinePool, dockerMachinePool, log)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to build new node pool")
@@ -179,6 +187,11 @@ func (r *DockerMachinePoolReconciler) reconcileNormal(ctx context.Context, clust
 		return ctrl.Result{}, errors.Wrap(err, "failed to build new node pool")
 	}
 
+	// delete the instances the MachineHealthCheck controller requested to remediate, so they get replaced
+	if err := pool.RemediateInstances(ctx, utilexp.GetMachinePoolInstancesToRemediate(dockerMachinePool)); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to remediate unhealthy machines")
+	}
+
 	// if we don't have enough nodes matching spec, build them
 	if err := pool.ReconcileMachines(ctx); err != nil {
 		if errors.Is(err, &docker.TransientError{}) {
@@ -107,6 +107,44 @@ func (np *NodePool) ReconcileMachines(ctx context.Context) error {
 	return np.refresh()
 }
 
+// RemediateInstances will delete the machines of the instances identified by the given provider IDs and update the
+// docker machine pool status; the deleted instances are replaced with new machines by ReconcileMachines.
+func (np *NodePool) RemediateInstances(ctx context.Context, providerIDs []string) error {
+	if len(providerIDs) == 0 {
+		return nil
+	}
+
+	toRemediate := map[string]interface{}{}
+	for _, providerID := range providerIDs {
+		toRemediate[providerID] = nil
+	}
+
+	var stats []*infrav1exp.DockerMachinePoolInstanceStatus
+	for _, machineStatus := range np.dockerMachinePool.Status.Instances {
+		if machineStatus.ProviderID == nil {
+			stats = append(stats, machineStatus)
+			continue
+		}
+		if _, ok := toRemediate[*machineStatus.ProviderID]; !ok {
+			stats = append(stats, machineStatus)
+			continue
+		}
+
+		externalMachine, err := docker.NewMachine(np.cluster.Name, machineStatus.InstanceName, np.dockerMachinePool.Spec.Template.CustomImage, np.labelFilters, np.logger)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create helper for managing the externalMachine named %s", machineStatus.InstanceName)
+		}
+
+		np.logger.Info("Remediating unhealthy machine", "instance", machineStatus.InstanceName)
+		if err := externalMachine.Delete(ctx); err != nil {
+			return errors.Wrapf(err, "failed to delete machine %s", machineStatus.InstanceName)
+		}
+	}
+
+	np.dockerMachinePool.Status.Instances = stats
+	return np.refresh()
+}
+
 // DeleteExtraMachines will delete all of the machines outside of the machine pool / docker machine pool spec and update
 // the docker machine pool status.
 func (np *NodePool) DeleteExtraMachines(ctx context.Context) error {
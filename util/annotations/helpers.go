@@ -17,6 +17,7 @@ limitations under the License.
 package annotations
 
 import (
+	"sort"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,6 +42,26 @@ func HasPausedAnnotation(o metav1.Object) bool {
 	return ok
 }
 
+// IsOrphanDeletion returns true if the Cluster is being deleted and has the `orphan` annotation; in this case
+// controllers must release the infrastructure they manage instead of destroying it.
+func IsOrphanDeletion(cluster *clusterv1.Cluster) bool {
+	if cluster.DeletionTimestamp.IsZero() {
+		return false
+	}
+	return HasOrphanAnnotation(cluster)
+}
+
+// HasOrphanAnnotation returns true if the object has the `orphan` annotation.
+func HasOrphanAnnotation(o metav1.Object) bool {
+	annotations := o.GetAnnotations()
+	if annotations == nil {
+		return false
+	}
+	_, ok := annotations[clusterv1.OrphanAnnotation]
+	return ok
+}
+
+// HasWithPrefix returns true if at least one of the annotations has the prefix specified.
 func HasWithPrefix(prefix string, annotations map[string]string) bool {
 	for key := range annotations {
 		if strings.HasPrefix(key, prefix) {
@@ -49,3 +70,15 @@ func HasWithPrefix(prefix string, annotations map[string]string) bool {
 	}
 	return false
 }
+
+// GetWithPrefix returns the sorted list of annotation keys with the prefix specified.
+func GetWithPrefix(prefix string, annotations map[string]string) []string {
+	keys := []string{}
+	for key := range annotations {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance implements utilities to check the maintenance windows of a Cluster.
+package maintenance
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+// IsOpen returns true if disruptive operations can start for the Cluster at the given time, i.e. the Cluster does not
+// define maintenance windows, one of them is open or the Cluster has the MaintenanceWindowOverrideAnnotation.
+// Otherwise it returns the time the next maintenance window opens, or the zero time if none of them will ever open.
+func IsOpen(cluster *clusterv1.Cluster, now time.Time) (bool, time.Time, error) {
+	if len(cluster.Spec.MaintenanceWindows) == 0 {
+		return true, time.Time{}, nil
+	}
+	if _, ok := cluster.Annotations[clusterv1.MaintenanceWindowOverrideAnnotation]; ok {
+		return true, time.Time{}, nil
+	}
+
+	var next time.Time
+	for i := range cluster.Spec.MaintenanceWindows {
+		window := cluster.Spec.MaintenanceWindows[i]
+		schedule, location, err := parse(window)
+		if err != nil {
+			return false, time.Time{}, errors.Wrapf(err, "invalid maintenance window %d of Cluster %s/%s", i, cluster.Namespace, cluster.Name)
+		}
+
+		// The window is open if it opened during the last Duration, i.e. the first activation after now-Duration is not after now.
+		opensAt := schedule.Next(now.In(location).Add(-window.Duration.Duration))
+		if opensAt.IsZero() {
+			continue
+		}
+		if !opensAt.After(now) {
+			return true, time.Time{}, nil
+		}
+		if next.IsZero() || opensAt.Before(next) {
+			next = opensAt
+		}
+	}
+	return false, next, nil
+}
+
+// parse parses the schedule and the time zone of a maintenance window.
+func parse(window clusterv1.MaintenanceWindow) (cron.Schedule, *time.Location, error) {
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse schedule %q", window.Schedule)
+	}
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to load time zone %q", window.TimeZone)
+	}
+	return schedule, location, nil
+}
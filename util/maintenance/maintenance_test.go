@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+func TestIsOpen(t *testing.T) {
+	// Monday, 1st of March 2021, 12:00 UTC.
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	nightly := clusterv1.MaintenanceWindow{
+		Schedule: "0 22 * * *",
+		Duration: metav1.Duration{Duration: 4 * time.Hour},
+	}
+	lunch := clusterv1.MaintenanceWindow{
+		Schedule: "0 11 * * 1-5",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+	}
+	lunchInNewYork := clusterv1.MaintenanceWindow{
+		Schedule: "0 11 * * 1-5",
+		Duration: metav1.Duration{Duration: 2 * time.Hour},
+		TimeZone: "America/New_York",
+	}
+
+	tests := []struct {
+		name        string
+		windows     []clusterv1.MaintenanceWindow
+		annotations map[string]string
+		expectOpen  bool
+		expectNext  time.Time
+		expectErr   bool
+	}{
+		{
+			name:       "open if the Cluster does not define maintenance windows",
+			expectOpen: true,
+		},
+		{
+			name:       "open if a window is open",
+			windows:    []clusterv1.MaintenanceWindow{nightly, lunch},
+			expectOpen: true,
+		},
+		{
+			name:        "open if the Cluster has the override annotation",
+			windows:     []clusterv1.MaintenanceWindow{nightly},
+			annotations: map[string]string{clusterv1.MaintenanceWindowOverrideAnnotation: ""},
+			expectOpen:  true,
+		},
+		{
+			name:       "closed until the next window opens",
+			windows:    []clusterv1.MaintenanceWindow{nightly},
+			expectOpen: false,
+			expectNext: time.Date(2021, 3, 1, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "closed until the earliest of the next windows opens, in its time zone",
+			windows:    []clusterv1.MaintenanceWindow{nightly, lunchInNewYork},
+			expectOpen: false,
+			expectNext: time.Date(2021, 3, 1, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "error if a schedule is not valid",
+			windows:   []clusterv1.MaintenanceWindow{{Schedule: "every day", Duration: metav1.Duration{Duration: time.Hour}}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec:       clusterv1.ClusterSpec{MaintenanceWindows: tt.windows},
+			}
+			open, next, err := IsOpen(cluster, now)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(open).To(Equal(tt.expectOpen))
+			g.Expect(next.Equal(tt.expectNext)).To(BeTrue(), "expected next window at %s, got %s", tt.expectNext, next)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics implements helpers for exposing the state of Cluster API objects as Prometheus metrics.
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// Namespace is the Prometheus namespace of the metrics exposed by Cluster API controllers.
+	Namespace = "capi"
+
+	// collectTimeout is the maximum time a collector spends reading objects on each scrape.
+	collectTimeout = 10 * time.Second
+)
+
+var conditionStatuses = []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown}
+
+// NewPhaseDesc returns the description of a gauge reporting the phase of the objects of a kind, identified by subsystem.
+// The gauge has the namespace and name labels, followed by the given labels and by the phase label.
+func NewPhaseDesc(subsystem string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, subsystem, "status_phase"),
+		"The current phase of the "+subsystem+"; the value is 1 for the current phase, 0 otherwise.",
+		objectLabels(labels, "phase"),
+		nil,
+	)
+}
+
+// NewConditionDesc returns the description of a gauge reporting the conditions of the objects of a kind, identified by subsystem.
+// The gauge has the namespace and name labels, followed by the given labels and by the type and status labels.
+func NewConditionDesc(subsystem string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, subsystem, "status_condition"),
+		"The condition of the "+subsystem+"; the value is 1 for the current status of the condition, 0 otherwise.",
+		objectLabels(labels, "type", "status"),
+		nil,
+	)
+}
+
+func objectLabels(labels []string, extra ...string) []string {
+	all := []string{"namespace", "name"}
+	all = append(all, labels...)
+	return append(all, extra...)
+}
+
+// CollectPhase reports a sample of a phase gauge for each of the given phases;
+// the sample is 1 for the current phase of the object and 0 for the other ones.
+func CollectPhase(ch chan<- prometheus.Metric, desc *prometheus.Desc, phase string, phases []string, labelValues ...string) {
+	for _, p := range phases {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, boolValue(p == phase), append(labelValues, p)...)
+	}
+}
+
+// CollectConditions reports three samples of a condition gauge for each condition of the object, one for each
+// condition status; the sample is 1 for the current status of the condition and 0 for the other ones.
+func CollectConditions(ch chan<- prometheus.Metric, desc *prometheus.Desc, getter conditions.Getter, labelValues ...string) {
+	for _, c := range getter.GetConditions() {
+		for _, s := range conditionStatuses {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, boolValue(c.Status == s), append(labelValues, string(c.Type), string(s))...)
+		}
+	}
+}
+
+// CollectContext returns the context used by a collector for reading objects while collecting metrics.
+func CollectContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), collectTimeout)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicates
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestClusterUpdateMaintenanceWindows(t *testing.T) {
+	window := clusterv1.MaintenanceWindow{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+
+	tests := []struct {
+		name     string
+		update   func(c *clusterv1.Cluster)
+		expected bool
+	}{
+		{
+			name:     "maintenance windows added",
+			update:   func(c *clusterv1.Cluster) { c.Spec.MaintenanceWindows = append(c.Spec.MaintenanceWindows, window) },
+			expected: true,
+		},
+		{
+			name:     "maintenance windows changed",
+			update:   func(c *clusterv1.Cluster) { c.Spec.MaintenanceWindows[0].Duration.Duration = time.Hour },
+			expected: true,
+		},
+		{
+			name:     "maintenance windows removed",
+			update:   func(c *clusterv1.Cluster) { c.Spec.MaintenanceWindows = nil },
+			expected: true,
+		},
+		{
+			name: "override annotation added",
+			update: func(c *clusterv1.Cluster) {
+				c.Annotations = map[string]string{clusterv1.MaintenanceWindowOverrideAnnotation: ""}
+			},
+			expected: true,
+		},
+		{
+			name:     "other annotation added",
+			update:   func(c *clusterv1.Cluster) { c.Annotations = map[string]string{"foo": "bar"} },
+			expected: false,
+		},
+		{
+			name:     "other field changed",
+			update:   func(c *clusterv1.Cluster) { c.Status.InfrastructureReady = true },
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			oldCluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				Spec:       clusterv1.ClusterSpec{MaintenanceWindows: []clusterv1.MaintenanceWindow{window}},
+			}
+			newCluster := oldCluster.DeepCopy()
+			tt.update(newCluster)
+
+			p := ClusterUpdateMaintenanceWindows(log.NullLogger{})
+			g.Expect(p.Update(event.UpdateEvent{ObjectOld: oldCluster, ObjectNew: newCluster})).To(Equal(tt.expected))
+			g.Expect(p.Create(event.CreateEvent{Object: newCluster})).To(BeFalse())
+
+			// The predicate composes with the ones used to watch Clusters.
+			g.Expect(Any(log.NullLogger{}, ClusterUnpaused(log.NullLogger{}), p).Update(event.UpdateEvent{ObjectOld: oldCluster, ObjectNew: newCluster})).To(Equal(tt.expected))
+		})
+	}
+}